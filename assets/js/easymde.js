@@ -26,6 +26,12 @@ import "./easymde_2-26-0.min.js";
 		});
 	}
 
+	// Insert markdown picked from the media library at the cursor
+	document.addEventListener('media:insert', function(event) {
+		easyMDE.codemirror.replaceSelection(event.detail);
+		easyMDE.codemirror.focus();
+	});
+
 	// Also sync on editor change for data-bind compatibility
 	easyMDE.codemirror.on('change', function() {
 		targetElement.value = easyMDE.value();
@@ -0,0 +1,94 @@
+package mediaclients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"mortenvistisen/services"
+)
+
+var _ services.MediaStorage = (*LocalDisk)(nil)
+
+// LocalDisk stores media as files in a single directory.
+type LocalDisk struct {
+	dir string
+}
+
+func NewLocalDisk(dir string) (*LocalDisk, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create media directory: %w", err)
+	}
+
+	return &LocalDisk{dir}, nil
+}
+
+func (l *LocalDisk) Put(ctx context.Context, key string, contentType string, data []byte) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so a file is never served half
+	// written.
+	tmp, err := os.CreateTemp(l.dir, ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func (l *LocalDisk) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, services.ErrMediaFileNotFound
+	}
+
+	return file, err
+}
+
+func (l *LocalDisk) Delete(ctx context.Context, key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// path maps a key to its file, refusing keys that would escape the media
+// directory.
+func (l *LocalDisk) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, ".") || strings.ContainsAny(key, `/\`) {
+		return "", fmt.Errorf("invalid media key %q", key)
+	}
+
+	return filepath.Join(l.dir, key), nil
+}
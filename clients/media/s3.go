@@ -0,0 +1,104 @@
+package mediaclients
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"mortenvistisen/services"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+var _ services.MediaStorage = (*S3)(nil)
+
+// S3 stores media in a bucket on AWS S3 or any S3 compatible service,
+// such as MinIO, Cloudflare R2 or Hetzner Object Storage.
+type S3 struct {
+	client *s3.Client
+	bucket string
+}
+
+// NewS3 creates an S3 backed storage. Leave endpoint empty for AWS; other
+// services need their endpoint and often path style addressing.
+func NewS3(
+	endpoint, region, bucket, accessKeyID, secretAccessKey string,
+	usePathStyle bool,
+) *S3 {
+	client := s3.New(s3.Options{
+		Region: region,
+		Credentials: credentials.NewStaticCredentialsProvider(
+			accessKeyID,
+			secretAccessKey,
+			"",
+		),
+		BaseEndpoint: func() *string {
+			if endpoint == "" {
+				return nil
+			}
+			return aws.String(endpoint)
+		}(),
+		UsePathStyle: usePathStyle,
+		// Not every S3 compatible service understands the checksums the
+		// SDK adds by default.
+		RequestChecksumCalculation: aws.RequestChecksumCalculationWhenRequired,
+		ResponseChecksumValidation: aws.ResponseChecksumValidationWhenRequired,
+	})
+
+	return &S3{
+		client: client,
+		bucket: bucket,
+	}
+}
+
+func (s *S3) Put(ctx context.Context, key string, contentType string, data []byte) error {
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(s.bucket),
+		Key:           aws.String(key),
+		Body:          bytes.NewReader(data),
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(int64(len(data))),
+	})
+	if err != nil {
+		return fmt.Errorf("put s3 object: %w", err)
+	}
+
+	return nil
+}
+
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		var responseErr *awshttp.ResponseError
+		if errors.As(err, &noSuchKey) ||
+			(errors.As(err, &responseErr) && responseErr.HTTPStatusCode() == http.StatusNotFound) {
+			return nil, services.ErrMediaFileNotFound
+		}
+		return nil, fmt.Errorf("get s3 object: %w", err)
+	}
+
+	return out.Body, nil
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("delete s3 object: %w", err)
+	}
+
+	return nil
+}
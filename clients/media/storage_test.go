@@ -0,0 +1,143 @@
+package mediaclients_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	mediaclients "mortenvistisen/clients/media"
+	"mortenvistisen/services"
+)
+
+// fakeS3 is a minimal stand-in for an S3 compatible service, enough for
+// path style object puts, gets and deletes.
+type fakeS3 struct {
+	mu           sync.Mutex
+	objects      map[string][]byte
+	contentTypes map[string]string
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{
+		objects:      make(map[string][]byte),
+		contentTypes: make(map[string]string),
+	}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Header.Get("Authorization") == "" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		f.objects[r.URL.Path] = body
+		f.contentTypes[r.URL.Path] = r.Header.Get("Content-Type")
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet:
+		body, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`)
+			return
+		}
+		w.Header().Set("Content-Type", f.contentTypes[r.URL.Path])
+		w.Write(body)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestMediaStorage(t *testing.T) {
+	fake := newFakeS3()
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	local, err := mediaclients.NewLocalDisk(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	backends := map[string]services.MediaStorage{
+		"local disk": local,
+		"s3": mediaclients.NewS3(
+			server.URL,
+			"us-east-1",
+			"media",
+			"access-key",
+			"secret-key",
+			true,
+		),
+	}
+
+	for name, store := range backends {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			key := "0b5a3b9e-7c53-4b1e-9a57-0f1f3c5d2e11-480w.webp"
+
+			if _, err := store.Get(ctx, key); !errors.Is(err, services.ErrMediaFileNotFound) {
+				t.Fatalf("expected ErrMediaFileNotFound before put, got %v", err)
+			}
+
+			if err := store.Put(ctx, key, "image/webp", []byte("RIFF-image-data")); err != nil {
+				t.Fatalf("put: %v", err)
+			}
+
+			body, err := store.Get(ctx, key)
+			if err != nil {
+				t.Fatalf("get: %v", err)
+			}
+			data, err := io.ReadAll(body)
+			body.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != "RIFF-image-data" {
+				t.Errorf("expected stored data back, got %q", data)
+			}
+
+			if err := store.Delete(ctx, key); err != nil {
+				t.Fatalf("delete: %v", err)
+			}
+			if _, err := store.Get(ctx, key); !errors.Is(err, services.ErrMediaFileNotFound) {
+				t.Errorf("expected ErrMediaFileNotFound after delete, got %v", err)
+			}
+		})
+	}
+
+	if got := fake.contentTypes["/media/0b5a3b9e-7c53-4b1e-9a57-0f1f3c5d2e11-480w.webp"]; got != "image/webp" {
+		t.Errorf("expected content type to reach s3, got %q", got)
+	}
+}
+
+func TestLocalDiskRejectsPathsOutsideDirectory(t *testing.T) {
+	local, err := mediaclients.NewLocalDisk(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"../escape.png", "nested/file.png", ".hidden", ""} {
+		if err := local.Put(context.Background(), key, "image/png", []byte("x")); err == nil ||
+			!strings.Contains(err.Error(), "invalid media key") {
+			t.Errorf("expected %q to be rejected, got %v", key, err)
+		}
+	}
+}
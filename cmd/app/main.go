@@ -11,6 +11,7 @@ import (
 	"time"
 
 	mailclients "mortenvistisen/clients/email"
+	mediaclients "mortenvistisen/clients/media"
 	"mortenvistisen/config"
 	"mortenvistisen/controllers"
 	"mortenvistisen/database"
@@ -21,6 +22,7 @@ import (
 	"mortenvistisen/queue/workers"
 	"mortenvistisen/router"
 	"mortenvistisen/router/middleware"
+	"mortenvistisen/services"
 	"mortenvistisen/telemetry"
 
 	"riverqueue.com/riverui"
@@ -58,6 +60,7 @@ func setupControllers(
 	registrations := controllers.NewRegistrations(db, insertOnly, cfg)
 	confirmations := controllers.NewConfirmations(db, cfg)
 	resetPasswords := controllers.NewResetPasswords(db, insertOnly, cfg)
+	invitations := controllers.NewInvitations(db, cfg)
 
 	if err := r.RegisterAPIRoutes(api); err != nil {
 		return err
@@ -83,20 +86,29 @@ func setupControllers(
 		return err
 	}
 
+	if err := r.RegisterInvitationsRoutes(invitations); err != nil {
+		return err
+	}
+
 	if err := r.RegisterRegistrationsRoutes(registrations); err != nil {
 		return err
 	}
 
-	articles := controllers.NewArticles(db, insertOnly, cfg)
+	articles := controllers.NewArticles(db, insertOnly, pagesCache, cfg)
 	if err := r.RegisterArticleRoutes(articles); err != nil {
 		return err
 	}
 
-	tags := controllers.NewTags(db)
+	tags := controllers.NewTags(db, pagesCache)
 	if err := r.RegisterTagRoutes(tags); err != nil {
 		return err
 	}
 
+	series := controllers.NewSeries(db, pagesCache)
+	if err := r.RegisterSeriesRoutes(series); err != nil {
+		return err
+	}
+
 	newsletters := controllers.NewNewsletters(db, insertOnly, cfg)
 	if err := r.RegisterNewsletterRoutes(newsletters); err != nil {
 		return err
@@ -112,6 +124,80 @@ func setupControllers(
 		return err
 	}
 
+	users := controllers.NewUsers(db, insertOnly, cfg)
+	if err := r.RegisterUserRoutes(users); err != nil {
+		return err
+	}
+
+	rateLimits := controllers.NewRateLimits(db)
+	if err := r.RegisterRateLimitRoutes(rateLimits); err != nil {
+		return err
+	}
+
+	auditLogs := controllers.NewAuditLogs(db)
+	if err := r.RegisterAuditLogRoutes(auditLogs); err != nil {
+		return err
+	}
+
+	contentAPI := controllers.NewContentAPI(db, insertOnly, pagesCache, cfg)
+	if err := r.RegisterContentAPIRoutes(contentAPI); err != nil {
+		return err
+	}
+
+	accessTokens := controllers.NewAccessTokens(db, cfg)
+	if err := r.RegisterAccessTokenRoutes(accessTokens); err != nil {
+		return err
+	}
+
+	contentExports := controllers.NewContentExports(db)
+	if err := r.RegisterContentExportRoutes(contentExports); err != nil {
+		return err
+	}
+
+	webhooks := controllers.NewWebhooks(db)
+	if err := r.RegisterWebhookRoutes(webhooks); err != nil {
+		return err
+	}
+
+	webmentions := controllers.NewWebmentions(db, insertOnly, pagesCache)
+	if err := r.RegisterWebmentionRoutes(webmentions); err != nil {
+		return err
+	}
+
+	comments := controllers.NewComments(db, insertOnly, pagesCache, cfg)
+	if err := r.RegisterCommentRoutes(comments); err != nil {
+		return err
+	}
+
+	mediaStorage, err := setupMediaStorage(cfg)
+	if err != nil {
+		return err
+	}
+	media := controllers.NewMedia(db, mediaStorage, cfg)
+	if err := r.RegisterMediaRoutes(media); err != nil {
+		return err
+	}
+
+	linkChecks := controllers.NewLinkChecks(db, insertOnly)
+	if err := r.RegisterLinkCheckRoutes(linkChecks); err != nil {
+		return err
+	}
+
+	ogImages := controllers.NewOGImages(db)
+	if err := r.RegisterOGImageRoutes(ogImages); err != nil {
+		return err
+	}
+
+	activityPub := controllers.NewActivityPub(
+		db,
+		insertOnly,
+		services.NewExternalClient(),
+		services.NewActivityPubSite(cfg.ActivityPub.Username, cfg.ActivityPub.ObjectType),
+	)
+	if err := r.RegisterActivityPubRoutes(activityPub); err != nil {
+		return err
+	}
+
 	// andurel:controller-registration-point
 
 	r.RegisterCustomRoutes(
@@ -122,6 +208,39 @@ func setupControllers(
 	return nil
 }
 
+// setupMediaStorage picks the media library backend from the MEDIA_STORAGE
+// setting.
+func setupMediaStorage(cfg config.Config) (services.MediaStorage, error) {
+	switch cfg.Media.Storage {
+	case "local":
+		return mediaclients.NewLocalDisk(cfg.Media.LocalDir)
+	case "s3":
+		return mediaclients.NewS3(
+			cfg.Media.S3Endpoint,
+			cfg.Media.S3Region,
+			cfg.Media.S3Bucket,
+			cfg.Media.S3AccessKeyID,
+			cfg.Media.S3SecretAccessKey,
+			cfg.Media.S3UsePathStyle,
+		), nil
+	default:
+		return nil, fmt.Errorf("invalid media storage %q", cfg.Media.Storage)
+	}
+}
+
+// publicRoutes lists the routes internal links in content are checked
+// against: the public pages that build-static renders too.
+func publicRoutes(r *router.Router) []services.SiteRoute {
+	static := r.StaticRoutes()
+
+	siteRoutes := make([]services.SiteRoute, len(static))
+	for i, route := range static {
+		siteRoutes[i] = services.SiteRoute{Name: route.Name, Path: route.Path}
+	}
+
+	return siteRoutes
+}
+
 func setupRouter(
 	cfg config.Config,
 	tel *telemetry.Telemetry,
@@ -144,6 +263,7 @@ func setupRouter(
 	r, err := router.New(
 		true,
 		globalMiddleware,
+		mw,
 	)
 	if err != nil {
 		return nil, err
@@ -247,7 +367,15 @@ func run(ctx context.Context) error {
 		markSender = emailClient
 	}
 
-	wrks, err := workers.Register(transSender, markSender)
+	siteRoutes := services.NewSiteRoutes()
+
+	wrks, err := workers.Register(
+		db,
+		transSender,
+		markSender,
+		time.Duration(cfg.Audit.LogRetentionDays)*24*time.Hour,
+		siteRoutes,
+	)
 	if err != nil {
 		return err
 	}
@@ -276,7 +404,7 @@ func run(ctx context.Context) error {
 		}
 	}()
 
-	mw := middleware.New(db)
+	mw := middleware.New(db, cfg)
 
 	endpoints := riverui.NewEndpoints(processor.Client, nil)
 	opts := &riverui.HandlerOpts{
@@ -308,6 +436,8 @@ func run(ctx context.Context) error {
 		return err
 	}
 
+	siteRoutes.Set(publicRoutes(r))
+
 	server := server.New(
 		ctx,
 		cfg.App.Host,
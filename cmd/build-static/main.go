@@ -0,0 +1,128 @@
+// Command build-static renders the public site into a directory that any
+// static host can serve. Only routes the router registers as static are
+// rendered; the admin, API and auth pages stay with the dynamic app.
+//
+//	go run ./cmd/build-static -out dist -base-url https://mortenvistisen.com
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+
+	mediaclients "mortenvistisen/clients/media"
+	"mortenvistisen/config"
+	"mortenvistisen/controllers"
+	"mortenvistisen/database"
+	"mortenvistisen/queue"
+	"mortenvistisen/router"
+	"mortenvistisen/router/middleware"
+	"mortenvistisen/services"
+
+	"github.com/a-h/templ"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run() error {
+	godotenv.Load()
+
+	out := flag.String("out", "dist", "directory to write the site to")
+	baseURL := flag.String("base-url", config.BaseURL, "public URL the site is served from, used in the sitemap and canonical links")
+	clean := flag.Bool("clean", false, "remove the output directory before building")
+	flag.Parse()
+
+	base, err := url.Parse(*baseURL)
+	if err != nil || base.Host == "" {
+		return fmt.Errorf("invalid base url %q", *baseURL)
+	}
+	config.BaseURL = *baseURL
+
+	ctx := context.Background()
+	cfg := config.NewConfig()
+
+	db, err := database.NewPostgres(ctx, cfg.DB.GetDatabaseURL())
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Conn().Close()
+
+	r, err := router.New(false, nil, middleware.New(db, cfg))
+	if err != nil {
+		return err
+	}
+
+	pagesCache, err := controllers.NewCacheBuilder[templ.Component]().Build()
+	if err != nil {
+		return err
+	}
+	assetsCache, err := controllers.NewCacheBuilder[string]().WithSize(2).Build()
+	if err != nil {
+		return err
+	}
+
+	// Static routes only read published content, so the queue is never used.
+	pages := controllers.NewPages(db, queue.InsertOnly{}, pagesCache)
+	if err := r.RegisterPagesRoutes(pages); err != nil {
+		return err
+	}
+	if err := r.RegisterAssetsRoutes(controllers.NewAssets(db, assetsCache)); err != nil {
+		return err
+	}
+	mediaStorage, err := setupMediaStorage(cfg)
+	if err != nil {
+		return err
+	}
+	if err := r.RegisterMediaRoutes(controllers.NewMedia(db, mediaStorage, cfg)); err != nil {
+		return err
+	}
+	if err := r.RegisterOGImageRoutes(controllers.NewOGImages(db)); err != nil {
+		return err
+	}
+	r.RegisterCustomRoutes(http.NotFoundHandler(), pages.NotFound)
+
+	if *clean {
+		if err := os.RemoveAll(*out); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Building static site into %s...\n", *out)
+
+	b := newBuilder(r.Handler, r.StaticRoutes(), base, *out)
+	if err := b.build(ctx); err != nil {
+		return err
+	}
+
+	fmt.Printf("Build complete! %d file(s) written.\n", b.written)
+	return nil
+}
+
+// setupMediaStorage mirrors the app's choice of media backend, so pages
+// can link to uploaded images.
+func setupMediaStorage(cfg config.Config) (services.MediaStorage, error) {
+	switch cfg.Media.Storage {
+	case "local":
+		return mediaclients.NewLocalDisk(cfg.Media.LocalDir)
+	case "s3":
+		return mediaclients.NewS3(
+			cfg.Media.S3Endpoint,
+			cfg.Media.S3Region,
+			cfg.Media.S3Bucket,
+			cfg.Media.S3AccessKeyID,
+			cfg.Media.S3SecretAccessKey,
+			cfg.Media.S3UsePathStyle,
+		), nil
+	default:
+		return nil, fmt.Errorf("invalid media storage %q", cfg.Media.Storage)
+	}
+}
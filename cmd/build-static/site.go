@@ -0,0 +1,295 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"mortenvistisen/assets"
+	"mortenvistisen/router/routes"
+
+	"github.com/labstack/echo/v5"
+)
+
+// notFoundPath is requested once to render 404.html through the app's own
+// not found handler.
+const notFoundPath = "/__build_static_not_found__"
+
+var (
+	htmlLinkPattern    = regexp.MustCompile(`\s(?:href|src|action)="([^"]*)"`)
+	sitemapLinkPattern = regexp.MustCompile(`<loc>([^<]+)</loc>`)
+	cssLinkPattern     = regexp.MustCompile(`url\(\s*['"]?([^'")]+)['"]?\s*\)`)
+	// ogImagePattern finds the generated Open Graph images, which are only
+	// linked from meta tags.
+	ogImagePattern = regexp.MustCompile(`<meta property="og:image" content="([^"]*)"`)
+)
+
+// builder crawls the static routes through the app's handler. Routes
+// without parameters are the starting points; parameterised routes are
+// filled in from links found in rendered pages and the sitemap, and :file
+// parameters of asset routes from the embedded assets.
+type builder struct {
+	handler http.Handler
+	routes  []echo.RouteInfo
+	base    *url.URL
+	out     string
+
+	queue   []string
+	seen    map[string]bool
+	written int
+}
+
+func newBuilder(
+	handler http.Handler,
+	routes []echo.RouteInfo,
+	base *url.URL,
+	out string,
+) *builder {
+	return &builder{
+		handler: handler,
+		routes:  routes,
+		base:    base,
+		out:     out,
+		seen:    make(map[string]bool),
+	}
+}
+
+func (b *builder) build(ctx context.Context) error {
+	assetNames, err := embeddedAssetNames()
+	if err != nil {
+		return err
+	}
+
+	for _, route := range b.routes {
+		switch {
+		case len(route.Parameters) == 0:
+			b.enqueue(route.Path)
+		case len(route.Parameters) == 1 && route.Parameters[0] == "file" &&
+			strings.HasPrefix(route.Path, routes.AssetsPrefix+"/"):
+			for _, name := range assetNames {
+				b.enqueue(strings.Replace(route.Path, ":file", name, 1))
+			}
+		}
+	}
+
+	for len(b.queue) > 0 {
+		p := b.queue[0]
+		b.queue = b.queue[1:]
+
+		if err := b.render(ctx, p); err != nil {
+			return err
+		}
+	}
+
+	return b.renderNotFound(ctx)
+}
+
+func (b *builder) enqueue(p string) {
+	if b.seen[p] {
+		return
+	}
+	b.seen[p] = true
+	b.queue = append(b.queue, p)
+}
+
+func (b *builder) render(ctx context.Context, p string) error {
+	res := b.get(ctx, p)
+	if res.Code != http.StatusOK {
+		// Asset names are tried against every :file route, so misses there
+		// are expected.
+		if !strings.HasPrefix(p, routes.AssetsPrefix+"/") {
+			slog.WarnContext(ctx, "skipping route", "path", p, "status", res.Code)
+		}
+		return nil
+	}
+
+	body := res.Body.String()
+	contentType := res.Header().Get(echo.HeaderContentType)
+
+	switch {
+	case strings.HasPrefix(contentType, echo.MIMETextHTML):
+		b.discover(p, htmlLinkPattern, body)
+		b.discover(p, ogImagePattern, body)
+		body = b.relativize(p, body)
+	case strings.Contains(contentType, "xml"):
+		b.discover(p, sitemapLinkPattern, body)
+	case strings.HasPrefix(contentType, "text/css"):
+		b.discover(p, cssLinkPattern, body)
+	}
+
+	return b.write(outputFile(p), body)
+}
+
+func (b *builder) renderNotFound(ctx context.Context) error {
+	res := b.get(ctx, notFoundPath)
+	if !strings.HasPrefix(res.Header().Get(echo.HeaderContentType), echo.MIMETextHTML) {
+		return nil
+	}
+
+	// 404.html is served at arbitrary depths, so its links stay root
+	// relative.
+	return b.write("404.html", res.Body.String())
+}
+
+func (b *builder) get(ctx context.Context, p string) *httptest.ResponseRecorder {
+	req := httptest.NewRequestWithContext(ctx, http.MethodGet, p, nil)
+	req.Host = b.base.Host
+
+	res := httptest.NewRecorder()
+	b.handler.ServeHTTP(res, req)
+
+	return res
+}
+
+// discover queues every link in body that points at a static route on
+// this site.
+func (b *builder) discover(from string, pattern *regexp.Regexp, body string) {
+	current := &url.URL{Path: from}
+
+	for _, match := range pattern.FindAllStringSubmatch(body, -1) {
+		link, err := url.Parse(strings.TrimSpace(match[1]))
+		if err != nil {
+			continue
+		}
+		if link.Host != "" && link.Host != b.base.Host {
+			continue
+		}
+
+		target := current.ResolveReference(link).Path
+		if b.isStatic(target) {
+			b.enqueue(target)
+		}
+	}
+}
+
+// relativize rewrites root relative links to static routes so the site
+// works from any directory. Links to dynamic routes are left alone.
+func (b *builder) relativize(from string, body string) string {
+	dir := outputDir(from)
+
+	return htmlLinkPattern.ReplaceAllStringFunc(body, func(attr string) string {
+		match := htmlLinkPattern.FindStringSubmatch(attr)
+		link := match[1]
+		if !strings.HasPrefix(link, "/") || strings.HasPrefix(link, "//") {
+			return attr
+		}
+
+		target, suffix := link, ""
+		if i := strings.IndexAny(link, "?#"); i >= 0 {
+			target, suffix = link[:i], link[i:]
+		}
+		if !b.isStatic(target) {
+			return attr
+		}
+
+		rel, err := filepath.Rel(filepath.FromSlash("/"+dir), filepath.FromSlash(target))
+		if err != nil {
+			return attr
+		}
+		rel = filepath.ToSlash(rel)
+		if !hasExtension(target) {
+			rel = strings.TrimSuffix(rel, "/") + "/"
+		}
+		if rel == "./" || strings.HasPrefix(rel, "../") {
+			return strings.Replace(attr, `"`+link+`"`, `"`+rel+suffix+`"`, 1)
+		}
+
+		return strings.Replace(attr, `"`+link+`"`, `"./`+rel+suffix+`"`, 1)
+	})
+}
+
+func (b *builder) isStatic(p string) bool {
+	for _, route := range b.routes {
+		if matchPath(route.Path, p) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (b *builder) write(name string, body string) error {
+	target := filepath.Join(b.out, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(target, []byte(body), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", target, err)
+	}
+
+	b.written++
+	return nil
+}
+
+// matchPath reports whether p matches an echo route pattern, where :name
+// matches one segment and * matches the rest.
+func matchPath(pattern, p string) bool {
+	patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(p, "/"), "/")
+
+	for i, segment := range patternSegments {
+		if segment == "*" {
+			return true
+		}
+		if i >= len(pathSegments) {
+			return false
+		}
+		if strings.HasPrefix(segment, ":") {
+			if pathSegments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if segment != pathSegments[i] {
+			return false
+		}
+	}
+
+	return len(patternSegments) == len(pathSegments)
+}
+
+// outputFile maps a route path to the file it is written to. Paths that
+// look like files keep their name; pages become directory indexes.
+func outputFile(p string) string {
+	trimmed := strings.Trim(p, "/")
+	if hasExtension(p) {
+		return trimmed
+	}
+	if trimmed == "" {
+		return "index.html"
+	}
+
+	return trimmed + "/index.html"
+}
+
+func outputDir(p string) string {
+	return path.Dir("/" + outputFile(p))[1:]
+}
+
+func hasExtension(p string) bool {
+	return path.Ext(path.Base(p)) != ""
+}
+
+func embeddedAssetNames() ([]string, error) {
+	var names []string
+	err := fs.WalkDir(assets.Files, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && path.Ext(p) != ".go" {
+			names = append(names, path.Base(p))
+		}
+		return nil
+	})
+
+	return names, err
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"net/url"
+	"slices"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+)
+
+func TestMatchPath(t *testing.T) {
+	tests := map[string]struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		"root":                       {pattern: "/", path: "/", want: true},
+		"static segment":             {pattern: "/about", path: "/about", want: true},
+		"trailing slash":             {pattern: "/about", path: "/about/", want: true},
+		"different segment":          {pattern: "/about", path: "/contact"},
+		"parameter":                  {pattern: "/posts/:slug", path: "/posts/hello", want: true},
+		"empty parameter":            {pattern: "/posts/:slug", path: "/posts/"},
+		"too many segments":          {pattern: "/posts/:slug", path: "/posts/hello/world"},
+		"too few segments":           {pattern: "/posts/:slug/edit", path: "/posts/hello"},
+		"wildcard matches the rest":  {pattern: "/assets/*", path: "/assets/css/style.css", want: true},
+		"wildcard needs its prefix":  {pattern: "/assets/*", path: "/static/style.css"},
+		"nested parameters":          {pattern: "/tags/:slug/page/:n", path: "/tags/go/page/2", want: true},
+		"nested parameters mismatch": {pattern: "/tags/:slug/page/:n", path: "/tags/go/pages/2"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := matchPath(tt.pattern, tt.path); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestOutputFile(t *testing.T) {
+	tests := map[string]struct {
+		path string
+		want string
+	}{
+		"root":             {path: "/", want: "index.html"},
+		"page":             {path: "/about", want: "about/index.html"},
+		"page with slash":  {path: "/about/", want: "about/index.html"},
+		"nested page":      {path: "/posts/hello", want: "posts/hello/index.html"},
+		"file":             {path: "/sitemap.xml", want: "sitemap.xml"},
+		"nested file":      {path: "/assets/css/style.css", want: "assets/css/style.css"},
+		"dot in directory": {path: "/v1.2/notes", want: "v1.2/notes/index.html"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := outputFile(tt.path); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func newTestBuilder() *builder {
+	routes := []echo.RouteInfo{
+		{Path: "/"},
+		{Path: "/about"},
+		{Path: "/posts/:slug"},
+		{Path: "/assets/css/:file"},
+	}
+	base := &url.URL{Scheme: "https", Host: "example.com"}
+
+	return newBuilder(nil, routes, base, "")
+}
+
+func TestRelativize(t *testing.T) {
+	tests := map[string]struct {
+		from string
+		body string
+		want string
+	}{
+		"page from root": {
+			from: "/",
+			body: `<a href="/about">`,
+			want: `<a href="./about/">`,
+		},
+		"root from page": {
+			from: "/about",
+			body: `<a href="/">`,
+			want: `<a href="../">`,
+		},
+		"same page": {
+			from: "/about",
+			body: `<a href="/about">`,
+			want: `<a href="./">`,
+		},
+		"sibling page": {
+			from: "/posts/hello",
+			body: `<a href="/posts/world">`,
+			want: `<a href="../world/">`,
+		},
+		"file": {
+			from: "/posts/hello",
+			body: `<link href="/assets/css/style.css">`,
+			want: `<link href="../../assets/css/style.css">`,
+		},
+		"query string is kept": {
+			from: "/about",
+			body: `<a href="/posts/hello?ref=about">`,
+			want: `<a href="../posts/hello/?ref=about">`,
+		},
+		"fragment is kept": {
+			from: "/",
+			body: `<a href="/posts/hello#comments">`,
+			want: `<a href="./posts/hello/#comments">`,
+		},
+		"dynamic route": {
+			from: "/",
+			body: `<form action="/login">`,
+			want: `<form action="/login">`,
+		},
+		"protocol relative": {
+			from: "/",
+			body: `<script src="//cdn.example.com/app.js">`,
+			want: `<script src="//cdn.example.com/app.js">`,
+		},
+		"absolute": {
+			from: "/",
+			body: `<a href="https://example.com/about">`,
+			want: `<a href="https://example.com/about">`,
+		},
+	}
+
+	b := newTestBuilder()
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := b.relativize(tt.from, tt.body); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	tests := map[string]struct {
+		from string
+		body string
+		want []string
+	}{
+		"root relative": {
+			from: "/",
+			body: `<a href="/posts/hello">`,
+			want: []string{"/posts/hello"},
+		},
+		"relative": {
+			from: "/posts/hello",
+			body: `<a href="world">`,
+			want: []string{"/posts/world"},
+		},
+		"query string is dropped": {
+			from: "/",
+			body: `<a href="/posts/hello?ref=home">`,
+			want: []string{"/posts/hello"},
+		},
+		"own host": {
+			from: "/",
+			body: `<a href="https://example.com/about">`,
+			want: []string{"/about"},
+		},
+		"other host": {
+			from: "/",
+			body: `<a href="https://other.example/about">`,
+		},
+		"dynamic route": {
+			from: "/",
+			body: `<form action="/login">`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			b := newTestBuilder()
+			b.discover(tt.from, htmlLinkPattern, tt.body)
+			if !slices.Equal(b.queue, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, b.queue)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package config
+
+import "github.com/caarlos0/env/v10"
+
+type activityPub struct {
+	// Username is the handle the site federates as, so followers find it at
+	// @Username@Domain.
+	Username string `env:"ACTIVITYPUB_USERNAME" envDefault:"blog"`
+	// ObjectType is how articles federate: "Article" sends the full post,
+	// "Note" sends the title, excerpt and a link, which most microblogging
+	// servers display better.
+	ObjectType string `env:"ACTIVITYPUB_OBJECT_TYPE" envDefault:"Article"`
+}
+
+func newActivityPubConfig() activityPub {
+	activityPubCfg := activityPub{}
+
+	if err := env.ParseWithOptions(&activityPubCfg, env.Options{
+		RequiredIfNoDef: true,
+	}); err != nil {
+		panic(err)
+	}
+
+	return activityPubCfg
+}
@@ -0,0 +1,19 @@
+package config
+
+import "github.com/caarlos0/env/v10"
+
+type audit struct {
+	LogRetentionDays int `env:"AUDIT_LOG_RETENTION_DAYS" envDefault:"365"`
+}
+
+func newAuditConfig() audit {
+	auditCfg := audit{}
+
+	if err := env.ParseWithOptions(&auditCfg, env.Options{
+		RequiredIfNoDef: true,
+	}); err != nil {
+		panic(err)
+	}
+
+	return auditCfg
+}
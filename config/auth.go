@@ -3,7 +3,8 @@ package config
 import "github.com/caarlos0/env/v10"
 
 type auth struct {
-	Pepper         string `env:"PEPPER"`
+	Pepper           string `env:"PEPPER"`
+	RegistrationOpen bool   `env:"REGISTRATION_OPEN" envDefault:"true"`
 }
 
 func newAuthConfig() auth {
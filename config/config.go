@@ -41,38 +41,50 @@ var (
 
 		return "localhost:8080"
 	}()
-	BaseURL = func() string {
-		var protocol string
-
-		if os.Getenv("PROTOCOL") != "" {
-			protocol = os.Getenv("PROTOCOL")
-		} else {
-			protocol = "http"
-		}
-
-		return fmt.Sprintf("%s://%s", protocol, Domain)
-	}()
+	BaseURL              = ResolveBaseURL()
 	AppCookieSessionName = func() string {
 		return "app_sess_" + slug.Make(strings.ToLower(ProjectName)) + "-" + Env
 	}()
 )
 
+// ResolveBaseURL builds the public URL from PROTOCOL and DOMAIN. BaseURL is
+// resolved when the package loads, so commands that load .env afterwards
+// call this to pick up values that only live there.
+func ResolveBaseURL() string {
+	protocol := os.Getenv("PROTOCOL")
+	if protocol == "" {
+		protocol = "http"
+	}
+	domain := os.Getenv("DOMAIN")
+	if domain == "" {
+		domain = "localhost:8080"
+	}
+
+	return fmt.Sprintf("%s://%s", protocol, domain)
+}
+
 type Config struct {
-	App       app
-	DB        database
-	Telemetry telemetry
-	Email     email
-	AwsSes    awsSes
-	Auth      auth
+	App         app
+	DB          database
+	Telemetry   telemetry
+	Email       email
+	AwsSes      awsSes
+	Auth        auth
+	Audit       audit
+	ActivityPub activityPub
+	Media       media
 }
 
 func NewConfig() Config {
 	return Config{
-		App:       newAppConfig(),
-		DB:        newDatabaseConfig(),
-		Telemetry: newTelemetryConfig(),
-		Email:     newEmailConfig(),
-		Auth:      newAuthConfig(),
-		AwsSes:    newAwsSesConfig(),
+		App:         newAppConfig(),
+		DB:          newDatabaseConfig(),
+		Telemetry:   newTelemetryConfig(),
+		Email:       newEmailConfig(),
+		Auth:        newAuthConfig(),
+		AwsSes:      newAwsSesConfig(),
+		Audit:       newAuditConfig(),
+		ActivityPub: newActivityPubConfig(),
+		Media:       newMediaConfig(),
 	}
 }
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"github.com/caarlos0/env/v10"
+)
+
+type media struct {
+	// Storage selects where uploads are kept: "local" or "s3".
+	Storage        string `env:"MEDIA_STORAGE" envDefault:"local"`
+	LocalDir       string `env:"MEDIA_LOCAL_DIR" envDefault:"media"`
+	MaxUploadBytes int64  `env:"MEDIA_MAX_UPLOAD_BYTES" envDefault:"20971520"`
+	// S3Endpoint is only needed for S3 compatible services other than AWS.
+	S3Endpoint        string `env:"MEDIA_S3_ENDPOINT" envDefault:""`
+	S3Region          string `env:"MEDIA_S3_REGION" envDefault:"eu-central-1"`
+	S3Bucket          string `env:"MEDIA_S3_BUCKET" envDefault:""`
+	S3AccessKeyID     string `env:"MEDIA_S3_ACCESS_KEY_ID" envDefault:""`
+	S3SecretAccessKey string `env:"MEDIA_S3_SECRET_ACCESS_KEY" envDefault:""`
+	S3UsePathStyle    bool   `env:"MEDIA_S3_USE_PATH_STYLE" envDefault:"false"`
+}
+
+func newMediaConfig() media {
+	cfg := media{}
+
+	if err := env.ParseWithOptions(&cfg, env.Options{
+		RequiredIfNoDef: true,
+	}); err != nil {
+		panic(err)
+	}
+
+	return cfg
+}
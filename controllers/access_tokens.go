@@ -0,0 +1,134 @@
+package controllers
+
+import (
+	"fmt"
+	"log/slog"
+	"mortenvistisen/config"
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models"
+	"mortenvistisen/router/cookies"
+	"mortenvistisen/router/routes"
+	"mortenvistisen/views"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+)
+
+type AccessTokens struct {
+	db  storage.Pool
+	cfg config.Config
+}
+
+func NewAccessTokens(db storage.Pool, cfg config.Config) AccessTokens {
+	return AccessTokens{db, cfg}
+}
+
+func (at AccessTokens) Index(etx *echo.Context) error {
+	return at.renderIndex(etx, "")
+}
+
+type CreateAccessTokenFormPayload struct {
+	Name            string          `json:"name"`
+	ExpiresInDays   string          `json:"expiresInDays"`
+	ScopeSelections map[string]bool `json:"scopeSelections"`
+}
+
+func (at AccessTokens) Create(etx *echo.Context) error {
+	var payload CreateAccessTokenFormPayload
+	if err := etx.Bind(&payload); err != nil {
+		slog.ErrorContext(
+			etx.Request().Context(),
+			"could not parse CreateAccessTokenFormPayload",
+			"error",
+			err,
+		)
+
+		return render(etx, views.BadRequest())
+	}
+
+	scopes := make([]models.APIScope, 0, len(payload.ScopeSelections))
+	for _, scope := range models.APIScopes {
+		if payload.ScopeSelections[views.APIScopeSignal(scope)] {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	var expiresAt time.Time
+	if days, err := strconv.Atoi(payload.ExpiresInDays); err == nil && days > 0 {
+		expiresAt = time.Now().AddDate(0, 0, days)
+	}
+
+	token, plain, err := models.CreatePersonalAccessToken(
+		etx.Request().Context(),
+		at.db.Conn(),
+		at.cfg.Auth.Pepper,
+		models.CreatePersonalAccessTokenData{
+			UserID:    cookies.GetApp(etx).UserID,
+			Name:      payload.Name,
+			Scopes:    scopes,
+			ExpiresAt: expiresAt,
+		},
+	)
+	if err != nil {
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to create token: %v", err)); flashErr != nil {
+			return render(etx, views.InternalError())
+		}
+		return etx.Redirect(http.StatusSeeOther, routes.AccessTokenIndex.URL())
+	}
+
+	recordAudit(etx, at.db.Conn(), "access_token.create", "access_token", token.ID.String(), nil, token)
+
+	// The plaintext token is never stored, so it is shown on this response
+	// only instead of redirecting.
+	return at.renderIndex(etx, plain)
+}
+
+func (at AccessTokens) Destroy(etx *echo.Context) error {
+	tokenID, err := uuid.Parse(etx.Param("id"))
+	if err != nil {
+		return render(etx, views.BadRequest())
+	}
+
+	err = models.DestroyPersonalAccessToken(
+		etx.Request().Context(),
+		at.db.Conn(),
+		tokenID,
+		cookies.GetApp(etx).UserID,
+	)
+	if err != nil {
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to revoke token: %v", err)); flashErr != nil {
+			return render(etx, views.InternalError())
+		}
+		return etx.Redirect(http.StatusSeeOther, routes.AccessTokenIndex.URL())
+	}
+
+	recordAudit(etx, at.db.Conn(), "access_token.destroy", "access_token", tokenID.String(), nil, nil)
+
+	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, "Token revoked successfully"); flashErr != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return etx.Redirect(http.StatusSeeOther, routes.AccessTokenIndex.URL())
+}
+
+func (at AccessTokens) renderIndex(etx *echo.Context, plain string) error {
+	tokens, err := models.AllPersonalAccessTokensForUser(
+		etx.Request().Context(),
+		at.db.Conn(),
+		cookies.GetApp(etx).UserID,
+	)
+	if err != nil {
+		slog.ErrorContext(
+			etx.Request().Context(),
+			"could not list personal access tokens",
+			"error",
+			err,
+		)
+		return render(etx, views.InternalError())
+	}
+
+	return render(etx, views.AccessTokenIndex(tokens, plain))
+}
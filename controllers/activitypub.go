@@ -0,0 +1,276 @@
+package controllers
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mortenvistisen/internal/activitypub"
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models"
+	"mortenvistisen/queue"
+	"mortenvistisen/services"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+)
+
+const (
+	// activityPubOutboxSize is how many articles the outbox lists.
+	activityPubOutboxSize = 20
+	maxActivitySize       = 1 << 20
+)
+
+type ActivityPub struct {
+	db         storage.Pool
+	insertOnly queue.InsertOnly
+	client     *http.Client
+	site       services.ActivityPubSite
+}
+
+func NewActivityPub(
+	db storage.Pool,
+	insertOnly queue.InsertOnly,
+	client *http.Client,
+	site services.ActivityPubSite,
+) ActivityPub {
+	return ActivityPub{db, insertOnly, client, site}
+}
+
+type webFingerLink struct {
+	Rel  string `json:"rel"`
+	Type string `json:"type,omitempty"`
+	Href string `json:"href"`
+}
+
+type webFingerResponse struct {
+	Subject string          `json:"subject"`
+	Aliases []string        `json:"aliases"`
+	Links   []webFingerLink `json:"links"`
+}
+
+// WebFinger resolves @username@domain, or the actor URL itself, to the site
+// actor.
+func (a ActivityPub) WebFinger(etx *echo.Context) error {
+	resource := strings.TrimSpace(etx.QueryParam("resource"))
+	if resource == "" {
+		return etx.String(http.StatusBadRequest, "resource is required")
+	}
+
+	if !strings.EqualFold(strings.TrimPrefix(resource, "acct:"), a.site.Handle()) &&
+		resource != a.site.ActorURL() {
+		return etx.String(http.StatusNotFound, "unknown resource")
+	}
+
+	body, err := json.Marshal(webFingerResponse{
+		Subject: "acct:" + a.site.Handle(),
+		Aliases: []string{a.site.ActorURL()},
+		Links: []webFingerLink{
+			{Rel: "self", Type: activitypub.ContentType, Href: a.site.ActorURL()},
+			{Rel: "http://webfinger.net/rel/profile-page", Type: "text/html", Href: a.site.ProfileURL()},
+		},
+	})
+	if err != nil {
+		return etx.String(http.StatusInternalServerError, "could not render webfinger")
+	}
+
+	etx.Response().Header().Set("Access-Control-Allow-Origin", "*")
+
+	return etx.Blob(http.StatusOK, "application/jrd+json", body)
+}
+
+func (a ActivityPub) Actor(etx *echo.Context) error {
+	key, err := models.FindOrCreateActivityPubKey(etx.Request().Context(), a.db.Conn(), models.ActivityPubSiteKey)
+	if err != nil {
+		slog.ErrorContext(etx.Request().Context(), "could not load activitypub key", "error", err)
+		return etx.String(http.StatusInternalServerError, "could not load actor")
+	}
+
+	return activityJSON(etx, a.site.Actor(key))
+}
+
+// Outbox lists the latest published articles as Create activities.
+func (a ActivityPub) Outbox(etx *echo.Context) error {
+	articles, err := models.AllPublishedArticles(etx.Request().Context(), a.db.Conn())
+	if err != nil {
+		slog.ErrorContext(etx.Request().Context(), "could not list articles for outbox", "error", err)
+		return etx.String(http.StatusInternalServerError, "could not load outbox")
+	}
+
+	items := make([]any, 0, activityPubOutboxSize)
+	for _, article := range articles[:min(len(articles), activityPubOutboxSize)] {
+		items = append(items, a.site.CreateActivity(article))
+	}
+
+	return activityJSON(etx, activitypub.OrderedCollection{
+		Context:      activitypub.ActivityStreamsContext,
+		ID:           a.site.OutboxURL(),
+		Type:         "OrderedCollection",
+		TotalItems:   len(articles),
+		OrderedItems: items,
+	})
+}
+
+// Followers only reveals how many followers there are, not who they are.
+func (a ActivityPub) Followers(etx *echo.Context) error {
+	count, err := models.CountActivityPubFollowers(etx.Request().Context(), a.db.Conn())
+	if err != nil {
+		return etx.String(http.StatusInternalServerError, "could not load followers")
+	}
+
+	return activityJSON(etx, activitypub.OrderedCollection{
+		Context:    activitypub.ActivityStreamsContext,
+		ID:         a.site.FollowersURL(),
+		Type:       "OrderedCollection",
+		TotalItems: int(count),
+	})
+}
+
+func (a ActivityPub) Article(etx *echo.Context) error {
+	article, err := models.FindArticleBySlug(etx.Request().Context(), a.db.Conn(), etx.Param("slug"))
+	if err != nil || !article.Published {
+		return etx.String(http.StatusNotFound, "article not found")
+	}
+
+	object := a.site.ArticleObject(article)
+	object.Context = activitypub.ActivityStreamsContext
+
+	return activityJSON(etx, object)
+}
+
+// Inbox handles Follow and Undo Follow from remote actors. Every activity
+// must carry an HTTP signature from a key owned by its actor; anything else
+// is accepted and ignored.
+func (a ActivityPub) Inbox(etx *echo.Context) error {
+	ctx := etx.Request().Context()
+
+	body, err := io.ReadAll(io.LimitReader(etx.Request().Body, maxActivitySize+1))
+	if err != nil {
+		return etx.String(http.StatusBadRequest, "could not read activity")
+	}
+	if len(body) > maxActivitySize {
+		return etx.String(http.StatusRequestEntityTooLarge, "activity is too large")
+	}
+
+	var activity activitypub.Activity
+	if err := json.Unmarshal(body, &activity); err != nil || activity.Actor == "" || activity.Type == "" {
+		return etx.String(http.StatusBadRequest, "invalid activity")
+	}
+
+	key, err := models.FindOrCreateActivityPubKey(ctx, a.db.Conn(), models.ActivityPubSiteKey)
+	if err != nil {
+		slog.ErrorContext(ctx, "could not load activitypub key", "error", err)
+		return etx.String(http.StatusInternalServerError, "could not process activity")
+	}
+
+	var sender activitypub.Actor
+	_, err = activitypub.Verify(ctx, etx.Request(), body, a.fetchKey(key, &sender))
+	if err != nil {
+		// Deleted accounts announce themselves after their key is gone, so
+		// the key cannot be fetched. There is nothing to do with them anyway.
+		if activity.Type == "Delete" &&
+			!errors.Is(err, activitypub.ErrInvalidSignature) &&
+			!errors.Is(err, activitypub.ErrMissingSignature) &&
+			!errors.Is(err, activitypub.ErrActorMismatch) {
+			return etx.NoContent(http.StatusAccepted)
+		}
+		slog.InfoContext(ctx, "rejecting unsigned activity", "actor", activity.Actor, "type", activity.Type, "error", err)
+		return etx.String(http.StatusUnauthorized, "invalid signature")
+	}
+	if sender.ID != activity.Actor {
+		return etx.String(http.StatusUnauthorized, "signature does not belong to actor")
+	}
+
+	switch activity.Type {
+	case "Follow":
+		if activity.ObjectID() != a.site.ActorURL() {
+			return etx.NoContent(http.StatusAccepted)
+		}
+		if err := a.follow(ctx, activity, sender); err != nil {
+			slog.ErrorContext(ctx, "could not accept follow", "actor", sender.ID, "error", err)
+			return etx.String(http.StatusInternalServerError, "could not process activity")
+		}
+	case "Undo":
+		if activity.ObjectType() != "Follow" {
+			return etx.NoContent(http.StatusAccepted)
+		}
+		if _, err := models.DestroyActivityPubFollower(ctx, a.db.Conn(), sender.ID); err != nil {
+			slog.ErrorContext(ctx, "could not remove follower", "actor", sender.ID, "error", err)
+			return etx.String(http.StatusInternalServerError, "could not process activity")
+		}
+	}
+
+	return etx.NoContent(http.StatusAccepted)
+}
+
+// follow stores the follower and queues the Accept in one transaction so a
+// follower is never stored without being told.
+func (a ActivityPub) follow(
+	ctx context.Context,
+	activity activitypub.Activity,
+	sender activitypub.Actor,
+) error {
+	tx, err := a.db.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	sharedInbox := ""
+	if sender.Endpoints != nil {
+		sharedInbox = sender.Endpoints.SharedInbox
+	}
+
+	if _, err := models.CreateActivityPubFollower(ctx, tx, models.CreateActivityPubFollowerData{
+		ActorID:          sender.ID,
+		Inbox:            sender.Inbox,
+		SharedInbox:      sharedInbox,
+		FollowActivityID: activity.ID,
+	}); err != nil {
+		return err
+	}
+
+	if err := services.EnqueueActivityPubAccept(ctx, tx, a.insertOnly, a.site, activity, sender.Inbox); err != nil {
+		return err
+	}
+
+	return a.db.CommitTx(ctx, tx)
+}
+
+// fetchKey resolves a signature's keyId by fetching the actor document it
+// belongs to, which is stored in sender for the caller.
+func (a ActivityPub) fetchKey(
+	key models.ActivityPubKey,
+	sender *activitypub.Actor,
+) activitypub.KeyFetcher {
+	signer := &activitypub.Signer{KeyID: a.site.KeyID(), Key: key.PrivateKey}
+
+	return func(ctx context.Context, keyID string) (*rsa.PublicKey, error) {
+		actorURL, _, _ := strings.Cut(keyID, "#")
+
+		actor, err := activitypub.FetchActor(ctx, a.client, signer, actorURL)
+		if err != nil {
+			return nil, err
+		}
+		if actor.PublicKey.ID != keyID {
+			return nil, fmt.Errorf("actor %s does not own key %s", actor.ID, keyID)
+		}
+
+		*sender = actor
+
+		return activitypub.ParsePublicKey(actor.PublicKey.PublicKeyPem)
+	}
+}
+
+func activityJSON(etx *echo.Context, document any) error {
+	body, err := json.Marshal(document)
+	if err != nil {
+		return etx.String(http.StatusInternalServerError, "could not render document")
+	}
+
+	return etx.Blob(http.StatusOK, activitypub.ContentType, body)
+}
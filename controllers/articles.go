@@ -19,8 +19,10 @@ import (
 	"mortenvistisen/queue/jobs"
 	"mortenvistisen/router/cookies"
 	"mortenvistisen/router/routes"
+	"mortenvistisen/services"
 	"mortenvistisen/views"
 
+	"github.com/a-h/templ"
 	"github.com/jackc/pgx/v5"
 	"github.com/labstack/echo/v5"
 	"github.com/riverqueue/river"
@@ -29,13 +31,20 @@ import (
 type Articles struct {
 	db         storage.Pool
 	insertOnly queue.InsertOnly
+	cache      *Cache[templ.Component]
 	cfg        config.Config
 }
 
-func NewArticles(db storage.Pool, insertOnly queue.InsertOnly, cfg config.Config) Articles {
+func NewArticles(
+	db storage.Pool,
+	insertOnly queue.InsertOnly,
+	cache *Cache[templ.Component],
+	cfg config.Config,
+) Articles {
 	return Articles{
 		db:         db,
 		insertOnly: insertOnly,
+		cache:      cache,
 		cfg:        cfg,
 	}
 }
@@ -90,7 +99,12 @@ func (a Articles) New(etx *echo.Context) error {
 		return render(etx, views.InternalError())
 	}
 
-	return render(etx, views.ArticleNew(tags, nil))
+	media, err := models.RecentMedia(etx.Request().Context(), a.db.Conn(), mediaPickerShown)
+	if err != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return render(etx, views.ArticleNew(tags, media, nil))
 }
 
 type CreateArticleFormPayload struct {
@@ -100,7 +114,7 @@ type CreateArticleFormPayload struct {
 	MetaTitle       string          `json:"metaTitle"       validate:"omitempty,max=100"`
 	MetaDescription string          `json:"metaDescription" validate:"omitempty,max=160"`
 	ImageLink       string          `json:"imageLink"       validate:"omitempty,url"`
-	ReadTime        int32           `json:"readTime"        validate:"gt=0"`
+	ReadTime        int32           `json:"readTime"        validate:"gte=0"`
 	Content         string          `json:"content"`
 	TagSelections   map[string]bool `json:"tagSelections"`
 }
@@ -120,8 +134,10 @@ func (a Articles) Create(etx *echo.Context) error {
 		return render(etx, views.NotFound())
 	}
 
+	app := cookies.GetApp(etx)
+
 	data := models.CreateArticleData{
-		Published:       payload.Published,
+		Published:       payload.Published && app.Can(models.PermissionPublishArticles),
 		Title:           payload.Title,
 		Excerpt:         payload.Excerpt,
 		MetaTitle:       payload.MetaTitle,
@@ -129,6 +145,7 @@ func (a Articles) Create(etx *echo.Context) error {
 		ImageLink:       payload.ImageLink,
 		ReadTime:        payload.ReadTime,
 		Content:         payload.Content,
+		AuthorID:        app.UserID,
 	}
 
 	tx, err := a.db.BeginTx(ctx)
@@ -152,6 +169,14 @@ func (a Articles) Create(etx *echo.Context) error {
 		return etx.Redirect(http.StatusSeeOther, routes.ArticleNew.URL())
 	}
 
+	article, err = services.RenderArticleContent(ctx, tx, article)
+	if err != nil {
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to render article: %v", err)); flashErr != nil {
+			return flashErr
+		}
+		return etx.Redirect(http.StatusSeeOther, routes.ArticleNew.URL())
+	}
+
 	tagIDs := make([]int32, 0, len(payload.TagSelections))
 	for rawID, selected := range payload.TagSelections {
 		if !selected {
@@ -175,9 +200,18 @@ func (a Articles) Create(etx *echo.Context) error {
 	scheduledJobs := 0
 	becameFirstPublished := article.Published && !article.FirstPublishedAt.IsZero()
 	if becameFirstPublished {
-		scheduledJobs, err = a.scheduleArticleReleaseEmails(ctx, tx, article)
+		scheduledJobs, err = a.releaseArticle(ctx, tx, article)
 		if err != nil {
-			if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to schedule article release emails: %v", err)); flashErr != nil {
+			if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to schedule article release: %v", err)); flashErr != nil {
+				return render(etx, views.InternalError())
+			}
+			return etx.Redirect(http.StatusSeeOther, routes.ArticleNew.URL())
+		}
+	}
+
+	if article.Published {
+		if err := services.EnqueueRelatedArticlesRefresh(ctx, tx, a.insertOnly); err != nil {
+			if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to schedule related articles refresh: %v", err)); flashErr != nil {
 				return render(etx, views.InternalError())
 			}
 			return etx.Redirect(http.StatusSeeOther, routes.ArticleNew.URL())
@@ -191,6 +225,10 @@ func (a Articles) Create(etx *echo.Context) error {
 		return etx.Redirect(http.StatusSeeOther, routes.ArticleNew.URL())
 	}
 
+	invalidateTagPages(a.cache)
+
+	recordAudit(etx, a.db.Conn(), "article.create", "article", strconv.Itoa(int(article.ID)), nil, article)
+
 	successMessage := "Article created successfully"
 	if scheduledJobs > 0 {
 		successMessage = fmt.Sprintf(
@@ -218,6 +256,11 @@ func (a Articles) Edit(etx *echo.Context) error {
 		return render(etx, views.NotFound())
 	}
 
+	app := cookies.GetApp(etx)
+	if !models.CanManageArticle(app.Role, app.UserID, article) {
+		return forbidden(etx, routes.ArticleShow.URL(articleID))
+	}
+
 	tags, err := models.AllTags(etx.Request().Context(), a.db.Conn())
 	if err != nil {
 		return render(etx, views.InternalError())
@@ -237,7 +280,12 @@ func (a Articles) Edit(etx *echo.Context) error {
 		selectedTagIDs[tagID] = true
 	}
 
-	return render(etx, views.ArticleUpdate(article, tags, selectedTagIDs))
+	media, err := models.RecentMedia(etx.Request().Context(), a.db.Conn(), mediaPickerShown)
+	if err != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return render(etx, views.ArticleUpdate(article, tags, selectedTagIDs, media))
 }
 
 type UpdateArticleFormPayload struct {
@@ -310,6 +358,14 @@ func (a Articles) Update(etx *echo.Context) error {
 		)
 	}
 
+	app := cookies.GetApp(etx)
+	if !models.CanManageArticle(app.Role, app.UserID, currentArticle) {
+		return forbidden(etx, routes.ArticleShow.URL(articleID))
+	}
+	if !app.Can(models.PermissionPublishArticles) {
+		data.Published = currentArticle.Published
+	}
+
 	article, err := models.UpdateArticle(
 		ctx,
 		tx,
@@ -325,6 +381,17 @@ func (a Articles) Update(etx *echo.Context) error {
 		)
 	}
 
+	article, err = services.EnsureArticleContent(ctx, tx, article)
+	if err != nil {
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to render article: %v", err)); flashErr != nil {
+			return render(etx, views.InternalError())
+		}
+		return etx.Redirect(
+			http.StatusSeeOther,
+			routes.ArticleEdit.URL(articleID),
+		)
+	}
+
 	tagIDs := make([]int32, 0, len(payload.TagSelections))
 	for rawID, selected := range payload.TagSelections {
 		if !selected {
@@ -347,9 +414,21 @@ func (a Articles) Update(etx *echo.Context) error {
 	becameFirstPublished := currentArticle.FirstPublishedAt.IsZero() &&
 		!article.FirstPublishedAt.IsZero()
 	if becameFirstPublished {
-		scheduledJobs, err = a.scheduleArticleReleaseEmails(ctx, tx, article)
+		scheduledJobs, err = a.releaseArticle(ctx, tx, article)
 		if err != nil {
-			if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to schedule article release emails: %v", err)); flashErr != nil {
+			if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to schedule article release: %v", err)); flashErr != nil {
+				return render(etx, views.InternalError())
+			}
+			return etx.Redirect(
+				http.StatusSeeOther,
+				routes.ArticleEdit.URL(articleID),
+			)
+		}
+	}
+
+	if article.Published || currentArticle.Published {
+		if err := services.EnqueueRelatedArticlesRefresh(ctx, tx, a.insertOnly); err != nil {
+			if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to schedule related articles refresh: %v", err)); flashErr != nil {
 				return render(etx, views.InternalError())
 			}
 			return etx.Redirect(
@@ -369,6 +448,10 @@ func (a Articles) Update(etx *echo.Context) error {
 		)
 	}
 
+	invalidateTagPages(a.cache)
+
+	recordAudit(etx, a.db.Conn(), "article.update", "article", strconv.Itoa(int(article.ID)), currentArticle, article)
+
 	successMessage := "Article updated successfully"
 	if scheduledJobs > 0 {
 		successMessage = fmt.Sprintf(
@@ -390,6 +473,16 @@ func (a Articles) Destroy(etx *echo.Context) error {
 	}
 	articleID := int32(parsed)
 
+	article, err := models.FindArticle(etx.Request().Context(), a.db.Conn(), articleID)
+	if err != nil {
+		return render(etx, views.NotFound())
+	}
+
+	app := cookies.GetApp(etx)
+	if !models.CanManageArticle(app.Role, app.UserID, article) {
+		return forbidden(etx, routes.ArticleIndex.URL())
+	}
+
 	err = models.DestroyArticle(etx.Request().Context(), a.db.Conn(), articleID)
 	if err != nil {
 		if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to delete article: %v", err)); flashErr != nil {
@@ -398,6 +491,10 @@ func (a Articles) Destroy(etx *echo.Context) error {
 		return etx.Redirect(http.StatusSeeOther, routes.ArticleIndex.URL())
 	}
 
+	invalidateTagPages(a.cache)
+
+	recordAudit(etx, a.db.Conn(), "article.destroy", "article", strconv.Itoa(int(articleID)), article, nil)
+
 	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, "Article destroyed successfully"); flashErr != nil {
 		return render(etx, views.InternalError())
 	}
@@ -405,6 +502,112 @@ func (a Articles) Destroy(etx *echo.Context) error {
 	return etx.Redirect(http.StatusSeeOther, routes.ArticleIndex.URL())
 }
 
+// UpdateTableOfContents turns the article's table of contents and heading
+// permalinks on or off, going by the enabled query parameter.
+func (a Articles) UpdateTableOfContents(etx *echo.Context) error {
+	ctx := etx.Request().Context()
+
+	parsed, err := strconv.ParseInt(etx.Param("id"), 10, 32)
+	if err != nil {
+		return render(etx, views.BadRequest())
+	}
+	articleID := int32(parsed)
+
+	enabled, err := strconv.ParseBool(etx.QueryParam("enabled"))
+	if err != nil {
+		return render(etx, views.BadRequest())
+	}
+
+	currentArticle, err := models.FindArticle(ctx, a.db.Conn(), articleID)
+	if err != nil {
+		return render(etx, views.NotFound())
+	}
+
+	app := cookies.GetApp(etx)
+	if !models.CanManageArticle(app.Role, app.UserID, currentArticle) {
+		return forbidden(etx, routes.ArticleShow.URL(articleID))
+	}
+
+	article, err := models.SetArticleTableOfContents(ctx, a.db.Conn(), articleID, enabled)
+	if err != nil {
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to update table of contents: %v", err)); flashErr != nil {
+			return render(etx, views.InternalError())
+		}
+		return etx.Redirect(http.StatusSeeOther, routes.ArticleShow.URL(articleID))
+	}
+
+	recordAudit(etx, a.db.Conn(), "article.table_of_contents.update", "article", strconv.Itoa(int(article.ID)), currentArticle, article)
+
+	if _, err := services.EnsureArticleContent(ctx, a.db.Conn(), article); err != nil {
+		logRenderError(etx, "article", article.ID, err)
+	}
+	a.cache.Invalidate("pages:article:" + article.Slug)
+
+	successMessage := "Table of contents hidden"
+	if enabled {
+		successMessage = "Table of contents shown"
+	}
+	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, successMessage); flashErr != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return etx.Redirect(http.StatusSeeOther, routes.ArticleShow.URL(articleID))
+}
+
+// releaseArticle queues everything that goes out when an article is first
+// published: the subscriber emails, the article.published webhooks and
+// webmentions to the pages it links to. It returns the number of emails
+// scheduled.
+func (a Articles) releaseArticle(
+	ctx context.Context,
+	tx pgx.Tx,
+	article models.Article,
+) (int, error) {
+	scheduledJobs, err := a.scheduleArticleReleaseEmails(ctx, tx, article)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := services.EnqueueWebhooks(
+		ctx,
+		tx,
+		a.insertOnly,
+		models.WebhookEventArticlePublished,
+		services.ArticleWebhookData{
+			ID:          article.ID,
+			Title:       article.Title,
+			Slug:        article.Slug,
+			Excerpt:     article.Excerpt,
+			URL:         articlePublicURL(article),
+			PublishedAt: article.FirstPublishedAt,
+		},
+	); err != nil {
+		return 0, err
+	}
+
+	if _, err := services.EnqueueWebmentions(
+		ctx,
+		tx,
+		a.insertOnly,
+		articlePublicURL(article),
+		services.RenderedHTML(article.Rendered, article.Content),
+	); err != nil {
+		return 0, err
+	}
+
+	if _, err := services.EnqueueActivityPubCreate(
+		ctx,
+		tx,
+		a.insertOnly,
+		services.NewActivityPubSite(a.cfg.ActivityPub.Username, a.cfg.ActivityPub.ObjectType),
+		article,
+	); err != nil {
+		return 0, err
+	}
+
+	return scheduledJobs, nil
+}
+
 func (a Articles) scheduleArticleReleaseEmails(
 	ctx context.Context,
 	tx pgx.Tx,
@@ -172,6 +172,34 @@ func (a Assets) createSitemap(ctx context.Context) (string, error) {
 		})
 	}
 
+	// Published series
+	series, err := models.AllPublishedSeries(ctx, conn)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to fetch published series for sitemap", "error", err)
+	}
+	for _, s := range series {
+		urls = append(urls, URL{
+			Loc:        baseURL + routes.Series.URL(s.Slug),
+			ChangeFreq: "weekly",
+			LastMod:    s.UpdatedAt.Format("2006-01-02T15:04:05+00:00"),
+			Priority:   "0.8",
+		})
+	}
+
+	// Tags with published articles
+	tags, err := models.AllPublishedTags(ctx, conn)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to fetch published tags for sitemap", "error", err)
+	}
+	for _, tag := range tags {
+		urls = append(urls, URL{
+			Loc:        baseURL + routes.Tag.URL(tag.Slug),
+			ChangeFreq: "weekly",
+			LastMod:    tag.LastArticleUpdate.Format("2006-01-02T15:04:05+00:00"),
+			Priority:   "0.6",
+		})
+	}
+
 	// Published projects
 	projects, err := models.AllPublishedProjects(ctx, conn)
 	if err != nil {
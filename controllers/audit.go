@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"log/slog"
+
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models"
+	"mortenvistisen/router/cookies"
+	"mortenvistisen/router/middleware"
+	"mortenvistisen/services"
+
+	"github.com/labstack/echo/v5"
+)
+
+// recordAudit appends an entry for an admin action to the audit log. Before
+// and after are diffed field by field; pass nil for the side that doesn't
+// exist. Failures are logged rather than surfaced, so a broken audit insert
+// never blocks the action itself.
+func recordAudit(
+	etx *echo.Context,
+	exec storage.Executor,
+	action string,
+	targetType string,
+	targetID string,
+	before any,
+	after any,
+) {
+	ctx := etx.Request().Context()
+
+	// API requests carry no session; the token owner is the actor.
+	actorID := cookies.GetApp(etx).UserID
+	if apiUser, ok := middleware.GetAPIUser(etx); ok {
+		actorID = apiUser.ID
+	}
+
+	// The email is stored alongside the id so the entry stays readable after
+	// the actor's account is deleted.
+	var actorEmail string
+	if actor, err := models.FindUser(ctx, exec, actorID); err == nil {
+		actorEmail = actor.Email
+	}
+
+	_, err := models.CreateAuditLog(ctx, exec, models.CreateAuditLogData{
+		ActorID:    actorID,
+		ActorEmail: actorEmail,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Changes:    services.AuditDiff(before, after),
+		IPAddress:  etx.RealIP(),
+		UserAgent:  etx.Request().UserAgent(),
+		RequestID:  etx.Response().Header().Get(echo.HeaderXRequestID),
+	})
+	if err != nil {
+		slog.ErrorContext(
+			ctx,
+			"could not record audit log",
+			"action",
+			action,
+			"target_type",
+			targetType,
+			"target_id",
+			targetID,
+			"error",
+			err,
+		)
+	}
+}
@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"log/slog"
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models"
+	"mortenvistisen/views"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v5"
+)
+
+type AuditLogs struct {
+	db storage.Pool
+}
+
+func NewAuditLogs(db storage.Pool) AuditLogs {
+	return AuditLogs{db}
+}
+
+func (al AuditLogs) Index(etx *echo.Context) error {
+	page := int64(1)
+	if p := etx.QueryParam("page"); p != "" {
+		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
+			page = int64(parsed)
+		}
+	}
+
+	perPage := int64(50)
+	if pp := etx.QueryParam("per_page"); pp != "" {
+		if parsed, err := strconv.Atoi(pp); err == nil && parsed > 0 &&
+			parsed <= 100 {
+			perPage = int64(parsed)
+		}
+	}
+
+	filter := models.AuditLogFilter{
+		ActorEmail: strings.TrimSpace(etx.QueryParam("actor")),
+		Action:     strings.TrimSpace(etx.QueryParam("action")),
+		TargetType: strings.TrimSpace(etx.QueryParam("target_type")),
+		TargetID:   strings.TrimSpace(etx.QueryParam("target_id")),
+	}
+	if from, err := time.Parse("2006-01-02", etx.QueryParam("from")); err == nil {
+		filter.Since = from
+	}
+	if to, err := time.Parse("2006-01-02", etx.QueryParam("to")); err == nil {
+		filter.Until = to.AddDate(0, 0, 1)
+	}
+
+	ctx := etx.Request().Context()
+
+	logs, err := models.PaginateAuditLogs(ctx, al.db.Conn(), filter, page, perPage)
+	if err != nil {
+		slog.ErrorContext(ctx, "could not list audit logs", "error", err)
+		return render(etx, views.InternalError())
+	}
+
+	actions, err := models.AllAuditLogActions(ctx, al.db.Conn())
+	if err != nil {
+		slog.ErrorContext(ctx, "could not list audit log actions", "error", err)
+		return render(etx, views.InternalError())
+	}
+
+	return render(etx, views.AuditLogIndex(logs, actions, etx.QueryParams()))
+}
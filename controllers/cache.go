@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/maypok86/otter/v2"
@@ -83,3 +84,13 @@ func (c *Cache[T]) SetDefault(key string, value T) {
 func (c *Cache[T]) Invalidate(key string) {
 	c.cache.Invalidate(key)
 }
+
+// InvalidatePrefix drops every entry whose key starts with prefix, for pages
+// cached under one key per variant such as page number.
+func (c *Cache[T]) InvalidatePrefix(prefix string) {
+	for key := range c.cache.Keys() {
+		if strings.HasPrefix(key, prefix) {
+			c.cache.Invalidate(key)
+		}
+	}
+}
@@ -0,0 +1,401 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"mortenvistisen/config"
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models"
+	"mortenvistisen/queue"
+	"mortenvistisen/router/cookies"
+	"mortenvistisen/router/routes"
+	"mortenvistisen/services"
+	"mortenvistisen/views"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+)
+
+// commentsShown is how many comments the moderation page lists.
+const commentsShown = 200
+
+type Comments struct {
+	db         storage.Pool
+	insertOnly queue.InsertOnly
+	cache      *Cache[templ.Component]
+	cfg        config.Config
+}
+
+func NewComments(
+	db storage.Pool,
+	insertOnly queue.InsertOnly,
+	cache *Cache[templ.Component],
+	cfg config.Config,
+) Comments {
+	return Comments{db, insertOnly, cache, cfg}
+}
+
+type CommentFormPayload struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Content  string `json:"content"`
+	ParentID string `json:"parentId"`
+	// Website is the honeypot; the field is hidden from readers.
+	Website string `json:"website"`
+}
+
+// Create stores a comment and sends the commenter a verification code. The
+// comment is published only after the email is verified and a moderator
+// approves it.
+func (c Comments) Create(etx *echo.Context) error {
+	ctx := etx.Request().Context()
+
+	article, err := models.FindArticleBySlug(ctx, c.db.Conn(), etx.Param("slug"))
+	if err != nil || !article.Published {
+		return render(etx, views.NotFound())
+	}
+	commentsURL := routes.Article.URL(article.Slug) + "#comments"
+
+	var payload CommentFormPayload
+	if err := etx.Bind(&payload); err != nil {
+		return render(etx, views.BadRequest())
+	}
+
+	var parentID uuid.UUID
+	if payload.ParentID != "" {
+		parentID, err = uuid.Parse(payload.ParentID)
+		if err != nil {
+			return render(etx, views.BadRequest())
+		}
+	}
+
+	_, err = services.SubmitComment(
+		ctx,
+		c.db,
+		c.insertOnly,
+		c.cfg.Auth.Pepper,
+		services.SubmitCommentData{
+			ArticleID:   article.ID,
+			ParentID:    parentID,
+			AuthorName:  payload.Name,
+			AuthorEmail: payload.Email,
+			Content:     payload.Content,
+			Honeypot:    payload.Website,
+		},
+	)
+	if err != nil {
+		var errorMsg string
+		switch {
+		case errors.Is(err, services.ErrCommentsClosed):
+			errorMsg = "Comments are closed for this article"
+		case errors.Is(err, models.ErrCommentParentInvalid):
+			errorMsg = "The comment you replied to is no longer available"
+		case errors.Is(err, models.ErrDomainValidation):
+			errorMsg = "Please enter your name, a valid email and a comment"
+		default:
+			slog.ErrorContext(ctx, "failed to submit comment", "error", err)
+			errorMsg = "Could not submit your comment"
+		}
+
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, errorMsg); flashErr != nil {
+			return render(etx, views.InternalError())
+		}
+		return etx.Redirect(http.StatusSeeOther, commentsURL)
+	}
+
+	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, "Check your email for the verification code"); flashErr != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return etx.Redirect(http.StatusSeeOther, routes.CommentVerificationNew.URL())
+}
+
+func (c Comments) VerificationNew(etx *echo.Context) error {
+	return render(etx, views.CommentVerificationForm())
+}
+
+func (c Comments) VerificationCreate(etx *echo.Context) error {
+	ctx := etx.Request().Context()
+
+	var payload struct {
+		Code string `json:"code"`
+	}
+	if err := etx.Bind(&payload); err != nil {
+		return render(etx, views.BadRequest())
+	}
+
+	comment, err := services.VerifyComment(
+		ctx,
+		c.db,
+		c.cfg.Auth.Pepper,
+		services.VerifyCommentData{
+			Code: strings.ToUpper(strings.TrimSpace(payload.Code)),
+		},
+	)
+	if err != nil {
+		var errorMsg string
+		switch {
+		case errors.Is(err, services.ErrCommentVerificationInvalidCode):
+			errorMsg = "Invalid verification code"
+		case errors.Is(err, services.ErrCommentVerificationExpiredCode):
+			errorMsg = "Verification code has expired"
+		default:
+			slog.ErrorContext(ctx, "failed to verify comment", "error", err)
+			errorMsg = "Failed to verify your comment"
+		}
+
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, errorMsg); flashErr != nil {
+			return render(etx, views.InternalError())
+		}
+		return etx.Redirect(http.StatusSeeOther, routes.CommentVerificationNew.URL())
+	}
+
+	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, "Thanks! Your comment will appear once it has been approved"); flashErr != nil {
+		return render(etx, views.InternalError())
+	}
+
+	article, err := models.FindArticle(ctx, c.db.Conn(), comment.ArticleID)
+	if err != nil {
+		return etx.Redirect(http.StatusSeeOther, routes.HomePage.URL())
+	}
+
+	return etx.Redirect(http.StatusSeeOther, routes.Article.URL(article.Slug)+"#comments")
+}
+
+func (c Comments) Index(etx *echo.Context) error {
+	ctx := etx.Request().Context()
+
+	status := models.CommentStatus(etx.QueryParam("status"))
+	if status == "" {
+		status = models.CommentStatusPending
+	}
+	if status == "all" {
+		status = ""
+	} else if !status.Valid() {
+		return render(etx, views.BadRequest())
+	}
+
+	comments, err := models.RecentComments(ctx, c.db.Conn(), status, commentsShown)
+	if err != nil {
+		slog.ErrorContext(ctx, "could not list comments", "error", err)
+		return render(etx, views.InternalError())
+	}
+
+	articles, err := models.AllArticles(ctx, c.db.Conn())
+	if err != nil {
+		slog.ErrorContext(ctx, "could not list articles", "error", err)
+		return render(etx, views.InternalError())
+	}
+	articlesByID := make(map[int32]models.Article, len(articles))
+	for _, article := range articles {
+		articlesByID[article.ID] = article
+	}
+
+	return render(etx, views.CommentIndex(comments, articlesByID, status))
+}
+
+// Approve publishes a comment. The first approval tells the article's author
+// and the others in the thread about it.
+func (c Comments) Approve(etx *echo.Context) error {
+	ctx := etx.Request().Context()
+
+	commentID, err := uuid.Parse(etx.Param("id"))
+	if err != nil {
+		return render(etx, views.BadRequest())
+	}
+
+	tx, err := c.db.BeginTx(ctx)
+	if err != nil {
+		return render(etx, views.InternalError())
+	}
+	defer tx.Rollback(ctx)
+
+	currentComment, err := models.FindComment(ctx, tx, commentID)
+	if err != nil {
+		return render(etx, views.NotFound())
+	}
+	if currentComment.Status == models.CommentStatusUnverified {
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, "Comments can only be approved once the email is verified"); flashErr != nil {
+			return render(etx, views.InternalError())
+		}
+		return etx.Redirect(http.StatusSeeOther, routes.CommentIndex.URL())
+	}
+
+	comment, err := models.UpdateCommentStatus(ctx, tx, commentID, models.CommentStatusApproved)
+	if err != nil {
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to update comment: %v", err)); flashErr != nil {
+			return render(etx, views.InternalError())
+		}
+		return etx.Redirect(http.StatusSeeOther, routes.CommentIndex.URL())
+	}
+
+	article, err := models.FindArticle(ctx, tx, comment.ArticleID)
+	if err != nil {
+		return render(etx, views.InternalError())
+	}
+
+	notified := 0
+	if currentComment.Status != models.CommentStatusApproved {
+		approved, err := models.ApprovedCommentsForArticle(ctx, tx, article.ID)
+		if err != nil {
+			return render(etx, views.InternalError())
+		}
+
+		notified, err = services.EnqueueCommentNotifications(ctx, tx, c.insertOnly, article, comment, approved)
+		if err != nil {
+			if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to schedule comment notifications: %v", err)); flashErr != nil {
+				return render(etx, views.InternalError())
+			}
+			return etx.Redirect(http.StatusSeeOther, routes.CommentIndex.URL())
+		}
+	}
+
+	if err := c.db.CommitTx(ctx, tx); err != nil {
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, "Failed to update comment"); flashErr != nil {
+			return render(etx, views.InternalError())
+		}
+		return etx.Redirect(http.StatusSeeOther, routes.CommentIndex.URL())
+	}
+
+	c.cache.Invalidate("pages:article:" + article.Slug)
+	recordAudit(etx, c.db.Conn(), "comment.approve", "comment", comment.ID.String(), currentComment, comment)
+
+	successMessage := "Comment approved"
+	if notified > 0 {
+		successMessage = fmt.Sprintf("Comment approved and %d notification emails were scheduled", notified)
+	}
+	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, successMessage); flashErr != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return etx.Redirect(http.StatusSeeOther, routes.CommentIndex.URL())
+}
+
+func (c Comments) Reject(etx *echo.Context) error {
+	return c.moderate(etx, models.CommentStatusRejected, "comment.reject", "Comment rejected")
+}
+
+func (c Comments) MarkSpam(etx *echo.Context) error {
+	return c.moderate(etx, models.CommentStatusSpam, "comment.spam", "Comment marked as spam")
+}
+
+func (c Comments) Destroy(etx *echo.Context) error {
+	commentID, err := uuid.Parse(etx.Param("id"))
+	if err != nil {
+		return render(etx, views.BadRequest())
+	}
+
+	comment, err := models.FindComment(etx.Request().Context(), c.db.Conn(), commentID)
+	if err != nil {
+		return render(etx, views.NotFound())
+	}
+
+	if err := models.DestroyComment(etx.Request().Context(), c.db.Conn(), commentID); err != nil {
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to delete comment: %v", err)); flashErr != nil {
+			return render(etx, views.InternalError())
+		}
+		return etx.Redirect(http.StatusSeeOther, routes.CommentIndex.URL())
+	}
+
+	c.invalidateArticle(etx, comment.ArticleID)
+	recordAudit(etx, c.db.Conn(), "comment.destroy", "comment", commentID.String(), comment, nil)
+
+	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, "Comment destroyed successfully"); flashErr != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return etx.Redirect(http.StatusSeeOther, routes.CommentIndex.URL())
+}
+
+// UpdateArticle opens or closes an article for new comments, going by the
+// open query parameter.
+func (c Comments) UpdateArticle(etx *echo.Context) error {
+	ctx := etx.Request().Context()
+
+	parsed, err := strconv.ParseInt(etx.Param("id"), 10, 32)
+	if err != nil {
+		return render(etx, views.BadRequest())
+	}
+	articleID := int32(parsed)
+
+	open, err := strconv.ParseBool(etx.QueryParam("open"))
+	if err != nil {
+		return render(etx, views.BadRequest())
+	}
+
+	currentArticle, err := models.FindArticle(ctx, c.db.Conn(), articleID)
+	if err != nil {
+		return render(etx, views.NotFound())
+	}
+
+	article, err := models.SetArticleCommentsOpen(ctx, c.db.Conn(), articleID, open)
+	if err != nil {
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to update comments: %v", err)); flashErr != nil {
+			return render(etx, views.InternalError())
+		}
+		return etx.Redirect(http.StatusSeeOther, routes.ArticleShow.URL(articleID))
+	}
+
+	c.cache.Invalidate("pages:article:" + article.Slug)
+	recordAudit(etx, c.db.Conn(), "article.comments.update", "article", strconv.Itoa(int(article.ID)), currentArticle, article)
+
+	successMessage := "Comments closed"
+	if open {
+		successMessage = "Comments opened"
+	}
+	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, successMessage); flashErr != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return etx.Redirect(http.StatusSeeOther, routes.ArticleShow.URL(articleID))
+}
+
+func (c Comments) moderate(
+	etx *echo.Context,
+	status models.CommentStatus,
+	action string,
+	message string,
+) error {
+	commentID, err := uuid.Parse(etx.Param("id"))
+	if err != nil {
+		return render(etx, views.BadRequest())
+	}
+
+	currentComment, err := models.FindComment(etx.Request().Context(), c.db.Conn(), commentID)
+	if err != nil {
+		return render(etx, views.NotFound())
+	}
+
+	comment, err := models.UpdateCommentStatus(etx.Request().Context(), c.db.Conn(), commentID, status)
+	if err != nil {
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to update comment: %v", err)); flashErr != nil {
+			return render(etx, views.InternalError())
+		}
+		return etx.Redirect(http.StatusSeeOther, routes.CommentIndex.URL())
+	}
+
+	c.invalidateArticle(etx, comment.ArticleID)
+	recordAudit(etx, c.db.Conn(), action, "comment", comment.ID.String(), currentComment, comment)
+
+	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, message); flashErr != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return etx.Redirect(http.StatusSeeOther, routes.CommentIndex.URL())
+}
+
+// invalidateArticle drops the cached article page so moderation shows up
+// straight away.
+func (c Comments) invalidateArticle(etx *echo.Context, articleID int32) {
+	article, err := models.FindArticle(etx.Request().Context(), c.db.Conn(), articleID)
+	if err != nil {
+		return
+	}
+
+	c.cache.Invalidate("pages:article:" + article.Slug)
+}
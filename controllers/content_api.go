@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"mortenvistisen/config"
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models"
+	"mortenvistisen/queue"
+	"mortenvistisen/router/apierror"
+	"mortenvistisen/router/middleware"
+	"net/http"
+	"strconv"
+
+	"github.com/a-h/templ"
+	"github.com/labstack/echo/v5"
+)
+
+// ContentAPI serves the token authenticated JSON API under routes.APIV1Prefix.
+// Release emails are scheduled through the admin controllers so publishing
+// from the API behaves exactly like publishing from the admin.
+type ContentAPI struct {
+	db          storage.Pool
+	cache       *Cache[templ.Component]
+	articles    Articles
+	newsletters Newsletters
+}
+
+func NewContentAPI(
+	db storage.Pool,
+	insertOnly queue.InsertOnly,
+	cache *Cache[templ.Component],
+	cfg config.Config,
+) ContentAPI {
+	return ContentAPI{
+		db:          db,
+		cache:       cache,
+		articles:    NewArticles(db, insertOnly, cache, cfg),
+		newsletters: NewNewsletters(db, insertOnly, cfg),
+	}
+}
+
+type apiResponse struct {
+	Data any `json:"data"`
+}
+
+type apiListMeta struct {
+	Page       int64 `json:"page"`
+	PerPage    int64 `json:"per_page"`
+	TotalCount int64 `json:"total_count"`
+	TotalPages int64 `json:"total_pages"`
+}
+
+type apiListResponse struct {
+	Data any         `json:"data"`
+	Meta apiListMeta `json:"meta"`
+}
+
+func apiPagination(etx *echo.Context) (int64, int64) {
+	page := int64(1)
+	if p := etx.QueryParam("page"); p != "" {
+		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
+			page = int64(parsed)
+		}
+	}
+
+	perPage := int64(25)
+	if pp := etx.QueryParam("per_page"); pp != "" {
+		if parsed, err := strconv.Atoi(pp); err == nil && parsed > 0 &&
+			parsed <= 100 {
+			perPage = int64(parsed)
+		}
+	}
+
+	return page, perPage
+}
+
+func apiSerialID(etx *echo.Context) (int32, bool) {
+	parsed, err := strconv.ParseInt(etx.Param("id"), 10, 32)
+	if err != nil {
+		return 0, false
+	}
+
+	return int32(parsed), true
+}
+
+func apiBadRequest(etx *echo.Context, message string) error {
+	return apierror.Write(etx, http.StatusBadRequest, apierror.CodeBadRequest, message)
+}
+
+// apiModelError maps an error from the models package to a JSON response.
+func apiModelError(etx *echo.Context, err error, message string) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return apierror.Write(etx, http.StatusNotFound, apierror.CodeNotFound, "resource not found")
+	case errors.Is(err, models.ErrNewsletterCannotBeUnpublished),
+		errors.Is(err, models.ErrTagCycle),
+		errors.Is(err, models.ErrTagParentNotFound):
+		return apierror.Write(etx, http.StatusUnprocessableEntity, apierror.CodeValidation, err.Error())
+	case errors.Is(err, models.ErrDomainValidation):
+		return apierror.Validation(etx, err)
+	}
+
+	slog.ErrorContext(etx.Request().Context(), message, "error", err)
+
+	return apierror.Write(etx, http.StatusInternalServerError, apierror.CodeInternal, message)
+}
+
+func apiForbidden(etx *echo.Context, message string) error {
+	return apierror.Write(etx, http.StatusForbidden, apierror.CodeForbidden, message)
+}
+
+func apiUser(etx *echo.Context) models.User {
+	user, _ := middleware.GetAPIUser(etx)
+	return user
+}
@@ -0,0 +1,332 @@
+package controllers
+
+import (
+	"mortenvistisen/models"
+	"mortenvistisen/router/apierror"
+	"mortenvistisen/services"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+)
+
+type apiArticle struct {
+	ID               int32      `json:"id"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	FirstPublishedAt *time.Time `json:"first_published_at"`
+	Published        bool       `json:"published"`
+	Title            string     `json:"title"`
+	Excerpt          string     `json:"excerpt"`
+	MetaTitle        string     `json:"meta_title"`
+	MetaDescription  string     `json:"meta_description"`
+	Slug             string     `json:"slug"`
+	ImageLink        string     `json:"image_link"`
+	ReadTime         int32      `json:"read_time"`
+	ReadTimeOverride bool       `json:"read_time_override"`
+	WordCount        int32      `json:"word_count"`
+	ImageCount       int32      `json:"image_count"`
+	HeadingCount     int32      `json:"heading_count"`
+	Content          string     `json:"content"`
+	AuthorID         string     `json:"author_id,omitempty"`
+	TagIDs           []int32    `json:"tag_ids"`
+}
+
+func toAPIArticle(article models.Article, tagIDs []int32) apiArticle {
+	result := apiArticle{
+		ID:               article.ID,
+		CreatedAt:        article.CreatedAt,
+		UpdatedAt:        article.UpdatedAt,
+		Published:        article.Published,
+		Title:            article.Title,
+		Excerpt:          article.Excerpt,
+		MetaTitle:        article.MetaTitle,
+		MetaDescription:  article.MetaDescription,
+		Slug:             article.Slug,
+		ImageLink:        article.ImageLink,
+		ReadTime:         article.ReadTime,
+		ReadTimeOverride: article.ReadTimeOverride,
+		WordCount:        article.Stats.WordCount,
+		ImageCount:       article.Stats.ImageCount,
+		HeadingCount:     article.Stats.HeadingCount,
+		Content:          article.Content,
+		TagIDs:           tagIDs,
+	}
+	if !article.FirstPublishedAt.IsZero() {
+		result.FirstPublishedAt = &article.FirstPublishedAt
+	}
+	if article.AuthorID != uuid.Nil {
+		result.AuthorID = article.AuthorID.String()
+	}
+	if result.TagIDs == nil {
+		result.TagIDs = []int32{}
+	}
+
+	return result
+}
+
+type APIArticlePayload struct {
+	Published       bool    `json:"published"`
+	Title           string  `json:"title"            validate:"required,max=100"`
+	Excerpt         string  `json:"excerpt"          validate:"omitempty,max=255"`
+	MetaTitle       string  `json:"meta_title"       validate:"omitempty,max=100"`
+	MetaDescription string  `json:"meta_description" validate:"omitempty,max=160"`
+	Slug            string  `json:"slug"`
+	ImageLink       string  `json:"image_link"       validate:"omitempty,url"`
+	ReadTime        int32   `json:"read_time"        validate:"gte=0"`
+	Content         string  `json:"content"`
+	TagIDs          []int32 `json:"tag_ids"`
+}
+
+func (c ContentAPI) ArticleIndex(etx *echo.Context) error {
+	ctx := etx.Request().Context()
+	page, perPage := apiPagination(etx)
+
+	articles, err := models.PaginateArticles(ctx, c.db.Conn(), page, perPage)
+	if err != nil {
+		return apiModelError(etx, err, "could not list articles")
+	}
+
+	data := make([]apiArticle, len(articles.Articles))
+	for i, article := range articles.Articles {
+		tagIDs, err := models.TagIDsForArticle(ctx, c.db.Conn(), article.ID)
+		if err != nil {
+			return apiModelError(etx, err, "could not list articles")
+		}
+		data[i] = toAPIArticle(article, tagIDs)
+	}
+
+	return etx.JSON(http.StatusOK, apiListResponse{
+		Data: data,
+		Meta: apiListMeta{
+			Page:       articles.Page,
+			PerPage:    articles.PageSize,
+			TotalCount: articles.TotalCount,
+			TotalPages: articles.TotalPages,
+		},
+	})
+}
+
+func (c ContentAPI) ArticleShow(etx *echo.Context) error {
+	ctx := etx.Request().Context()
+
+	articleID, ok := apiSerialID(etx)
+	if !ok {
+		return apiBadRequest(etx, "id must be an integer")
+	}
+
+	article, err := models.FindArticle(ctx, c.db.Conn(), articleID)
+	if err != nil {
+		return apiModelError(etx, err, "could not load article")
+	}
+
+	tagIDs, err := models.TagIDsForArticle(ctx, c.db.Conn(), article.ID)
+	if err != nil {
+		return apiModelError(etx, err, "could not load article")
+	}
+
+	return etx.JSON(http.StatusOK, apiResponse{Data: toAPIArticle(article, tagIDs)})
+}
+
+func (c ContentAPI) ArticleCreate(etx *echo.Context) error {
+	ctx := etx.Request().Context()
+	user := apiUser(etx)
+
+	var payload APIArticlePayload
+	if err := etx.Bind(&payload); err != nil {
+		return apiBadRequest(etx, "request body must be valid JSON")
+	}
+	if err := models.Validate.Struct(payload); err != nil {
+		return apierror.Validation(etx, err)
+	}
+
+	if payload.Published && !user.Role.Can(models.PermissionPublishArticles) {
+		return apiForbidden(etx, "your role is not allowed to publish articles")
+	}
+
+	tx, err := c.db.BeginTx(ctx)
+	if err != nil {
+		return apiModelError(etx, err, "could not create article")
+	}
+	defer tx.Rollback(ctx)
+
+	article, err := models.CreateArticle(ctx, tx, models.CreateArticleData{
+		Published:       payload.Published,
+		Title:           payload.Title,
+		Excerpt:         payload.Excerpt,
+		MetaTitle:       payload.MetaTitle,
+		MetaDescription: payload.MetaDescription,
+		ImageLink:       payload.ImageLink,
+		ReadTime:        payload.ReadTime,
+		Content:         payload.Content,
+		AuthorID:        user.ID,
+	})
+	if err != nil {
+		return apiModelError(etx, err, "could not create article")
+	}
+
+	article, err = services.RenderArticleContent(ctx, tx, article)
+	if err != nil {
+		return apiModelError(etx, err, "could not render article")
+	}
+
+	if err := models.AttachTagsToArticle(ctx, tx, article.ID, payload.TagIDs); err != nil {
+		return apiModelError(etx, err, "could not attach tags to article")
+	}
+
+	if article.Published && !article.FirstPublishedAt.IsZero() {
+		if _, err := c.articles.releaseArticle(ctx, tx, article); err != nil {
+			return apiModelError(etx, err, "could not schedule article release")
+		}
+	}
+
+	if article.Published {
+		if err := services.EnqueueRelatedArticlesRefresh(ctx, tx, c.articles.insertOnly); err != nil {
+			return apiModelError(etx, err, "could not schedule related articles refresh")
+		}
+	}
+
+	if err := c.db.CommitTx(ctx, tx); err != nil {
+		return apiModelError(etx, err, "could not create article")
+	}
+
+	invalidateTagPages(c.cache)
+
+	recordAudit(etx, c.db.Conn(), "article.create", "article", strconv.Itoa(int(article.ID)), nil, article)
+
+	tagIDs, err := models.TagIDsForArticle(ctx, c.db.Conn(), article.ID)
+	if err != nil {
+		return apiModelError(etx, err, "could not load article")
+	}
+
+	return etx.JSON(http.StatusCreated, apiResponse{Data: toAPIArticle(article, tagIDs)})
+}
+
+func (c ContentAPI) ArticleUpdate(etx *echo.Context) error {
+	ctx := etx.Request().Context()
+	user := apiUser(etx)
+
+	articleID, ok := apiSerialID(etx)
+	if !ok {
+		return apiBadRequest(etx, "id must be an integer")
+	}
+
+	var payload APIArticlePayload
+	if err := etx.Bind(&payload); err != nil {
+		return apiBadRequest(etx, "request body must be valid JSON")
+	}
+	if err := models.Validate.Struct(payload); err != nil {
+		return apierror.Validation(etx, err)
+	}
+
+	tx, err := c.db.BeginTx(ctx)
+	if err != nil {
+		return apiModelError(etx, err, "could not update article")
+	}
+	defer tx.Rollback(ctx)
+
+	currentArticle, err := models.FindArticle(ctx, tx, articleID)
+	if err != nil {
+		return apiModelError(etx, err, "could not load article")
+	}
+
+	if !models.CanManageArticle(user.Role, user.ID, currentArticle) {
+		return apiForbidden(etx, "you are not allowed to modify this article")
+	}
+	if payload.Published != currentArticle.Published &&
+		!user.Role.Can(models.PermissionPublishArticles) {
+		return apiForbidden(etx, "your role is not allowed to publish articles")
+	}
+
+	slug := payload.Slug
+	if slug == "" {
+		slug = currentArticle.Slug
+	}
+
+	article, err := models.UpdateArticle(ctx, tx, models.UpdateArticleData{
+		ID:              articleID,
+		Published:       payload.Published,
+		Title:           payload.Title,
+		Excerpt:         payload.Excerpt,
+		MetaTitle:       payload.MetaTitle,
+		MetaDescription: payload.MetaDescription,
+		Slug:            slug,
+		ImageLink:       payload.ImageLink,
+		ReadTime:        payload.ReadTime,
+		Content:         payload.Content,
+	})
+	if err != nil {
+		return apiModelError(etx, err, "could not update article")
+	}
+
+	article, err = services.EnsureArticleContent(ctx, tx, article)
+	if err != nil {
+		return apiModelError(etx, err, "could not render article")
+	}
+
+	if payload.TagIDs != nil {
+		if err := models.ReplaceTagsForArticle(ctx, tx, articleID, payload.TagIDs); err != nil {
+			return apiModelError(etx, err, "could not attach tags to article")
+		}
+	}
+
+	becameFirstPublished := currentArticle.FirstPublishedAt.IsZero() &&
+		!article.FirstPublishedAt.IsZero()
+	if becameFirstPublished {
+		if _, err := c.articles.releaseArticle(ctx, tx, article); err != nil {
+			return apiModelError(etx, err, "could not schedule article release")
+		}
+	}
+
+	if article.Published || currentArticle.Published {
+		if err := services.EnqueueRelatedArticlesRefresh(ctx, tx, c.articles.insertOnly); err != nil {
+			return apiModelError(etx, err, "could not schedule related articles refresh")
+		}
+	}
+
+	if err := c.db.CommitTx(ctx, tx); err != nil {
+		return apiModelError(etx, err, "could not update article")
+	}
+
+	invalidateTagPages(c.cache)
+
+	recordAudit(etx, c.db.Conn(), "article.update", "article", strconv.Itoa(int(article.ID)), currentArticle, article)
+
+	tagIDs, err := models.TagIDsForArticle(ctx, c.db.Conn(), article.ID)
+	if err != nil {
+		return apiModelError(etx, err, "could not load article")
+	}
+
+	return etx.JSON(http.StatusOK, apiResponse{Data: toAPIArticle(article, tagIDs)})
+}
+
+func (c ContentAPI) ArticleDestroy(etx *echo.Context) error {
+	ctx := etx.Request().Context()
+	user := apiUser(etx)
+
+	articleID, ok := apiSerialID(etx)
+	if !ok {
+		return apiBadRequest(etx, "id must be an integer")
+	}
+
+	article, err := models.FindArticle(ctx, c.db.Conn(), articleID)
+	if err != nil {
+		return apiModelError(etx, err, "could not load article")
+	}
+
+	if !models.CanManageArticle(user.Role, user.ID, article) {
+		return apiForbidden(etx, "you are not allowed to delete this article")
+	}
+
+	if err := models.DestroyArticle(ctx, c.db.Conn(), articleID); err != nil {
+		return apiModelError(etx, err, "could not delete article")
+	}
+
+	invalidateTagPages(c.cache)
+
+	recordAudit(etx, c.db.Conn(), "article.destroy", "article", strconv.Itoa(int(articleID)), article, nil)
+
+	return etx.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,234 @@
+package controllers
+
+import (
+	"mortenvistisen/models"
+	"mortenvistisen/router/apierror"
+	"mortenvistisen/services"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v5"
+)
+
+type apiNewsletter struct {
+	ID              int32      `json:"id"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	Title           string     `json:"title"`
+	Slug            string     `json:"slug"`
+	MetaTitle       string     `json:"meta_title"`
+	MetaDescription string     `json:"meta_description"`
+	IsPublished     bool       `json:"is_published"`
+	ReleasedAt      *time.Time `json:"released_at"`
+	Content         string     `json:"content"`
+}
+
+func toAPINewsletter(newsletter models.Newsletter) apiNewsletter {
+	result := apiNewsletter{
+		ID:              newsletter.ID,
+		CreatedAt:       newsletter.CreatedAt,
+		UpdatedAt:       newsletter.UpdatedAt,
+		Title:           newsletter.Title,
+		Slug:            newsletter.Slug,
+		MetaTitle:       newsletter.MetaTitle,
+		MetaDescription: newsletter.MetaDescription,
+		IsPublished:     newsletter.IsPublished,
+		Content:         newsletter.Content,
+	}
+	if !newsletter.ReleasedAt.IsZero() {
+		result.ReleasedAt = &newsletter.ReleasedAt
+	}
+
+	return result
+}
+
+type APINewsletterPayload struct {
+	Title           string    `json:"title"            validate:"required,max=255"`
+	MetaTitle       string    `json:"meta_title"       validate:"omitempty,max=100"`
+	MetaDescription string    `json:"meta_description" validate:"omitempty,max=160"`
+	IsPublished     bool      `json:"is_published"`
+	ReleasedAt      time.Time `json:"released_at"`
+	Content         string    `json:"content"`
+}
+
+func (c ContentAPI) NewsletterIndex(etx *echo.Context) error {
+	page, perPage := apiPagination(etx)
+
+	newsletters, err := models.PaginateNewsletters(etx.Request().Context(), c.db.Conn(), page, perPage)
+	if err != nil {
+		return apiModelError(etx, err, "could not list newsletters")
+	}
+
+	data := make([]apiNewsletter, len(newsletters.Newsletters))
+	for i, newsletter := range newsletters.Newsletters {
+		data[i] = toAPINewsletter(newsletter)
+	}
+
+	return etx.JSON(http.StatusOK, apiListResponse{
+		Data: data,
+		Meta: apiListMeta{
+			Page:       newsletters.Page,
+			PerPage:    newsletters.PageSize,
+			TotalCount: newsletters.TotalCount,
+			TotalPages: newsletters.TotalPages,
+		},
+	})
+}
+
+func (c ContentAPI) NewsletterShow(etx *echo.Context) error {
+	newsletterID, ok := apiSerialID(etx)
+	if !ok {
+		return apiBadRequest(etx, "id must be an integer")
+	}
+
+	newsletter, err := models.FindNewsletter(etx.Request().Context(), c.db.Conn(), newsletterID)
+	if err != nil {
+		return apiModelError(etx, err, "could not load newsletter")
+	}
+
+	return etx.JSON(http.StatusOK, apiResponse{Data: toAPINewsletter(newsletter)})
+}
+
+func (c ContentAPI) NewsletterCreate(etx *echo.Context) error {
+	ctx := etx.Request().Context()
+
+	var payload APINewsletterPayload
+	if err := etx.Bind(&payload); err != nil {
+		return apiBadRequest(etx, "request body must be valid JSON")
+	}
+	if err := models.Validate.Struct(payload); err != nil {
+		return apierror.Validation(etx, err)
+	}
+
+	tx, err := c.db.BeginTx(ctx)
+	if err != nil {
+		return apiModelError(etx, err, "could not create newsletter")
+	}
+	defer tx.Rollback(ctx)
+
+	newsletter, err := models.CreateNewsletter(ctx, tx, models.CreateNewsletterData{
+		Title:           payload.Title,
+		MetaTitle:       payload.MetaTitle,
+		MetaDescription: payload.MetaDescription,
+		IsPublished:     payload.IsPublished,
+		ReleasedAt:      payload.ReleasedAt,
+		Content:         payload.Content,
+	})
+	if err != nil {
+		return apiModelError(etx, err, "could not create newsletter")
+	}
+
+	newsletter, err = services.RenderNewsletterContent(ctx, tx, newsletter)
+	if err != nil {
+		return apiModelError(etx, err, "could not render newsletter")
+	}
+
+	scheduledJobs := 0
+	if newsletter.IsPublished {
+		scheduledJobs, err = c.newsletters.releaseNewsletter(ctx, tx, newsletter)
+		if err != nil {
+			return apiModelError(etx, err, "could not schedule newsletter delivery")
+		}
+	}
+
+	if err := c.db.CommitTx(ctx, tx); err != nil {
+		return apiModelError(etx, err, "could not create newsletter")
+	}
+
+	action := "newsletter.create"
+	if scheduledJobs > 0 {
+		action = "newsletter.publish"
+	}
+	recordAudit(etx, c.db.Conn(), action, "newsletter", strconv.Itoa(int(newsletter.ID)), nil, newsletter)
+
+	return etx.JSON(http.StatusCreated, apiResponse{Data: toAPINewsletter(newsletter)})
+}
+
+func (c ContentAPI) NewsletterUpdate(etx *echo.Context) error {
+	ctx := etx.Request().Context()
+
+	newsletterID, ok := apiSerialID(etx)
+	if !ok {
+		return apiBadRequest(etx, "id must be an integer")
+	}
+
+	var payload APINewsletterPayload
+	if err := etx.Bind(&payload); err != nil {
+		return apiBadRequest(etx, "request body must be valid JSON")
+	}
+	if err := models.Validate.Struct(payload); err != nil {
+		return apierror.Validation(etx, err)
+	}
+
+	tx, err := c.db.BeginTx(ctx)
+	if err != nil {
+		return apiModelError(etx, err, "could not update newsletter")
+	}
+	defer tx.Rollback(ctx)
+
+	currentNewsletter, err := models.FindNewsletter(ctx, tx, newsletterID)
+	if err != nil {
+		return apiModelError(etx, err, "could not load newsletter")
+	}
+
+	newsletter, err := models.UpdateNewsletter(ctx, tx, models.UpdateNewsletterData{
+		ID:              newsletterID,
+		Title:           payload.Title,
+		MetaTitle:       payload.MetaTitle,
+		MetaDescription: payload.MetaDescription,
+		IsPublished:     payload.IsPublished,
+		ReleasedAt:      payload.ReleasedAt,
+		Content:         payload.Content,
+	})
+	if err != nil {
+		return apiModelError(etx, err, "could not update newsletter")
+	}
+
+	newsletter, err = services.EnsureNewsletterContent(ctx, tx, newsletter)
+	if err != nil {
+		return apiModelError(etx, err, "could not render newsletter")
+	}
+
+	scheduledJobs := 0
+	if !currentNewsletter.IsPublished && newsletter.IsPublished {
+		scheduledJobs, err = c.newsletters.releaseNewsletter(ctx, tx, newsletter)
+		if err != nil {
+			return apiModelError(etx, err, "could not schedule newsletter delivery")
+		}
+	}
+
+	if err := c.db.CommitTx(ctx, tx); err != nil {
+		return apiModelError(etx, err, "could not update newsletter")
+	}
+
+	action := "newsletter.update"
+	if scheduledJobs > 0 {
+		action = "newsletter.publish"
+	}
+	recordAudit(etx, c.db.Conn(), action, "newsletter", strconv.Itoa(int(newsletter.ID)), currentNewsletter, newsletter)
+
+	return etx.JSON(http.StatusOK, apiResponse{Data: toAPINewsletter(newsletter)})
+}
+
+func (c ContentAPI) NewsletterDestroy(etx *echo.Context) error {
+	ctx := etx.Request().Context()
+
+	newsletterID, ok := apiSerialID(etx)
+	if !ok {
+		return apiBadRequest(etx, "id must be an integer")
+	}
+
+	newsletter, err := models.FindNewsletter(ctx, c.db.Conn(), newsletterID)
+	if err != nil {
+		return apiModelError(etx, err, "could not load newsletter")
+	}
+
+	if err := models.DestroyNewsletter(ctx, c.db.Conn(), newsletterID); err != nil {
+		return apiModelError(etx, err, "could not delete newsletter")
+	}
+
+	recordAudit(etx, c.db.Conn(), "newsletter.destroy", "newsletter", strconv.Itoa(int(newsletterID)), newsletter, nil)
+
+	return etx.NoContent(http.StatusNoContent)
+}
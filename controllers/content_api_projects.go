@@ -0,0 +1,220 @@
+package controllers
+
+import (
+	"mortenvistisen/models"
+	"mortenvistisen/router/apierror"
+	"mortenvistisen/services"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+)
+
+type apiProject struct {
+	ID          int32      `json:"id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Published   bool       `json:"published"`
+	Title       string     `json:"title"`
+	Slug        string     `json:"slug"`
+	StartedAt   *time.Time `json:"started_at"`
+	Status      string     `json:"status"`
+	Description string     `json:"description"`
+	Content     string     `json:"content"`
+	ProjectURL  string     `json:"project_url"`
+	AuthorID    string     `json:"author_id,omitempty"`
+}
+
+func toAPIProject(project models.Project) apiProject {
+	result := apiProject{
+		ID:          project.ID,
+		CreatedAt:   project.CreatedAt,
+		UpdatedAt:   project.UpdatedAt,
+		Published:   project.Published,
+		Title:       project.Title,
+		Slug:        project.Slug,
+		Status:      project.Status,
+		Description: project.Description,
+		Content:     project.Content,
+		ProjectURL:  project.ProjectURL,
+	}
+	if !project.StartedAt.IsZero() {
+		result.StartedAt = &project.StartedAt
+	}
+	if project.AuthorID != uuid.Nil {
+		result.AuthorID = project.AuthorID.String()
+	}
+
+	return result
+}
+
+type APIProjectPayload struct {
+	Published   bool      `json:"published"`
+	Title       string    `json:"title"       validate:"required,max=255"`
+	Slug        string    `json:"slug"        validate:"required,max=255"`
+	StartedAt   time.Time `json:"started_at"`
+	Status      string    `json:"status"      validate:"required"`
+	Description string    `json:"description" validate:"required"`
+	Content     string    `json:"content"     validate:"required"`
+	ProjectURL  string    `json:"project_url" validate:"omitempty,url"`
+}
+
+func (c ContentAPI) ProjectIndex(etx *echo.Context) error {
+	page, perPage := apiPagination(etx)
+
+	projects, err := models.PaginateProjects(etx.Request().Context(), c.db.Conn(), page, perPage)
+	if err != nil {
+		return apiModelError(etx, err, "could not list projects")
+	}
+
+	data := make([]apiProject, len(projects.Projects))
+	for i, project := range projects.Projects {
+		data[i] = toAPIProject(project)
+	}
+
+	return etx.JSON(http.StatusOK, apiListResponse{
+		Data: data,
+		Meta: apiListMeta{
+			Page:       projects.Page,
+			PerPage:    projects.PageSize,
+			TotalCount: projects.TotalCount,
+			TotalPages: projects.TotalPages,
+		},
+	})
+}
+
+func (c ContentAPI) ProjectShow(etx *echo.Context) error {
+	projectID, ok := apiSerialID(etx)
+	if !ok {
+		return apiBadRequest(etx, "id must be an integer")
+	}
+
+	project, err := models.FindProject(etx.Request().Context(), c.db.Conn(), projectID)
+	if err != nil {
+		return apiModelError(etx, err, "could not load project")
+	}
+
+	return etx.JSON(http.StatusOK, apiResponse{Data: toAPIProject(project)})
+}
+
+func (c ContentAPI) ProjectCreate(etx *echo.Context) error {
+	user := apiUser(etx)
+
+	var payload APIProjectPayload
+	if err := etx.Bind(&payload); err != nil {
+		return apiBadRequest(etx, "request body must be valid JSON")
+	}
+	if err := models.Validate.Struct(payload); err != nil {
+		return apierror.Validation(etx, err)
+	}
+
+	if payload.Published && !user.Role.Can(models.PermissionPublishProjects) {
+		return apiForbidden(etx, "your role is not allowed to publish projects")
+	}
+
+	project, err := models.CreateProject(etx.Request().Context(), c.db.Conn(), models.CreateProjectData{
+		Published:   payload.Published,
+		Title:       payload.Title,
+		Slug:        payload.Slug,
+		StartedAt:   payload.StartedAt,
+		Status:      payload.Status,
+		Description: payload.Description,
+		Content:     payload.Content,
+		ProjectURL:  payload.ProjectURL,
+		AuthorID:    user.ID,
+	})
+	if err != nil {
+		return apiModelError(etx, err, "could not create project")
+	}
+
+	recordAudit(etx, c.db.Conn(), "project.create", "project", strconv.Itoa(int(project.ID)), nil, project)
+
+	if _, err := services.RenderProjectContent(etx.Request().Context(), c.db.Conn(), project); err != nil {
+		logRenderError(etx, "project", project.ID, err)
+	}
+
+	return etx.JSON(http.StatusCreated, apiResponse{Data: toAPIProject(project)})
+}
+
+func (c ContentAPI) ProjectUpdate(etx *echo.Context) error {
+	ctx := etx.Request().Context()
+	user := apiUser(etx)
+
+	projectID, ok := apiSerialID(etx)
+	if !ok {
+		return apiBadRequest(etx, "id must be an integer")
+	}
+
+	var payload APIProjectPayload
+	if err := etx.Bind(&payload); err != nil {
+		return apiBadRequest(etx, "request body must be valid JSON")
+	}
+	if err := models.Validate.Struct(payload); err != nil {
+		return apierror.Validation(etx, err)
+	}
+
+	currentProject, err := models.FindProject(ctx, c.db.Conn(), projectID)
+	if err != nil {
+		return apiModelError(etx, err, "could not load project")
+	}
+
+	if !models.CanManageProject(user.Role, user.ID, currentProject) {
+		return apiForbidden(etx, "you are not allowed to modify this project")
+	}
+	if payload.Published != currentProject.Published &&
+		!user.Role.Can(models.PermissionPublishProjects) {
+		return apiForbidden(etx, "your role is not allowed to publish projects")
+	}
+
+	project, err := models.UpdateProject(ctx, c.db.Conn(), models.UpdateProjectData{
+		ID:          projectID,
+		Published:   payload.Published,
+		Title:       payload.Title,
+		Slug:        payload.Slug,
+		StartedAt:   payload.StartedAt,
+		Status:      payload.Status,
+		Description: payload.Description,
+		Content:     payload.Content,
+		ProjectURL:  payload.ProjectURL,
+	})
+	if err != nil {
+		return apiModelError(etx, err, "could not update project")
+	}
+
+	recordAudit(etx, c.db.Conn(), "project.update", "project", strconv.Itoa(int(project.ID)), currentProject, project)
+
+	if _, err := services.EnsureProjectContent(ctx, c.db.Conn(), project); err != nil {
+		logRenderError(etx, "project", project.ID, err)
+	}
+
+	return etx.JSON(http.StatusOK, apiResponse{Data: toAPIProject(project)})
+}
+
+func (c ContentAPI) ProjectDestroy(etx *echo.Context) error {
+	ctx := etx.Request().Context()
+	user := apiUser(etx)
+
+	projectID, ok := apiSerialID(etx)
+	if !ok {
+		return apiBadRequest(etx, "id must be an integer")
+	}
+
+	project, err := models.FindProject(ctx, c.db.Conn(), projectID)
+	if err != nil {
+		return apiModelError(etx, err, "could not load project")
+	}
+
+	if !models.CanManageProject(user.Role, user.ID, project) {
+		return apiForbidden(etx, "you are not allowed to delete this project")
+	}
+
+	if err := models.DestroyProject(ctx, c.db.Conn(), projectID); err != nil {
+		return apiModelError(etx, err, "could not delete project")
+	}
+
+	recordAudit(etx, c.db.Conn(), "project.destroy", "project", strconv.Itoa(int(projectID)), project, nil)
+
+	return etx.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,183 @@
+package controllers
+
+import (
+	"mortenvistisen/models"
+	"mortenvistisen/router/apierror"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v5"
+)
+
+type apiTag struct {
+	ID              int32     `json:"id"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	Title           string    `json:"title"`
+	Slug            string    `json:"slug"`
+	Description     string    `json:"description"`
+	MetaTitle       string    `json:"meta_title"`
+	MetaDescription string    `json:"meta_description"`
+	ParentID        *int32    `json:"parent_id"`
+}
+
+func toAPITag(tag models.Tag) apiTag {
+	return apiTag{
+		ID:              tag.ID,
+		CreatedAt:       tag.CreatedAt,
+		UpdatedAt:       tag.UpdatedAt,
+		Title:           tag.Title,
+		Slug:            tag.Slug,
+		Description:     tag.Description,
+		MetaTitle:       tag.MetaTitle,
+		MetaDescription: tag.MetaDescription,
+		ParentID:        apiTagParentID(tag.ParentID),
+	}
+}
+
+func apiTagParentID(parentID int32) *int32 {
+	if parentID == 0 {
+		return nil
+	}
+
+	return &parentID
+}
+
+type APITagPayload struct {
+	Title           string `json:"title"            validate:"required,max=100"`
+	Slug            string `json:"slug"             validate:"omitempty,max=255"`
+	Description     string `json:"description"`
+	MetaTitle       string `json:"meta_title"       validate:"omitempty,max=255"`
+	MetaDescription string `json:"meta_description" validate:"omitempty,max=255"`
+	ParentID        int32  `json:"parent_id"        validate:"omitempty,min=1"`
+}
+
+func (c ContentAPI) TagIndex(etx *echo.Context) error {
+	page, perPage := apiPagination(etx)
+
+	tags, err := models.PaginateTags(etx.Request().Context(), c.db.Conn(), page, perPage)
+	if err != nil {
+		return apiModelError(etx, err, "could not list tags")
+	}
+
+	data := make([]apiTag, len(tags.Tags))
+	for i, tag := range tags.Tags {
+		data[i] = toAPITag(tag)
+	}
+
+	return etx.JSON(http.StatusOK, apiListResponse{
+		Data: data,
+		Meta: apiListMeta{
+			Page:       tags.Page,
+			PerPage:    tags.PageSize,
+			TotalCount: tags.TotalCount,
+			TotalPages: tags.TotalPages,
+		},
+	})
+}
+
+func (c ContentAPI) TagShow(etx *echo.Context) error {
+	tagID, ok := apiSerialID(etx)
+	if !ok {
+		return apiBadRequest(etx, "id must be an integer")
+	}
+
+	tag, err := models.FindTag(etx.Request().Context(), c.db.Conn(), tagID)
+	if err != nil {
+		return apiModelError(etx, err, "could not load tag")
+	}
+
+	return etx.JSON(http.StatusOK, apiResponse{Data: toAPITag(tag)})
+}
+
+func (c ContentAPI) TagCreate(etx *echo.Context) error {
+	var payload APITagPayload
+	if err := etx.Bind(&payload); err != nil {
+		return apiBadRequest(etx, "request body must be valid JSON")
+	}
+	if err := models.Validate.Struct(payload); err != nil {
+		return apierror.Validation(etx, err)
+	}
+
+	tag, err := models.CreateTag(etx.Request().Context(), c.db.Conn(), models.CreateTagData{
+		Title:           payload.Title,
+		Slug:            payload.Slug,
+		Description:     payload.Description,
+		MetaTitle:       payload.MetaTitle,
+		MetaDescription: payload.MetaDescription,
+		ParentID:        payload.ParentID,
+	})
+	if err != nil {
+		return apiModelError(etx, err, "could not create tag")
+	}
+
+	recordAudit(etx, c.db.Conn(), "tag.create", "tag", strconv.Itoa(int(tag.ID)), nil, tag)
+
+	return etx.JSON(http.StatusCreated, apiResponse{Data: toAPITag(tag)})
+}
+
+func (c ContentAPI) TagUpdate(etx *echo.Context) error {
+	ctx := etx.Request().Context()
+
+	tagID, ok := apiSerialID(etx)
+	if !ok {
+		return apiBadRequest(etx, "id must be an integer")
+	}
+
+	var payload APITagPayload
+	if err := etx.Bind(&payload); err != nil {
+		return apiBadRequest(etx, "request body must be valid JSON")
+	}
+	if err := models.Validate.Struct(payload); err != nil {
+		return apierror.Validation(etx, err)
+	}
+
+	currentTag, err := models.FindTag(ctx, c.db.Conn(), tagID)
+	if err != nil {
+		return apiModelError(etx, err, "could not load tag")
+	}
+
+	tag, err := models.UpdateTag(ctx, c.db.Conn(), models.UpdateTagData{
+		ID:              tagID,
+		Title:           payload.Title,
+		Slug:            payload.Slug,
+		Description:     payload.Description,
+		MetaTitle:       payload.MetaTitle,
+		MetaDescription: payload.MetaDescription,
+		ParentID:        payload.ParentID,
+	})
+	if err != nil {
+		return apiModelError(etx, err, "could not update tag")
+	}
+
+	invalidateTagPages(c.cache)
+
+	recordAudit(etx, c.db.Conn(), "tag.update", "tag", strconv.Itoa(int(tag.ID)), currentTag, tag)
+
+	return etx.JSON(http.StatusOK, apiResponse{Data: toAPITag(tag)})
+}
+
+func (c ContentAPI) TagDestroy(etx *echo.Context) error {
+	ctx := etx.Request().Context()
+
+	tagID, ok := apiSerialID(etx)
+	if !ok {
+		return apiBadRequest(etx, "id must be an integer")
+	}
+
+	tag, err := models.FindTag(ctx, c.db.Conn(), tagID)
+	if err != nil {
+		return apiModelError(etx, err, "could not load tag")
+	}
+
+	if err := models.DestroyTag(ctx, c.db.Conn(), tagID); err != nil {
+		return apiModelError(etx, err, "could not delete tag")
+	}
+
+	invalidateTagPages(c.cache)
+
+	recordAudit(etx, c.db.Conn(), "tag.destroy", "tag", strconv.Itoa(int(tagID)), tag, nil)
+
+	return etx.NoContent(http.StatusNoContent)
+}
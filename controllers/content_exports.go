@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"mortenvistisen/config"
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/router/cookies"
+	"mortenvistisen/router/routes"
+	"mortenvistisen/services"
+	"mortenvistisen/views"
+
+	"github.com/labstack/echo/v5"
+)
+
+type ContentExports struct {
+	db storage.Pool
+}
+
+func NewContentExports(db storage.Pool) ContentExports {
+	return ContentExports{db}
+}
+
+// Download builds the archive in a temporary file first, so a failed export
+// can still redirect with a flash instead of sending half a zip.
+func (ce ContentExports) Download(etx *echo.Context) error {
+	ctx := etx.Request().Context()
+
+	file, err := os.CreateTemp("", "content-export-*.zip")
+	if err != nil {
+		return render(etx, views.InternalError())
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	if err := services.ExportContent(ctx, ce.db.Conn(), file, services.ContentExportOptions{
+		BaseURL:    config.BaseURL,
+		HTTPClient: &http.Client{Timeout: 15 * time.Second},
+	}); err != nil {
+		slog.ErrorContext(ctx, "could not export content", "error", err)
+
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, "Failed to export content"); flashErr != nil {
+			return render(etx, views.InternalError())
+		}
+
+		return etx.Redirect(http.StatusSeeOther, routes.AdminHome.URL())
+	}
+
+	recordAudit(etx, ce.db.Conn(), "content.export", "content", "", nil, nil)
+
+	return etx.Attachment(
+		file.Name(),
+		"content-export-"+time.Now().Format("20060102")+".zip",
+	)
+}
@@ -4,8 +4,11 @@ package controllers
 import (
 	"context"
 	"io"
+	"log/slog"
 	"mortenvistisen/internal/renderer"
 	"mortenvistisen/router/cookies"
+	"mortenvistisen/views"
+	"net/http"
 
 	"github.com/a-h/templ"
 	"github.com/labstack/echo/v5"
@@ -26,3 +29,23 @@ func render(etx *echo.Context, t templ.Component) error {
 		},
 	)
 }
+
+func forbidden(etx *echo.Context, redirectURL string) error {
+	if flashErr := cookies.AddFlash(etx, cookies.FlashError, "You do not have permission to do that"); flashErr != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return etx.Redirect(http.StatusSeeOther, redirectURL)
+}
+
+// logRenderError logs a failed render of content that was saved without
+// it. The content's page renders it on the first view instead.
+func logRenderError(etx *echo.Context, subjectType string, subjectID int32, err error) {
+	slog.ErrorContext(
+		etx.Request().Context(),
+		"failed to render content",
+		"subject_type", subjectType,
+		"subject_id", subjectID,
+		"error", err,
+	)
+}
@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"encoding/xml"
+	"mortenvistisen/config"
+	"mortenvistisen/models"
+	"mortenvistisen/router/routes"
+	"strings"
+	"time"
+)
+
+const atomContentType = "application/atom+xml; charset=utf-8"
+
+type AtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type AtomAuthor struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type AtomEntry struct {
+	ID        string     `xml:"id"`
+	Title     string     `xml:"title"`
+	Link      AtomLink   `xml:"link"`
+	Published string     `xml:"published"`
+	Updated   string     `xml:"updated"`
+	Summary   string     `xml:"summary,omitempty"`
+	Author    AtomAuthor `xml:"author"`
+}
+
+type AtomFeed struct {
+	XMLName  xml.Name    `xml:"feed"`
+	XMLNS    string      `xml:"xmlns,attr"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Links    []AtomLink  `xml:"link"`
+	Author   AtomAuthor  `xml:"author"`
+	Entries  []AtomEntry `xml:"entry"`
+}
+
+// articleAtomFeed renders articles as an Atom feed. selfPath and pagePath
+// are site relative. The feed is as recent as its newest article, or
+// updated when there is none.
+func articleAtomFeed(
+	title string,
+	subtitle string,
+	selfPath string,
+	pagePath string,
+	updated time.Time,
+	articles []models.Article,
+) ([]byte, error) {
+	baseURL := strings.TrimRight(config.BaseURL, "/")
+	author := AtomAuthor{Name: config.ProjectName, URI: baseURL + routes.AboutPage.URL()}
+
+	entries := make([]AtomEntry, len(articles))
+	for i, article := range articles {
+		articleURL := baseURL + routes.Article.URL(article.Slug)
+		entries[i] = AtomEntry{
+			ID:        articleURL,
+			Title:     article.Title,
+			Link:      AtomLink{Href: articleURL, Rel: "alternate", Type: "text/html"},
+			Published: article.FirstPublishedAt.UTC().Format(time.RFC3339),
+			Updated:   article.UpdatedAt.UTC().Format(time.RFC3339),
+			Summary:   article.Excerpt,
+			Author:    author,
+		}
+		if article.UpdatedAt.After(updated) {
+			updated = article.UpdatedAt
+		}
+	}
+
+	feed := AtomFeed{
+		XMLNS:    "http://www.w3.org/2005/Atom",
+		ID:       baseURL + selfPath,
+		Title:    title,
+		Subtitle: subtitle,
+		Updated:  updated.UTC().Format(time.RFC3339),
+		Links: []AtomLink{
+			{Href: baseURL + selfPath, Rel: "self", Type: "application/atom+xml"},
+			{Href: baseURL + pagePath, Rel: "alternate", Type: "text/html"},
+		},
+		Author:  author,
+		Entries: entries,
+	}
+
+	body, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), body...), nil
+}
@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"mortenvistisen/config"
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/router/cookies"
+	"mortenvistisen/router/routes"
+	"mortenvistisen/services"
+	"mortenvistisen/views"
+
+	"github.com/labstack/echo/v5"
+
+	"mortenvistisen/internal/hypermedia"
+)
+
+type Invitations struct {
+	db  storage.Pool
+	cfg config.Config
+}
+
+func NewInvitations(db storage.Pool, cfg config.Config) Invitations {
+	return Invitations{db, cfg}
+}
+
+func (i Invitations) Edit(etx *echo.Context) error {
+	etx.Response().Header().Set("Referrer-Policy", "strict-origin")
+
+	token := etx.Param("token")
+	if token == "" {
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, "Invalid or missing invitation"); flashErr != nil {
+			return render(etx, views.InternalError())
+		}
+		return etx.Redirect(http.StatusSeeOther, routes.SessionNew.URL())
+	}
+
+	return render(etx, views.AcceptInvitationForm(token))
+}
+
+func (i Invitations) Update(etx *echo.Context) error {
+	var payload struct {
+		Token           string `json:"invitationToken"`
+		Password        string `json:"password"`
+		ConfirmPassword string `json:"confirmPassword"`
+	}
+
+	if err := etx.Bind(&payload); err != nil {
+		slog.ErrorContext(
+			etx.Request().Context(),
+			"could not parse invitation payload",
+			"error",
+			err,
+		)
+		return render(etx, views.BadRequest())
+	}
+
+	user, err := services.AcceptInvitation(
+		etx.Request().Context(),
+		i.db,
+		i.cfg.Auth.Pepper,
+		services.AcceptInvitationData{
+			Token:           payload.Token,
+			Password:        payload.Password,
+			ConfirmPassword: payload.ConfirmPassword,
+		},
+	)
+	if err != nil {
+		slog.ErrorContext(
+			etx.Request().Context(),
+			"failed to accept invitation",
+			"error",
+			err,
+		)
+
+		var errorMsg string
+		switch err {
+		case services.ErrInvalidInvitation:
+			errorMsg = "Invalid invitation"
+		case services.ErrExpiredInvitation:
+			errorMsg = "Invitation has expired"
+		default:
+			errorMsg = "Failed to accept invitation"
+		}
+
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, errorMsg); flashErr != nil {
+			return render(etx, views.InternalError())
+		}
+
+		return etx.Redirect(http.StatusSeeOther, routes.InvitationEdit.URL(payload.Token))
+	}
+
+	if err := cookies.CreateAppSession(etx, user); err != nil {
+		slog.ErrorContext(
+			etx.Request().Context(),
+			"failed to create session",
+			"error",
+			err,
+		)
+
+		return render(etx, views.InternalError())
+	}
+
+	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, "Welcome! Your account is ready."); flashErr != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return hypermedia.Redirect(etx, routes.HomePage.URL())
+}
@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"fmt"
+	"log/slog"
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models"
+	"mortenvistisen/queue"
+	"mortenvistisen/router/cookies"
+	"mortenvistisen/router/routes"
+	"mortenvistisen/services"
+	"mortenvistisen/views"
+	"net/http"
+
+	"github.com/labstack/echo/v5"
+)
+
+// linkChecksShown is how many links the report lists.
+const linkChecksShown = 500
+
+type LinkChecks struct {
+	db         storage.Pool
+	insertOnly queue.InsertOnly
+}
+
+func NewLinkChecks(db storage.Pool, insertOnly queue.InsertOnly) LinkChecks {
+	return LinkChecks{db, insertOnly}
+}
+
+// Index lists the broken links, or every checked link with ?show=all.
+func (lc LinkChecks) Index(etx *echo.Context) error {
+	showAll := etx.QueryParam("show") == "all"
+
+	checks, err := models.LinkChecks(
+		etx.Request().Context(),
+		lc.db.Conn(),
+		!showAll,
+		linkChecksShown,
+	)
+	if err != nil {
+		slog.ErrorContext(
+			etx.Request().Context(),
+			"could not list link checks",
+			"error",
+			err,
+		)
+		return render(etx, views.InternalError())
+	}
+
+	return render(etx, views.LinkCheckIndex(checks, showAll))
+}
+
+// Run queues a link check straight away instead of waiting for the daily
+// one.
+func (lc LinkChecks) Run(etx *echo.Context) error {
+	if err := services.EnqueueLinkCheck(etx.Request().Context(), lc.insertOnly); err != nil {
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to queue link check: %v", err)); flashErr != nil {
+			return render(etx, views.InternalError())
+		}
+		return etx.Redirect(http.StatusSeeOther, routes.LinkCheckIndex.URL())
+	}
+
+	recordAudit(etx, lc.db.Conn(), "link_check.run", "link_check", "", nil, nil)
+
+	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, "Link check queued. Results show up here when it finishes."); flashErr != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return etx.Redirect(http.StatusSeeOther, routes.LinkCheckIndex.URL())
+}
@@ -0,0 +1,231 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mortenvistisen/config"
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models"
+	"mortenvistisen/router/cookies"
+	"mortenvistisen/router/routes"
+	"mortenvistisen/services"
+	"mortenvistisen/views"
+	"net/http"
+	"path"
+	"regexp"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+)
+
+const (
+	// mediaShown is how many uploads the media library lists.
+	mediaShown = 200
+	// mediaPickerShown is how many recent uploads the editors offer.
+	mediaPickerShown = 24
+	// mediaCacheControl lets browsers and CDNs keep media files for a year;
+	// storage keys are never reused, so a file never changes.
+	mediaCacheControl = "public, max-age=31536000, immutable"
+)
+
+var mediaKeyPattern = regexp.MustCompile(
+	`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}(-\d+w)?\.(jpg|png|webp)$`,
+)
+
+var mediaContentTypes = map[string]string{
+	".jpg":  "image/jpeg",
+	".png":  "image/png",
+	".webp": "image/webp",
+}
+
+type Media struct {
+	db    storage.Pool
+	store services.MediaStorage
+	cfg   config.Config
+}
+
+func NewMedia(db storage.Pool, store services.MediaStorage, cfg config.Config) Media {
+	return Media{db, store, cfg}
+}
+
+// File serves a stored variant. Only keys the media library generates are
+// accepted.
+func (m Media) File(etx *echo.Context) error {
+	key := etx.Param("file")
+	if !mediaKeyPattern.MatchString(key) {
+		return render(etx, views.NotFound())
+	}
+
+	etag := `"` + key + `"`
+	header := etx.Response().Header()
+	header.Set("Cache-Control", mediaCacheControl)
+	header.Set("ETag", etag)
+
+	if etx.Request().Header.Get("If-None-Match") == etag {
+		return etx.NoContent(http.StatusNotModified)
+	}
+
+	body, err := m.store.Get(etx.Request().Context(), key)
+	if err != nil {
+		header.Del("Cache-Control")
+		header.Del("ETag")
+
+		if errors.Is(err, services.ErrMediaFileNotFound) {
+			return render(etx, views.NotFound())
+		}
+
+		slog.ErrorContext(
+			etx.Request().Context(),
+			"failed to read media file",
+			"key", key,
+			"error", err,
+		)
+		return render(etx, views.InternalError())
+	}
+	defer body.Close()
+
+	header.Set("X-Content-Type-Options", "nosniff")
+
+	return etx.Stream(http.StatusOK, mediaContentTypes[path.Ext(key)], body)
+}
+
+func (m Media) Index(etx *echo.Context) error {
+	media, err := models.RecentMedia(etx.Request().Context(), m.db.Conn(), mediaShown)
+	if err != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return render(etx, views.MediaIndex(media, m.cfg.Media.MaxUploadBytes))
+}
+
+// Create accepts a multipart upload with the image in the file field and
+// an optional altText field.
+func (m Media) Create(etx *echo.Context) error {
+	req := etx.Request()
+	// Leave room for the other multipart fields on top of the image.
+	req.Body = http.MaxBytesReader(etx.Response(), req.Body, m.cfg.Media.MaxUploadBytes+1<<20)
+
+	fileHeader, err := etx.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return m.uploadFailed(etx, fmt.Sprintf("Images can be at most %d MB", m.cfg.Media.MaxUploadBytes>>20))
+		}
+		return m.uploadFailed(etx, "Choose an image to upload")
+	}
+	if fileHeader.Size > m.cfg.Media.MaxUploadBytes {
+		return m.uploadFailed(etx, fmt.Sprintf("Images can be at most %d MB", m.cfg.Media.MaxUploadBytes>>20))
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return m.uploadFailed(etx, fmt.Sprintf("Failed to read upload: %v", err))
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return m.uploadFailed(etx, fmt.Sprintf("Failed to read upload: %v", err))
+	}
+
+	media, err := services.UploadMedia(req.Context(), m.db, m.store, services.UploadMediaData{
+		UploadedBy: cookies.GetApp(etx).UserID,
+		Filename:   path.Base(fileHeader.Filename),
+		AltText:    etx.FormValue("altText"),
+		Content:    content,
+	})
+	if err != nil {
+		return m.uploadFailed(etx, fmt.Sprintf("Failed to upload image: %v", err))
+	}
+
+	recordAudit(etx, m.db.Conn(), "media.create", "media", media.ID.String(), nil, media)
+
+	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, "Image uploaded successfully"); flashErr != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return etx.Redirect(http.StatusSeeOther, routes.MediaIndex.URL())
+}
+
+func (m Media) uploadFailed(etx *echo.Context, msg string) error {
+	if flashErr := cookies.AddFlash(etx, cookies.FlashError, msg); flashErr != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return etx.Redirect(http.StatusSeeOther, routes.MediaIndex.URL())
+}
+
+// Update changes the alt text, read from the altText form field.
+func (m Media) Update(etx *echo.Context) error {
+	mediaID, err := uuid.Parse(etx.Param("id"))
+	if err != nil {
+		return render(etx, views.BadRequest())
+	}
+
+	current, err := models.FindMedia(etx.Request().Context(), m.db.Conn(), mediaID)
+	if err != nil {
+		return render(etx, views.NotFound())
+	}
+
+	app := cookies.GetApp(etx)
+	if !models.CanManageMedia(app.Role, app.UserID, current) {
+		return forbidden(etx, routes.MediaIndex.URL())
+	}
+
+	media, err := models.UpdateMediaAltText(
+		etx.Request().Context(),
+		m.db.Conn(),
+		models.UpdateMediaAltTextData{
+			ID:      mediaID,
+			AltText: etx.FormValue("altText"),
+		},
+	)
+	if err != nil {
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to update image: %v", err)); flashErr != nil {
+			return render(etx, views.InternalError())
+		}
+		return etx.Redirect(http.StatusSeeOther, routes.MediaIndex.URL())
+	}
+
+	recordAudit(etx, m.db.Conn(), "media.update", "media", mediaID.String(), current, media)
+
+	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, "Image updated successfully"); flashErr != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return etx.Redirect(http.StatusSeeOther, routes.MediaIndex.URL())
+}
+
+func (m Media) Destroy(etx *echo.Context) error {
+	mediaID, err := uuid.Parse(etx.Param("id"))
+	if err != nil {
+		return render(etx, views.BadRequest())
+	}
+
+	media, err := models.FindMedia(etx.Request().Context(), m.db.Conn(), mediaID)
+	if err != nil {
+		return render(etx, views.NotFound())
+	}
+
+	app := cookies.GetApp(etx)
+	if !models.CanManageMedia(app.Role, app.UserID, media) {
+		return forbidden(etx, routes.MediaIndex.URL())
+	}
+
+	if err := services.DestroyMedia(etx.Request().Context(), m.db, m.store, media); err != nil {
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to delete image: %v", err)); flashErr != nil {
+			return render(etx, views.InternalError())
+		}
+		return etx.Redirect(http.StatusSeeOther, routes.MediaIndex.URL())
+	}
+
+	recordAudit(etx, m.db.Conn(), "media.destroy", "media", mediaID.String(), media, nil)
+
+	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, "Image deleted successfully"); flashErr != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return etx.Redirect(http.StatusSeeOther, routes.MediaIndex.URL())
+}
@@ -158,9 +158,17 @@ func (n Newsletters) Create(etx *echo.Context) error {
 		return etx.Redirect(http.StatusSeeOther, routes.NewsletterNew.URL())
 	}
 
+	newsletter, err = services.RenderNewsletterContent(ctx, tx, newsletter)
+	if err != nil {
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to render newsletter: %v", err)); flashErr != nil {
+			return flashErr
+		}
+		return etx.Redirect(http.StatusSeeOther, routes.NewsletterNew.URL())
+	}
+
 	scheduledJobs := 0
 	if newsletter.IsPublished {
-		scheduledJobs, err = n.scheduleNewsletterReleaseEmails(ctx, tx, newsletter)
+		scheduledJobs, err = n.releaseNewsletter(ctx, tx, newsletter)
 		if err != nil {
 			if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to schedule newsletter delivery: %v", err)); flashErr != nil {
 				return flashErr
@@ -178,6 +186,12 @@ func (n Newsletters) Create(etx *echo.Context) error {
 		return etx.Redirect(http.StatusSeeOther, routes.NewsletterNew.URL())
 	}
 
+	action := "newsletter.create"
+	if newsletter.IsPublished {
+		action = "newsletter.publish"
+	}
+	recordAudit(etx, n.db.Conn(), action, "newsletter", strconv.Itoa(int(newsletter.ID)), nil, newsletter)
+
 	successMessage := "Newsletter created successfully"
 	if scheduledJobs > 0 {
 		successMessage = fmt.Sprintf(
@@ -294,10 +308,21 @@ func (n Newsletters) Update(etx *echo.Context) error {
 		)
 	}
 
+	newsletter, err = services.EnsureNewsletterContent(ctx, tx, newsletter)
+	if err != nil {
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to render newsletter: %v", err)); flashErr != nil {
+			return render(etx, views.InternalError())
+		}
+		return etx.Redirect(
+			http.StatusSeeOther,
+			routes.NewsletterEdit.URL(newsletterID),
+		)
+	}
+
 	scheduledJobs := 0
 	becamePublished := !currentNewsletter.IsPublished && newsletter.IsPublished
 	if becamePublished {
-		scheduledJobs, err = n.scheduleNewsletterReleaseEmails(ctx, tx, newsletter)
+		scheduledJobs, err = n.releaseNewsletter(ctx, tx, newsletter)
 		if err != nil {
 			if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to schedule newsletter delivery: %v", err)); flashErr != nil {
 				return render(etx, views.InternalError())
@@ -319,6 +344,12 @@ func (n Newsletters) Update(etx *echo.Context) error {
 		)
 	}
 
+	action := "newsletter.update"
+	if becamePublished {
+		action = "newsletter.publish"
+	}
+	recordAudit(etx, n.db.Conn(), action, "newsletter", strconv.Itoa(int(newsletter.ID)), currentNewsletter, newsletter)
+
 	successMessage := "Newsletter updated successfully"
 	if scheduledJobs > 0 {
 		successMessage = fmt.Sprintf(
@@ -340,6 +371,11 @@ func (n Newsletters) Destroy(etx *echo.Context) error {
 	}
 	newsletterID := int32(parsed)
 
+	newsletter, err := models.FindNewsletter(etx.Request().Context(), n.db.Conn(), newsletterID)
+	if err != nil {
+		return render(etx, views.NotFound())
+	}
+
 	err = models.DestroyNewsletter(etx.Request().Context(), n.db.Conn(), newsletterID)
 	if err != nil {
 		if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to delete newsletter: %v", err)); flashErr != nil {
@@ -348,6 +384,8 @@ func (n Newsletters) Destroy(etx *echo.Context) error {
 		return etx.Redirect(http.StatusSeeOther, routes.NewsletterIndex.URL())
 	}
 
+	recordAudit(etx, n.db.Conn(), "newsletter.destroy", "newsletter", strconv.Itoa(int(newsletterID)), newsletter, nil)
+
 	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, "Newsletter destroyed successfully"); flashErr != nil {
 		return render(etx, views.InternalError())
 	}
@@ -355,6 +393,38 @@ func (n Newsletters) Destroy(etx *echo.Context) error {
 	return etx.Redirect(http.StatusSeeOther, routes.NewsletterIndex.URL())
 }
 
+// releaseNewsletter queues everything that goes out when a newsletter is
+// published: the subscriber emails and the newsletter.released webhooks. It
+// returns the number of emails scheduled.
+func (n Newsletters) releaseNewsletter(
+	ctx context.Context,
+	tx pgx.Tx,
+	newsletter models.Newsletter,
+) (int, error) {
+	scheduledJobs, err := n.scheduleNewsletterReleaseEmails(ctx, tx, newsletter)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := services.EnqueueWebhooks(
+		ctx,
+		tx,
+		n.insertOnly,
+		models.WebhookEventNewsletterReleased,
+		services.NewsletterWebhookData{
+			ID:         newsletter.ID,
+			Title:      newsletter.Title,
+			Slug:       newsletter.Slug,
+			URL:        newsletterPublicURL(newsletter),
+			ReleasedAt: newsletter.ReleasedAt,
+		},
+	); err != nil {
+		return 0, err
+	}
+
+	return scheduledJobs, nil
+}
+
 func (n Newsletters) scheduleNewsletterReleaseEmails(
 	ctx context.Context,
 	tx pgx.Tx,
@@ -370,6 +440,7 @@ func (n Newsletters) scheduleNewsletterReleaseEmails(
 	})
 
 	readURL := newsletterPublicURL(newsletter)
+	newsletterHTML := services.RenderedHTML(newsletter.Rendered, newsletter.Content)
 
 	scheduleBase := time.Now().UTC().Add(10 * time.Second).Truncate(time.Second)
 
@@ -400,7 +471,7 @@ func (n Newsletters) scheduleNewsletterReleaseEmails(
 			NewsletterTitle: newsletter.Title,
 			IssueLabel:      newsletterIssueLabel(newsletter),
 			Highlights:      newsletter.MetaDescription,
-			NewsletterHTML:  services.MarkdownToHTML(newsletter.Content),
+			NewsletterHTML:  newsletterHTML,
 			ReadURL:         readURL,
 			UnsubscribeURL:  unsubscribeURL,
 		}
@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"mortenvistisen/config"
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models"
+	"mortenvistisen/services"
+	"mortenvistisen/views"
+
+	"github.com/labstack/echo/v5"
+)
+
+// ogImageCacheControl is short because the URLs are stable: an edited title
+// has to reach link previews that refetch the same URL.
+const ogImageCacheControl = "public, max-age=3600"
+
+// OGImages serves the generated Open Graph images of published content.
+// Images are rendered on first request and again whenever what they show
+// has changed since.
+type OGImages struct {
+	db storage.Pool
+}
+
+func NewOGImages(db storage.Pool) OGImages {
+	return OGImages{db}
+}
+
+func (o OGImages) Article(etx *echo.Context) error {
+	ctx := etx.Request().Context()
+
+	article, err := models.FindArticleBySlug(ctx, o.db.Conn(), etx.Param("slug"))
+	if err != nil || !article.Published {
+		return render(etx, views.NotFound())
+	}
+
+	tags, err := models.TagsForArticle(ctx, o.db.Conn(), article.ID)
+	if err != nil {
+		return render(etx, views.InternalError())
+	}
+	titles := make([]string, len(tags))
+	for i, tag := range tags {
+		titles[i] = tag.Title
+	}
+
+	return o.serve(etx, models.OGImageSubjectArticle, article.ID, services.OGImageCard{
+		Label:    "Article",
+		Title:    article.Title,
+		Tags:     titles,
+		Date:     article.FirstPublishedAt,
+		SiteName: config.Domain,
+	})
+}
+
+func (o OGImages) Project(etx *echo.Context) error {
+	project, err := models.FindProjectBySlug(etx.Request().Context(), o.db.Conn(), etx.Param("slug"))
+	if err != nil || !project.Published {
+		return render(etx, views.NotFound())
+	}
+
+	var tags []string
+	if project.Status != "" {
+		tags = append(tags, project.Status)
+	}
+
+	return o.serve(etx, models.OGImageSubjectProject, project.ID, services.OGImageCard{
+		Label:    "Project",
+		Title:    project.Title,
+		Tags:     tags,
+		Date:     project.StartedAt,
+		SiteName: config.Domain,
+	})
+}
+
+func (o OGImages) Newsletter(etx *echo.Context) error {
+	newsletter, err := models.FindNewsletterBySlug(etx.Request().Context(), o.db.Conn(), etx.Param("slug"))
+	if err != nil || !newsletter.IsPublished {
+		return render(etx, views.NotFound())
+	}
+
+	return o.serve(etx, models.OGImageSubjectNewsletter, newsletter.ID, services.OGImageCard{
+		Label:    "Newsletter",
+		Title:    newsletter.Title,
+		Date:     newsletter.ReleasedAt,
+		SiteName: config.Domain,
+	})
+}
+
+func (o OGImages) serve(
+	etx *echo.Context,
+	subjectType string,
+	subjectID int32,
+	card services.OGImageCard,
+) error {
+	etag := `"` + card.Fingerprint() + `"`
+	header := etx.Response().Header()
+
+	if etx.Request().Header.Get("If-None-Match") == etag {
+		header.Set("Cache-Control", ogImageCacheControl)
+		header.Set("ETag", etag)
+		return etx.NoContent(http.StatusNotModified)
+	}
+
+	image, err := services.OGImageFor(etx.Request().Context(), o.db.Conn(), subjectType, subjectID, card)
+	if err != nil {
+		slog.ErrorContext(
+			etx.Request().Context(),
+			"failed to generate og image",
+			"subject_type", subjectType,
+			"subject_id", subjectID,
+			"error", err,
+		)
+		return render(etx, views.InternalError())
+	}
+
+	header.Set("Cache-Control", ogImageCacheControl)
+	header.Set("ETag", `"`+image.Fingerprint+`"`)
+
+	return etx.Blob(http.StatusOK, "image/png", image.Image)
+}
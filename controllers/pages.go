@@ -1,14 +1,22 @@
 package controllers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
 
+	"mortenvistisen/internal/activitypub"
 	"mortenvistisen/internal/storage"
 	"mortenvistisen/models"
 	"mortenvistisen/queue"
+	"mortenvistisen/router/routes"
+	"mortenvistisen/services"
 	"mortenvistisen/views"
 
 	"github.com/a-h/templ"
+	"github.com/jackc/pgx/v5"
 	"github.com/labstack/echo/v5"
 )
 
@@ -48,17 +56,128 @@ func (p Pages) About(etx *echo.Context) error {
 	return render(etx, views.About())
 }
 
+func (p Pages) Series(etx *echo.Context) error {
+	slug := etx.Param("slug")
+	cacheKey := "pages:series:" + slug
+
+	component, err := p.cache.Get(cacheKey, func() (templ.Component, error) {
+		series, articles, err := p.publishedSeries(etx, slug)
+		if err != nil {
+			return views.SeriesPage(models.Series{}, nil), err
+		}
+
+		return views.SeriesPage(series, articles), nil
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return render(etx, views.NotFound())
+	}
+	if err != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return render(etx, component)
+}
+
+// SeriesFeed is an Atom feed of the series' articles in reading order, so
+// subscribers can follow along as new parts come out.
+func (p Pages) SeriesFeed(etx *echo.Context) error {
+	slug := etx.Param("slug")
+
+	series, articles, err := p.publishedSeries(etx, slug)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return render(etx, views.NotFound())
+	}
+	if err != nil {
+		return render(etx, views.InternalError())
+	}
+
+	feed, err := articleAtomFeed(
+		series.Title,
+		series.Description,
+		routes.SeriesFeed.URL(series.Slug),
+		routes.Series.URL(series.Slug),
+		series.UpdatedAt,
+		articles,
+	)
+	if err != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return etx.Blob(http.StatusOK, atomContentType, feed)
+}
+
+// publishedSeries loads a published series and its published articles. Both
+// a missing and an unpublished series are reported as pgx.ErrNoRows.
+func (p Pages) publishedSeries(etx *echo.Context, slug string) (models.Series, []models.Article, error) {
+	series, err := models.FindSeriesBySlug(etx.Request().Context(), p.db.Conn(), slug)
+	if err != nil {
+		return models.Series{}, nil, err
+	}
+	if !series.Published {
+		return models.Series{}, nil, pgx.ErrNoRows
+	}
+
+	articles, err := models.PublishedSeriesArticles(etx.Request().Context(), p.db.Conn(), series.ID)
+	if err != nil {
+		return models.Series{}, nil, err
+	}
+
+	return series, articles, nil
+}
+
+// relatedArticlesShown is how many related articles an article page lists.
+const relatedArticlesShown = 3
+
 func (p Pages) Article(etx *echo.Context) error {
 	slug := etx.Param("slug")
+	if activitypub.IsActivityPubRequest(etx.Request().Header.Get("Accept")) {
+		return etx.Redirect(http.StatusSeeOther, routes.ActivityPubArticle.URL(slug))
+	}
 	cacheKey := "pages:article:" + slug
 
 	component, err := p.cache.Get(cacheKey, func() (templ.Component, error) {
 		article, err := models.FindArticleBySlug(etx.Request().Context(), p.db.Conn(), slug)
 		if err != nil {
-			return views.Article(models.Article{}), err
+			return views.Article(views.ArticlePage{}), err
+		}
+
+		article, err = services.EnsureArticleContent(etx.Request().Context(), p.db.Conn(), article)
+		if err != nil {
+			return views.Article(views.ArticlePage{}), err
+		}
+
+		mentions, err := models.ApprovedWebmentionsForArticle(etx.Request().Context(), p.db.Conn(), article.ID)
+		if err != nil {
+			return views.Article(views.ArticlePage{}), err
 		}
 
-		return views.Article(article), nil
+		tags, err := models.TagsForArticle(etx.Request().Context(), p.db.Conn(), article.ID)
+		if err != nil {
+			return views.Article(views.ArticlePage{}), err
+		}
+
+		page := views.ArticlePage{Article: article, Tags: tags, Mentions: mentions}
+
+		nav, ok, err := models.ArticleSeriesNavigation(etx.Request().Context(), p.db.Conn(), article.ID)
+		if err != nil {
+			return views.Article(views.ArticlePage{}), err
+		}
+		if ok {
+			page.Series = &nav
+		}
+
+		page.Related, err = models.RelatedArticlesFor(etx.Request().Context(), p.db.Conn(), article.ID, relatedArticlesShown)
+		if err != nil {
+			return views.Article(views.ArticlePage{}), err
+		}
+
+		comments, err := models.ApprovedCommentsForArticle(etx.Request().Context(), p.db.Conn(), article.ID)
+		if err != nil {
+			return views.Article(views.ArticlePage{}), err
+		}
+		page.Comments = models.CommentThreads(comments)
+
+		return views.Article(page), nil
 	})
 	if err != nil {
 		return render(etx, views.InternalError())
@@ -67,6 +186,78 @@ func (p Pages) Article(etx *echo.Context) error {
 	return render(etx, component)
 }
 
+// tagPageSize is how many articles a public tag page lists.
+const tagPageSize = 10
+
+// Tag lists a tag's published articles. The page path parameter selects a
+// later page; the first page is only served at the tag's own URL, and pages
+// past the last are not found.
+func (p Pages) Tag(etx *echo.Context) error {
+	slug := etx.Param("slug")
+
+	page := int64(1)
+	if raw := etx.Param("page"); raw != "" {
+		parsed, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || parsed < 2 {
+			return render(etx, views.NotFound())
+		}
+		page = parsed
+	}
+
+	cacheKey := fmt.Sprintf("pages:tag:%s:%d", slug, page)
+
+	component, err := p.cache.Get(cacheKey, func() (templ.Component, error) {
+		tag, err := models.FindTagBySlug(etx.Request().Context(), p.db.Conn(), slug)
+		if err != nil {
+			return views.TagPage(models.PaginatedTagArticles{}, nil, nil), err
+		}
+
+		articles, err := models.PaginateTagArticles(etx.Request().Context(), p.db.Conn(), tag, page, tagPageSize)
+		if err != nil {
+			return views.TagPage(models.PaginatedTagArticles{}, nil, nil), err
+		}
+		if articles.TotalCount == 0 || page > articles.TotalPages {
+			return views.TagPage(models.PaginatedTagArticles{}, nil, nil), pgx.ErrNoRows
+		}
+
+		var parent *models.Tag
+		if tag.ParentID != 0 {
+			found, err := models.FindTag(etx.Request().Context(), p.db.Conn(), tag.ParentID)
+			if err != nil {
+				return views.TagPage(models.PaginatedTagArticles{}, nil, nil), err
+			}
+			parent = &found
+		}
+
+		children, err := models.ChildTags(etx.Request().Context(), p.db.Conn(), tag.ID)
+		if err != nil {
+			return views.TagPage(models.PaginatedTagArticles{}, nil, nil), err
+		}
+		published, err := models.AllPublishedTags(etx.Request().Context(), p.db.Conn())
+		if err != nil {
+			return views.TagPage(models.PaginatedTagArticles{}, nil, nil), err
+		}
+
+		// Only link to children that have a page of their own.
+		listed := make([]models.Tag, 0, len(children))
+		for _, child := range children {
+			if slices.ContainsFunc(published, func(t models.PublishedTag) bool { return t.ID == child.ID }) {
+				listed = append(listed, child)
+			}
+		}
+
+		return views.TagPage(articles, parent, listed), nil
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return render(etx, views.NotFound())
+	}
+	if err != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return render(etx, component)
+}
+
 func (p Pages) ArticlesOverview(etx *echo.Context) error {
 	cacheKey := "pages:articles_overview"
 
@@ -102,6 +293,11 @@ func (p Pages) Project(etx *echo.Context) error {
 			return views.Project(models.Project{}), err
 		}
 
+		project, err = services.EnsureProjectContent(etx.Request().Context(), p.db.Conn(), project)
+		if err != nil {
+			return views.Project(models.Project{}), err
+		}
+
 		return views.Project(project), nil
 	})
 	if err != nil {
@@ -146,6 +342,11 @@ func (p Pages) Newsletter(etx *echo.Context) error {
 			return views.Newsletter(models.Newsletter{}), err
 		}
 
+		newsletter, err = services.EnsureNewsletterContent(etx.Request().Context(), p.db.Conn(), newsletter)
+		if err != nil {
+			return views.Newsletter(models.Newsletter{}), err
+		}
+
 		return views.Newsletter(newsletter), nil
 	})
 	if err != nil {
@@ -11,6 +11,7 @@ import (
 	"mortenvistisen/models"
 	"mortenvistisen/router/cookies"
 	"mortenvistisen/router/routes"
+	"mortenvistisen/services"
 	"mortenvistisen/views"
 
 	"github.com/labstack/echo/v5"
@@ -69,7 +70,12 @@ func (p Projects) Show(etx *echo.Context) error {
 }
 
 func (p Projects) New(etx *echo.Context) error {
-	return render(etx, views.ProjectNew())
+	media, err := models.RecentMedia(etx.Request().Context(), p.db.Conn(), mediaPickerShown)
+	if err != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return render(etx, views.ProjectNew(media))
 }
 
 type CreateProjectFormPayload struct {
@@ -96,8 +102,10 @@ func (p Projects) Create(etx *echo.Context) error {
 		return render(etx, views.NotFound())
 	}
 
+	app := cookies.GetApp(etx)
+
 	data := models.CreateProjectData{
-		Published: payload.Published,
+		Published: payload.Published && app.Can(models.PermissionPublishProjects),
 		Title:     payload.Title,
 		Slug:      payload.Slug,
 		StartedAt: func() time.Time {
@@ -113,6 +121,7 @@ func (p Projects) Create(etx *echo.Context) error {
 		Description: payload.Description,
 		Content:     payload.Content,
 		ProjectURL:  payload.ProjectURL,
+		AuthorID:    app.UserID,
 	}
 
 	project, err := models.CreateProject(
@@ -128,6 +137,12 @@ func (p Projects) Create(etx *echo.Context) error {
 		return etx.Redirect(http.StatusSeeOther, routes.ProjectNew.URL())
 	}
 
+	recordAudit(etx, p.db.Conn(), "project.create", "project", strconv.Itoa(int(project.ID)), nil, project)
+
+	if _, err := services.RenderProjectContent(etx.Request().Context(), p.db.Conn(), project); err != nil {
+		logRenderError(etx, "project", project.ID, err)
+	}
+
 	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, "Project created successfully"); flashErr != nil {
 		return render(etx, views.InternalError())
 	}
@@ -147,7 +162,17 @@ func (p Projects) Edit(etx *echo.Context) error {
 		return render(etx, views.NotFound())
 	}
 
-	return render(etx, views.ProjectUpdate(project))
+	app := cookies.GetApp(etx)
+	if !models.CanManageProject(app.Role, app.UserID, project) {
+		return forbidden(etx, routes.ProjectShow.URL(projectID))
+	}
+
+	media, err := models.RecentMedia(etx.Request().Context(), p.db.Conn(), mediaPickerShown)
+	if err != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return render(etx, views.ProjectUpdate(project, media))
 }
 
 type UpdateProjectFormPayload struct {
@@ -200,6 +225,19 @@ func (p Projects) Update(etx *echo.Context) error {
 		ProjectURL:  payload.ProjectURL,
 	}
 
+	currentProject, err := models.FindProject(etx.Request().Context(), p.db.Conn(), projectID)
+	if err != nil {
+		return render(etx, views.NotFound())
+	}
+
+	app := cookies.GetApp(etx)
+	if !models.CanManageProject(app.Role, app.UserID, currentProject) {
+		return forbidden(etx, routes.ProjectShow.URL(projectID))
+	}
+	if !app.Can(models.PermissionPublishProjects) {
+		data.Published = currentProject.Published
+	}
+
 	project, err := models.UpdateProject(
 		etx.Request().Context(),
 		p.db.Conn(),
@@ -216,6 +254,12 @@ func (p Projects) Update(etx *echo.Context) error {
 		)
 	}
 
+	recordAudit(etx, p.db.Conn(), "project.update", "project", strconv.Itoa(int(project.ID)), currentProject, project)
+
+	if _, err := services.EnsureProjectContent(etx.Request().Context(), p.db.Conn(), project); err != nil {
+		logRenderError(etx, "project", project.ID, err)
+	}
+
 	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, "Project updated successfully"); flashErr != nil {
 		return render(etx, views.InternalError())
 	}
@@ -230,6 +274,16 @@ func (p Projects) Destroy(etx *echo.Context) error {
 	}
 	projectID := int32(parsed)
 
+	project, err := models.FindProject(etx.Request().Context(), p.db.Conn(), projectID)
+	if err != nil {
+		return render(etx, views.NotFound())
+	}
+
+	app := cookies.GetApp(etx)
+	if !models.CanManageProject(app.Role, app.UserID, project) {
+		return forbidden(etx, routes.ProjectIndex.URL())
+	}
+
 	err = models.DestroyProject(etx.Request().Context(), p.db.Conn(), projectID)
 	if err != nil {
 		if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to delete project: %v", err)); flashErr != nil {
@@ -239,6 +293,8 @@ func (p Projects) Destroy(etx *echo.Context) error {
 		return etx.Redirect(http.StatusSeeOther, routes.ProjectIndex.URL())
 	}
 
+	recordAudit(etx, p.db.Conn(), "project.destroy", "project", strconv.Itoa(int(projectID)), project, nil)
+
 	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, "Project destroyed successfully"); flashErr != nil {
 		return render(etx, views.InternalError())
 	}
@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"fmt"
+	"log/slog"
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models"
+	"mortenvistisen/router/cookies"
+	"mortenvistisen/router/routes"
+	"mortenvistisen/views"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v5"
+)
+
+type RateLimits struct {
+	db storage.Pool
+}
+
+func NewRateLimits(db storage.Pool) RateLimits {
+	return RateLimits{db}
+}
+
+func (rl RateLimits) Index(etx *echo.Context) error {
+	page := int64(1)
+	if p := etx.QueryParam("page"); p != "" {
+		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
+			page = int64(parsed)
+		}
+	}
+
+	perPage := int64(25)
+	if pp := etx.QueryParam("per_page"); pp != "" {
+		if parsed, err := strconv.Atoi(pp); err == nil && parsed > 0 &&
+			parsed <= 100 {
+			perPage = int64(parsed)
+		}
+	}
+
+	bans, err := models.PaginateActiveRateLimitBans(
+		etx.Request().Context(),
+		rl.db.Conn(),
+		page,
+		perPage,
+	)
+	if err != nil {
+		slog.ErrorContext(
+			etx.Request().Context(),
+			"could not list rate limit bans",
+			"error",
+			err,
+		)
+		return render(etx, views.InternalError())
+	}
+
+	return render(etx, views.RateLimitBanIndex(bans.Bans))
+}
+
+func (rl RateLimits) Destroy(etx *echo.Context) error {
+	parsed, err := strconv.ParseInt(etx.Param("id"), 10, 32)
+	if err != nil {
+		return render(etx, views.BadRequest())
+	}
+	banID := int32(parsed)
+
+	ban, err := models.FindRateLimitBan(etx.Request().Context(), rl.db.Conn(), banID)
+	if err != nil {
+		return render(etx, views.NotFound())
+	}
+
+	err = models.DestroyRateLimitBan(etx.Request().Context(), rl.db.Conn(), banID)
+	if err != nil {
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to lift ban: %v", err)); flashErr != nil {
+			return render(etx, views.InternalError())
+		}
+		return etx.Redirect(http.StatusSeeOther, routes.RateLimitBanIndex.URL())
+	}
+
+	recordAudit(etx, rl.db.Conn(), "rate_limit_ban.destroy", "rate_limit_ban", strconv.Itoa(int(banID)), ban, nil)
+
+	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, "Ban lifted successfully"); flashErr != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return etx.Redirect(http.StatusSeeOther, routes.RateLimitBanIndex.URL())
+}
@@ -32,10 +32,18 @@ func NewRegistrations(
 }
 
 func (r Registrations) New(etx *echo.Context) error {
+	if !r.cfg.Auth.RegistrationOpen {
+		return r.registrationClosed(etx)
+	}
+
 	return render(etx, views.RegistrationForm())
 }
 
 func (r Registrations) Create(etx *echo.Context) error {
+	if !r.cfg.Auth.RegistrationOpen {
+		return r.registrationClosed(etx)
+	}
+
 	var payload struct {
 		Email           string `json:"email"`
 		Password        string `json:"password"`
@@ -79,3 +87,11 @@ func (r Registrations) Create(etx *echo.Context) error {
 
 	return hypermedia.Redirect(etx, routes.ConfirmationNew.URL())
 }
+
+func (r Registrations) registrationClosed(etx *echo.Context) error {
+	if flashErr := cookies.AddFlash(etx, cookies.FlashError, "Registration is invite only"); flashErr != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return etx.Redirect(http.StatusSeeOther, routes.SessionNew.URL())
+}
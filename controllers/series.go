@@ -0,0 +1,413 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models"
+	"mortenvistisen/router/cookies"
+	"mortenvistisen/router/routes"
+	"mortenvistisen/views"
+	"net/http"
+	"strconv"
+
+	"github.com/a-h/templ"
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v5"
+)
+
+type Series struct {
+	db    storage.Pool
+	cache *Cache[templ.Component]
+}
+
+func NewSeries(db storage.Pool, cache *Cache[templ.Component]) Series {
+	return Series{db, cache}
+}
+
+func (s Series) Index(etx *echo.Context) error {
+	series, err := models.AllSeries(etx.Request().Context(), s.db.Conn())
+	if err != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return render(etx, views.SeriesIndex(series))
+}
+
+func (s Series) Show(etx *echo.Context) error {
+	seriesID, err := seriesIDParam(etx)
+	if err != nil {
+		return render(etx, views.BadRequest())
+	}
+
+	series, err := models.FindSeries(etx.Request().Context(), s.db.Conn(), seriesID)
+	if err != nil {
+		return render(etx, views.NotFound())
+	}
+
+	entries, err := models.SeriesArticles(etx.Request().Context(), s.db.Conn(), seriesID)
+	if err != nil {
+		return render(etx, views.InternalError())
+	}
+
+	articles, err := models.AllArticles(etx.Request().Context(), s.db.Conn())
+	if err != nil {
+		return render(etx, views.InternalError())
+	}
+
+	members := make(map[int32]bool, len(entries))
+	for _, entry := range entries {
+		members[entry.Article.ID] = true
+	}
+	candidates := make([]models.Article, 0, len(articles))
+	for _, article := range articles {
+		if !members[article.ID] {
+			candidates = append(candidates, article)
+		}
+	}
+
+	return render(etx, views.SeriesShow(series, entries, candidates))
+}
+
+func (s Series) New(etx *echo.Context) error {
+	return render(etx, views.SeriesNew())
+}
+
+type SeriesFormPayload struct {
+	Title       string `json:"title"`
+	Slug        string `json:"slug"`
+	Description string `json:"description"`
+	Published   bool   `json:"published"`
+}
+
+func (s Series) Create(etx *echo.Context) error {
+	var payload SeriesFormPayload
+	if err := etx.Bind(&payload); err != nil {
+		slog.ErrorContext(
+			etx.Request().Context(),
+			"could not parse SeriesFormPayload",
+			"error",
+			err,
+		)
+
+		return render(etx, views.BadRequest())
+	}
+
+	series, err := models.CreateSeries(
+		etx.Request().Context(),
+		s.db.Conn(),
+		models.CreateSeriesData{
+			Title:       payload.Title,
+			Slug:        payload.Slug,
+			Description: payload.Description,
+			Published:   payload.Published,
+		},
+	)
+	if err != nil {
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to create series: %v", err)); flashErr != nil {
+			return render(etx, views.InternalError())
+		}
+		return etx.Redirect(http.StatusSeeOther, routes.SeriesNew.URL())
+	}
+
+	recordAudit(etx, s.db.Conn(), "series.create", "series", strconv.Itoa(int(series.ID)), nil, series)
+
+	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, "Series created successfully"); flashErr != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return etx.Redirect(http.StatusSeeOther, routes.SeriesShow.URL(series.ID))
+}
+
+func (s Series) Edit(etx *echo.Context) error {
+	seriesID, err := seriesIDParam(etx)
+	if err != nil {
+		return render(etx, views.BadRequest())
+	}
+
+	series, err := models.FindSeries(etx.Request().Context(), s.db.Conn(), seriesID)
+	if err != nil {
+		return render(etx, views.NotFound())
+	}
+
+	return render(etx, views.SeriesEdit(series))
+}
+
+func (s Series) Update(etx *echo.Context) error {
+	seriesID, err := seriesIDParam(etx)
+	if err != nil {
+		return render(etx, views.BadRequest())
+	}
+
+	var payload SeriesFormPayload
+	if err := etx.Bind(&payload); err != nil {
+		slog.ErrorContext(
+			etx.Request().Context(),
+			"could not parse SeriesFormPayload",
+			"error",
+			err,
+		)
+
+		return render(etx, views.BadRequest())
+	}
+
+	currentSeries, err := models.FindSeries(etx.Request().Context(), s.db.Conn(), seriesID)
+	if err != nil {
+		return render(etx, views.NotFound())
+	}
+
+	series, err := models.UpdateSeries(
+		etx.Request().Context(),
+		s.db.Conn(),
+		models.UpdateSeriesData{
+			ID:          seriesID,
+			Title:       payload.Title,
+			Slug:        payload.Slug,
+			Description: payload.Description,
+			Published:   payload.Published,
+		},
+	)
+	if err != nil {
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to update series: %v", err)); flashErr != nil {
+			return render(etx, views.InternalError())
+		}
+		return etx.Redirect(http.StatusSeeOther, routes.SeriesEdit.URL(seriesID))
+	}
+
+	s.cache.Invalidate("pages:series:" + currentSeries.Slug)
+	s.invalidate(etx, series)
+	recordAudit(etx, s.db.Conn(), "series.update", "series", strconv.Itoa(int(series.ID)), currentSeries, series)
+
+	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, "Series updated successfully"); flashErr != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return etx.Redirect(http.StatusSeeOther, routes.SeriesShow.URL(series.ID))
+}
+
+func (s Series) Destroy(etx *echo.Context) error {
+	seriesID, err := seriesIDParam(etx)
+	if err != nil {
+		return render(etx, views.BadRequest())
+	}
+
+	series, err := models.FindSeries(etx.Request().Context(), s.db.Conn(), seriesID)
+	if err != nil {
+		return render(etx, views.NotFound())
+	}
+
+	// Invalidate while the members can still be looked up.
+	s.invalidate(etx, series)
+
+	if err := models.DestroySeries(etx.Request().Context(), s.db.Conn(), seriesID); err != nil {
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to delete series: %v", err)); flashErr != nil {
+			return render(etx, views.InternalError())
+		}
+		return etx.Redirect(http.StatusSeeOther, routes.SeriesIndex.URL())
+	}
+
+	recordAudit(etx, s.db.Conn(), "series.destroy", "series", strconv.Itoa(int(seriesID)), series, nil)
+
+	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, "Series destroyed successfully"); flashErr != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return etx.Redirect(http.StatusSeeOther, routes.SeriesIndex.URL())
+}
+
+type SeriesArticlePayload struct {
+	ArticleID string `json:"articleId"`
+}
+
+// AddArticle appends an article to the end of the series. An article can
+// only be part of one series.
+func (s Series) AddArticle(etx *echo.Context) error {
+	ctx := etx.Request().Context()
+
+	seriesID, err := seriesIDParam(etx)
+	if err != nil {
+		return render(etx, views.BadRequest())
+	}
+
+	var payload SeriesArticlePayload
+	if err := etx.Bind(&payload); err != nil {
+		return render(etx, views.BadRequest())
+	}
+	parsed, err := strconv.ParseInt(payload.ArticleID, 10, 32)
+	if err != nil {
+		return s.flashRedirect(etx, seriesID, cookies.FlashError, "Choose an article to add")
+	}
+	articleID := int32(parsed)
+
+	series, err := models.FindSeries(ctx, s.db.Conn(), seriesID)
+	if err != nil {
+		return render(etx, views.NotFound())
+	}
+	article, err := models.FindArticle(ctx, s.db.Conn(), articleID)
+	if err != nil {
+		return render(etx, views.NotFound())
+	}
+
+	existing, err := models.FindSeriesForArticle(ctx, s.db.Conn(), articleID)
+	if err == nil {
+		return s.flashRedirect(etx, seriesID, cookies.FlashError, fmt.Sprintf("%q is already part of %q", article.Title, existing.Title))
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return render(etx, views.InternalError())
+	}
+
+	before := s.membership(etx, seriesID)
+	if err := models.AddArticleToSeries(ctx, s.db.Conn(), seriesID, articleID); err != nil {
+		return s.flashRedirect(etx, seriesID, cookies.FlashError, fmt.Sprintf("Failed to add article: %v", err))
+	}
+
+	s.invalidate(etx, series)
+	recordAudit(etx, s.db.Conn(), "series.add_article", "series", strconv.Itoa(int(seriesID)), before, s.membership(etx, seriesID))
+
+	return s.flashRedirect(etx, seriesID, cookies.FlashSuccess, "Article added to series")
+}
+
+func (s Series) RemoveArticle(etx *echo.Context) error {
+	ctx := etx.Request().Context()
+
+	seriesID, articleID, err := seriesArticleParams(etx)
+	if err != nil {
+		return render(etx, views.BadRequest())
+	}
+
+	series, err := models.FindSeries(ctx, s.db.Conn(), seriesID)
+	if err != nil {
+		return render(etx, views.NotFound())
+	}
+
+	// Invalidate before removing so the removed article's page is included.
+	s.invalidate(etx, series)
+	before := s.membership(etx, seriesID)
+
+	tx, err := s.db.BeginTx(ctx)
+	if err != nil {
+		return render(etx, views.InternalError())
+	}
+	defer tx.Rollback(ctx)
+
+	if err := models.RemoveArticleFromSeries(ctx, tx, seriesID, articleID); err != nil {
+		return s.flashRedirect(etx, seriesID, cookies.FlashError, fmt.Sprintf("Failed to remove article: %v", err))
+	}
+	if err := s.db.CommitTx(ctx, tx); err != nil {
+		return render(etx, views.InternalError())
+	}
+
+	recordAudit(etx, s.db.Conn(), "series.remove_article", "series", strconv.Itoa(int(seriesID)), before, s.membership(etx, seriesID))
+
+	return s.flashRedirect(etx, seriesID, cookies.FlashSuccess, "Article removed from series")
+}
+
+// MoveArticle moves an article one place up or down, given by the direction
+// query parameter.
+func (s Series) MoveArticle(etx *echo.Context) error {
+	ctx := etx.Request().Context()
+
+	seriesID, articleID, err := seriesArticleParams(etx)
+	if err != nil {
+		return render(etx, views.BadRequest())
+	}
+
+	offset := 1
+	switch etx.QueryParam("direction") {
+	case "up":
+		offset = -1
+	case "down":
+	default:
+		return render(etx, views.BadRequest())
+	}
+
+	series, err := models.FindSeries(ctx, s.db.Conn(), seriesID)
+	if err != nil {
+		return render(etx, views.NotFound())
+	}
+
+	before := s.membership(etx, seriesID)
+
+	tx, err := s.db.BeginTx(ctx)
+	if err != nil {
+		return render(etx, views.InternalError())
+	}
+	defer tx.Rollback(ctx)
+
+	if err := models.MoveSeriesArticle(ctx, tx, seriesID, articleID, offset); err != nil {
+		return s.flashRedirect(etx, seriesID, cookies.FlashError, fmt.Sprintf("Failed to move article: %v", err))
+	}
+	if err := s.db.CommitTx(ctx, tx); err != nil {
+		return render(etx, views.InternalError())
+	}
+
+	s.invalidate(etx, series)
+	recordAudit(etx, s.db.Conn(), "series.move_article", "series", strconv.Itoa(int(seriesID)), before, s.membership(etx, seriesID))
+
+	return etx.Redirect(http.StatusSeeOther, routes.SeriesShow.URL(seriesID))
+}
+
+// seriesMembership is the audited form of a series' article order.
+type seriesMembership struct {
+	Articles []string
+}
+
+func (s Series) membership(etx *echo.Context, seriesID int32) seriesMembership {
+	entries, err := models.SeriesArticles(etx.Request().Context(), s.db.Conn(), seriesID)
+	if err != nil {
+		return seriesMembership{}
+	}
+
+	slugs := make([]string, len(entries))
+	for i, entry := range entries {
+		slugs[i] = entry.Article.Slug
+	}
+
+	return seriesMembership{Articles: slugs}
+}
+
+// invalidate drops the cached series page and the pages of its articles,
+// whose part numbers and navigation may have changed.
+func (s Series) invalidate(etx *echo.Context, series models.Series) {
+	s.cache.Invalidate("pages:series:" + series.Slug)
+
+	entries, err := models.SeriesArticles(etx.Request().Context(), s.db.Conn(), series.ID)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		s.cache.Invalidate("pages:article:" + entry.Article.Slug)
+	}
+}
+
+func (s Series) flashRedirect(etx *echo.Context, seriesID int32, flashType cookies.FlashType, message string) error {
+	if flashErr := cookies.AddFlash(etx, flashType, message); flashErr != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return etx.Redirect(http.StatusSeeOther, routes.SeriesShow.URL(seriesID))
+}
+
+func seriesIDParam(etx *echo.Context) (int32, error) {
+	parsed, err := strconv.ParseInt(etx.Param("id"), 10, 32)
+	if err != nil {
+		return 0, err
+	}
+
+	return int32(parsed), nil
+}
+
+func seriesArticleParams(etx *echo.Context) (int32, int32, error) {
+	seriesID, err := seriesIDParam(etx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	articleID, err := strconv.ParseInt(etx.QueryParam("article_id"), 10, 32)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return seriesID, int32(articleID), nil
+}
@@ -6,7 +6,9 @@ import (
 
 	"mortenvistisen/config"
 	"mortenvistisen/internal/storage"
+	"mortenvistisen/models"
 	"mortenvistisen/router/cookies"
+	"mortenvistisen/router/middleware"
 	"mortenvistisen/router/routes"
 	"mortenvistisen/services"
 	"mortenvistisen/views"
@@ -45,15 +47,29 @@ func (s Sessions) Create(etx *echo.Context) error {
 		return render(etx, views.BadRequest())
 	}
 
-	user, err := services.AuthenticateUser(
-		etx.Request().Context(),
-		s.db,
-		s.cfg.Auth.Pepper,
-		services.LoginData{
-			Email:    payload.Email,
-			Password: payload.Password,
-		},
-	)
+	loginData := services.LoginData{
+		Email:    payload.Email,
+		Password: payload.Password,
+	}
+
+	var user models.User
+	var err error
+	if policy, ok := middleware.GetLockoutPolicy(etx); ok {
+		user, err = services.AuthenticateUserWithLockout(
+			etx.Request().Context(),
+			s.db,
+			s.cfg.Auth.Pepper,
+			policy,
+			loginData,
+		)
+	} else {
+		user, err = services.AuthenticateUser(
+			etx.Request().Context(),
+			s.db,
+			s.cfg.Auth.Pepper,
+			loginData,
+		)
+	}
 	if err != nil {
 		slog.ErrorContext(
 			etx.Request().Context(),
@@ -68,6 +84,10 @@ func (s Sessions) Create(etx *echo.Context) error {
 			errorMsg = "Invalid email or password"
 		case services.ErrEmailNotVerified:
 			errorMsg = "Please verify your email before logging in"
+		case services.ErrAccountDeactivated:
+			errorMsg = "This account has been deactivated"
+		case services.ErrAccountLocked:
+			errorMsg = "Too many failed login attempts. Please try again later"
 		default:
 			errorMsg = "Failed to log in"
 		}
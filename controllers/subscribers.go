@@ -176,6 +176,7 @@ func (s Subscribers) VerificationCreate(etx *echo.Context) error {
 	_, err := services.VerifySubscriber(
 		etx.Request().Context(),
 		s.db,
+		s.insertOnly,
 		s.cfg.Auth.Pepper,
 		services.VerifySubscriberData{
 			Code: strings.ToUpper(strings.TrimSpace(payload.Code)),
@@ -310,6 +311,8 @@ func (s Subscribers) Create(etx *echo.Context) error {
 		return etx.Redirect(http.StatusSeeOther, routes.SubscriberNew.URL())
 	}
 
+	recordAudit(etx, s.db.Conn(), "subscriber.create", "subscriber", strconv.Itoa(int(subscriber.ID)), nil, subscriber)
+
 	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, "Subscriber created successfully"); flashErr != nil {
 		return render(etx, views.InternalError())
 	}
@@ -358,6 +361,11 @@ func (s Subscribers) Update(etx *echo.Context) error {
 		return render(etx, views.NotFound())
 	}
 
+	currentSubscriber, err := models.FindSubscriber(etx.Request().Context(), s.db.Conn(), subscriberID)
+	if err != nil {
+		return render(etx, views.NotFound())
+	}
+
 	data := models.UpdateSubscriberData{
 		ID:    subscriberID,
 		Email: payload.Email,
@@ -389,6 +397,8 @@ func (s Subscribers) Update(etx *echo.Context) error {
 		)
 	}
 
+	recordAudit(etx, s.db.Conn(), "subscriber.update", "subscriber", strconv.Itoa(int(subscriber.ID)), currentSubscriber, subscriber)
+
 	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, "Subscriber updated successfully"); flashErr != nil {
 		return render(etx, views.InternalError())
 	}
@@ -403,6 +413,11 @@ func (s Subscribers) Destroy(etx *echo.Context) error {
 	}
 	subscriberID := int32(parsed)
 
+	subscriber, err := models.FindSubscriber(etx.Request().Context(), s.db.Conn(), subscriberID)
+	if err != nil {
+		return render(etx, views.NotFound())
+	}
+
 	err = models.DestroySubscriber(etx.Request().Context(), s.db.Conn(), subscriberID)
 	if err != nil {
 		if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to delete subscriber: %v", err)); flashErr != nil {
@@ -411,6 +426,8 @@ func (s Subscribers) Destroy(etx *echo.Context) error {
 		return etx.Redirect(http.StatusSeeOther, routes.SubscriberIndex.URL())
 	}
 
+	recordAudit(etx, s.db.Conn(), "subscriber.destroy", "subscriber", strconv.Itoa(int(subscriberID)), subscriber, nil)
+
 	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, "Subscriber destroyed successfully"); flashErr != nil {
 		return render(etx, views.InternalError())
 	}
@@ -11,15 +11,17 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/a-h/templ"
 	"github.com/labstack/echo/v5"
 )
 
 type Tags struct {
-	db storage.Pool
+	db    storage.Pool
+	cache *Cache[templ.Component]
 }
 
-func NewTags(db storage.Pool) Tags {
-	return Tags{db}
+func NewTags(db storage.Pool, cache *Cache[templ.Component]) Tags {
+	return Tags{db, cache}
 }
 
 func (t Tags) Index(etx *echo.Context) error {
@@ -63,15 +65,30 @@ func (t Tags) Show(etx *echo.Context) error {
 		return render(etx, views.NotFound())
 	}
 
-	return render(etx, views.TagShow(tag))
+	tags, err := models.AllTags(etx.Request().Context(), t.db.Conn())
+	if err != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return render(etx, views.TagShow(tag, tags))
 }
 
 func (t Tags) New(etx *echo.Context) error {
-	return render(etx, views.TagNew())
+	tags, err := models.AllTags(etx.Request().Context(), t.db.Conn())
+	if err != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return render(etx, views.TagNew(tags))
 }
 
 type CreateTagFormPayload struct {
-	Title string `json:"title"`
+	Title           string `json:"title"`
+	Slug            string `json:"slug"`
+	Description     string `json:"description"`
+	MetaTitle       string `json:"metaTitle"`
+	MetaDescription string `json:"metaDescription"`
+	ParentID        string `json:"parentId"`
 }
 
 func (t Tags) Create(etx *echo.Context) error {
@@ -87,8 +104,18 @@ func (t Tags) Create(etx *echo.Context) error {
 		return render(etx, views.NotFound())
 	}
 
+	parentID, err := tagParentParam(payload.ParentID)
+	if err != nil {
+		return render(etx, views.BadRequest())
+	}
+
 	data := models.CreateTagData{
-		Title: payload.Title,
+		ParentID:        parentID,
+		Title:           payload.Title,
+		Slug:            payload.Slug,
+		Description:     payload.Description,
+		MetaTitle:       payload.MetaTitle,
+		MetaDescription: payload.MetaDescription,
 	}
 
 	tag, err := models.CreateTag(
@@ -103,6 +130,8 @@ func (t Tags) Create(etx *echo.Context) error {
 		return etx.Redirect(http.StatusSeeOther, routes.TagNew.URL())
 	}
 
+	recordAudit(etx, t.db.Conn(), "tag.create", "tag", strconv.Itoa(int(tag.ID)), nil, tag)
+
 	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, "Tag created successfully"); flashErr != nil {
 		return render(etx, views.InternalError())
 	}
@@ -122,11 +151,21 @@ func (t Tags) Edit(etx *echo.Context) error {
 		return render(etx, views.NotFound())
 	}
 
-	return render(etx, views.TagEdit(tag))
+	tags, err := models.AllTags(etx.Request().Context(), t.db.Conn())
+	if err != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return render(etx, views.TagEdit(tag, tags))
 }
 
 type UpdateTagFormPayload struct {
-	Title string `json:"title"`
+	Title           string `json:"title"`
+	Slug            string `json:"slug"`
+	Description     string `json:"description"`
+	MetaTitle       string `json:"metaTitle"`
+	MetaDescription string `json:"metaDescription"`
+	ParentID        string `json:"parentId"`
 }
 
 func (t Tags) Update(etx *echo.Context) error {
@@ -148,9 +187,24 @@ func (t Tags) Update(etx *echo.Context) error {
 		return render(etx, views.NotFound())
 	}
 
+	currentTag, err := models.FindTag(etx.Request().Context(), t.db.Conn(), tagID)
+	if err != nil {
+		return render(etx, views.NotFound())
+	}
+
+	parentID, err := tagParentParam(payload.ParentID)
+	if err != nil {
+		return render(etx, views.BadRequest())
+	}
+
 	data := models.UpdateTagData{
-		ID:    tagID,
-		Title: payload.Title,
+		ID:              tagID,
+		ParentID:        parentID,
+		Title:           payload.Title,
+		Slug:            payload.Slug,
+		Description:     payload.Description,
+		MetaTitle:       payload.MetaTitle,
+		MetaDescription: payload.MetaDescription,
 	}
 
 	tag, err := models.UpdateTag(
@@ -168,6 +222,9 @@ func (t Tags) Update(etx *echo.Context) error {
 		)
 	}
 
+	invalidateTagPages(t.cache)
+	recordAudit(etx, t.db.Conn(), "tag.update", "tag", strconv.Itoa(int(tag.ID)), currentTag, tag)
+
 	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, "Tag updated successfully"); flashErr != nil {
 		return render(etx, views.InternalError())
 	}
@@ -182,6 +239,11 @@ func (t Tags) Destroy(etx *echo.Context) error {
 	}
 	tagID := int32(parsed)
 
+	tag, err := models.FindTag(etx.Request().Context(), t.db.Conn(), tagID)
+	if err != nil {
+		return render(etx, views.NotFound())
+	}
+
 	err = models.DestroyTag(etx.Request().Context(), t.db.Conn(), tagID)
 	if err != nil {
 		if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to delete tag: %v", err)); flashErr != nil {
@@ -190,9 +252,94 @@ func (t Tags) Destroy(etx *echo.Context) error {
 		return etx.Redirect(http.StatusSeeOther, routes.TagIndex.URL())
 	}
 
+	invalidateTagPages(t.cache)
+	recordAudit(etx, t.db.Conn(), "tag.destroy", "tag", strconv.Itoa(int(tagID)), tag, nil)
+
 	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, "Tag destroyed successfully"); flashErr != nil {
 		return render(etx, views.InternalError())
 	}
 
 	return etx.Redirect(http.StatusSeeOther, routes.TagIndex.URL())
 }
+
+type TagMergePayload struct {
+	TargetID string `json:"targetId"`
+}
+
+// Merge folds the tag into the chosen target tag and deletes it.
+func (t Tags) Merge(etx *echo.Context) error {
+	ctx := etx.Request().Context()
+
+	parsed, err := strconv.ParseInt(etx.Param("id"), 10, 32)
+	if err != nil {
+		return render(etx, views.BadRequest())
+	}
+	sourceID := int32(parsed)
+
+	var payload TagMergePayload
+	if err := etx.Bind(&payload); err != nil {
+		return render(etx, views.BadRequest())
+	}
+	parsed, err = strconv.ParseInt(payload.TargetID, 10, 32)
+	if err != nil {
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, "Choose a tag to merge into"); flashErr != nil {
+			return render(etx, views.InternalError())
+		}
+		return etx.Redirect(http.StatusSeeOther, routes.TagShow.URL(sourceID))
+	}
+	targetID := int32(parsed)
+
+	source, err := models.FindTag(ctx, t.db.Conn(), sourceID)
+	if err != nil {
+		return render(etx, views.NotFound())
+	}
+
+	tx, err := t.db.BeginTx(ctx)
+	if err != nil {
+		return render(etx, views.InternalError())
+	}
+	defer tx.Rollback(ctx)
+
+	target, err := models.MergeTags(ctx, tx, sourceID, targetID)
+	if err == nil {
+		err = t.db.CommitTx(ctx, tx)
+	}
+	if err != nil {
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to merge tag: %v", err)); flashErr != nil {
+			return render(etx, views.InternalError())
+		}
+		return etx.Redirect(http.StatusSeeOther, routes.TagShow.URL(sourceID))
+	}
+
+	invalidateTagPages(t.cache)
+	recordAudit(etx, t.db.Conn(), "tag.merge", "tag", strconv.Itoa(int(sourceID)), source, target)
+
+	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, fmt.Sprintf("Merged %q into %q", source.Title, target.Title)); flashErr != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return etx.Redirect(http.StatusSeeOther, routes.TagShow.URL(target.ID))
+}
+
+// tagParentParam parses the parent tag chosen in a form, where an empty
+// value means top level.
+func tagParentParam(raw string) (int32, error) {
+	if raw == "" {
+		return 0, nil
+	}
+
+	parsed, err := strconv.ParseInt(raw, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+
+	return int32(parsed), nil
+}
+
+// invalidateTagPages drops every cached tag page and the article pages that
+// list tags. With nesting, a change to one tag shows on its parents' and
+// children's pages too, and tags change rarely enough to drop them all.
+func invalidateTagPages(cache *Cache[templ.Component]) {
+	cache.InvalidatePrefix("pages:tag:")
+	cache.InvalidatePrefix("pages:article:")
+}
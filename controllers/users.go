@@ -0,0 +1,185 @@
+package controllers
+
+import (
+	"fmt"
+	"log/slog"
+	"mortenvistisen/config"
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models"
+	"mortenvistisen/queue"
+	"mortenvistisen/router/cookies"
+	"mortenvistisen/router/routes"
+	"mortenvistisen/services"
+	"mortenvistisen/views"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+)
+
+type Users struct {
+	db         storage.Pool
+	insertOnly queue.InsertOnly
+	cfg        config.Config
+}
+
+func NewUsers(
+	db storage.Pool,
+	insertOnly queue.InsertOnly,
+	cfg config.Config,
+) Users {
+	return Users{db, insertOnly, cfg}
+}
+
+func (u Users) Index(etx *echo.Context) error {
+	page := int64(1)
+	if p := etx.QueryParam("page"); p != "" {
+		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
+			page = int64(parsed)
+		}
+	}
+
+	perPage := int64(25)
+	if pp := etx.QueryParam("per_page"); pp != "" {
+		if parsed, err := strconv.Atoi(pp); err == nil && parsed > 0 &&
+			parsed <= 100 {
+			perPage = int64(parsed)
+		}
+	}
+
+	usersList, err := models.PaginateUsers(
+		etx.Request().Context(),
+		u.db.Conn(),
+		page,
+		perPage,
+	)
+	if err != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return render(etx, views.UserIndex(usersList.Users, cookies.GetApp(etx).UserID))
+}
+
+func (u Users) InvitationNew(etx *echo.Context) error {
+	return render(etx, views.UserInvitationNew())
+}
+
+type CreateUserInvitationFormPayload struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+func (u Users) InvitationCreate(etx *echo.Context) error {
+	var payload CreateUserInvitationFormPayload
+	if err := etx.Bind(&payload); err != nil {
+		slog.ErrorContext(
+			etx.Request().Context(),
+			"could not parse CreateUserInvitationFormPayload",
+			"error",
+			err,
+		)
+
+		return render(etx, views.BadRequest())
+	}
+
+	data := services.InviteUserData{
+		Email: payload.Email,
+		Role:  models.Role(payload.Role),
+	}
+
+	if err := services.InviteUser(
+		etx.Request().Context(),
+		u.db,
+		u.insertOnly,
+		u.cfg.Auth.Pepper,
+		data,
+	); err != nil {
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to send invitation: %v", err)); flashErr != nil {
+			return render(etx, views.InternalError())
+		}
+		return etx.Redirect(http.StatusSeeOther, routes.AdminUserInvitationNew.URL())
+	}
+
+	recordAudit(etx, u.db.Conn(), "user.invite", "invitation", data.Email, nil, data)
+
+	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, "Invitation sent successfully"); flashErr != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return etx.Redirect(http.StatusSeeOther, routes.AdminUserIndex.URL())
+}
+
+func (u Users) UpdateRole(etx *echo.Context) error {
+	role := models.Role(etx.QueryParam("role"))
+
+	return u.update(etx, "change role", "user.update_role", func(id uuid.UUID) (models.User, error) {
+		return models.UpdateUserRole(etx.Request().Context(), u.db.Conn(), id, role)
+	})
+}
+
+func (u Users) Deactivate(etx *echo.Context) error {
+	return u.update(etx, "deactivate user", "user.deactivate", func(id uuid.UUID) (models.User, error) {
+		return models.DeactivateUser(etx.Request().Context(), u.db.Conn(), id)
+	})
+}
+
+func (u Users) Reactivate(etx *echo.Context) error {
+	return u.update(etx, "reactivate user", "user.reactivate", func(id uuid.UUID) (models.User, error) {
+		return models.ReactivateUser(etx.Request().Context(), u.db.Conn(), id)
+	})
+}
+
+func (u Users) Destroy(etx *echo.Context) error {
+	return u.update(etx, "delete user", "user.destroy", func(id uuid.UUID) (models.User, error) {
+		return models.User{}, models.DestroyUser(etx.Request().Context(), u.db.Conn(), id)
+	})
+}
+
+// update applies action to the user in the id param and records it in the
+// audit log. Admins cannot change their own account from here so they can't
+// lock themselves out. apply returns the user after the change, or a zero
+// user if it was deleted.
+func (u Users) update(
+	etx *echo.Context,
+	action string,
+	auditAction string,
+	apply func(id uuid.UUID) (models.User, error),
+) error {
+	userID, err := uuid.Parse(etx.Param("id"))
+	if err != nil {
+		return render(etx, views.BadRequest())
+	}
+
+	if userID == cookies.GetApp(etx).UserID {
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("You cannot %s on your own account", action)); flashErr != nil {
+			return render(etx, views.InternalError())
+		}
+		return etx.Redirect(http.StatusSeeOther, routes.AdminUserIndex.URL())
+	}
+
+	before, err := models.FindUser(etx.Request().Context(), u.db.Conn(), userID)
+	if err != nil {
+		return render(etx, views.NotFound())
+	}
+
+	updated, err := apply(userID)
+	if err != nil {
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to %s: %v", action, err)); flashErr != nil {
+			return render(etx, views.InternalError())
+		}
+		return etx.Redirect(http.StatusSeeOther, routes.AdminUserIndex.URL())
+	}
+
+	var after any
+	if updated.ID != uuid.Nil {
+		after = updated
+	}
+	recordAudit(etx, u.db.Conn(), auditAction, "user", userID.String(), before, after)
+
+	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, "User updated successfully"); flashErr != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return etx.Redirect(http.StatusSeeOther, routes.AdminUserIndex.URL())
+}
@@ -0,0 +1,223 @@
+package controllers
+
+import (
+	"fmt"
+	"log/slog"
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models"
+	"mortenvistisen/router/cookies"
+	"mortenvistisen/router/routes"
+	"mortenvistisen/views"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+)
+
+// webhookDeliveriesShown is how many delivery attempts the show page lists.
+const webhookDeliveriesShown = 50
+
+type Webhooks struct {
+	db storage.Pool
+}
+
+func NewWebhooks(db storage.Pool) Webhooks {
+	return Webhooks{db}
+}
+
+func (w Webhooks) Index(etx *echo.Context) error {
+	endpoints, err := models.AllWebhookEndpoints(etx.Request().Context(), w.db.Conn())
+	if err != nil {
+		slog.ErrorContext(
+			etx.Request().Context(),
+			"could not list webhook endpoints",
+			"error",
+			err,
+		)
+		return render(etx, views.InternalError())
+	}
+
+	return render(etx, views.WebhookIndex(endpoints))
+}
+
+func (w Webhooks) Show(etx *echo.Context) error {
+	endpointID, err := uuid.Parse(etx.Param("id"))
+	if err != nil {
+		return render(etx, views.BadRequest())
+	}
+
+	endpoint, err := models.FindWebhookEndpoint(etx.Request().Context(), w.db.Conn(), endpointID)
+	if err != nil {
+		return render(etx, views.NotFound())
+	}
+
+	deliveries, err := models.RecentWebhookDeliveries(
+		etx.Request().Context(),
+		w.db.Conn(),
+		endpoint.ID,
+		webhookDeliveriesShown,
+	)
+	if err != nil {
+		slog.ErrorContext(
+			etx.Request().Context(),
+			"could not list webhook deliveries",
+			"error",
+			err,
+		)
+		return render(etx, views.InternalError())
+	}
+
+	return render(etx, views.WebhookShow(endpoint, deliveries))
+}
+
+func (w Webhooks) New(etx *echo.Context) error {
+	return render(etx, views.WebhookNew())
+}
+
+type WebhookFormPayload struct {
+	Name            string          `json:"name"`
+	URL             string          `json:"url"`
+	EventSelections map[string]bool `json:"eventSelections"`
+	IsActive        bool            `json:"isActive"`
+}
+
+func (p WebhookFormPayload) events() []models.WebhookEvent {
+	events := make([]models.WebhookEvent, 0, len(p.EventSelections))
+	for _, event := range models.WebhookEvents {
+		if p.EventSelections[views.WebhookEventSignal(event)] {
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
+
+func (w Webhooks) Create(etx *echo.Context) error {
+	var payload WebhookFormPayload
+	if err := etx.Bind(&payload); err != nil {
+		slog.ErrorContext(
+			etx.Request().Context(),
+			"could not parse WebhookFormPayload",
+			"error",
+			err,
+		)
+
+		return render(etx, views.BadRequest())
+	}
+
+	endpoint, err := models.CreateWebhookEndpoint(
+		etx.Request().Context(),
+		w.db.Conn(),
+		models.CreateWebhookEndpointData{
+			Name:     payload.Name,
+			URL:      payload.URL,
+			Events:   payload.events(),
+			IsActive: payload.IsActive,
+		},
+	)
+	if err != nil {
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to create webhook: %v", err)); flashErr != nil {
+			return render(etx, views.InternalError())
+		}
+		return etx.Redirect(http.StatusSeeOther, routes.WebhookNew.URL())
+	}
+
+	recordAudit(etx, w.db.Conn(), "webhook_endpoint.create", "webhook_endpoint", endpoint.ID.String(), nil, endpoint)
+
+	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, "Webhook created successfully"); flashErr != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return etx.Redirect(http.StatusSeeOther, routes.WebhookShow.URL(endpoint.ID))
+}
+
+func (w Webhooks) Edit(etx *echo.Context) error {
+	endpointID, err := uuid.Parse(etx.Param("id"))
+	if err != nil {
+		return render(etx, views.BadRequest())
+	}
+
+	endpoint, err := models.FindWebhookEndpoint(etx.Request().Context(), w.db.Conn(), endpointID)
+	if err != nil {
+		return render(etx, views.NotFound())
+	}
+
+	return render(etx, views.WebhookEdit(endpoint))
+}
+
+func (w Webhooks) Update(etx *echo.Context) error {
+	endpointID, err := uuid.Parse(etx.Param("id"))
+	if err != nil {
+		return render(etx, views.BadRequest())
+	}
+
+	var payload WebhookFormPayload
+	if err := etx.Bind(&payload); err != nil {
+		slog.ErrorContext(
+			etx.Request().Context(),
+			"could not parse WebhookFormPayload",
+			"error",
+			err,
+		)
+
+		return render(etx, views.BadRequest())
+	}
+
+	currentEndpoint, err := models.FindWebhookEndpoint(etx.Request().Context(), w.db.Conn(), endpointID)
+	if err != nil {
+		return render(etx, views.NotFound())
+	}
+
+	endpoint, err := models.UpdateWebhookEndpoint(
+		etx.Request().Context(),
+		w.db.Conn(),
+		models.UpdateWebhookEndpointData{
+			ID:       endpointID,
+			Name:     payload.Name,
+			URL:      payload.URL,
+			Events:   payload.events(),
+			IsActive: payload.IsActive,
+		},
+	)
+	if err != nil {
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to update webhook: %v", err)); flashErr != nil {
+			return render(etx, views.InternalError())
+		}
+		return etx.Redirect(http.StatusSeeOther, routes.WebhookEdit.URL(endpointID))
+	}
+
+	recordAudit(etx, w.db.Conn(), "webhook_endpoint.update", "webhook_endpoint", endpoint.ID.String(), currentEndpoint, endpoint)
+
+	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, "Webhook updated successfully"); flashErr != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return etx.Redirect(http.StatusSeeOther, routes.WebhookShow.URL(endpoint.ID))
+}
+
+func (w Webhooks) Destroy(etx *echo.Context) error {
+	endpointID, err := uuid.Parse(etx.Param("id"))
+	if err != nil {
+		return render(etx, views.BadRequest())
+	}
+
+	endpoint, err := models.FindWebhookEndpoint(etx.Request().Context(), w.db.Conn(), endpointID)
+	if err != nil {
+		return render(etx, views.NotFound())
+	}
+
+	if err := models.DestroyWebhookEndpoint(etx.Request().Context(), w.db.Conn(), endpointID); err != nil {
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to delete webhook: %v", err)); flashErr != nil {
+			return render(etx, views.InternalError())
+		}
+		return etx.Redirect(http.StatusSeeOther, routes.WebhookIndex.URL())
+	}
+
+	recordAudit(etx, w.db.Conn(), "webhook_endpoint.destroy", "webhook_endpoint", endpointID.String(), endpoint, nil)
+
+	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, "Webhook destroyed successfully"); flashErr != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return etx.Redirect(http.StatusSeeOther, routes.WebhookIndex.URL())
+}
@@ -0,0 +1,229 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"mortenvistisen/config"
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models"
+	"mortenvistisen/queue"
+	"mortenvistisen/queue/jobs"
+	"mortenvistisen/router/cookies"
+	"mortenvistisen/router/routes"
+	"mortenvistisen/views"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/a-h/templ"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+)
+
+// webmentionsShown is how many mentions the moderation page lists.
+const webmentionsShown = 200
+
+type Webmentions struct {
+	db         storage.Pool
+	insertOnly queue.InsertOnly
+	cache      *Cache[templ.Component]
+}
+
+func NewWebmentions(
+	db storage.Pool,
+	insertOnly queue.InsertOnly,
+	cache *Cache[templ.Component],
+) Webmentions {
+	return Webmentions{db, insertOnly, cache}
+}
+
+// Receive accepts a webmention and queues the source for verification. It
+// answers 202 because nothing has been checked yet.
+func (w Webmentions) Receive(etx *echo.Context) error {
+	ctx := etx.Request().Context()
+
+	source := strings.TrimSpace(etx.FormValue("source"))
+	target := strings.TrimSpace(etx.FormValue("target"))
+
+	slug, err := webmentionTargetSlug(source, target)
+	if err != nil {
+		return etx.String(http.StatusBadRequest, err.Error())
+	}
+
+	article, err := models.FindArticleBySlug(ctx, w.db.Conn(), slug)
+	if err != nil || !article.Published {
+		return etx.String(http.StatusBadRequest, "target is not an article on this site")
+	}
+
+	tx, err := w.db.BeginTx(ctx)
+	if err != nil {
+		return etx.String(http.StatusInternalServerError, "could not accept webmention")
+	}
+	defer tx.Rollback(ctx)
+
+	mention, err := models.ReceiveWebmention(ctx, tx, models.ReceiveWebmentionData{
+		ArticleID: article.ID,
+		Source:    source,
+		Target:    target,
+	})
+	if err != nil {
+		if errors.Is(err, models.ErrDomainValidation) {
+			return etx.String(http.StatusBadRequest, "source and target must be URLs")
+		}
+		slog.ErrorContext(ctx, "could not store webmention", "error", err)
+		return etx.String(http.StatusInternalServerError, "could not accept webmention")
+	}
+
+	if _, err := w.insertOnly.InsertTx(ctx, tx, jobs.VerifyWebmentionArgs{WebmentionID: mention.ID}, nil); err != nil {
+		slog.ErrorContext(ctx, "could not enqueue webmention verification", "error", err)
+		return etx.String(http.StatusInternalServerError, "could not accept webmention")
+	}
+
+	if err := w.db.CommitTx(ctx, tx); err != nil {
+		return etx.String(http.StatusInternalServerError, "could not accept webmention")
+	}
+
+	return etx.String(http.StatusAccepted, "Webmention accepted and queued for verification")
+}
+
+func (w Webmentions) Index(etx *echo.Context) error {
+	status := models.WebmentionStatus(etx.QueryParam("status"))
+	if status == "" {
+		status = models.WebmentionStatusReview
+	}
+	if status == "all" {
+		status = ""
+	} else if !status.Valid() {
+		return render(etx, views.BadRequest())
+	}
+
+	mentions, err := models.RecentWebmentions(
+		etx.Request().Context(),
+		w.db.Conn(),
+		status,
+		webmentionsShown,
+	)
+	if err != nil {
+		slog.ErrorContext(
+			etx.Request().Context(),
+			"could not list webmentions",
+			"error",
+			err,
+		)
+		return render(etx, views.InternalError())
+	}
+
+	return render(etx, views.WebmentionIndex(mentions, status))
+}
+
+func (w Webmentions) Approve(etx *echo.Context) error {
+	return w.moderate(etx, models.WebmentionStatusApproved, "webmention.approve", "Webmention approved")
+}
+
+func (w Webmentions) Reject(etx *echo.Context) error {
+	return w.moderate(etx, models.WebmentionStatusRejected, "webmention.reject", "Webmention rejected")
+}
+
+func (w Webmentions) Destroy(etx *echo.Context) error {
+	mentionID, err := uuid.Parse(etx.Param("id"))
+	if err != nil {
+		return render(etx, views.BadRequest())
+	}
+
+	mention, err := models.FindWebmention(etx.Request().Context(), w.db.Conn(), mentionID)
+	if err != nil {
+		return render(etx, views.NotFound())
+	}
+
+	if err := models.DestroyWebmention(etx.Request().Context(), w.db.Conn(), mentionID); err != nil {
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to delete webmention: %v", err)); flashErr != nil {
+			return render(etx, views.InternalError())
+		}
+		return etx.Redirect(http.StatusSeeOther, routes.WebmentionIndex.URL())
+	}
+
+	w.invalidateArticle(etx, mention.ArticleID)
+	recordAudit(etx, w.db.Conn(), "webmention.destroy", "webmention", mentionID.String(), mention, nil)
+
+	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, "Webmention destroyed successfully"); flashErr != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return etx.Redirect(http.StatusSeeOther, routes.WebmentionIndex.URL())
+}
+
+func (w Webmentions) moderate(
+	etx *echo.Context,
+	status models.WebmentionStatus,
+	action string,
+	message string,
+) error {
+	mentionID, err := uuid.Parse(etx.Param("id"))
+	if err != nil {
+		return render(etx, views.BadRequest())
+	}
+
+	currentMention, err := models.FindWebmention(etx.Request().Context(), w.db.Conn(), mentionID)
+	if err != nil {
+		return render(etx, views.NotFound())
+	}
+
+	mention, err := models.UpdateWebmentionStatus(etx.Request().Context(), w.db.Conn(), mentionID, status)
+	if err != nil {
+		if flashErr := cookies.AddFlash(etx, cookies.FlashError, fmt.Sprintf("Failed to update webmention: %v", err)); flashErr != nil {
+			return render(etx, views.InternalError())
+		}
+		return etx.Redirect(http.StatusSeeOther, routes.WebmentionIndex.URL())
+	}
+
+	w.invalidateArticle(etx, mention.ArticleID)
+	recordAudit(etx, w.db.Conn(), action, "webmention", mention.ID.String(), currentMention, mention)
+
+	if flashErr := cookies.AddFlash(etx, cookies.FlashSuccess, message); flashErr != nil {
+		return render(etx, views.InternalError())
+	}
+
+	return etx.Redirect(http.StatusSeeOther, routes.WebmentionIndex.URL())
+}
+
+// invalidateArticle drops the cached article page so moderation shows up
+// straight away.
+func (w Webmentions) invalidateArticle(etx *echo.Context, articleID int32) {
+	article, err := models.FindArticle(etx.Request().Context(), w.db.Conn(), articleID)
+	if err != nil {
+		return
+	}
+
+	w.cache.Invalidate("pages:article:" + article.Slug)
+}
+
+// webmentionTargetSlug checks a webmention request the way the spec asks and
+// returns the slug of the article it targets.
+func webmentionTargetSlug(source string, target string) (string, error) {
+	sourceURL, err := url.Parse(source)
+	if err != nil || (sourceURL.Scheme != "http" && sourceURL.Scheme != "https") || sourceURL.Host == "" {
+		return "", errors.New("source must be an http or https URL")
+	}
+
+	targetURL, err := url.Parse(target)
+	if err != nil || (targetURL.Scheme != "http" && targetURL.Scheme != "https") || targetURL.Host == "" {
+		return "", errors.New("target must be an http or https URL")
+	}
+
+	if source == target {
+		return "", errors.New("source and target must differ")
+	}
+
+	siteURL, err := url.Parse(config.BaseURL)
+	if err != nil || !strings.EqualFold(targetURL.Host, siteURL.Host) {
+		return "", errors.New("target is not on this site")
+	}
+
+	slug, ok := strings.CutPrefix(strings.TrimSuffix(targetURL.Path, "/"), routes.ArticleOverview.URL()+"/")
+	if !ok || slug == "" || strings.Contains(slug, "/") {
+		return "", errors.New("target is not an article on this site")
+	}
+
+	return slug, nil
+}
@@ -0,0 +1,85 @@
+// Command export writes every article, project and newsletter to a zip
+// archive of markdown files with front matter. Unzip the archive and point
+// database/sync at it to restore the content into another database.
+//
+//	go run ./database/export -out content.zip
+//	unzip content.zip -d content && go run ./database/sync -dir content
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+	"mortenvistisen/config"
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/services"
+)
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run() error {
+	godotenv.Load()
+
+	out := flag.String("out", "content-export-"+time.Now().Format("20060102")+".zip", "path of the archive to write")
+	images := flag.Bool("images", true, "download referenced images into the archive")
+	baseURL := flag.String("base-url", "", "base URL used to resolve root relative image links (default PROTOCOL://DOMAIN)")
+	flag.Parse()
+
+	ctx := context.Background()
+
+	db, err := storage.NewConnection(ctx, buildDatabaseURL())
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	file, err := os.Create(*out)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", *out, err)
+	}
+	defer file.Close()
+
+	opts := services.ContentExportOptions{BaseURL: *baseURL}
+	if opts.BaseURL == "" {
+		opts.BaseURL = config.ResolveBaseURL()
+	}
+	if *images {
+		opts.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	fmt.Printf("Exporting content to %s...\n", *out)
+
+	if err := services.ExportContent(ctx, db.Conn(), file, opts); err != nil {
+		os.Remove(*out)
+		return fmt.Errorf("failed to export content: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", *out, err)
+	}
+
+	fmt.Println("Export complete!")
+	return nil
+}
+
+func buildDatabaseURL() string {
+	return fmt.Sprintf("%s://%s:%s@%s:%s/%s?sslmode=%s",
+		os.Getenv("DB_KIND"),
+		os.Getenv("DB_USER"),
+		os.Getenv("DB_PASSWORD"),
+		os.Getenv("DB_HOST"),
+		os.Getenv("DB_PORT"),
+		os.Getenv("DB_NAME"),
+		os.Getenv("DB_SSL_MODE"),
+	)
+}
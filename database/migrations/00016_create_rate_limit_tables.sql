@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+create table if not exists rate_limit_hits (
+    id bigserial not null,
+    primary key (id),
+
+    created_at timestamp with time zone not null,
+
+    scope varchar(100) not null,
+    key varchar(255) not null
+);
+
+create index if not exists rate_limit_hits_scope_key_created_at_idx
+    on rate_limit_hits (scope, key, created_at);
+
+create table if not exists rate_limit_bans (
+    id serial not null,
+    primary key (id),
+
+    created_at timestamp with time zone not null,
+    updated_at timestamp with time zone not null,
+
+    scope varchar(100) not null,
+    key varchar(255) not null,
+    reason varchar(255) not null,
+    expires_at timestamp with time zone not null,
+
+    unique (scope, key)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+drop table if exists rate_limit_bans;
+drop table if exists rate_limit_hits;
+-- +goose StatementEnd
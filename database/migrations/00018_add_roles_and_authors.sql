@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'member';
+UPDATE users SET role = 'admin' WHERE is_admin;
+
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS author_id uuid REFERENCES users(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS articles_author_id_idx ON articles (author_id);
+
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS author_id uuid REFERENCES users(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS projects_author_id_idx ON projects (author_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP INDEX IF EXISTS projects_author_id_idx;
+ALTER TABLE projects DROP COLUMN IF EXISTS author_id;
+
+DROP INDEX IF EXISTS articles_author_id_idx;
+ALTER TABLE articles DROP COLUMN IF EXISTS author_id;
+
+ALTER TABLE users DROP COLUMN IF EXISTS role;
+-- +goose StatementEnd
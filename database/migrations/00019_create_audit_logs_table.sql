@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+create table if not exists audit_logs (
+    id bigserial not null,
+    primary key (id),
+
+    created_at timestamp with time zone not null,
+
+    actor_id uuid references users(id) on delete set null,
+    actor_email varchar(255) not null,
+    action varchar(100) not null,
+    target_type varchar(50) not null,
+    target_id varchar(100) not null,
+    changes jsonb not null default '{}'::jsonb,
+    ip_address varchar(100) not null,
+    user_agent text not null,
+    request_id varchar(100) not null
+);
+
+create index if not exists audit_logs_created_at_idx on audit_logs (created_at);
+create index if not exists audit_logs_actor_id_idx on audit_logs (actor_id);
+create index if not exists audit_logs_target_idx on audit_logs (target_type, target_id);
+
+create or replace function audit_logs_prevent_update() returns trigger as $$
+begin
+    raise exception 'audit_logs is append-only';
+end;
+$$ language plpgsql;
+
+create trigger audit_logs_append_only
+    before update on audit_logs
+    for each row execute function audit_logs_prevent_update();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+drop trigger if exists audit_logs_append_only on audit_logs;
+drop function if exists audit_logs_prevent_update();
+drop table if exists audit_logs;
+-- +goose StatementEnd
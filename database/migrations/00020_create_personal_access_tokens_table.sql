@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+create table if not exists personal_access_tokens (
+    id uuid not null,
+    primary key (id),
+
+    created_at timestamp with time zone not null,
+    updated_at timestamp with time zone not null,
+
+    user_id uuid not null references users(id) on delete cascade,
+    name varchar(100) not null,
+    token_prefix varchar(20) not null,
+    hash text not null unique,
+    scopes text[] not null,
+    expires_at timestamp with time zone,
+    last_used_at timestamp with time zone
+);
+
+create index if not exists personal_access_tokens_user_id_idx on personal_access_tokens (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+drop table if exists personal_access_tokens;
+-- +goose StatementEnd
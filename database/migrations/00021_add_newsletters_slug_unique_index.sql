@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+update newsletters n
+    set slug = n.slug || '-' || n.id
+where exists (
+    select 1 from newsletters o where o.slug = n.slug and o.id < n.id
+);
+
+create unique index if not exists newsletters_slug_key on newsletters (slug);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+drop index if exists newsletters_slug_key;
+-- +goose StatementEnd
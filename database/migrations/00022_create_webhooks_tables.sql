@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+create table if not exists webhook_endpoints (
+    id uuid not null,
+    primary key (id),
+
+    created_at timestamp with time zone not null,
+    updated_at timestamp with time zone not null,
+
+    name varchar(100) not null,
+    url text not null,
+    secret varchar(100) not null,
+    events text[] not null default '{}',
+    is_active boolean not null default true
+);
+
+create table if not exists webhook_deliveries (
+    id bigserial not null,
+    primary key (id),
+
+    created_at timestamp with time zone not null,
+
+    endpoint_id uuid not null references webhook_endpoints(id) on delete cascade,
+    event_id uuid not null,
+    event varchar(100) not null,
+    attempt integer not null,
+    succeeded boolean not null,
+    response_status integer,
+    response_body text not null default '',
+    error text not null default '',
+    duration_ms integer not null
+);
+
+create index if not exists webhook_deliveries_endpoint_id_created_at_idx on webhook_deliveries (endpoint_id, created_at desc);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+drop table if exists webhook_deliveries;
+drop table if exists webhook_endpoints;
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+create table if not exists webmentions (
+    id uuid not null,
+    primary key (id),
+
+    created_at timestamp with time zone not null,
+    updated_at timestamp with time zone not null,
+
+    article_id integer not null references articles(id) on delete cascade,
+    source text not null,
+    target text not null,
+    status varchar(20) not null default 'pending',
+    author_name varchar(255) not null default '',
+    author_url text not null default '',
+    title text not null default '',
+    excerpt text not null default '',
+    verified_at timestamp with time zone,
+
+    unique (source, target)
+);
+
+create index if not exists webmentions_article_id_status_idx on webmentions (article_id, status);
+create index if not exists webmentions_status_created_at_idx on webmentions (status, created_at desc);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+drop table if exists webmentions;
+-- +goose StatementEnd
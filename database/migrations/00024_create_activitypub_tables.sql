@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+create table if not exists activitypub_keys (
+    name varchar(50) not null,
+    primary key (name),
+
+    created_at timestamp with time zone not null,
+
+    private_key_pem text not null,
+    public_key_pem text not null
+);
+
+create table if not exists activitypub_followers (
+    id uuid not null,
+    primary key (id),
+
+    created_at timestamp with time zone not null,
+    updated_at timestamp with time zone not null,
+
+    actor_id text not null unique,
+    inbox text not null,
+    shared_inbox text not null default '',
+    follow_activity_id text not null default ''
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+drop table if exists activitypub_followers;
+drop table if exists activitypub_keys;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+create table if not exists series (
+    id serial not null,
+    primary key (id),
+
+    created_at timestamp with time zone not null,
+    updated_at timestamp with time zone not null,
+
+    title varchar(255) not null,
+    slug varchar(255) not null unique,
+    description text not null default '',
+    published boolean not null default false
+);
+
+-- An article belongs to at most one series so "part N of M" is unambiguous.
+create table if not exists series_articles (
+    series_id integer not null references series(id) on delete cascade,
+    article_id integer not null references articles(id) on delete cascade,
+    primary key (series_id, article_id),
+
+    position integer not null,
+
+    unique (article_id)
+);
+
+create index if not exists idx_series_articles_position on series_articles (series_id, position);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+drop table if exists series_articles;
+drop table if exists series;
+-- +goose StatementEnd
@@ -0,0 +1,49 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+alter table tags
+    add column slug varchar(255),
+    add column description text not null default '',
+    add column meta_title varchar(255) not null default '',
+    add column meta_description varchar(255) not null default '';
+
+-- Existing tags get a slug from their title. Tags whose titles collapse to
+-- the same slug, or to nothing, are told apart by their id.
+with slugs as (
+    select
+        id,
+        trim(both '-' from regexp_replace(lower(title), '[^a-z0-9]+', '-', 'g')) as base
+    from tags
+), ranked as (
+    select
+        id,
+        base,
+        row_number() over (partition by base order by id) as n
+    from slugs
+)
+update tags
+    set slug = case
+        when ranked.base = '' then 'tag-' || tags.id
+        when ranked.n > 1 then ranked.base || '-' || tags.id
+        else ranked.base
+    end
+from ranked
+where ranked.id = tags.id;
+
+alter table tags
+    alter column slug set not null,
+    add constraint tags_slug_key unique (slug);
+
+create index if not exists idx_article_tag_connections_tag_id on article_tag_connections (tag_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+drop index if exists idx_article_tag_connections_tag_id;
+alter table tags
+    drop column if exists meta_description,
+    drop column if exists meta_title,
+    drop column if exists description,
+    drop column if exists slug;
+-- +goose StatementEnd
@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- Keep the oldest connection of every duplicated article/tag pair.
+delete from article_tag_connections
+using article_tag_connections as kept
+where article_tag_connections.article_id = kept.article_id
+and article_tag_connections.tag_id = kept.tag_id
+and article_tag_connections.id > kept.id;
+
+alter table article_tag_connections
+    add constraint article_tag_connections_article_id_tag_id_key unique (article_id, tag_id),
+    drop constraint article_tag_connections_article_id_fkey,
+    drop constraint article_tag_connections_tag_id_fkey,
+    add constraint article_tag_connections_article_id_fkey
+        foreign key (article_id) references articles(id) on delete cascade,
+    add constraint article_tag_connections_tag_id_fkey
+        foreign key (tag_id) references tags(id) on delete cascade;
+
+-- Children of a deleted tag move to the top level.
+alter table tags
+    add column parent_id integer references tags(id) on delete set null,
+    add constraint tags_parent_id_not_self check (parent_id <> id);
+
+create index if not exists idx_tags_parent_id on tags (parent_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+drop index if exists idx_tags_parent_id;
+alter table tags
+    drop constraint if exists tags_parent_id_not_self,
+    drop column if exists parent_id;
+
+alter table article_tag_connections
+    drop constraint article_tag_connections_article_id_tag_id_key,
+    drop constraint article_tag_connections_article_id_fkey,
+    drop constraint article_tag_connections_tag_id_fkey,
+    add constraint article_tag_connections_article_id_fkey
+        foreign key (article_id) references articles(id),
+    add constraint article_tag_connections_tag_id_fkey
+        foreign key (tag_id) references tags(id);
+-- +goose StatementEnd
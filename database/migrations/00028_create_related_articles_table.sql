@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- Precomputed by the refresh_related_articles job; each article keeps its
+-- best scoring matches.
+create table if not exists related_articles (
+    article_id integer not null references articles(id) on delete cascade,
+    related_article_id integer not null references articles(id) on delete cascade,
+    primary key (article_id, related_article_id),
+
+    score double precision not null,
+    computed_at timestamp with time zone not null
+);
+
+create index if not exists idx_related_articles_score on related_articles (article_id, score desc);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+drop table if exists related_articles;
+-- +goose StatementEnd
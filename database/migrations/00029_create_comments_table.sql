@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+alter table articles add column if not exists comments_open boolean not null default true;
+
+create table if not exists comments (
+    id uuid not null,
+    primary key (id),
+
+    created_at timestamp with time zone not null,
+    updated_at timestamp with time zone not null,
+
+    article_id integer not null references articles(id) on delete cascade,
+    -- Replies are removed with the comment they answer.
+    parent_id uuid references comments(id) on delete cascade,
+    author_name varchar(255) not null,
+    author_email varchar(255) not null,
+    content text not null,
+    status varchar(20) not null default 'unverified',
+    spam_score integer not null default 0,
+    verified_at timestamp with time zone
+);
+
+create index if not exists comments_article_id_status_idx on comments (article_id, status, created_at);
+create index if not exists comments_status_created_at_idx on comments (status, created_at desc);
+create index if not exists comments_parent_id_idx on comments (parent_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+drop table if exists comments;
+alter table articles drop column if exists comments_open;
+-- +goose StatementEnd
@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+create table if not exists media (
+    id uuid not null,
+    primary key (id),
+
+    created_at timestamp with time zone not null,
+    updated_at timestamp with time zone not null,
+
+    uploaded_by uuid references users(id) on delete set null,
+    filename varchar(255) not null,
+    alt_text varchar(500) not null default '',
+    width integer not null,
+    height integer not null
+);
+
+create index if not exists media_created_at_idx on media (created_at desc);
+
+-- Every upload is stored as a set of files: the re-encoded original plus
+-- smaller widths, each in the upload's format and as WebP. Keys are never
+-- reused, so the files can be cached forever.
+create table if not exists media_variants (
+    storage_key varchar(255) not null,
+    primary key (storage_key),
+
+    media_id uuid not null references media(id) on delete cascade,
+    content_type varchar(50) not null,
+    width integer not null,
+    height integer not null,
+    byte_size bigint not null
+);
+
+create index if not exists media_variants_media_id_idx on media_variants (media_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+drop table if exists media_variants;
+drop table if exists media;
+-- +goose StatementEnd
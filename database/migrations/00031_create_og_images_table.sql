@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- Generated Open Graph images, one per article, project or newsletter. The
+-- fingerprint covers everything drawn on the image, so a stale image is
+-- detected and rendered again on the next request.
+create table if not exists og_images (
+    subject_type varchar(20) not null,
+    subject_id integer not null,
+    primary key (subject_type, subject_id),
+
+    created_at timestamp with time zone not null,
+    updated_at timestamp with time zone not null,
+
+    fingerprint varchar(64) not null,
+    image bytea not null
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+drop table if exists og_images;
+-- +goose StatementEnd
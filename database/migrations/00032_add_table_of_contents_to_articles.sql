@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+alter table articles add column if not exists table_of_contents boolean not null default true;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+alter table articles drop column if exists table_of_contents;
+-- +goose StatementEnd
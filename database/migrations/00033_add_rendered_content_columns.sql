@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+alter table articles
+    add column if not exists content_html text not null default '',
+    add column if not exists content_hash varchar(64) not null default '',
+    add column if not exists content_headings jsonb not null default '[]'::jsonb;
+alter table projects
+    add column if not exists content_html text not null default '',
+    add column if not exists content_hash varchar(64) not null default '';
+alter table newsletters
+    add column if not exists content_html text not null default '',
+    add column if not exists content_hash varchar(64) not null default '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+alter table articles
+    drop column if exists content_html,
+    drop column if exists content_hash,
+    drop column if exists content_headings;
+alter table projects
+    drop column if exists content_html,
+    drop column if exists content_hash;
+alter table newsletters
+    drop column if exists content_html,
+    drop column if exists content_hash;
+-- +goose StatementEnd
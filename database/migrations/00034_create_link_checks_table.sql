@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+create table if not exists link_checks (
+    id uuid not null,
+    primary key (id),
+
+    created_at timestamp with time zone not null,
+    updated_at timestamp with time zone not null,
+
+    url text not null,
+    internal boolean not null,
+    broken boolean not null,
+    status_code integer not null default 0,
+    error text not null default '',
+    sources jsonb not null default '[]'::jsonb,
+    checked_at timestamp with time zone not null,
+    first_failed_at timestamp with time zone,
+
+    unique (url)
+);
+
+create index if not exists link_checks_broken_first_failed_at_idx on link_checks (broken, first_failed_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+drop table if exists link_checks;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+alter table articles
+    add column if not exists word_count integer not null default 0,
+    add column if not exists image_count integer not null default 0,
+    add column if not exists heading_count integer not null default 0,
+    add column if not exists estimated_read_time integer not null default 0,
+    add column if not exists read_time_override boolean not null default false;
+-- Read times were typed in by hand until now, so keep them as overrides.
+update articles set read_time_override = true where read_time > 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+alter table articles
+    drop column if exists word_count,
+    drop column if exists image_count,
+    drop column if exists heading_count,
+    drop column if exists estimated_read_time,
+    drop column if exists read_time_override;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- Setting actor_id to null when a user is deleted is an update, which the
+-- append-only trigger rejects. actor_email already records who acted.
+alter table audit_logs drop constraint if exists audit_logs_actor_id_fkey;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+update audit_logs set actor_id = null
+    where actor_id is not null and actor_id not in (select id from users);
+alter table audit_logs
+    add constraint audit_logs_actor_id_fkey
+    foreign key (actor_id) references users(id) on delete set null;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- name: QueryActivityPubKeyByName :one
+select * from activitypub_keys where name=$1;
+
+-- name: InsertActivityPubKey :exec
+insert into
+    activitypub_keys (name, created_at, private_key_pem, public_key_pem)
+values
+    ($1, now(), $2, $3)
+on conflict (name) do nothing;
+
+-- name: UpsertActivityPubFollower :one
+insert into
+    activitypub_followers (id, created_at, updated_at, actor_id, inbox, shared_inbox, follow_activity_id)
+values
+    ($1, now(), now(), $2, $3, $4, $5)
+on conflict (actor_id) do update set
+    updated_at=now(),
+    inbox=excluded.inbox,
+    shared_inbox=excluded.shared_inbox,
+    follow_activity_id=excluded.follow_activity_id
+returning *;
+
+-- name: DeleteActivityPubFollowerByActorID :execrows
+delete from activitypub_followers where actor_id=$1;
+
+-- name: QueryActivityPubFollowerInboxes :many
+select distinct coalesce(nullif(shared_inbox, ''), inbox)::text as inbox from activitypub_followers order by inbox;
+
+-- name: CountActivityPubFollowers :one
+select count(*) from activitypub_followers;
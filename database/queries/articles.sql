@@ -12,14 +12,14 @@ select * from articles where published=true order by first_published_at desc;
 
 -- name: InsertArticle :one
 insert into
-    articles (created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content)
+    articles (created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, read_time_override, estimated_read_time, word_count, image_count, heading_count, content, author_id)
 values
-    (now(), now(), $1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+    (now(), now(), $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 returning *;
 
 -- name: UpdateArticle :one
 update articles
-    set updated_at=now(), first_published_at=$2, published=$3, title=$4, excerpt=$5, meta_title=$6, meta_description=$7, slug=$8, image_link=$9, read_time=$10, content=$11
+    set updated_at=now(), first_published_at=$2, published=$3, title=$4, excerpt=$5, meta_title=$6, meta_description=$7, slug=$8, image_link=$9, read_time=$10, read_time_override=$11, estimated_read_time=$12, word_count=$13, image_count=$14, heading_count=$15, content=$16
 where id = $1
 returning *;
 
@@ -34,10 +34,52 @@ limit sqlc.arg('limit')::bigint offset sqlc.arg('offset')::bigint;
 -- name: CountArticles :one
 select count(*) from articles;
 
+-- name: QueryArticlesChange :one
+select
+    count(*)::bigint as total,
+    coalesce(max(updated_at), 'epoch'::timestamptz)::timestamptz as last_updated_at
+from articles;
+
 -- name: UpsertArticle :one
 insert into
-    articles (created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content)
+    articles (created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, read_time_override, estimated_read_time, word_count, image_count, heading_count, content, author_id, comments_open, table_of_contents)
 values
-    (now(), now(), $1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-on conflict (id) do update set updated_at=now(), first_published_at=excluded.first_published_at, published=excluded.published, title=excluded.title, excerpt=excluded.excerpt, meta_title=excluded.meta_title, meta_description=excluded.meta_description, slug=excluded.slug, image_link=excluded.image_link, read_time=excluded.read_time, content=excluded.content
+    (
+      coalesce(sqlc.narg('created_at')::timestamptz, now()),
+      coalesce(sqlc.narg('updated_at')::timestamptz, now()),
+      sqlc.arg('first_published_at'),
+      sqlc.arg('published'),
+      sqlc.arg('title'),
+      sqlc.arg('excerpt'),
+      sqlc.arg('meta_title'),
+      sqlc.arg('meta_description'),
+      sqlc.arg('slug'),
+      sqlc.arg('image_link'),
+      sqlc.arg('read_time'),
+      sqlc.arg('read_time_override'),
+      sqlc.arg('estimated_read_time'),
+      sqlc.arg('word_count'),
+      sqlc.arg('image_count'),
+      sqlc.arg('heading_count'),
+      sqlc.arg('content'),
+      sqlc.arg('author_id'),
+      sqlc.arg('comments_open'),
+      sqlc.arg('table_of_contents')
+    )
+on conflict (slug) do update set updated_at=now(), first_published_at=excluded.first_published_at, published=excluded.published, title=excluded.title, excerpt=excluded.excerpt, meta_title=excluded.meta_title, meta_description=excluded.meta_description, image_link=excluded.image_link, read_time=excluded.read_time, read_time_override=excluded.read_time_override, estimated_read_time=excluded.estimated_read_time, word_count=excluded.word_count, image_count=excluded.image_count, heading_count=excluded.heading_count, content=excluded.content, comments_open=excluded.comments_open, table_of_contents=excluded.table_of_contents
 returning *;
+
+-- name: UpdateArticleCommentsOpen :one
+update articles set updated_at=now(), comments_open=$2 where id = $1 returning *;
+
+-- name: UpdateArticleTableOfContents :one
+update articles set updated_at=now(), table_of_contents=$2 where id = $1 returning *;
+
+-- name: UpdateArticleContentHTML :one
+update articles set content_html=$2, content_hash=$3, content_headings=$4 where id = $1 returning *;
+
+-- name: UpdateArticleStats :exec
+update articles
+    set estimated_read_time=$2, word_count=$3, image_count=$4, heading_count=$5,
+    read_time=case when read_time_override then read_time else $2 end
+where id = $1;
@@ -0,0 +1,34 @@
+-- name: InsertAuditLog :one
+insert into
+    audit_logs (created_at, actor_id, actor_email, action, target_type, target_id, changes, ip_address, user_agent, request_id)
+values
+    (now(), $1, $2, $3, $4, $5, $6, $7, $8, $9)
+returning *;
+
+-- name: QueryFilteredAuditLogs :many
+select * from audit_logs
+where
+    (sqlc.narg('actor_email')::varchar is null or actor_email ilike '%' || sqlc.narg('actor_email')::varchar || '%')
+    and (sqlc.narg('action')::varchar is null or action = sqlc.narg('action')::varchar)
+    and (sqlc.narg('target_type')::varchar is null or target_type = sqlc.narg('target_type')::varchar)
+    and (sqlc.narg('target_id')::varchar is null or target_id = sqlc.narg('target_id')::varchar)
+    and (sqlc.narg('since')::timestamptz is null or created_at >= sqlc.narg('since')::timestamptz)
+    and (sqlc.narg('until')::timestamptz is null or created_at < sqlc.narg('until')::timestamptz)
+order by created_at desc, id desc
+limit sqlc.arg('limit')::bigint offset sqlc.arg('offset')::bigint;
+
+-- name: CountFilteredAuditLogs :one
+select count(*) from audit_logs
+where
+    (sqlc.narg('actor_email')::varchar is null or actor_email ilike '%' || sqlc.narg('actor_email')::varchar || '%')
+    and (sqlc.narg('action')::varchar is null or action = sqlc.narg('action')::varchar)
+    and (sqlc.narg('target_type')::varchar is null or target_type = sqlc.narg('target_type')::varchar)
+    and (sqlc.narg('target_id')::varchar is null or target_id = sqlc.narg('target_id')::varchar)
+    and (sqlc.narg('since')::timestamptz is null or created_at >= sqlc.narg('since')::timestamptz)
+    and (sqlc.narg('until')::timestamptz is null or created_at < sqlc.narg('until')::timestamptz);
+
+-- name: QueryAuditLogActions :many
+select distinct action from audit_logs order by action;
+
+-- name: DeleteAuditLogsBefore :execrows
+delete from audit_logs where created_at < sqlc.arg('before')::timestamptz;
@@ -0,0 +1,30 @@
+-- name: QueryCommentByID :one
+select * from comments where id=$1;
+
+-- name: QueryCommentsByStatus :many
+select * from comments
+where sqlc.narg('status')::varchar is null or status = sqlc.narg('status')::varchar
+order by created_at desc
+limit sqlc.arg('limit')::bigint;
+
+-- name: QueryCommentsByArticleIDAndStatus :many
+select * from comments where article_id=$1 and status=$2 order by created_at;
+
+-- name: InsertComment :one
+insert into
+    comments (id, created_at, updated_at, article_id, parent_id, author_name, author_email, content, status, spam_score)
+values
+    ($1, now(), now(), $2, $3, $4, $5, $6, 'unverified', $7)
+returning *;
+
+-- name: UpdateCommentVerified :one
+update comments
+    set updated_at=now(), verified_at=now(), status=$2
+where id = $1 and status = 'unverified'
+returning *;
+
+-- name: UpdateCommentStatus :one
+update comments set updated_at=now(), status=$2 where id = $1 returning *;
+
+-- name: DeleteComment :exec
+delete from comments where id=$1;
@@ -0,0 +1,33 @@
+-- name: QueryLinkChecks :many
+select * from link_checks
+where sqlc.narg('broken')::boolean is null or broken = sqlc.narg('broken')::boolean
+order by broken desc, first_failed_at nulls last, url
+limit sqlc.arg('limit')::bigint;
+
+-- name: UpsertLinkCheck :one
+insert into
+    link_checks (
+        id, created_at, updated_at, url, internal, broken, status_code, error,
+        sources, checked_at, first_failed_at
+    )
+values
+    (
+        $1, now(), now(), $2, $3, $4, $5, $6, $7, $8,
+        case when $4::boolean then $8::timestamptz end
+    )
+on conflict (url) do update set
+    updated_at=now(),
+    internal=excluded.internal,
+    broken=excluded.broken,
+    status_code=excluded.status_code,
+    error=excluded.error,
+    sources=excluded.sources,
+    checked_at=excluded.checked_at,
+    first_failed_at=case
+        when not excluded.broken then null
+        else coalesce(link_checks.first_failed_at, excluded.checked_at)
+    end
+returning *;
+
+-- name: DeleteLinkChecksCheckedBefore :execrows
+delete from link_checks where checked_at < $1;
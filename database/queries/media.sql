@@ -0,0 +1,30 @@
+-- name: QueryMediaByID :one
+select * from media where id=$1;
+
+-- name: QueryRecentMedia :many
+select * from media order by created_at desc limit sqlc.arg('limit')::bigint;
+
+-- name: QueryMediaVariantsByMediaIDs :many
+select * from media_variants
+where media_id = any(sqlc.arg('media_ids')::uuid[])
+order by media_id, width, content_type;
+
+-- name: InsertMedia :one
+insert into
+    media (id, created_at, updated_at, uploaded_by, filename, alt_text, width, height)
+values
+    ($1, now(), now(), $2, $3, $4, $5, $6)
+returning *;
+
+-- name: InsertMediaVariant :one
+insert into
+    media_variants (storage_key, media_id, content_type, width, height, byte_size)
+values
+    ($1, $2, $3, $4, $5, $6)
+returning *;
+
+-- name: UpdateMediaAltText :one
+update media set updated_at=now(), alt_text=$2 where id = $1 returning *;
+
+-- name: DeleteMedia :exec
+delete from media where id=$1;
@@ -35,6 +35,19 @@ select count(*) from newsletters;
 insert into
     newsletters (created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content)
 values
-    (now(), now(), $1, $2, $3, $4, $5, $6, $7)
-on conflict (id) do update set updated_at=now(), title=excluded.title, slug=excluded.slug, meta_title=excluded.meta_title, meta_description=excluded.meta_description, is_published=excluded.is_published, released_at=excluded.released_at, content=excluded.content
+    (
+      coalesce(sqlc.narg('created_at')::timestamptz, now()),
+      coalesce(sqlc.narg('updated_at')::timestamptz, now()),
+      sqlc.arg('title'),
+      sqlc.arg('slug'),
+      sqlc.arg('meta_title'),
+      sqlc.arg('meta_description'),
+      sqlc.arg('is_published'),
+      sqlc.arg('released_at'),
+      sqlc.arg('content')
+    )
+on conflict (slug) do update set updated_at=now(), title=excluded.title, meta_title=excluded.meta_title, meta_description=excluded.meta_description, is_published=excluded.is_published, released_at=excluded.released_at, content=excluded.content
 returning *;
+
+-- name: UpdateNewsletterContentHTML :one
+update newsletters set content_html=$2, content_hash=$3 where id = $1 returning *;
@@ -0,0 +1,13 @@
+-- name: QueryOGImage :one
+select * from og_images where subject_type=$1 and subject_id=$2;
+
+-- name: UpsertOGImage :one
+insert into
+    og_images (subject_type, subject_id, created_at, updated_at, fingerprint, image)
+values
+    ($1, $2, now(), now(), $3, $4)
+on conflict (subject_type, subject_id) do update set
+    updated_at=now(),
+    fingerprint=excluded.fingerprint,
+    image=excluded.image
+returning *;
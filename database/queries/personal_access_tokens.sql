@@ -0,0 +1,18 @@
+-- name: QueryPersonalAccessTokenByHash :one
+select * from personal_access_tokens where hash=$1;
+
+-- name: QueryPersonalAccessTokensByUserID :many
+select * from personal_access_tokens where user_id=$1 order by created_at desc;
+
+-- name: InsertPersonalAccessToken :one
+insert into
+    personal_access_tokens (id, created_at, updated_at, user_id, name, token_prefix, hash, scopes, expires_at)
+values
+    ($1, now(), now(), $2, $3, $4, $5, $6, $7)
+returning *;
+
+-- name: UpdatePersonalAccessTokenLastUsedAt :exec
+update personal_access_tokens set last_used_at=now() where id=$1;
+
+-- name: DeletePersonalAccessToken :exec
+delete from personal_access_tokens where id=$1 and user_id=$2;
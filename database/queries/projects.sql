@@ -22,7 +22,8 @@ insert into
       status,
       description,
       content,
-      project_url
+      project_url,
+      author_id
     )
 values
     (
@@ -35,7 +36,8 @@ values
       $5,
       $6,
       $7,
-      $8
+      $8,
+      $9
     )
 returning *;
 
@@ -77,20 +79,22 @@ insert into
       status,
       description,
       content,
-      project_url
+      project_url,
+      author_id
     )
 values
     (
-      now(),
-      now(),
-      $1,
-      $2,
-      $3,
-      $4,
-      $5,
-      $6,
-      $7,
-      $8
+      coalesce(sqlc.narg('created_at')::timestamptz, now()),
+      coalesce(sqlc.narg('updated_at')::timestamptz, now()),
+      sqlc.arg('published'),
+      sqlc.arg('title'),
+      sqlc.arg('slug'),
+      sqlc.arg('started_at'),
+      sqlc.arg('status'),
+      sqlc.arg('description'),
+      sqlc.arg('content'),
+      sqlc.arg('project_url'),
+      sqlc.arg('author_id')
     )
 on conflict (slug) do update
 set
@@ -103,3 +107,6 @@ set
     content=excluded.content,
     project_url=excluded.project_url
 returning *;
+
+-- name: UpdateProjectContentHTML :one
+update projects set content_html=$2, content_hash=$3 where id = $1 returning *;
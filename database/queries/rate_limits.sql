@@ -0,0 +1,56 @@
+-- name: InsertRateLimitHit :exec
+insert into
+    rate_limit_hits (created_at, scope, key)
+values
+    (now(), $1, $2);
+
+-- name: CountRateLimitHitsSince :one
+select count(*) from rate_limit_hits
+where scope=$1 and key=$2 and created_at > sqlc.arg('since')::timestamptz;
+
+-- name: DeleteRateLimitHitsBefore :exec
+delete from rate_limit_hits
+where scope=$1 and key=$2 and created_at <= sqlc.arg('before')::timestamptz;
+
+-- name: DeleteAllRateLimitHitsBefore :execrows
+delete from rate_limit_hits where created_at < sqlc.arg('before')::timestamptz;
+
+-- name: DeleteRateLimitHits :exec
+delete from rate_limit_hits where scope=$1 and key=$2;
+
+-- name: QueryActiveRateLimitBan :one
+select * from rate_limit_bans
+where scope=$1 and key=$2 and expires_at > now();
+
+-- name: QueryRateLimitBanByID :one
+select * from rate_limit_bans where id=$1;
+
+-- name: UpsertRateLimitBan :one
+insert into
+    rate_limit_bans (created_at, updated_at, scope, key, reason, expires_at)
+values
+    (now(), now(), $1, $2, $3, $4)
+on conflict (scope, key) do update set
+    created_at=case
+        when rate_limit_bans.expires_at > now() then rate_limit_bans.created_at
+        else now()
+    end,
+    updated_at=now(),
+    reason=excluded.reason,
+    expires_at=excluded.expires_at
+returning *;
+
+-- name: DeleteRateLimitBan :exec
+delete from rate_limit_bans where id=$1;
+
+-- name: QueryPaginatedActiveRateLimitBans :many
+select * from rate_limit_bans
+where expires_at > now()
+order by expires_at desc
+limit sqlc.arg('limit')::bigint offset sqlc.arg('offset')::bigint;
+
+-- name: CountActiveRateLimitBans :one
+select count(*) from rate_limit_bans where expires_at > now();
+
+-- name: DeleteExpiredRateLimitBans :execrows
+delete from rate_limit_bans where expires_at <= now();
@@ -0,0 +1,16 @@
+-- name: DeleteAllRelatedArticles :exec
+delete from related_articles;
+
+-- name: InsertRelatedArticle :exec
+insert into
+    related_articles (article_id, related_article_id, score, computed_at)
+values
+    ($1, $2, $3, now());
+
+-- name: QueryRelatedArticles :many
+select sqlc.embed(articles), related_articles.score from related_articles
+join articles on articles.id = related_articles.related_article_id
+where related_articles.article_id = $1
+and articles.published = true
+order by related_articles.score desc, articles.first_published_at desc
+limit sqlc.arg('limit')::bigint;
@@ -0,0 +1,54 @@
+-- name: QuerySeriesByID :one
+select * from series where id=$1;
+
+-- name: QuerySeriesBySlug :one
+select * from series where slug=$1;
+
+-- name: QuerySeries :many
+select * from series order by created_at desc;
+
+-- name: QueryPublishedSeries :many
+select * from series where published=true order by title;
+
+-- name: QuerySeriesForArticle :one
+select series.* from series
+join series_articles on series_articles.series_id = series.id
+where series_articles.article_id=$1;
+
+-- name: InsertSeries :one
+insert into
+    series (created_at, updated_at, title, slug, description, published)
+values
+    (now(), now(), $1, $2, $3, $4)
+returning *;
+
+-- name: UpdateSeries :one
+update series
+    set updated_at=now(), title=$2, slug=$3, description=$4, published=$5
+where id = $1
+returning *;
+
+-- name: DeleteSeries :exec
+delete from series where id=$1;
+
+-- name: QuerySeriesArticles :many
+select series_articles.position, sqlc.embed(articles) from series_articles
+join articles on articles.id = series_articles.article_id
+where series_articles.series_id=$1
+order by series_articles.position, articles.id;
+
+-- name: InsertSeriesArticle :exec
+insert into
+    series_articles (series_id, article_id, position)
+values
+    (
+        sqlc.arg('series_id'),
+        sqlc.arg('article_id'),
+        (select coalesce(max(position), 0) + 1 from series_articles where series_id = sqlc.arg('series_id'))
+    );
+
+-- name: UpdateSeriesArticlePosition :exec
+update series_articles set position=$3 where series_id=$1 and article_id=$2;
+
+-- name: DeleteSeriesArticle :exec
+delete from series_articles where series_id=$1 and article_id=$2;
@@ -1,19 +1,22 @@
 -- name: QueryTagByID :one
 select * from tags where id=$1;
 
+-- name: QueryTagBySlug :one
+select * from tags where slug=$1;
+
 -- name: QueryTags :many
 select * from tags;
 
 -- name: InsertTag :one
 insert into
-    tags (created_at, updated_at, title)
+    tags (created_at, updated_at, title, slug, description, meta_title, meta_description, parent_id)
 values
-    (now(), now(), $1)
+    (now(), now(), $1, $2, $3, $4, $5, $6)
 returning *;
 
 -- name: UpdateTag :one
 update tags
-    set updated_at=now(), title=$2
+    set updated_at=now(), title=$2, slug=$3, description=$4, meta_title=$5, meta_description=$6, parent_id=$7
 where id = $1
 returning *;
 
@@ -30,8 +33,88 @@ select count(*) from tags;
 
 -- name: UpsertTag :one
 insert into
-    tags (created_at, updated_at, title)
+    tags (created_at, updated_at, title, slug, description, meta_title, meta_description)
 values
-    (now(), now(), $1)
+    (now(), now(), $1, $2, $3, $4, $5)
 on conflict (id) do update set updated_at=now(), title=excluded.title
 returning *;
+
+-- name: QueryTagsForArticle :many
+select distinct tags.* from tags
+join article_tag_connections on article_tag_connections.tag_id = tags.id
+where article_tag_connections.article_id = $1
+order by tags.title;
+
+-- name: QueryPublishedTags :many
+with recursive tree (root_id, tag_id) as (
+    select id, id from tags
+    union
+    select tree.root_id, tags.id from tags
+    join tree on tags.parent_id = tree.tag_id
+)
+select sqlc.embed(tags), max(articles.updated_at)::timestamptz as last_article_update from tags
+join tree on tree.root_id = tags.id
+join article_tag_connections on article_tag_connections.tag_id = tree.tag_id
+join articles on articles.id = article_tag_connections.article_id
+where articles.published = true
+group by tags.id
+order by tags.title;
+
+-- name: QueryPaginatedPublishedArticlesForTag :many
+with recursive tree (tag_id) as (
+    select sqlc.arg('tag_id')::integer
+    union
+    select tags.id from tags
+    join tree on tags.parent_id = tree.tag_id
+)
+select articles.* from articles
+where articles.published = true
+and exists (
+    select 1 from article_tag_connections
+    join tree on tree.tag_id = article_tag_connections.tag_id
+    where article_tag_connections.article_id = articles.id
+)
+order by articles.first_published_at desc
+limit sqlc.arg('limit')::bigint offset sqlc.arg('offset')::bigint;
+
+-- name: CountPublishedArticlesForTag :one
+with recursive tree (tag_id) as (
+    select sqlc.arg('tag_id')::integer
+    union
+    select tags.id from tags
+    join tree on tags.parent_id = tree.tag_id
+)
+select count(*) from articles
+where articles.published = true
+and exists (
+    select 1 from article_tag_connections
+    join tree on tree.tag_id = article_tag_connections.tag_id
+    where article_tag_connections.article_id = articles.id
+);
+
+-- name: QueryChildTags :many
+select * from tags where parent_id=$1 order by title;
+
+-- name: QueryTagAncestorIDs :many
+with recursive ancestors (id, parent_id) as (
+    select tags.id, tags.parent_id from tags where tags.id = $1
+    union
+    select tags.id, tags.parent_id from tags
+    join ancestors on tags.id = ancestors.parent_id
+)
+select ancestors.id::integer from ancestors;
+
+-- name: MoveArticleTagConnections :exec
+insert into article_tag_connections (article_id, tag_id)
+select article_id, sqlc.arg('target_id')::integer from article_tag_connections
+where tag_id = sqlc.arg('source_id')::integer
+on conflict (article_id, tag_id) do nothing;
+
+-- name: DeleteArticleTagConnectionsForTag :exec
+delete from article_tag_connections where tag_id=$1;
+
+-- name: ReparentTags :exec
+update tags
+    set updated_at=now(), parent_id=sqlc.arg('new_parent_id')
+where parent_id = sqlc.arg('old_parent_id')::integer
+and id <> coalesce(sqlc.arg('new_parent_id'), 0);
@@ -9,9 +9,9 @@ select * from users;
 
 -- name: InsertUser :one
 insert into
-    users (id, created_at, updated_at, email, email_validated_at, password, is_admin)
+    users (id, created_at, updated_at, email, email_validated_at, password, is_admin, role)
 values
-    ($1, now(), now(), $2, $3, $4, $5)
+    ($1, now(), now(), $2, $3, $4, $5, $6)
 returning *;
 
 -- name: UpdateUser :one
@@ -20,6 +20,18 @@ update users
 where id = $1
 returning *;
 
+-- name: UpdateUserRole :one
+update users
+    set updated_at=now(), role=sqlc.arg('role'), is_admin=sqlc.arg('role')::varchar = 'admin'
+where id = $1
+returning *;
+
+-- name: UpdateUserDeactivatedAt :one
+update users
+    set updated_at=now(), deactivated_at=$2
+where id = $1
+returning *;
+
 -- name: DeleteUser :exec
 delete from users where id=$1;
 
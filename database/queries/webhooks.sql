@@ -0,0 +1,34 @@
+-- name: QueryWebhookEndpointByID :one
+select * from webhook_endpoints where id=$1;
+
+-- name: QueryWebhookEndpoints :many
+select * from webhook_endpoints order by created_at desc;
+
+-- name: QueryActiveWebhookEndpointsForEvent :many
+select * from webhook_endpoints where is_active and sqlc.arg('event')::text = any(events) order by created_at;
+
+-- name: InsertWebhookEndpoint :one
+insert into
+    webhook_endpoints (id, created_at, updated_at, name, url, secret, events, is_active)
+values
+    ($1, now(), now(), $2, $3, $4, $5, $6)
+returning *;
+
+-- name: UpdateWebhookEndpoint :one
+update webhook_endpoints
+    set updated_at=now(), name=$2, url=$3, events=$4, is_active=$5
+where id = $1
+returning *;
+
+-- name: DeleteWebhookEndpoint :exec
+delete from webhook_endpoints where id=$1;
+
+-- name: InsertWebhookDelivery :one
+insert into
+    webhook_deliveries (created_at, endpoint_id, event_id, event, attempt, succeeded, response_status, response_body, error, duration_ms)
+values
+    (now(), $1, $2, $3, $4, $5, $6, $7, $8, $9)
+returning *;
+
+-- name: QueryWebhookDeliveriesByEndpointID :many
+select * from webhook_deliveries where endpoint_id=$1 order by created_at desc, id desc limit $2;
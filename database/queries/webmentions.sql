@@ -0,0 +1,39 @@
+-- name: QueryWebmentionByID :one
+select * from webmentions where id=$1;
+
+-- name: QueryWebmentionsByStatus :many
+select * from webmentions
+where sqlc.narg('status')::varchar is null or status = sqlc.narg('status')::varchar
+order by created_at desc
+limit sqlc.arg('limit')::bigint;
+
+-- name: QueryWebmentionsByArticleIDAndStatus :many
+select * from webmentions where article_id=$1 and status=$2 order by created_at;
+
+-- name: UpsertWebmention :one
+insert into
+    webmentions (id, created_at, updated_at, article_id, source, target, status)
+values
+    ($1, now(), now(), $2, $3, $4, 'pending')
+on conflict (source, target) do update set
+    updated_at=now(),
+    article_id=excluded.article_id
+returning *;
+
+-- name: UpdateWebmentionVerified :one
+update webmentions
+    set updated_at=now(),
+        verified_at=now(),
+        status=case when status in ('approved', 'rejected') then status else 'review' end,
+        author_name=$2,
+        author_url=$3,
+        title=$4,
+        excerpt=$5
+where id = $1
+returning *;
+
+-- name: UpdateWebmentionStatus :one
+update webmentions set updated_at=now(), status=$2 where id = $1 returning *;
+
+-- name: DeleteWebmention :exec
+delete from webmentions where id=$1;
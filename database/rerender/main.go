@@ -0,0 +1,91 @@
+// Command rerender stores freshly rendered HTML for every article, project
+// and newsletter whose content changed since it was last rendered, or that
+// was rendered by an older markdown renderer. Run it after migrating or
+// deploying a renderer change; pass -force to render everything again, for
+// instance after an embedded project or article was renamed. It also
+// recounts the word, image and heading counts and estimated read time of
+// articles, which the migration adding them leaves at zero.
+//
+//	go run ./database/rerender
+//	go run ./database/rerender -force
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+	"mortenvistisen/config"
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models"
+	"mortenvistisen/services"
+)
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run() error {
+	godotenv.Load()
+
+	force := flag.Bool("force", false, "render all content, not only content that is stale")
+	flag.Parse()
+
+	config.BaseURL = config.ResolveBaseURL()
+
+	ctx := context.Background()
+
+	db, err := storage.NewConnection(ctx, buildDatabaseURL())
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	tx, err := db.Conn().Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	fmt.Println("Rendering content...")
+
+	result, err := services.RerenderContent(ctx, tx, *force)
+	if err != nil {
+		return fmt.Errorf("failed to render content: %w", err)
+	}
+
+	recounted, err := models.RecountArticleStats(ctx, tx)
+	if err != nil {
+		return fmt.Errorf("failed to count article stats: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit rendered content: %w", err)
+	}
+
+	fmt.Printf(
+		"Render complete, %d article(s), %d project(s) and %d newsletter(s) rendered, stats of %d article(s) recounted.\n",
+		result.Articles,
+		result.Projects,
+		result.Newsletters,
+		recounted,
+	)
+	return nil
+}
+
+func buildDatabaseURL() string {
+	return fmt.Sprintf("%s://%s:%s@%s:%s/%s?sslmode=%s",
+		os.Getenv("DB_KIND"),
+		os.Getenv("DB_USER"),
+		os.Getenv("DB_PASSWORD"),
+		os.Getenv("DB_HOST"),
+		os.Getenv("DB_PORT"),
+		os.Getenv("DB_NAME"),
+		os.Getenv("DB_SSL_MODE"),
+	)
+}
@@ -113,7 +113,7 @@ func ensureAdminUser(
 		validatedAt = time.Now().UTC()
 	}
 
-	return models.UpdateUser(ctx, exec, models.UpdateUserData{
+	if _, err := models.UpdateUser(ctx, exec, models.UpdateUserData{
 		ID:    existing.ID,
 		Email: adminEmail,
 		EmailValidatedAt: sql.NullTime{
@@ -122,7 +122,11 @@ func ensureAdminUser(
 		},
 		Password: existing.Password,
 		IsAdmin:  true,
-	})
+	}); err != nil {
+		return models.User{}, err
+	}
+
+	return models.UpdateUserRole(ctx, exec, existing.ID, models.RoleAdmin)
 }
 
 func seedTags(
@@ -217,7 +221,6 @@ func seedArticles(
 			MetaDescription:  fmt.Sprintf("Seeded article %d about %s.", articleNumber, topic),
 			Slug:             slug,
 			ImageLink:        fmt.Sprintf("https://picsum.photos/seed/article-%d/1200/675", articleNumber),
-			ReadTime:         int32(3 + (articleNumber % 10)),
 			Content: fmt.Sprintf(
 				"## %s\n\nThis seeded article exists to populate the admin UI with realistic content. It includes enough text for previews, table listings, and detail pages.\n\n### Key points\n\n- Focus on clear writing.\n- Keep scope practical.\n- Publish consistently.",
 				topic,
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gosimple/slug"
+	"mortenvistisen/internal/frontmatter"
+)
+
+type document struct {
+	Path    string
+	Meta    frontmatter.Meta
+	Content string
+}
+
+// loadDocuments reads every markdown file in dir. The slug falls back to
+// the file name when the front matter does not set one.
+func loadDocuments(dir string) ([]document, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.md"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	documents := make([]document, 0, len(paths))
+	seen := make(map[string]string, len(paths))
+	for _, path := range paths {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		meta, content, err := frontmatter.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		if meta.Slug == "" {
+			meta.Slug = slug.Make(strings.TrimSuffix(filepath.Base(path), ".md"))
+		}
+		if meta.Title == "" {
+			return nil, fmt.Errorf("%s: front matter is missing a title", path)
+		}
+		if other, ok := seen[meta.Slug]; ok {
+			return nil, fmt.Errorf("%s: slug %q is already used by %s", path, meta.Slug, other)
+		}
+		seen[meta.Slug] = path
+
+		documents = append(documents, document{Path: path, Meta: meta, Content: content})
+	}
+
+	return documents, nil
+}
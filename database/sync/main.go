@@ -0,0 +1,627 @@
+// Command sync keeps articles, projects and newsletters in step with a
+// directory of markdown files so content can live in git.
+//
+// The directory is expected to contain "articles", "projects" and
+// "newsletters" subdirectories; a missing subdirectory leaves that content
+// type untouched. Files are matched to rows by slug. Rows without a matching
+// file are reported and only deleted when -prune is passed. Syncing never
+// schedules release emails.
+//
+//	go run ./database/sync -dir content -dry-run
+//	go run ./database/sync -dir content -prune -author admin@example.com
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/joho/godotenv"
+	"mortenvistisen/config"
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models"
+	"mortenvistisen/services"
+)
+
+type options struct {
+	dir      string
+	dryRun   bool
+	prune    bool
+	authorID uuid.UUID
+	authors  map[string]uuid.UUID
+}
+
+type changeKind string
+
+const (
+	changeCreate  changeKind = "+ create"
+	changeUpdate  changeKind = "~ update"
+	changeDelete  changeKind = "- delete"
+	changeMissing changeKind = "- missing"
+)
+
+type change struct {
+	Kind   changeKind
+	Slug   string
+	Fields []string
+}
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run() error {
+	godotenv.Load()
+
+	dir := flag.String("dir", "content", "directory holding articles/, projects/ and newsletters/")
+	dryRun := flag.Bool("dry-run", false, "print the changes without writing them")
+	prune := flag.Bool("prune", false, "delete rows that have no matching file")
+	authorEmail := flag.String("author", "", "email of the user set as author on new articles and projects")
+	flag.Parse()
+
+	config.BaseURL = config.ResolveBaseURL()
+
+	ctx := context.Background()
+
+	db, err := storage.NewConnection(ctx, buildDatabaseURL())
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	opts := options{
+		dir:     *dir,
+		dryRun:  *dryRun,
+		prune:   *prune,
+		authors: make(map[string]uuid.UUID),
+	}
+	if *authorEmail != "" {
+		author, err := models.FindUserByEmail(ctx, db.Conn(), *authorEmail)
+		if err != nil {
+			return fmt.Errorf("failed to find author %s: %w", *authorEmail, err)
+		}
+		opts.authorID = author.ID
+	}
+
+	tx, err := db.Conn().Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tags, err := newTagResolver(ctx, tx)
+	if err != nil {
+		return fmt.Errorf("failed to load tags: %w", err)
+	}
+
+	if opts.dryRun {
+		fmt.Printf("Syncing %s (dry run)...\n", opts.dir)
+	} else {
+		fmt.Printf("Syncing %s...\n", opts.dir)
+	}
+
+	syncers := []struct {
+		name string
+		sync func(context.Context, pgx.Tx, options, []document) ([]change, error)
+	}{
+		{"articles", func(ctx context.Context, tx pgx.Tx, opts options, docs []document) ([]change, error) {
+			return syncArticles(ctx, tx, opts, tags, docs)
+		}},
+		{"projects", syncProjects},
+		{"newsletters", syncNewsletters},
+	}
+
+	total := 0
+	for _, s := range syncers {
+		dir := filepath.Join(opts.dir, s.name)
+		if info, statErr := os.Stat(dir); statErr != nil || !info.IsDir() {
+			fmt.Printf("%s: skipped, %s not found\n", s.name, dir)
+			continue
+		}
+
+		docs, err := loadDocuments(dir)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", s.name, err)
+		}
+
+		changes, err := s.sync(ctx, tx, opts, docs)
+		if err != nil {
+			return fmt.Errorf("failed to sync %s: %w", s.name, err)
+		}
+
+		printChanges(s.name, len(docs), changes)
+		total += len(changes)
+	}
+
+	for _, title := range tags.created {
+		fmt.Printf("tags: + create  %s\n", title)
+	}
+
+	if opts.dryRun {
+		fmt.Printf("Dry run complete, %d change(s) not applied.\n", total)
+		return nil
+	}
+
+	if _, err := services.RerenderContent(ctx, tx, false); err != nil {
+		return fmt.Errorf("failed to render content: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit sync: %w", err)
+	}
+
+	fmt.Printf("Sync complete, %d change(s) applied.\n", total)
+	return nil
+}
+
+func syncArticles(
+	ctx context.Context,
+	tx pgx.Tx,
+	opts options,
+	tags *tagResolver,
+	docs []document,
+) ([]change, error) {
+	existing, err := models.AllArticles(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	bySlug := make(map[string]models.Article, len(existing))
+	for _, article := range existing {
+		bySlug[article.Slug] = article
+	}
+
+	var changes []change
+	for _, doc := range docs {
+		meta := doc.Meta
+		current, found := bySlug[meta.Slug]
+
+		data := models.CreateArticleData{
+			FirstPublishedAt: meta.Date,
+			Published:        meta.Published,
+			Title:            meta.Title,
+			Excerpt:          meta.Excerpt,
+			MetaTitle:        fallback(meta.MetaTitle, meta.Title),
+			MetaDescription:  fallback(meta.MetaDescription, meta.Excerpt),
+			Slug:             meta.Slug,
+			ImageLink:        meta.ImageLink,
+			ReadTime:         meta.ReadTime,
+			Content:          doc.Content,
+			CreatedAt:        meta.CreatedAt,
+			UpdatedAt:        meta.UpdatedAt,
+		}
+		data.AuthorID, err = opts.author(ctx, tx, meta.Author)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", doc.Path, err)
+		}
+		if data.FirstPublishedAt.IsZero() && found {
+			data.FirstPublishedAt = current.FirstPublishedAt
+		}
+		// Left out settings keep their current value, and new articles
+		// start with both on like the columns do.
+		data.CommentsOpen = optionalBool(meta.CommentsOpen, !found || current.CommentsOpen)
+		data.TableOfContents = optionalBool(meta.TableOfContents, !found || current.TableOfContents)
+
+		tagIDs, err := tags.resolve(ctx, tx, meta.Tags, opts.dryRun)
+		if err != nil {
+			return nil, err
+		}
+
+		if !found {
+			changes = append(changes, change{Kind: changeCreate, Slug: meta.Slug})
+		} else {
+			currentTagIDs, err := models.TagIDsForArticle(ctx, tx, current.ID)
+			if err != nil {
+				return nil, err
+			}
+
+			var fields []string
+			fields = diffField(fields, "title", current.Title, data.Title)
+			fields = diffField(fields, "excerpt", current.Excerpt, data.Excerpt)
+			fields = diffField(fields, "meta_title", current.MetaTitle, data.MetaTitle)
+			fields = diffField(fields, "meta_description", current.MetaDescription, data.MetaDescription)
+			fields = diffField(fields, "image_link", current.ImageLink, data.ImageLink)
+			fields = diffField(fields, "read_time", articleReadTimeOverride(current), data.ReadTime)
+			fields = diffField(fields, "published", current.Published, data.Published)
+			fields = diffField(fields, "comments_open", current.CommentsOpen, data.CommentsOpen)
+			fields = diffField(fields, "table_of_contents", current.TableOfContents, data.TableOfContents)
+			fields = diffTime(fields, "date", current.FirstPublishedAt, data.FirstPublishedAt)
+			fields = diffField(fields, "content", current.Content, data.Content)
+			fields = diffTags(fields, currentTagIDs, tagIDs)
+			if len(fields) == 0 {
+				continue
+			}
+			changes = append(changes, change{Kind: changeUpdate, Slug: meta.Slug, Fields: fields})
+		}
+
+		if opts.dryRun {
+			continue
+		}
+
+		article, err := models.UpsertArticle(ctx, tx, data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", doc.Path, err)
+		}
+		if err := models.ReplaceTagsForArticle(ctx, tx, article.ID, tagIDs); err != nil {
+			return nil, fmt.Errorf("%s: %w", doc.Path, err)
+		}
+	}
+
+	for _, article := range existing {
+		if hasDocument(docs, article.Slug) {
+			continue
+		}
+
+		changes = append(changes, deletion(opts, article.Slug))
+		if opts.dryRun || !opts.prune {
+			continue
+		}
+		if err := models.DestroyArticle(ctx, tx, article.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return changes, nil
+}
+
+func syncProjects(
+	ctx context.Context,
+	tx pgx.Tx,
+	opts options,
+	docs []document,
+) ([]change, error) {
+	existing, err := models.AllProjects(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	bySlug := make(map[string]models.Project, len(existing))
+	for _, project := range existing {
+		bySlug[project.Slug] = project
+	}
+
+	var changes []change
+	for _, doc := range docs {
+		meta := doc.Meta
+		current, found := bySlug[meta.Slug]
+
+		data := models.CreateProjectData{
+			Published:   meta.Published,
+			Title:       meta.Title,
+			Slug:        meta.Slug,
+			StartedAt:   meta.Date,
+			Status:      meta.Status,
+			Description: meta.Excerpt,
+			Content:     doc.Content,
+			ProjectURL:  meta.ProjectURL,
+			CreatedAt:   meta.CreatedAt,
+			UpdatedAt:   meta.UpdatedAt,
+		}
+		data.AuthorID, err = opts.author(ctx, tx, meta.Author)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", doc.Path, err)
+		}
+		if data.StartedAt.IsZero() && found {
+			data.StartedAt = current.StartedAt
+		}
+		if data.Status == "" {
+			data.Status = "Planned"
+			if found {
+				data.Status = current.Status
+			}
+		}
+
+		if !found {
+			changes = append(changes, change{Kind: changeCreate, Slug: meta.Slug})
+		} else {
+			var fields []string
+			fields = diffField(fields, "title", current.Title, data.Title)
+			fields = diffField(fields, "excerpt", current.Description, data.Description)
+			fields = diffField(fields, "status", current.Status, data.Status)
+			fields = diffField(fields, "project_url", current.ProjectURL, data.ProjectURL)
+			fields = diffField(fields, "published", current.Published, data.Published)
+			fields = diffTime(fields, "date", current.StartedAt, data.StartedAt)
+			fields = diffField(fields, "content", current.Content, data.Content)
+			if len(fields) == 0 {
+				continue
+			}
+			changes = append(changes, change{Kind: changeUpdate, Slug: meta.Slug, Fields: fields})
+		}
+
+		if opts.dryRun {
+			continue
+		}
+
+		if _, err := models.UpsertProject(ctx, tx, data); err != nil {
+			return nil, fmt.Errorf("%s: %w", doc.Path, err)
+		}
+	}
+
+	for _, project := range existing {
+		if hasDocument(docs, project.Slug) {
+			continue
+		}
+
+		changes = append(changes, deletion(opts, project.Slug))
+		if opts.dryRun || !opts.prune {
+			continue
+		}
+		if err := models.DestroyProject(ctx, tx, project.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return changes, nil
+}
+
+func syncNewsletters(
+	ctx context.Context,
+	tx pgx.Tx,
+	opts options,
+	docs []document,
+) ([]change, error) {
+	existing, err := models.AllNewsletters(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	bySlug := make(map[string]models.Newsletter, len(existing))
+	for _, newsletter := range existing {
+		bySlug[newsletter.Slug] = newsletter
+	}
+
+	var changes []change
+	for _, doc := range docs {
+		meta := doc.Meta
+		current, found := bySlug[meta.Slug]
+
+		if found && current.IsPublished && !meta.Published {
+			return nil, fmt.Errorf("%s: %w", doc.Path, models.ErrNewsletterCannotBeUnpublished)
+		}
+
+		data := models.CreateNewsletterData{
+			Title:           meta.Title,
+			Slug:            meta.Slug,
+			MetaTitle:       fallback(meta.MetaTitle, meta.Title),
+			MetaDescription: fallback(meta.MetaDescription, meta.Excerpt),
+			IsPublished:     meta.Published,
+			ReleasedAt:      meta.Date,
+			Content:         doc.Content,
+			CreatedAt:       meta.CreatedAt,
+			UpdatedAt:       meta.UpdatedAt,
+		}
+		if data.ReleasedAt.IsZero() && found {
+			data.ReleasedAt = current.ReleasedAt
+		}
+		if data.ReleasedAt.IsZero() && data.IsPublished {
+			data.ReleasedAt = time.Now().UTC()
+		}
+
+		if !found {
+			changes = append(changes, change{Kind: changeCreate, Slug: meta.Slug})
+		} else {
+			var fields []string
+			fields = diffField(fields, "title", current.Title, data.Title)
+			fields = diffField(fields, "meta_title", current.MetaTitle, data.MetaTitle)
+			fields = diffField(fields, "meta_description", current.MetaDescription, data.MetaDescription)
+			fields = diffField(fields, "published", current.IsPublished, data.IsPublished)
+			fields = diffTime(fields, "date", current.ReleasedAt, data.ReleasedAt)
+			fields = diffField(fields, "content", current.Content, data.Content)
+			if len(fields) == 0 {
+				continue
+			}
+			changes = append(changes, change{Kind: changeUpdate, Slug: meta.Slug, Fields: fields})
+		}
+
+		if opts.dryRun {
+			continue
+		}
+
+		if _, err := models.UpsertNewsletter(ctx, tx, data); err != nil {
+			return nil, fmt.Errorf("%s: %w", doc.Path, err)
+		}
+	}
+
+	for _, newsletter := range existing {
+		if hasDocument(docs, newsletter.Slug) {
+			continue
+		}
+
+		changes = append(changes, deletion(opts, newsletter.Slug))
+		if opts.dryRun || !opts.prune {
+			continue
+		}
+		if err := models.DestroyNewsletter(ctx, tx, newsletter.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return changes, nil
+}
+
+// author returns the user a new row is attributed to: the author named in
+// the front matter when that user exists, otherwise the -author user.
+// Existing rows keep their author.
+func (o options) author(
+	ctx context.Context,
+	exec storage.Executor,
+	email string,
+) (uuid.UUID, error) {
+	if email == "" {
+		return o.authorID, nil
+	}
+	if id, ok := o.authors[email]; ok {
+		return id, nil
+	}
+
+	user, err := models.FindUserByEmail(ctx, exec, email)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, err
+	}
+
+	id := o.authorID
+	if err == nil {
+		id = user.ID
+	}
+	o.authors[email] = id
+
+	return id, nil
+}
+
+// tagResolver maps front matter tag names onto tag rows, matching titles
+// case-insensitively and creating tags that do not exist yet.
+type tagResolver struct {
+	byTitle map[string]models.Tag
+	created []string
+	nextID  int32
+}
+
+func newTagResolver(ctx context.Context, exec storage.Executor) (*tagResolver, error) {
+	tags, err := models.AllTags(ctx, exec)
+	if err != nil {
+		return nil, err
+	}
+
+	byTitle := make(map[string]models.Tag, len(tags))
+	for _, tag := range tags {
+		key := strings.ToLower(tag.Title)
+		if _, exists := byTitle[key]; !exists {
+			byTitle[key] = tag
+		}
+	}
+
+	return &tagResolver{byTitle: byTitle}, nil
+}
+
+func (r *tagResolver) resolve(
+	ctx context.Context,
+	exec storage.Executor,
+	titles []string,
+	dryRun bool,
+) ([]int32, error) {
+	ids := make([]int32, 0, len(titles))
+	for _, title := range titles {
+		title = strings.TrimSpace(title)
+		if title == "" {
+			continue
+		}
+
+		key := strings.ToLower(title)
+		tag, ok := r.byTitle[key]
+		if !ok {
+			if dryRun {
+				// Placeholder IDs keep new tags visible in the diff without
+				// touching the database.
+				r.nextID--
+				tag = models.Tag{ID: r.nextID, Title: title}
+			} else {
+				created, err := models.CreateTag(ctx, exec, models.CreateTagData{Title: title})
+				if err != nil {
+					return nil, fmt.Errorf("failed to create tag %q: %w", title, err)
+				}
+				tag = created
+			}
+			r.byTitle[key] = tag
+			r.created = append(r.created, title)
+		}
+
+		if !slices.Contains(ids, tag.ID) {
+			ids = append(ids, tag.ID)
+		}
+	}
+
+	return ids, nil
+}
+
+func diffField[T comparable](fields []string, name string, before, after T) []string {
+	if before != after {
+		return append(fields, name)
+	}
+	return fields
+}
+
+func diffTime(fields []string, name string, before, after time.Time) []string {
+	if !before.Truncate(time.Microsecond).Equal(after.Truncate(time.Microsecond)) {
+		return append(fields, name)
+	}
+	return fields
+}
+
+func diffTags(fields []string, before, after []int32) []string {
+	before = slices.Sorted(slices.Values(before))
+	after = slices.Sorted(slices.Values(after))
+	if !slices.Equal(slices.Compact(before), slices.Compact(after)) {
+		return append(fields, "tags")
+	}
+	return fields
+}
+
+func hasDocument(docs []document, slug string) bool {
+	return slices.ContainsFunc(docs, func(doc document) bool {
+		return doc.Meta.Slug == slug
+	})
+}
+
+func deletion(opts options, slug string) change {
+	if opts.prune {
+		return change{Kind: changeDelete, Slug: slug}
+	}
+	return change{Kind: changeMissing, Slug: slug}
+}
+
+func printChanges(name string, files int, changes []change) {
+	fmt.Printf("%s: %d file(s), %d change(s)\n", name, files, len(changes))
+	for _, c := range changes {
+		switch {
+		case len(c.Fields) > 0:
+			fmt.Printf("  %s  %s (%s)\n", c.Kind, c.Slug, strings.Join(c.Fields, ", "))
+		case c.Kind == changeMissing:
+			fmt.Printf("  %s  %s (no file, pass -prune to delete)\n", c.Kind, c.Slug)
+		default:
+			fmt.Printf("  %s  %s\n", c.Kind, c.Slug)
+		}
+	}
+}
+
+// articleReadTimeOverride is the read_time front matter that matches an
+// article: its read time when set by hand, otherwise zero for the estimate.
+func articleReadTimeOverride(article models.Article) int32 {
+	if article.ReadTimeOverride {
+		return article.ReadTime
+	}
+	return 0
+}
+
+func fallback(value, def string) string {
+	if value != "" {
+		return value
+	}
+	return def
+}
+
+func optionalBool(value *bool, def bool) bool {
+	if value != nil {
+		return *value
+	}
+	return def
+}
+
+func buildDatabaseURL() string {
+	return fmt.Sprintf("%s://%s:%s@%s:%s/%s?sslmode=%s",
+		os.Getenv("DB_KIND"),
+		os.Getenv("DB_USER"),
+		os.Getenv("DB_PASSWORD"),
+		os.Getenv("DB_HOST"),
+		os.Getenv("DB_PORT"),
+		os.Getenv("DB_NAME"),
+		os.Getenv("DB_SSL_MODE"),
+	)
+}
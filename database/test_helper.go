@@ -2,11 +2,12 @@ package database
 
 import (
 	"context"
-	"database/sql"
+	"errors"
 	"fmt"
-	"log"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -46,7 +47,7 @@ func NewTestDB() (*TestDB, error) {
 		return nil, fmt.Errorf("failed to get connection string: %w", err)
 	}
 
-	if err := runMigrations("postgres", dsn); err != nil {
+	if err := runMigrations(ctx, dsn); err != nil {
 		pgContainer.Terminate(ctx)
 		return nil, fmt.Errorf("failed to run migrations: %w", err)
 	}
@@ -72,6 +73,37 @@ func (tdb *TestDB) Close() error {
 	return nil
 }
 
+var (
+	sharedOnce sync.Once
+	shared     *TestDB
+	errShared  error
+)
+
+// RequireTestDB returns a database shared by the tests of a package and
+// starts it on first use. It skips the test when the database cannot be
+// started, for instance without Docker. Packages using it call
+// CloseSharedTestDB from TestMain.
+func RequireTestDB(t *testing.T) *TestDB {
+	t.Helper()
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	sharedOnce.Do(func() {
+		shared, errShared = NewTestDB()
+	})
+	if errShared != nil {
+		t.Skipf("test database unavailable: %v", errShared)
+	}
+
+	return shared
+}
+
+// CloseSharedTestDB stops the database started by RequireTestDB, if any.
+func CloseSharedTestDB() {
+	if shared != nil {
+		shared.Close()
+	}
+}
+
 func (tdb *TestDB) WithTx(t *testing.T, fn func(tx pgx.Tx)) {
 	t.Helper()
 	ctx := context.Background()
@@ -92,25 +124,20 @@ func (tdb *TestDB) WithTx(t *testing.T, fn func(tx pgx.Tx)) {
 	fn(tx)
 }
 
-func runMigrations(driver, dsn string) error {
-	wd, err := os.Getwd()
+// runMigrations applies the Up section of every migration in order. Tests
+// run from their package directory, so the migrations are found from the
+// module root.
+func runMigrations(ctx context.Context, dsn string) error {
+	migrationsDir, err := findMigrationsDir()
 	if err != nil {
-		return fmt.Errorf("failed to get working directory: %w", err)
+		return err
 	}
 
-	migrationsDir := filepath.Join(wd, "database", "migrations")
-
-	if _, err := os.Stat(migrationsDir); os.IsNotExist(err) {
-		cwd, _ := os.Getwd()
-		log.Printf("Warning: migrations directory not found at %s (cwd: %s)", migrationsDir, cwd)
-		return nil
-	}
-
-	db, err := sql.Open(driver, dsn)
+	conn, err := pgx.Connect(ctx, dsn)
 	if err != nil {
-		return fmt.Errorf("failed to open database: %w", err)
+		return fmt.Errorf("failed to connect to database: %w", err)
 	}
-	defer db.Close()
+	defer conn.Close(ctx)
 
 	files, err := filepath.Glob(filepath.Join(migrationsDir, "*.sql"))
 	if err != nil {
@@ -123,10 +150,32 @@ func runMigrations(driver, dsn string) error {
 			return fmt.Errorf("failed to read migration %s: %w", file, err)
 		}
 
-		if _, err := db.Exec(string(content)); err != nil {
+		// Without arguments pgx uses the simple protocol, which runs every
+		// statement in the section.
+		up, _, _ := strings.Cut(string(content), "-- +goose Down")
+		if _, err := conn.Exec(ctx, up); err != nil {
 			return fmt.Errorf("failed to execute migration %s: %w", file, err)
 		}
 	}
 
 	return nil
 }
+
+func findMigrationsDir() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return filepath.Join(dir, "database", "migrations"), nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", errors.New("could not find the module root")
+		}
+		dir = parent
+	}
+}
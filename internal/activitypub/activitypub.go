@@ -0,0 +1,323 @@
+// Package activitypub implements the parts of ActivityPub the blog needs to
+// federate: the JSON shapes of actors and activities, fetching remote actors,
+// and signing and verifying requests with HTTP signatures.
+package activitypub
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	// ContentType is sent with every ActivityPub document.
+	ContentType = "application/activity+json"
+	// LDContentType is the other media type servers accept documents as.
+	LDContentType = `application/ld+json; profile="https://www.w3.org/ns/activitystreams"`
+
+	ActivityStreamsContext = "https://www.w3.org/ns/activitystreams"
+	PublicCollection       = "https://www.w3.org/ns/activitystreams#Public"
+
+	maxDocumentSize = 1 << 20
+)
+
+// ErrActorMismatch is returned when an actor document claims an id other
+// than the URL it was fetched from. Without the check any server could serve
+// a document, and its key, in the name of an actor on another server.
+var ErrActorMismatch = errors.New("actor id does not match its url")
+
+// ActorContext is the JSON-LD context of actor documents, which carry a
+// public key.
+var ActorContext = []string{
+	ActivityStreamsContext,
+	"https://w3id.org/security/v1",
+}
+
+type PublicKey struct {
+	ID           string `json:"id"`
+	Owner        string `json:"owner"`
+	PublicKeyPem string `json:"publicKeyPem"`
+}
+
+type Endpoints struct {
+	SharedInbox string `json:"sharedInbox,omitempty"`
+}
+
+type Image struct {
+	Type      string `json:"type"`
+	MediaType string `json:"mediaType,omitempty"`
+	URL       string `json:"url"`
+}
+
+type Actor struct {
+	Context                   any        `json:"@context,omitempty"`
+	ID                        string     `json:"id"`
+	Type                      string     `json:"type"`
+	PreferredUsername         string     `json:"preferredUsername"`
+	Name                      string     `json:"name,omitempty"`
+	Summary                   string     `json:"summary,omitempty"`
+	URL                       string     `json:"url,omitempty"`
+	Icon                      *Image     `json:"icon,omitempty"`
+	Inbox                     string     `json:"inbox"`
+	Outbox                    string     `json:"outbox,omitempty"`
+	Followers                 string     `json:"followers,omitempty"`
+	Endpoints                 *Endpoints `json:"endpoints,omitempty"`
+	PublicKey                 PublicKey  `json:"publicKey"`
+	ManuallyApprovesFollowers bool       `json:"manuallyApprovesFollowers"`
+	Discoverable              bool       `json:"discoverable"`
+}
+
+// SharedInbox returns the actor's shared inbox, falling back to its own.
+func (a Actor) SharedInbox() string {
+	if a.Endpoints != nil && a.Endpoints.SharedInbox != "" {
+		return a.Endpoints.SharedInbox
+	}
+
+	return a.Inbox
+}
+
+type Object struct {
+	Context      any      `json:"@context,omitempty"`
+	ID           string   `json:"id"`
+	Type         string   `json:"type"`
+	AttributedTo string   `json:"attributedTo"`
+	Name         string   `json:"name,omitempty"`
+	Summary      string   `json:"summary,omitempty"`
+	Content      string   `json:"content"`
+	URL          string   `json:"url,omitempty"`
+	Published    string   `json:"published"`
+	Updated      string   `json:"updated,omitempty"`
+	To           []string `json:"to"`
+	Cc           []string `json:"cc,omitempty"`
+	Image        *Image   `json:"image,omitempty"`
+}
+
+// Activity is an incoming or outgoing activity. Object stays raw because it
+// is a URL for some activities and an embedded document for others.
+type Activity struct {
+	Context any             `json:"@context,omitempty"`
+	ID      string          `json:"id"`
+	Type    string          `json:"type"`
+	Actor   string          `json:"actor"`
+	Object  json.RawMessage `json:"object"`
+	To      []string        `json:"to,omitempty"`
+	Cc      []string        `json:"cc,omitempty"`
+}
+
+// ObjectID returns the id of the activity's object whether it was sent as a
+// URL or embedded.
+func (a Activity) ObjectID() string {
+	var id string
+	if err := json.Unmarshal(a.Object, &id); err == nil {
+		return id
+	}
+
+	var object struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(a.Object, &object); err == nil {
+		return object.ID
+	}
+
+	return ""
+}
+
+// ObjectType returns the type of an embedded object, or "" when the object
+// is a bare URL.
+func (a Activity) ObjectType() string {
+	var object struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(a.Object, &object); err != nil {
+		return ""
+	}
+
+	return object.Type
+}
+
+type OrderedCollection struct {
+	Context      any    `json:"@context,omitempty"`
+	ID           string `json:"id"`
+	Type         string `json:"type"`
+	TotalItems   int    `json:"totalItems"`
+	OrderedItems []any  `json:"orderedItems,omitempty"`
+}
+
+// FetchActor retrieves the actor document at actorURL. The request is signed
+// when signer is non-nil, which servers running in authorized fetch mode
+// require. Documents whose id is not actorURL are rejected with
+// ErrActorMismatch.
+func FetchActor(
+	ctx context.Context,
+	client *http.Client,
+	signer *Signer,
+	actorURL string,
+) (Actor, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, actorURL, nil)
+	if err != nil {
+		return Actor{}, err
+	}
+	req.Header.Set("Accept", ContentType+", "+LDContentType)
+
+	if signer != nil {
+		if err := signer.Sign(req, nil); err != nil {
+			return Actor{}, err
+		}
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return Actor{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return Actor{}, &StatusError{URL: actorURL, StatusCode: res.StatusCode}
+	}
+
+	var actor Actor
+	if err := json.NewDecoder(io.LimitReader(res.Body, maxDocumentSize)).Decode(&actor); err != nil {
+		return Actor{}, fmt.Errorf("decoding actor %s: %w", actorURL, err)
+	}
+	if actor.ID == "" || actor.Inbox == "" {
+		return Actor{}, fmt.Errorf("actor %s has no id or inbox", actorURL)
+	}
+	if actor.ID != actorURL {
+		return Actor{}, fmt.Errorf("%w: fetched %s, got %s", ErrActorMismatch, actorURL, actor.ID)
+	}
+
+	return actor, nil
+}
+
+// Deliver POSTs a signed activity to inbox.
+func Deliver(
+	ctx context.Context,
+	client *http.Client,
+	signer Signer,
+	inbox string,
+	activity []byte,
+) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, inbox, bytes.NewReader(activity))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", ContentType)
+	req.Header.Set("Accept", ContentType)
+
+	if err := signer.Sign(req, activity); err != nil {
+		return err
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(res.Body, maxDocumentSize))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return &StatusError{URL: inbox, StatusCode: res.StatusCode}
+	}
+
+	return nil
+}
+
+// StatusError reports a remote server answering with a non-2xx status.
+type StatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s responded with status %d", e.URL, e.StatusCode)
+}
+
+// Permanent reports whether retrying the request is pointless: the server
+// rejected it rather than failing or asking us to slow down.
+func (e *StatusError) Permanent() bool {
+	return e.StatusCode >= 400 && e.StatusCode < 500 &&
+		e.StatusCode != http.StatusRequestTimeout &&
+		e.StatusCode != http.StatusTooManyRequests
+}
+
+// IsActivityPubRequest reports whether an Accept header asks for an
+// ActivityPub document rather than HTML.
+func IsActivityPubRequest(accept string) bool {
+	return strings.Contains(accept, "application/activity+json") ||
+		strings.Contains(accept, "application/ld+json")
+}
+
+// GenerateKey returns a new PEM encoded RSA key pair for signing requests.
+func GenerateKey() (privatePEM string, publicPEM string, err error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return "", "", err
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", "", err
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return "", "", err
+	}
+
+	privatePEM = string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}))
+	publicPEM = string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}))
+
+	return privatePEM, publicPEM, nil
+}
+
+func ParsePrivateKey(privatePEM string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(privatePEM))
+	if block == nil {
+		return nil, errors.New("invalid private key PEM")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not RSA")
+	}
+
+	return key, nil
+}
+
+func ParsePublicKey(publicPEM string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(publicPEM))
+	if block == nil {
+		return nil, errors.New("invalid public key PEM")
+	}
+
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("public key is not RSA")
+	}
+
+	return key, nil
+}
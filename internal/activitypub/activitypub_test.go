@@ -0,0 +1,363 @@
+package activitypub_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"mortenvistisen/internal/activitypub"
+)
+
+// fakeInstance is a remote server with one actor. It serves the actor
+// document and records what is POSTed to its inbox after checking the
+// signature the way a real server would.
+type fakeInstance struct {
+	server    *httptest.Server
+	key       *rsa.PrivateKey
+	publicPEM string
+	// requireSignedFetch makes the actor document only available to signed
+	// requests, like servers running in authorized fetch mode.
+	requireSignedFetch bool
+	// claimedID replaces the id in the actor document, like a server
+	// impersonating an actor elsewhere.
+	claimedID string
+	// fetchKey resolves the keys of actors delivering to the inbox.
+	fetchKey   activitypub.KeyFetcher
+	inboxCode  int
+	received   [][]byte
+	verifyErrs []error
+}
+
+func newFakeInstance(t *testing.T) *fakeInstance {
+	t.Helper()
+
+	privatePEM, publicPEM, err := activitypub.GenerateKey()
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	key, err := activitypub.ParsePrivateKey(privatePEM)
+	if err != nil {
+		t.Fatalf("parse key: %v", err)
+	}
+
+	f := &fakeInstance{key: key, publicPEM: publicPEM, inboxCode: http.StatusAccepted}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/alice", f.serveActor)
+	mux.HandleFunc("POST /users/alice/inbox", f.serveInbox)
+	f.server = httptest.NewServer(mux)
+	t.Cleanup(f.server.Close)
+
+	return f
+}
+
+func (f *fakeInstance) actorURL() string { return f.server.URL + "/users/alice" }
+func (f *fakeInstance) keyID() string    { return f.actorURL() + "#main-key" }
+
+func (f *fakeInstance) signer() activitypub.Signer {
+	return activitypub.Signer{KeyID: f.keyID(), Key: f.key}
+}
+
+func (f *fakeInstance) serveActor(w http.ResponseWriter, r *http.Request) {
+	if f.requireSignedFetch && r.Header.Get("Signature") == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	id := f.actorURL()
+	if f.claimedID != "" {
+		id = f.claimedID
+	}
+
+	w.Header().Set("Content-Type", activitypub.ContentType)
+	json.NewEncoder(w).Encode(activitypub.Actor{
+		Context:           activitypub.ActorContext,
+		ID:                id,
+		Type:              "Person",
+		PreferredUsername: "alice",
+		Inbox:             f.actorURL() + "/inbox",
+		Endpoints:         &activitypub.Endpoints{SharedInbox: f.server.URL + "/inbox"},
+		PublicKey: activitypub.PublicKey{
+			ID:           f.keyID(),
+			Owner:        f.actorURL(),
+			PublicKeyPem: f.publicPEM,
+		},
+	})
+}
+
+func (f *fakeInstance) serveInbox(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	if _, err := activitypub.Verify(r.Context(), r, body, f.fetchKey); err != nil {
+		f.verifyErrs = append(f.verifyErrs, err)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	f.received = append(f.received, body)
+	w.WriteHeader(f.inboxCode)
+}
+
+// actorKeyFetcher resolves keys the way the blog's inbox does: by fetching
+// the actor the key belongs to.
+func actorKeyFetcher(client *http.Client, signer *activitypub.Signer) activitypub.KeyFetcher {
+	return func(ctx context.Context, keyID string) (*rsa.PublicKey, error) {
+		actorURL, _, _ := strings.Cut(keyID, "#")
+		actor, err := activitypub.FetchActor(ctx, client, signer, actorURL)
+		if err != nil {
+			return nil, err
+		}
+		if actor.PublicKey.ID != keyID {
+			return nil, errors.New("key is not owned by actor")
+		}
+		return activitypub.ParsePublicKey(actor.PublicKey.PublicKeyPem)
+	}
+}
+
+func signedInboxRequest(t *testing.T, signer activitypub.Signer, body []byte) *http.Request {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "https://blog.example/ap/inbox", bytes.NewReader(body))
+	req.Header.Set("Content-Type", activitypub.ContentType)
+	if err := signer.Sign(req, body); err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	return req
+}
+
+func TestVerifyAgainstRemoteActor(t *testing.T) {
+	remote := newFakeInstance(t)
+	fetch := actorKeyFetcher(remote.server.Client(), nil)
+	body := []byte(`{"type":"Follow","actor":"` + remote.actorURL() + `","object":"https://blog.example/ap/actor"}`)
+
+	tests := []struct {
+		name   string
+		tamper func(req *http.Request)
+		err    error
+	}{
+		{
+			name:   "valid signature",
+			tamper: func(*http.Request) {},
+		},
+		{
+			name: "missing signature",
+			tamper: func(req *http.Request) {
+				req.Header.Del("Signature")
+			},
+			err: activitypub.ErrMissingSignature,
+		},
+		{
+			name: "body changed after signing",
+			tamper: func(req *http.Request) {
+				req.Body = io.NopCloser(strings.NewReader(`{"type":"Delete"}`))
+			},
+			err: activitypub.ErrInvalidSignature,
+		},
+		{
+			name: "digest replaced to match a new body",
+			tamper: func(req *http.Request) {
+				other := activitypub.Signer{KeyID: "x", Key: remote.key}
+				forged := httptest.NewRequest(http.MethodPost, "https://blog.example/ap/inbox", nil)
+				other.Sign(forged, []byte(`{"type":"Delete"}`))
+				req.Header.Set("Digest", forged.Header.Get("Digest"))
+				req.Body = io.NopCloser(strings.NewReader(`{"type":"Delete"}`))
+			},
+			err: activitypub.ErrInvalidSignature,
+		},
+		{
+			name: "stale date",
+			tamper: func(req *http.Request) {
+				req.Header.Set("Date", time.Now().Add(-2*time.Hour).UTC().Format(http.TimeFormat))
+			},
+			err: activitypub.ErrInvalidSignature,
+		},
+		{
+			name: "sent to another path",
+			tamper: func(req *http.Request) {
+				req.URL.Path = "/ap/other"
+			},
+			err: activitypub.ErrInvalidSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := signedInboxRequest(t, remote.signer(), body)
+			tt.tamper(req)
+			received, _ := io.ReadAll(req.Body)
+
+			keyID, err := activitypub.Verify(context.Background(), req, received, fetch)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("expected error %v, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if keyID != remote.keyID() {
+				t.Errorf("expected key %q, got %q", remote.keyID(), keyID)
+			}
+		})
+	}
+}
+
+func TestVerifyRejectsKeyOfAnotherActor(t *testing.T) {
+	remote := newFakeInstance(t)
+	impostor := newFakeInstance(t)
+
+	body := []byte(`{"type":"Follow"}`)
+	// Signed with the impostor's private key but claiming the remote's key.
+	req := signedInboxRequest(t, activitypub.Signer{KeyID: remote.keyID(), Key: impostor.key}, body)
+
+	_, err := activitypub.Verify(context.Background(), req, body, actorKeyFetcher(remote.server.Client(), nil))
+	if !errors.Is(err, activitypub.ErrInvalidSignature) {
+		t.Fatalf("expected invalid signature, got %v", err)
+	}
+}
+
+func TestFetchActorRejectsAnotherID(t *testing.T) {
+	victim := newFakeInstance(t)
+	liar := newFakeInstance(t)
+	liar.claimedID = victim.actorURL()
+
+	_, err := activitypub.FetchActor(context.Background(), liar.server.Client(), nil, liar.actorURL())
+	if !errors.Is(err, activitypub.ErrActorMismatch) {
+		t.Fatalf("expected actor mismatch, got %v", err)
+	}
+
+	// The liar signs with its own key, which its document publishes under
+	// the victim's id.
+	body := []byte(`{"type":"Follow","actor":"` + victim.actorURL() + `","object":"https://blog.example/ap/actor"}`)
+	req := signedInboxRequest(t, liar.signer(), body)
+
+	_, err = activitypub.Verify(context.Background(), req, body, actorKeyFetcher(liar.server.Client(), nil))
+	if !errors.Is(err, activitypub.ErrActorMismatch) {
+		t.Fatalf("expected actor mismatch, got %v", err)
+	}
+}
+
+func TestFetchActorSignsForAuthorizedFetch(t *testing.T) {
+	remote := newFakeInstance(t)
+	remote.requireSignedFetch = true
+	local := newFakeInstance(t)
+
+	if _, err := activitypub.FetchActor(context.Background(), remote.server.Client(), nil, remote.actorURL()); err == nil {
+		t.Fatal("expected an unsigned fetch to be refused")
+	}
+
+	signer := local.signer()
+	actor, err := activitypub.FetchActor(context.Background(), remote.server.Client(), &signer, remote.actorURL())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actor.SharedInbox() != remote.server.URL+"/inbox" {
+		t.Errorf("unexpected shared inbox %q", actor.SharedInbox())
+	}
+}
+
+func TestDeliver(t *testing.T) {
+	blog := newFakeInstance(t)
+	remote := newFakeInstance(t)
+	remote.fetchKey = actorKeyFetcher(blog.server.Client(), nil)
+
+	activity := []byte(`{"type":"Create","actor":"` + blog.actorURL() + `"}`)
+
+	err := activitypub.Deliver(
+		context.Background(),
+		remote.server.Client(),
+		blog.signer(),
+		remote.actorURL()+"/inbox",
+		activity,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v (inbox rejected with %v)", err, remote.verifyErrs)
+	}
+	if len(remote.received) != 1 || !bytes.Equal(remote.received[0], activity) {
+		t.Fatalf("expected the activity to arrive unchanged, got %q", remote.received)
+	}
+}
+
+func TestDeliverStatusErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		permanent bool
+	}{
+		{name: "gone", status: http.StatusGone, permanent: true},
+		{name: "rate limited", status: http.StatusTooManyRequests},
+		{name: "server error", status: http.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blog := newFakeInstance(t)
+			remote := newFakeInstance(t)
+			remote.fetchKey = actorKeyFetcher(blog.server.Client(), nil)
+			remote.inboxCode = tt.status
+
+			err := activitypub.Deliver(
+				context.Background(),
+				remote.server.Client(),
+				blog.signer(),
+				remote.actorURL()+"/inbox",
+				[]byte(`{}`),
+			)
+
+			var statusErr *activitypub.StatusError
+			if !errors.As(err, &statusErr) {
+				t.Fatalf("expected a status error, got %v", err)
+			}
+			if statusErr.StatusCode != tt.status || statusErr.Permanent() != tt.permanent {
+				t.Errorf("expected status %d permanent=%v, got %d permanent=%v",
+					tt.status, tt.permanent, statusErr.StatusCode, statusErr.Permanent())
+			}
+		})
+	}
+}
+
+func TestActivityObject(t *testing.T) {
+	tests := []struct {
+		name       string
+		object     string
+		expectedID string
+		objectType string
+	}{
+		{
+			name:       "url",
+			object:     `"https://blog.example/ap/actor"`,
+			expectedID: "https://blog.example/ap/actor",
+		},
+		{
+			name:       "embedded follow",
+			object:     `{"id":"https://remote.example/follows/1","type":"Follow","object":"https://blog.example/ap/actor"}`,
+			expectedID: "https://remote.example/follows/1",
+			objectType: "Follow",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var activity activitypub.Activity
+			if err := json.Unmarshal([]byte(`{"type":"Undo","object":`+tt.object+`}`), &activity); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if got := activity.ObjectID(); got != tt.expectedID {
+				t.Errorf("expected object id %q, got %q", tt.expectedID, got)
+			}
+			if got := activity.ObjectType(); got != tt.objectType {
+				t.Errorf("expected object type %q, got %q", tt.objectType, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+// Package frontmatter reads and writes markdown files that start with a YAML
+// ("---") or TOML ("+++") metadata block. It is the on-disk format shared by
+// the content export and the database/sync importer.
+package frontmatter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	yamlDelimiter = []byte("---")
+	tomlDelimiter = []byte("+++")
+)
+
+// Meta holds the metadata of one article, project or newsletter. Not every
+// field applies to every content type; unused fields are left empty. Date is
+// the first publish date of an article, the start date of a project and the
+// release date of a newsletter. CommentsOpen and TableOfContents are nil when
+// a file leaves them out, which keeps the article's current setting.
+type Meta struct {
+	Title           string    `yaml:"title"                       toml:"title"`
+	Slug            string    `yaml:"slug,omitempty"              toml:"slug,omitempty"`
+	Excerpt         string    `yaml:"excerpt,omitempty"           toml:"excerpt,omitempty"`
+	MetaTitle       string    `yaml:"meta_title,omitempty"        toml:"meta_title,omitempty"`
+	MetaDescription string    `yaml:"meta_description,omitempty"  toml:"meta_description,omitempty"`
+	ImageLink       string    `yaml:"image_link,omitempty"        toml:"image_link,omitempty"`
+	ReadTime        int32     `yaml:"read_time,omitempty"         toml:"read_time,omitempty"`
+	Tags            []string  `yaml:"tags,omitempty"              toml:"tags,omitempty"`
+	Published       bool      `yaml:"published"                   toml:"published"`
+	CommentsOpen    *bool     `yaml:"comments_open,omitempty"     toml:"comments_open,omitempty"`
+	TableOfContents *bool     `yaml:"table_of_contents,omitempty" toml:"table_of_contents,omitempty"`
+	Date            time.Time `yaml:"date,omitempty"              toml:"date,omitempty"`
+	Status          string    `yaml:"status,omitempty"            toml:"status,omitempty"`
+	ProjectURL      string    `yaml:"project_url,omitempty"       toml:"project_url,omitempty"`
+	Author          string    `yaml:"author,omitempty"            toml:"author,omitempty"`
+	CreatedAt       time.Time `yaml:"created_at,omitempty"        toml:"created_at,omitempty"`
+	UpdatedAt       time.Time `yaml:"updated_at,omitempty"        toml:"updated_at,omitempty"`
+}
+
+// Parse splits raw into its metadata and markdown body.
+func Parse(raw []byte) (Meta, string, error) {
+	raw = bytes.TrimPrefix(raw, []byte("\ufeff"))
+	raw = bytes.ReplaceAll(raw, []byte("\r\n"), []byte("\n"))
+
+	var delimiter []byte
+	switch {
+	case bytes.HasPrefix(raw, yamlDelimiter):
+		delimiter = yamlDelimiter
+	case bytes.HasPrefix(raw, tomlDelimiter):
+		delimiter = tomlDelimiter
+	default:
+		return Meta{}, "", errors.New("file does not start with front matter")
+	}
+
+	rest := raw[len(delimiter):]
+	if !bytes.HasPrefix(rest, []byte("\n")) {
+		return Meta{}, "", errors.New("front matter delimiter must be on its own line")
+	}
+	rest = rest[1:]
+
+	closing := append([]byte("\n"), delimiter...)
+	var header, body []byte
+	if bytes.HasPrefix(rest, delimiter) {
+		body = rest[len(delimiter):]
+	} else {
+		end := bytes.Index(rest, closing)
+		if end < 0 {
+			return Meta{}, "", errors.New("front matter is not closed")
+		}
+		header = rest[:end]
+		body = rest[end+len(closing):]
+	}
+
+	var meta Meta
+	if bytes.Equal(delimiter, yamlDelimiter) {
+		if err := yaml.Unmarshal(header, &meta); err != nil {
+			return Meta{}, "", fmt.Errorf("invalid YAML front matter: %w", err)
+		}
+	} else {
+		if _, err := toml.Decode(string(header), &meta); err != nil {
+			return Meta{}, "", fmt.Errorf("invalid TOML front matter: %w", err)
+		}
+	}
+
+	return meta, strings.TrimSpace(string(body)), nil
+}
+
+// Render writes meta as YAML front matter followed by body.
+func Render(meta Meta, body string) ([]byte, error) {
+	header, err := yaml.Marshal(meta)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	out.Write(yamlDelimiter)
+	out.WriteByte('\n')
+	out.Write(header)
+	out.Write(yamlDelimiter)
+	out.WriteString("\n\n")
+	out.WriteString(strings.TrimSpace(body))
+	out.WriteByte('\n')
+
+	return out.Bytes(), nil
+}
@@ -0,0 +1,78 @@
+package frontmatter_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"mortenvistisen/internal/frontmatter"
+)
+
+func TestRenderParseRoundTrip(t *testing.T) {
+	date := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	open, toc := true, false
+
+	tests := map[string]struct {
+		meta frontmatter.Meta
+		body string
+	}{
+		"every field": {
+			meta: frontmatter.Meta{
+				Title:           "Running Postgres: \"in\" tests",
+				Slug:            "running-postgres-in-tests",
+				Excerpt:         "Multi\nline excerpt",
+				MetaTitle:       "Postgres in tests",
+				MetaDescription: "How the test helper starts a database",
+				ImageLink:       "https://example.com/cover.png",
+				ReadTime:        7,
+				Tags:            []string{"go", "postgres: testing"},
+				Published:       true,
+				CommentsOpen:    &open,
+				TableOfContents: &toc,
+				Date:            date,
+				Status:          "active",
+				ProjectURL:      "https://github.com/example/project",
+				Author:          "jane@example.com",
+				CreatedAt:       date.Add(-time.Hour),
+				UpdatedAt:       date.Add(time.Hour),
+			},
+			body: "# Heading\n\n---\n\nBody with a rule.",
+		},
+		"zero dates and no tags": {
+			meta: frontmatter.Meta{
+				Title: "Draft",
+				Tags:  []string{},
+			},
+			body: "Draft body",
+		},
+		"empty body": {
+			meta: frontmatter.Meta{Title: "Empty"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			raw, err := frontmatter.Render(tt.meta, tt.body)
+			if err != nil {
+				t.Fatalf("render: %v", err)
+			}
+
+			meta, body, err := frontmatter.Parse(raw)
+			if err != nil {
+				t.Fatalf("parse %q: %v", raw, err)
+			}
+
+			want := tt.meta
+			// An empty tag list is left out of the front matter.
+			if len(want.Tags) == 0 {
+				want.Tags = nil
+			}
+			if !reflect.DeepEqual(meta, want) {
+				t.Errorf("expected meta %+v, got %+v", want, meta)
+			}
+			if body != tt.body {
+				t.Errorf("expected body %q, got %q", tt.body, body)
+			}
+		})
+	}
+}
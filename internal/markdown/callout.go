@@ -0,0 +1,121 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+const (
+	CalloutNote    = "note"
+	CalloutTip     = "tip"
+	CalloutWarning = "warning"
+)
+
+// calloutMarker is the first line of a blockquote that makes it a callout,
+// as GitHub writes them: > [!NOTE].
+var calloutMarker = regexp.MustCompile(`^\[!(?i)(note|tip|warning)\]$`)
+
+var KindCallout = ast.NewNodeKind("Callout")
+
+// Callout is a blockquote that starts with a callout marker. Its children
+// are the blockquote's content without the marker. Variant is one of
+// CalloutNote, CalloutTip and CalloutWarning.
+type Callout struct {
+	ast.BaseBlock
+	Variant string
+}
+
+func (n *Callout) Kind() ast.NodeKind {
+	return KindCallout
+}
+
+func (n *Callout) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Variant": n.Variant}, nil)
+}
+
+type calloutTransformer struct{}
+
+func (calloutTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+
+	var quotes []*ast.Blockquote
+	_ = ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if quote, ok := node.(*ast.Blockquote); ok && entering {
+			quotes = append(quotes, quote)
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, quote := range quotes {
+		paragraph, ok := quote.FirstChild().(*ast.Paragraph)
+		if !ok || paragraph.Lines().Len() == 0 {
+			continue
+		}
+
+		first := paragraph.Lines().At(0)
+		match := calloutMarker.FindSubmatch(util.TrimRightSpace(util.TrimLeftSpace(first.Value(source))))
+		if match == nil {
+			continue
+		}
+
+		// The marker is parsed as text: an unmatched link bracket and
+		// the rest of the line.
+		for child := paragraph.FirstChild(); child != nil; {
+			next := child.NextSibling()
+			marker, ok := child.(*ast.Text)
+			if !ok || marker.Segment.Start >= first.Stop {
+				break
+			}
+			paragraph.RemoveChild(paragraph, child)
+			child = next
+		}
+		if !paragraph.HasChildren() {
+			quote.RemoveChild(quote, paragraph)
+		}
+
+		callout := &Callout{Variant: strings.ToLower(string(match[1]))}
+		for child := quote.FirstChild(); child != nil; {
+			next := child.NextSibling()
+			callout.AppendChild(callout, child)
+			child = next
+		}
+		quote.Parent().ReplaceChild(quote.Parent(), quote, callout)
+	}
+}
+
+type calloutRenderer struct{}
+
+func (r calloutRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindCallout, r.renderCallout)
+}
+
+func (calloutRenderer) renderCallout(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*Callout)
+	if !entering {
+		_, _ = w.WriteString("</aside>\n")
+		return ast.WalkContinue, nil
+	}
+
+	_, _ = w.WriteString(`<aside class="callout callout-` + n.Variant + `" role="note">` + "\n")
+	_, _ = w.WriteString(`<p class="callout-title">` + strings.ToUpper(n.Variant[:1]) + n.Variant[1:] + "</p>\n")
+
+	return ast.WalkContinue, nil
+}
+
+type calloutExtension struct{}
+
+// CalloutExtension turns blockquotes that start with [!NOTE], [!TIP] or
+// [!WARNING] into callouts.
+var CalloutExtension goldmark.Extender = calloutExtension{}
+
+func (calloutExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(util.Prioritized(calloutTransformer{}, 100)))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(util.Prioritized(calloutRenderer{}, 500)))
+}
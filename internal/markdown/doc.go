@@ -0,0 +1,4 @@
+// Package markdown holds the goldmark extensions of the site's markdown:
+// callouts, figures, math and embeds of the site's own projects and
+// articles. The pipeline that puts them together is in services.
+package markdown
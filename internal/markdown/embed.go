@@ -0,0 +1,158 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+const (
+	EmbedProject = "project"
+	EmbedArticle = "article"
+)
+
+// embedShortcode is a line with a shortcode like {{< project "slug" >}}.
+var embedShortcode = regexp.MustCompile(`^\{\{<\s*(project|article)\s+"([^"\s]+)"\s*>\}\}\s*$`)
+
+// EmbedRef names what an embed shortcode shows.
+type EmbedRef struct {
+	Kind string
+	Slug string
+}
+
+// EmbedTarget is what an embed links to.
+type EmbedTarget struct {
+	Title       string
+	Description string
+	URL         string
+}
+
+// EmbedResolver looks up the target of an embed. Embeds it does not find
+// are left out of the page.
+type EmbedResolver func(ref EmbedRef) (EmbedTarget, bool)
+
+var KindEmbed = ast.NewNodeKind("Embed")
+
+// Embed is a shortcode on a line of its own.
+type Embed struct {
+	ast.BaseBlock
+	Ref EmbedRef
+}
+
+func (n *Embed) Kind() ast.NodeKind {
+	return KindEmbed
+}
+
+func (n *Embed) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Kind": n.Ref.Kind, "Slug": n.Ref.Slug}, nil)
+}
+
+// EmbedRefs lists what the embeds in a parsed document refer to, each once
+// and in order.
+func EmbedRefs(doc ast.Node) []EmbedRef {
+	var refs []EmbedRef
+	seen := map[EmbedRef]bool{}
+	_ = ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if embed, ok := node.(*Embed); ok && entering && !seen[embed.Ref] {
+			seen[embed.Ref] = true
+			refs = append(refs, embed.Ref)
+		}
+		return ast.WalkContinue, nil
+	})
+
+	return refs
+}
+
+type embedParser struct{}
+
+func (embedParser) Trigger() []byte {
+	return []byte{'{'}
+}
+
+func (embedParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, _ := reader.PeekLine()
+	pos := pc.BlockOffset()
+	if pos < 0 {
+		return nil, parser.NoChildren
+	}
+
+	match := embedShortcode.FindSubmatch(line[pos:])
+	if match == nil {
+		return nil, parser.NoChildren
+	}
+
+	reader.AdvanceToEOL()
+	return &Embed{Ref: EmbedRef{Kind: string(match[1]), Slug: string(match[2])}}, parser.NoChildren
+}
+
+func (embedParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	return parser.Close
+}
+
+func (embedParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {}
+
+func (embedParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (embedParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+type embedRenderer struct {
+	resolve EmbedResolver
+}
+
+func (r embedRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindEmbed, r.renderEmbed)
+}
+
+func (r embedRenderer) renderEmbed(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering || r.resolve == nil {
+		return ast.WalkSkipChildren, nil
+	}
+
+	ref := node.(*Embed).Ref
+	target, ok := r.resolve(ref)
+	if !ok {
+		return ast.WalkSkipChildren, nil
+	}
+
+	_, _ = w.WriteString(`<aside class="embed embed-` + ref.Kind + `">`)
+	_, _ = w.WriteString(`<a href="`)
+	_, _ = w.Write(util.EscapeHTML(util.URLEscape([]byte(target.URL), true)))
+	_, _ = w.WriteString(`">`)
+	_, _ = w.WriteString(`<span class="embed-kind">` + strings.ToUpper(ref.Kind[:1]) + ref.Kind[1:] + "</span>")
+	_, _ = w.WriteString(`<span class="embed-title">`)
+	_, _ = w.Write(util.EscapeHTML([]byte(target.Title)))
+	_, _ = w.WriteString("</span>")
+	if target.Description != "" {
+		_, _ = w.WriteString(`<span class="embed-description">`)
+		_, _ = w.Write(util.EscapeHTML([]byte(target.Description)))
+		_, _ = w.WriteString("</span>")
+	}
+	_, _ = w.WriteString("</a></aside>\n")
+
+	return ast.WalkSkipChildren, nil
+}
+
+type embedExtension struct {
+	resolve EmbedResolver
+}
+
+// NewEmbedExtension renders {{< project "slug" >}} and
+// {{< article "slug" >}} lines as cards linking to what resolve finds.
+func NewEmbedExtension(resolve EmbedResolver) goldmark.Extender {
+	return embedExtension{resolve}
+}
+
+func (e embedExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithBlockParsers(util.Prioritized(embedParser{}, 650)))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(util.Prioritized(embedRenderer{e.resolve}, 500)))
+}
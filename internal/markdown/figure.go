@@ -0,0 +1,88 @@
+package markdown
+
+import (
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var KindFigure = ast.NewNodeKind("Figure")
+
+// Figure is an image with a caption. Its only child is the image.
+type Figure struct {
+	ast.BaseBlock
+	Caption []byte
+}
+
+func (n *Figure) Kind() ast.NodeKind {
+	return KindFigure
+}
+
+func (n *Figure) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Caption": string(n.Caption)}, nil)
+}
+
+type figureTransformer struct{}
+
+// Transform makes a figure of every paragraph that is only an image with a
+// title. The title becomes the caption.
+func (figureTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	var paragraphs []*ast.Paragraph
+	_ = ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if paragraph, ok := node.(*ast.Paragraph); ok && entering {
+			paragraphs = append(paragraphs, paragraph)
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, paragraph := range paragraphs {
+		image, ok := paragraph.FirstChild().(*ast.Image)
+		if !ok || paragraph.ChildCount() != 1 || len(image.Title) == 0 {
+			continue
+		}
+
+		figure := &Figure{Caption: image.Title}
+		image.Title = nil
+		figure.AppendChild(figure, image)
+		paragraph.Parent().ReplaceChild(paragraph.Parent(), paragraph, figure)
+	}
+}
+
+type figureRenderer struct {
+	html.Config
+}
+
+func (r figureRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindFigure, r.renderFigure)
+}
+
+func (r figureRenderer) renderFigure(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString("<figure>\n")
+		return ast.WalkContinue, nil
+	}
+
+	_, _ = w.WriteString("\n<figcaption>")
+	r.Writer.Write(w, node.(*Figure).Caption)
+	_, _ = w.WriteString("</figcaption>\n</figure>\n")
+
+	return ast.WalkContinue, nil
+}
+
+type figureExtension struct{}
+
+// FigureExtension sets images that have a paragraph to themselves and a
+// title as figures, captioned by the title.
+var FigureExtension goldmark.Extender = figureExtension{}
+
+func (figureExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(util.Prioritized(figureTransformer{}, 100)))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(figureRenderer{html.NewConfig()}, 500),
+	))
+}
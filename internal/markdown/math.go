@@ -0,0 +1,231 @@
+package markdown
+
+import (
+	"bytes"
+	"html"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var mathDelimiter = []byte("$$")
+
+var (
+	KindMath      = ast.NewNodeKind("Math")
+	KindMathBlock = ast.NewNodeKind("MathBlock")
+)
+
+// Math is TeX between $ or $$ inside a paragraph. $$ sets it as display
+// math.
+type Math struct {
+	ast.BaseInline
+	TeX     []byte
+	Display bool
+}
+
+func (n *Math) Kind() ast.NodeKind {
+	return KindMath
+}
+
+func (n *Math) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"TeX": string(n.TeX)}, nil)
+}
+
+// MathBlock is display math on lines of its own, between $$ lines or on a
+// single $$ ... $$ line.
+type MathBlock struct {
+	ast.BaseBlock
+	closed bool
+}
+
+func (n *MathBlock) Kind() ast.NodeKind {
+	return KindMathBlock
+}
+
+func (n *MathBlock) IsRaw() bool {
+	return true
+}
+
+func (n *MathBlock) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+type mathParser struct{}
+
+func (mathParser) Trigger() []byte {
+	return []byte{'$'}
+}
+
+// Parse follows pandoc for inline math: the opening $ must be followed by
+// a non-space and the closing $ preceded by one and not followed by a
+// digit, so "$5 and $10" stays text.
+func (mathParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+	if block.PrecendingCharacter() == '$' {
+		return nil
+	}
+
+	delimiter := 1
+	if bytes.HasPrefix(line, mathDelimiter) {
+		delimiter = 2
+	}
+
+	rest := line[delimiter:]
+	if len(rest) == 0 || util.IsSpace(rest[0]) {
+		return nil
+	}
+
+	for i := 0; i < len(rest); i++ {
+		switch rest[i] {
+		case '\\':
+			i++
+			continue
+		case '\n':
+			return nil
+		case '$':
+		default:
+			continue
+		}
+
+		if i == 0 {
+			return nil
+		}
+		if delimiter == 2 && !bytes.HasPrefix(rest[i:], mathDelimiter) {
+			continue
+		}
+		if delimiter == 1 && (util.IsSpace(rest[i-1]) || (i+1 < len(rest) && util.IsNumeric(rest[i+1]))) {
+			continue
+		}
+
+		block.Advance(delimiter + i + delimiter)
+		return &Math{TeX: bytes.Clone(rest[:i]), Display: delimiter == 2}
+	}
+
+	return nil
+}
+
+type mathBlockParser struct{}
+
+func (mathBlockParser) Trigger() []byte {
+	return []byte{'$'}
+}
+
+func (mathBlockParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	pos := pc.BlockOffset()
+	if pos < 0 || !bytes.HasPrefix(line[pos:], mathDelimiter) {
+		return nil, parser.NoChildren
+	}
+
+	node := &MathBlock{}
+	start := segment.Start - segment.Padding + pos + len(mathDelimiter)
+	rest := util.TrimRightSpace(line[pos+len(mathDelimiter):])
+	if i := bytes.Index(rest, mathDelimiter); i >= 0 {
+		if !util.IsBlank(rest[i+len(mathDelimiter):]) {
+			return nil, parser.NoChildren
+		}
+		node.Lines().Append(text.NewSegment(start, start+i))
+		node.closed = true
+	} else if !util.IsBlank(rest) {
+		node.Lines().Append(text.NewSegment(start, start+len(rest)))
+	}
+
+	reader.AdvanceToEOL()
+	return node, parser.NoChildren
+}
+
+func (mathBlockParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	if node.(*MathBlock).closed {
+		return parser.Close
+	}
+
+	line, segment := reader.PeekLine()
+	if i := bytes.Index(line, mathDelimiter); i >= 0 {
+		if !util.IsBlank(line[:i]) {
+			node.Lines().Append(text.NewSegment(segment.Start, segment.Start+i))
+		}
+		reader.AdvanceToEOL()
+		return parser.Close
+	}
+
+	node.Lines().Append(segment)
+	reader.AdvanceToEOL()
+	return parser.Continue | parser.NoChildren
+}
+
+func (mathBlockParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {}
+
+func (mathBlockParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (mathBlockParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+type mathRenderer struct{}
+
+func (r mathRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindMath, r.renderMath)
+	reg.Register(KindMathBlock, r.renderMathBlock)
+}
+
+func (mathRenderer) renderMath(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		n := node.(*Math)
+		writeMath(w, string(n.TeX), n.Display)
+	}
+
+	return ast.WalkSkipChildren, nil
+}
+
+func (mathRenderer) renderMathBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	var tex bytes.Buffer
+	lines := node.Lines()
+	for i := range lines.Len() {
+		segment := lines.At(i)
+		tex.Write(segment.Value(source))
+	}
+
+	writeMath(w, string(bytes.TrimSpace(tex.Bytes())), true)
+	_ = w.WriteByte('\n')
+
+	return ast.WalkSkipChildren, nil
+}
+
+// writeMath writes TeX as MathML, keeping the source as an annotation for
+// copying and for readers that cannot show MathML.
+func writeMath(w util.BufWriter, tex string, display bool) {
+	if display {
+		_, _ = w.WriteString(`<math display="block">`)
+	} else {
+		_, _ = w.WriteString("<math>")
+	}
+	_, _ = w.WriteString("<semantics>")
+	_, _ = w.WriteString(texToMathML(tex))
+	_, _ = w.WriteString(`<annotation encoding="application/x-tex">`)
+	_, _ = w.WriteString(html.EscapeString(tex))
+	_, _ = w.WriteString("</annotation></semantics></math>")
+}
+
+type mathExtension struct{}
+
+// MathExtension renders TeX between dollar signs to MathML: $...$ inline and $$...$$
+// as display math.
+var MathExtension goldmark.Extender = mathExtension{}
+
+func (mathExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithBlockParsers(util.Prioritized(mathBlockParser{}, 650)),
+		parser.WithInlineParsers(util.Prioritized(mathParser{}, 500)),
+	)
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(util.Prioritized(mathRenderer{}, 500)))
+}
@@ -0,0 +1,648 @@
+package markdown
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// texToMathML converts TeX math to MathML, without the enclosing math
+// element. It covers the LaTeX that posts use: scripts, fractions, roots,
+// accents, fences, font styles, matrices and cases, and the common
+// symbols. Anything else is shown in place as an merror.
+func texToMathML(tex string) string {
+	p := &texParser{src: []rune(tex)}
+
+	var items []string
+	for {
+		items = append(items, p.parseList()...)
+		if p.atEnd() {
+			break
+		}
+		// A closer outside of the construct it belongs to is dropped.
+		p.skipCloser()
+	}
+
+	return "<mrow>" + strings.Join(items, "") + "</mrow>"
+}
+
+type texParser struct {
+	src []rune
+	pos int
+	// style is the font style of the letters being parsed, as set by
+	// commands like \mathbf. Empty is the default italic.
+	style string
+}
+
+func (p *texParser) atEnd() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *texParser) skipSpace() {
+	for !p.atEnd() && unicode.IsSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *texParser) peek() rune {
+	p.skipSpace()
+	if p.atEnd() {
+		return 0
+	}
+
+	return p.src[p.pos]
+}
+
+// peekCommand returns the name of the command at the current position
+// without consuming it.
+func (p *texParser) peekCommand() string {
+	if p.peek() != '\\' {
+		return ""
+	}
+
+	pos := p.pos
+	name := p.readCommand()
+	p.pos = pos
+
+	return name
+}
+
+// readCommand consumes a command and returns its name: a run of letters or
+// a single other character.
+func (p *texParser) readCommand() string {
+	p.pos++
+	if p.atEnd() {
+		return ""
+	}
+
+	start := p.pos
+	if !unicode.IsLetter(p.src[p.pos]) {
+		p.pos++
+		return string(p.src[start:p.pos])
+	}
+	for !p.atEnd() && unicode.IsLetter(p.src[p.pos]) {
+		p.pos++
+	}
+
+	return string(p.src[start:p.pos])
+}
+
+// atCloser reports whether the parser is at something that ends a list:
+// a closing brace, a cell or row separator, \right or \end.
+func (p *texParser) atCloser() bool {
+	switch p.peek() {
+	case '}', '&':
+		return true
+	case '\\':
+		name := p.peekCommand()
+		return name == "\\" || name == "right" || name == "end"
+	}
+
+	return false
+}
+
+func (p *texParser) skipCloser() {
+	if p.peek() != '\\' {
+		p.pos++
+		return
+	}
+
+	switch p.readCommand() {
+	case "right":
+		p.readDelimiter()
+	case "end":
+		p.readGroupText()
+	}
+}
+
+// parseList parses atoms up to the end of the input or the next closer.
+func (p *texParser) parseList() []string {
+	var items []string
+	for !p.atEnd() && !p.atCloser() {
+		items = append(items, p.parseScripted())
+	}
+
+	return items
+}
+
+// parseScripted parses an atom with its subscript, superscript and primes.
+func (p *texParser) parseScripted() string {
+	base, limits := p.parseAtom(false)
+
+	var sub, sup, primes string
+	var hasSub, hasSup bool
+scripts:
+	for {
+		switch p.peek() {
+		case '_':
+			if hasSub {
+				break scripts
+			}
+			p.pos++
+			sub, hasSub = p.parseArgument(), true
+		case '^':
+			if hasSup {
+				break scripts
+			}
+			p.pos++
+			sup, hasSup = p.parseArgument(), true
+		case '\'':
+			p.pos++
+			primes += "′"
+		default:
+			break scripts
+		}
+	}
+
+	if primes != "" {
+		sup = "<mrow><mo>" + primes + "</mo>" + sup + "</mrow>"
+		hasSup = true
+	}
+
+	under, over, both := "msub", "msup", "msubsup"
+	if limits {
+		under, over, both = "munder", "mover", "munderover"
+	}
+
+	switch {
+	case hasSub && hasSup:
+		return "<" + both + ">" + base + sub + sup + "</" + both + ">"
+	case hasSub:
+		return "<" + under + ">" + base + sub + "</" + under + ">"
+	case hasSup:
+		return "<" + over + ">" + base + sup + "</" + over + ">"
+	}
+
+	return base
+}
+
+// parseArgument parses the argument of a command or script: a group or a
+// single atom.
+func (p *texParser) parseArgument() string {
+	if p.atCloser() || p.atEnd() {
+		return "<mrow></mrow>"
+	}
+
+	atom, _ := p.parseAtom(true)
+	return atom
+}
+
+// parseStyled parses an argument with letters in the given font style.
+func (p *texParser) parseStyled(style string) string {
+	outer := p.style
+	p.style = style
+	defer func() { p.style = outer }()
+
+	return p.parseArgument()
+}
+
+// parseAtom parses one element. As an argument a number is a single digit,
+// as TeX has it: x^23 is x squared followed by 3. Limits is set for
+// operators whose scripts go above and below them.
+func (p *texParser) parseAtom(argument bool) (markup string, limits bool) {
+	r := p.peek()
+
+	switch {
+	case r == '{':
+		p.pos++
+		items := p.parseList()
+		if p.peek() == '}' {
+			p.pos++
+		}
+		return "<mrow>" + strings.Join(items, "") + "</mrow>", false
+	case r == '_' || r == '^':
+		return "<mrow></mrow>", false
+	case r == '\\':
+		return p.parseCommand()
+	case unicode.IsDigit(r):
+		return p.parseNumber(argument), false
+	case unicode.IsLetter(r):
+		p.pos++
+		return p.identifier(r), false
+	case r == '~':
+		p.pos++
+		return texSpace("0.333em"), false
+	}
+
+	p.pos++
+	return mo(texCharOperator(r)), false
+}
+
+func (p *texParser) parseNumber(single bool) string {
+	start := p.pos
+	p.pos++
+	for !single && !p.atEnd() {
+		r := p.src[p.pos]
+		if unicode.IsDigit(r) ||
+			(r == '.' && p.pos+1 < len(p.src) && unicode.IsDigit(p.src[p.pos+1])) {
+			p.pos++
+			continue
+		}
+		break
+	}
+
+	var number strings.Builder
+	for _, r := range p.src[start:p.pos] {
+		number.WriteRune(styledRune(r, p.style))
+	}
+
+	return "<mn>" + html.EscapeString(number.String()) + "</mn>"
+}
+
+func (p *texParser) identifier(r rune) string {
+	if p.style == "normal" {
+		return `<mi mathvariant="normal">` + html.EscapeString(string(r)) + "</mi>"
+	}
+
+	return "<mi>" + html.EscapeString(string(styledRune(r, p.style))) + "</mi>"
+}
+
+func (p *texParser) parseCommand() (string, bool) {
+	name := p.readCommand()
+
+	if symbol, ok := texIdentifiers[name]; ok {
+		return "<mi>" + symbol + "</mi>", false
+	}
+	if symbol, ok := texUprightIdentifiers[name]; ok {
+		return `<mi mathvariant="normal">` + symbol + "</mi>", false
+	}
+	if symbol, ok := texOperators[name]; ok {
+		return mo(symbol), false
+	}
+	if symbol, ok := texLargeOperators[name]; ok {
+		return `<mo largeop="true" movablelimits="true">` + symbol + "</mo>", true
+	}
+	if symbol, ok := texIntegrals[name]; ok {
+		return `<mo largeop="true">` + symbol + "</mo>", false
+	}
+	if texFunctions[name] {
+		return "<mi>" + name + "</mi>", false
+	}
+	if texLimitFunctions[name] {
+		return `<mo form="prefix" movablelimits="true">` + name + "</mo>", true
+	}
+	if width, ok := texSpaces[name]; ok {
+		return texSpace(width), false
+	}
+	if accent, ok := texAccents[name]; ok {
+		return "<mover accent=\"true\">" + p.parseArgument() + mo(accent) + "</mover>", false
+	}
+	if style, ok := texStyles[name]; ok {
+		return p.parseStyled(style), false
+	}
+
+	switch name {
+	case "{", "}", "|", "%", "$", "#", "&", "_":
+		if name == "|" {
+			name = "‖"
+		}
+		return mo(html.EscapeString(name)), false
+	case "frac", "dfrac", "tfrac":
+		numerator := p.parseArgument()
+		return "<mfrac>" + numerator + p.parseArgument() + "</mfrac>", false
+	case "binom":
+		top := p.parseArgument()
+		return `<mrow><mo>(</mo><mfrac linethickness="0">` + top + p.parseArgument() +
+			"</mfrac><mo>)</mo></mrow>", false
+	case "sqrt":
+		if p.peek() == '[' {
+			index := p.readUntil(']')
+			return "<mroot>" + p.parseArgument() + texToMathML(index) + "</mroot>", false
+		}
+		return "<msqrt>" + p.parseArgument() + "</msqrt>", false
+	case "text", "textrm", "textnormal", "mbox":
+		return "<mtext>" + html.EscapeString(p.readGroupText()) + "</mtext>", false
+	case "operatorname":
+		return "<mi>" + html.EscapeString(p.readGroupText()) + "</mi>", false
+	case "underline":
+		return `<munder accentunder="true">` + p.parseArgument() + mo("_") + "</munder>", false
+	case "left":
+		return p.parseFenced(), false
+	case "big", "Big", "bigg", "Bigg", "bigl", "bigr", "Bigl", "Bigr", "biggl", "biggr":
+		return mo(p.readDelimiter()), false
+	case "begin":
+		return p.parseEnvironment(p.readGroupText()), false
+	}
+
+	return "<merror><mtext>\\" + html.EscapeString(name) + "</mtext></merror>", false
+}
+
+// parseFenced parses \left ... \right, with the delimiters stretching to
+// the height of what they enclose. A "." delimiter is left out.
+func (p *texParser) parseFenced() string {
+	open := p.readDelimiter()
+	items := p.parseList()
+
+	closing := ""
+	if p.peekCommand() == "right" {
+		p.readCommand()
+		closing = p.readDelimiter()
+	}
+
+	var row strings.Builder
+	row.WriteString("<mrow>")
+	if open != "" {
+		row.WriteString(`<mo fence="true" form="prefix">` + open + "</mo>")
+	}
+	row.WriteString(strings.Join(items, ""))
+	if closing != "" {
+		row.WriteString(`<mo fence="true" form="postfix">` + closing + "</mo>")
+	}
+	row.WriteString("</mrow>")
+
+	return row.String()
+}
+
+// readDelimiter reads the delimiter after \left, \right or \big and returns
+// it escaped.
+func (p *texParser) readDelimiter() string {
+	switch r := p.peek(); r {
+	case 0:
+		return ""
+	case '.':
+		p.pos++
+		return ""
+	case '\\':
+		name := p.readCommand()
+		if delimiter, ok := texDelimiters[name]; ok {
+			return delimiter
+		}
+		return html.EscapeString(name)
+	default:
+		p.pos++
+		return html.EscapeString(string(r))
+	}
+}
+
+// texEnvironmentFences are the delimiters around each matrix environment.
+var texEnvironmentFences = map[string][2]string{
+	"pmatrix": {"(", ")"},
+	"bmatrix": {"[", "]"},
+	"Bmatrix": {"{", "}"},
+	"vmatrix": {"|", "|"},
+	"Vmatrix": {"‖", "‖"},
+	"cases":   {"{", ""},
+}
+
+// parseEnvironment parses the rows and cells of a \begin ... \end block
+// into a table.
+func (p *texParser) parseEnvironment(name string) string {
+	var rows [][]string
+	row := []string{}
+	for {
+		row = append(row, strings.Join(p.parseList(), ""))
+
+		if p.atEnd() {
+			break
+		}
+		if p.peek() == '&' {
+			p.pos++
+			continue
+		}
+
+		command := p.peekCommand()
+		if command == "\\" {
+			p.readCommand()
+			rows = append(rows, row)
+			row = []string{}
+			continue
+		}
+		if command == "end" {
+			p.readCommand()
+			p.readGroupText()
+			break
+		}
+		p.skipCloser()
+	}
+	if len(row) > 1 || row[0] != "" {
+		rows = append(rows, row)
+	}
+
+	attributes := ""
+	switch name {
+	case "cases":
+		attributes = ` columnalign="left left"`
+	case "aligned", "align", "align*", "split":
+		attributes = ` columnalign="right left" displaystyle="true"`
+	}
+
+	var table strings.Builder
+	table.WriteString("<mtable" + attributes + ">")
+	for _, cells := range rows {
+		table.WriteString("<mtr>")
+		for _, cell := range cells {
+			table.WriteString("<mtd>" + cell + "</mtd>")
+		}
+		table.WriteString("</mtr>")
+	}
+	table.WriteString("</mtable>")
+
+	fences, ok := texEnvironmentFences[name]
+	if !ok {
+		return table.String()
+	}
+
+	fenced := "<mrow>"
+	if fences[0] != "" {
+		fenced += `<mo fence="true" form="prefix">` + fences[0] + "</mo>"
+	}
+	fenced += table.String()
+	if fences[1] != "" {
+		fenced += `<mo fence="true" form="postfix">` + fences[1] + "</mo>"
+	}
+
+	return fenced + "</mrow>"
+}
+
+// readGroupText consumes a braced group and returns its raw text.
+func (p *texParser) readGroupText() string {
+	if p.peek() != '{' {
+		return ""
+	}
+	p.pos++
+
+	start, depth := p.pos, 1
+	for ; !p.atEnd(); p.pos++ {
+		switch p.src[p.pos] {
+		case '\\':
+			p.pos++
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				text := string(p.src[start:p.pos])
+				p.pos++
+				return text
+			}
+		}
+	}
+
+	return string(p.src[start:min(p.pos, len(p.src))])
+}
+
+// readUntil consumes an opening bracket and the text up to closing, which
+// is consumed too.
+func (p *texParser) readUntil(closing rune) string {
+	p.pos++
+	start := p.pos
+	for !p.atEnd() && p.src[p.pos] != closing {
+		p.pos++
+	}
+
+	text := string(p.src[start:p.pos])
+	if !p.atEnd() {
+		p.pos++
+	}
+
+	return text
+}
+
+func mo(symbol string) string {
+	return "<mo>" + symbol + "</mo>"
+}
+
+func texSpace(width string) string {
+	return `<mspace width="` + width + `"></mspace>`
+}
+
+// texCharOperator returns the escaped operator a character stands for.
+func texCharOperator(r rune) string {
+	switch r {
+	case '-':
+		return "−"
+	case '*':
+		return "∗"
+	}
+
+	return html.EscapeString(string(r))
+}
+
+// mathAlphabets are where the letters of each font style start in the
+// Mathematical Alphanumeric Symbols block, as capitals, small letters and
+// digits. MathML Core only styles identifiers through these characters.
+var mathAlphabets = map[string][3]rune{
+	"bold":          {0x1D400, 0x1D41A, 0x1D7CE},
+	"double-struck": {0x1D538, 0x1D552, 0x1D7D8},
+	"script":        {0x1D49C, 0x1D4B6, 0},
+	"fraktur":       {0x1D504, 0x1D51E, 0},
+}
+
+// mathAlphabetHoles are the letters that were encoded before the block and
+// are left out of it.
+var mathAlphabetHoles = map[string]map[rune]rune{
+	"double-struck": {'C': 'ℂ', 'H': 'ℍ', 'N': 'ℕ', 'P': 'ℙ', 'Q': 'ℚ', 'R': 'ℝ', 'Z': 'ℤ'},
+	"script": {
+		'B': 'ℬ', 'E': 'ℰ', 'F': 'ℱ', 'H': 'ℋ', 'I': 'ℐ', 'L': 'ℒ', 'M': 'ℳ', 'R': 'ℛ',
+		'e': 'ℯ', 'g': 'ℊ', 'o': 'ℴ',
+	},
+	"fraktur": {'C': 'ℭ', 'H': 'ℌ', 'I': 'ℑ', 'R': 'ℜ', 'Z': 'ℨ'},
+}
+
+func styledRune(r rune, style string) rune {
+	alphabet, ok := mathAlphabets[style]
+	if !ok {
+		return r
+	}
+	if hole, ok := mathAlphabetHoles[style][r]; ok {
+		return hole
+	}
+
+	switch {
+	case r >= 'A' && r <= 'Z':
+		return alphabet[0] + r - 'A'
+	case r >= 'a' && r <= 'z':
+		return alphabet[1] + r - 'a'
+	case r >= '0' && r <= '9' && alphabet[2] != 0:
+		return alphabet[2] + r - '0'
+	}
+
+	return r
+}
+
+var texStyles = map[string]string{
+	"mathrm":     "normal",
+	"mathit":     "",
+	"mathbf":     "bold",
+	"boldsymbol": "bold",
+	"mathbb":     "double-struck",
+	"mathcal":    "script",
+	"mathscr":    "script",
+	"mathfrak":   "fraktur",
+}
+
+var texIdentifiers = map[string]string{
+	"alpha": "α", "beta": "β", "gamma": "γ", "delta": "δ", "epsilon": "ϵ",
+	"varepsilon": "ε", "zeta": "ζ", "eta": "η", "theta": "θ", "vartheta": "ϑ",
+	"iota": "ι", "kappa": "κ", "lambda": "λ", "mu": "μ", "nu": "ν", "xi": "ξ",
+	"pi": "π", "varpi": "ϖ", "rho": "ρ", "varrho": "ϱ", "sigma": "σ",
+	"varsigma": "ς", "tau": "τ", "upsilon": "υ", "phi": "ϕ", "varphi": "φ",
+	"chi": "χ", "psi": "ψ", "omega": "ω",
+	"ell": "ℓ", "hbar": "ℏ", "infty": "∞", "emptyset": "∅", "varnothing": "∅",
+	"nabla": "∇", "partial": "∂", "aleph": "ℵ", "Re": "ℜ", "Im": "ℑ",
+}
+
+// texUprightIdentifiers are capital Greek letters, which TeX sets upright.
+var texUprightIdentifiers = map[string]string{
+	"Gamma": "Γ", "Delta": "Δ", "Theta": "Θ", "Lambda": "Λ", "Xi": "Ξ",
+	"Pi": "Π", "Sigma": "Σ", "Upsilon": "Υ", "Phi": "Φ", "Psi": "Ψ", "Omega": "Ω",
+}
+
+var texOperators = map[string]string{
+	"pm": "±", "mp": "∓", "times": "×", "div": "÷", "cdot": "⋅", "ast": "∗",
+	"star": "⋆", "circ": "∘", "bullet": "∙", "oplus": "⊕", "otimes": "⊗",
+	"le": "≤", "leq": "≤", "ge": "≥", "geq": "≥", "ne": "≠", "neq": "≠",
+	"approx": "≈", "equiv": "≡", "sim": "∼", "simeq": "≃", "cong": "≅",
+	"propto": "∝", "ll": "≪", "gg": "≫", "in": "∈", "notin": "∉", "ni": "∋",
+	"subset": "⊂", "subseteq": "⊆", "supset": "⊃", "supseteq": "⊇",
+	"cup": "∪", "cap": "∩", "setminus": "∖", "wedge": "∧", "land": "∧",
+	"vee": "∨", "lor": "∨", "neg": "¬", "lnot": "¬", "forall": "∀",
+	"exists": "∃", "to": "→", "rightarrow": "→", "leftarrow": "←", "gets": "←",
+	"leftrightarrow": "↔", "Rightarrow": "⇒", "Leftarrow": "⇐",
+	"Leftrightarrow": "⇔", "iff": "⟺", "implies": "⟹", "mapsto": "↦",
+	"uparrow": "↑", "downarrow": "↓", "ldots": "…", "dots": "…", "cdots": "⋯",
+	"vdots": "⋮", "ddots": "⋱", "mid": "∣", "parallel": "∥", "perp": "⊥",
+	"angle": "∠", "triangle": "△", "prime": "′", "colon": ":",
+	"langle": "⟨", "rangle": "⟩", "lceil": "⌈", "rceil": "⌉",
+	"lfloor": "⌊", "rfloor": "⌋", "vert": "|", "Vert": "‖",
+}
+
+var texLargeOperators = map[string]string{
+	"sum": "∑", "prod": "∏", "coprod": "∐", "bigcup": "⋃", "bigcap": "⋂",
+	"bigoplus": "⨁", "bigotimes": "⨂", "bigvee": "⋁", "bigwedge": "⋀",
+}
+
+var texIntegrals = map[string]string{
+	"int": "∫", "iint": "∬", "iiint": "∭", "oint": "∮",
+}
+
+var texFunctions = map[string]bool{
+	"sin": true, "cos": true, "tan": true, "sec": true, "csc": true, "cot": true,
+	"arcsin": true, "arccos": true, "arctan": true, "sinh": true, "cosh": true,
+	"tanh": true, "log": true, "ln": true, "lg": true, "exp": true, "det": true,
+	"dim": true, "gcd": true, "deg": true, "arg": true, "ker": true, "hom": true,
+	"Pr": true,
+}
+
+// texLimitFunctions take their scripts below them in display math.
+var texLimitFunctions = map[string]bool{
+	"lim": true, "max": true, "min": true, "sup": true, "inf": true,
+	"limsup": true, "liminf": true, "argmax": true, "argmin": true,
+}
+
+var texSpaces = map[string]string{
+	",": "0.167em", ":": "0.222em", ">": "0.222em", ";": "0.278em",
+	" ": "0.25em", "!": "-0.167em", "quad": "1em", "qquad": "2em",
+}
+
+var texAccents = map[string]string{
+	"hat": "^", "widehat": "^", "bar": "¯", "overline": "‾", "vec": "→",
+	"dot": "˙", "ddot": "¨", "tilde": "~", "widetilde": "~",
+}
+
+var texDelimiters = map[string]string{
+	"{": "{", "}": "}", "|": "‖", "langle": "⟨", "rangle": "⟩",
+	"lvert": "|", "rvert": "|", "vert": "|", "lVert": "‖", "rVert": "‖",
+	"Vert": "‖", "lceil": "⌈", "rceil": "⌉", "lfloor": "⌊", "rfloor": "⌋",
+	"backslash": "∖",
+}
@@ -0,0 +1,156 @@
+package models
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"mortenvistisen/internal/activitypub"
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models/internal/db"
+)
+
+// ActivityPubSiteKey names the key the site actor signs with.
+const ActivityPubSiteKey = "site"
+
+// ActivityPubKey is a signing key pair for an actor. It is generated the
+// first time it is needed and kept in the database so every instance of the
+// app signs with the same key.
+type ActivityPubKey struct {
+	Name         string
+	CreatedAt    time.Time
+	PrivateKey   *rsa.PrivateKey
+	PublicKeyPEM string
+}
+
+// ActivityPubFollower is a remote actor that follows the site.
+type ActivityPubFollower struct {
+	ID               uuid.UUID
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	ActorID          string
+	Inbox            string
+	SharedInbox      string
+	FollowActivityID string
+}
+
+// FindOrCreateActivityPubKey returns the key stored under name, generating
+// it if there is none yet. Concurrent callers all end up with the key that
+// was stored first.
+func FindOrCreateActivityPubKey(
+	ctx context.Context,
+	exec storage.Executor,
+	name string,
+) (ActivityPubKey, error) {
+	row, err := queries.QueryActivityPubKeyByName(ctx, exec, name)
+	if errors.Is(err, pgx.ErrNoRows) {
+		privatePEM, publicPEM, genErr := activitypub.GenerateKey()
+		if genErr != nil {
+			return ActivityPubKey{}, genErr
+		}
+
+		if err := queries.InsertActivityPubKey(ctx, exec, db.InsertActivityPubKeyParams{
+			Name:          name,
+			PrivateKeyPem: privatePEM,
+			PublicKeyPem:  publicPEM,
+		}); err != nil {
+			return ActivityPubKey{}, err
+		}
+
+		row, err = queries.QueryActivityPubKeyByName(ctx, exec, name)
+	}
+	if err != nil {
+		return ActivityPubKey{}, err
+	}
+
+	privateKey, err := activitypub.ParsePrivateKey(row.PrivateKeyPem)
+	if err != nil {
+		return ActivityPubKey{}, err
+	}
+
+	return ActivityPubKey{
+		Name:         row.Name,
+		CreatedAt:    row.CreatedAt.Time,
+		PrivateKey:   privateKey,
+		PublicKeyPEM: row.PublicKeyPem,
+	}, nil
+}
+
+type CreateActivityPubFollowerData struct {
+	ActorID          string `validate:"required,url"`
+	Inbox            string `validate:"required,url"`
+	SharedInbox      string `validate:"omitempty,url"`
+	FollowActivityID string
+}
+
+// CreateActivityPubFollower stores a follower. Following again refreshes the
+// stored inboxes instead of adding a duplicate.
+func CreateActivityPubFollower(
+	ctx context.Context,
+	exec storage.Executor,
+	data CreateActivityPubFollowerData,
+) (ActivityPubFollower, error) {
+	if err := Validate.Struct(data); err != nil {
+		return ActivityPubFollower{}, errors.Join(ErrDomainValidation, err)
+	}
+
+	row, err := queries.UpsertActivityPubFollower(ctx, exec, db.UpsertActivityPubFollowerParams{
+		ID:               uuid.New(),
+		ActorID:          data.ActorID,
+		Inbox:            data.Inbox,
+		SharedInbox:      data.SharedInbox,
+		FollowActivityID: data.FollowActivityID,
+	})
+	if err != nil {
+		return ActivityPubFollower{}, err
+	}
+
+	return rowToActivityPubFollower(row), nil
+}
+
+// DestroyActivityPubFollower removes the follower with actorID and reports
+// whether there was one.
+func DestroyActivityPubFollower(
+	ctx context.Context,
+	exec storage.Executor,
+	actorID string,
+) (bool, error) {
+	deleted, err := queries.DeleteActivityPubFollowerByActorID(ctx, exec, actorID)
+	if err != nil {
+		return false, err
+	}
+
+	return deleted > 0, nil
+}
+
+// ActivityPubFollowerInboxes returns the inboxes to deliver to, using shared
+// inboxes where followers have one so each server gets a single request.
+func ActivityPubFollowerInboxes(
+	ctx context.Context,
+	exec storage.Executor,
+) ([]string, error) {
+	return queries.QueryActivityPubFollowerInboxes(ctx, exec)
+}
+
+func CountActivityPubFollowers(
+	ctx context.Context,
+	exec storage.Executor,
+) (int64, error) {
+	return queries.CountActivityPubFollowers(ctx, exec)
+}
+
+func rowToActivityPubFollower(row db.ActivitypubFollower) ActivityPubFollower {
+	return ActivityPubFollower{
+		ID:               row.ID,
+		CreatedAt:        row.CreatedAt.Time,
+		UpdatedAt:        row.UpdatedAt.Time,
+		ActorID:          row.ActorID,
+		Inbox:            row.Inbox,
+		SharedInbox:      row.SharedInbox,
+		FollowActivityID: row.FollowActivityID,
+	}
+}
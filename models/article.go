@@ -5,6 +5,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/gosimple/slug"
 	"github.com/jackc/pgx/v5/pgtype"
 
@@ -24,8 +25,18 @@ type Article struct {
 	MetaDescription  string
 	Slug             string
 	ImageLink        string
+	// ReadTime is the read time shown with the article: set by hand when
+	// ReadTimeOverride is true, otherwise Stats.ReadTime.
 	ReadTime         int32
+	ReadTimeOverride bool
+	Stats            ContentStats
 	Content          string
+	AuthorID         uuid.UUID
+	CommentsOpen     bool
+	// TableOfContents shows a table of contents and heading permalinks on
+	// the article's page.
+	TableOfContents bool
+	Rendered        RenderedContent
 }
 
 func FindArticle(
@@ -63,8 +74,18 @@ type CreateArticleData struct {
 	MetaDescription  string
 	Slug             string
 	ImageLink        string
-	ReadTime         int32
-	Content          string
+	// ReadTime overrides the estimated read time when above zero.
+	ReadTime int32
+	Content  string
+	AuthorID uuid.UUID
+	// CreatedAt and UpdatedAt are only read by UpsertArticle, so restored
+	// content keeps its original timestamps. Zero means now.
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	// CommentsOpen and TableOfContents are only read by UpsertArticle.
+	// CreateArticle leaves both on.
+	CommentsOpen    bool
+	TableOfContents bool
 }
 
 func CreateArticle(
@@ -76,20 +97,29 @@ func CreateArticle(
 		return Article{}, errors.Join(ErrDomainValidation, err)
 	}
 
+	stats := CountContentStats(data.Content)
+	readTime, override := readTimeOrEstimate(data.ReadTime, stats)
+
 	params := db.InsertArticleParams{
 		FirstPublishedAt: pgtype.Timestamptz{
 			Time:  time.Now(),
 			Valid: data.Published,
 		},
-		Published:       data.Published,
-		Title:           data.Title,
-		Excerpt:         pgtype.Text{String: data.Excerpt, Valid: true},
-		MetaTitle:       pgtype.Text{String: data.MetaTitle, Valid: true},
-		MetaDescription: pgtype.Text{String: data.MetaDescription, Valid: true},
-		Slug:            slug.Make(data.Title),
-		ImageLink:       pgtype.Text{String: data.ImageLink, Valid: true},
-		ReadTime:        pgtype.Int4{Int32: data.ReadTime, Valid: true},
-		Content:         pgtype.Text{String: data.Content, Valid: true},
+		Published:         data.Published,
+		Title:             data.Title,
+		Excerpt:           pgtype.Text{String: data.Excerpt, Valid: true},
+		MetaTitle:         pgtype.Text{String: data.MetaTitle, Valid: true},
+		MetaDescription:   pgtype.Text{String: data.MetaDescription, Valid: true},
+		Slug:              slug.Make(data.Title),
+		ImageLink:         pgtype.Text{String: data.ImageLink, Valid: true},
+		ReadTime:          pgtype.Int4{Int32: readTime, Valid: true},
+		ReadTimeOverride:  override,
+		EstimatedReadTime: stats.ReadTime,
+		WordCount:         stats.WordCount,
+		ImageCount:        stats.ImageCount,
+		HeadingCount:      stats.HeadingCount,
+		Content:           pgtype.Text{String: data.Content, Valid: true},
+		AuthorID:          pgtype.UUID{Bytes: data.AuthorID, Valid: data.AuthorID != uuid.Nil},
 	}
 	row, err := queries.InsertArticle(ctx, exec, params)
 	if err != nil {
@@ -109,8 +139,9 @@ type UpdateArticleData struct {
 	MetaDescription string
 	Slug            string
 	ImageLink       string
-	ReadTime        int32
-	Content         string
+	// ReadTime overrides the estimated read time when above zero.
+	ReadTime int32
+	Content  string
 }
 
 func UpdateArticle(
@@ -132,21 +163,29 @@ func UpdateArticle(
 		firstPublishedAt = time.Now().UTC()
 	}
 
+	stats := CountContentStats(data.Content)
+	readTime, override := readTimeOrEstimate(data.ReadTime, stats)
+
 	params := db.UpdateArticleParams{
 		ID: data.ID,
 		FirstPublishedAt: pgtype.Timestamptz{
 			Time:  firstPublishedAt,
 			Valid: !firstPublishedAt.IsZero(),
 		},
-		Published:       data.Published,
-		Title:           data.Title,
-		Excerpt:         pgtype.Text{String: data.Excerpt, Valid: true},
-		MetaTitle:       pgtype.Text{String: data.MetaTitle, Valid: true},
-		MetaDescription: pgtype.Text{String: data.MetaDescription, Valid: true},
-		Slug:            data.Slug,
-		ImageLink:       pgtype.Text{String: data.ImageLink, Valid: true},
-		ReadTime:        pgtype.Int4{Int32: data.ReadTime, Valid: true},
-		Content:         pgtype.Text{String: data.Content, Valid: true},
+		Published:         data.Published,
+		Title:             data.Title,
+		Excerpt:           pgtype.Text{String: data.Excerpt, Valid: true},
+		MetaTitle:         pgtype.Text{String: data.MetaTitle, Valid: true},
+		MetaDescription:   pgtype.Text{String: data.MetaDescription, Valid: true},
+		Slug:              data.Slug,
+		ImageLink:         pgtype.Text{String: data.ImageLink, Valid: true},
+		ReadTime:          pgtype.Int4{Int32: readTime, Valid: true},
+		ReadTimeOverride:  override,
+		EstimatedReadTime: stats.ReadTime,
+		WordCount:         stats.WordCount,
+		ImageCount:        stats.ImageCount,
+		HeadingCount:      stats.HeadingCount,
+		Content:           pgtype.Text{String: data.Content, Valid: true},
 	}
 
 	row, err := queries.UpdateArticle(ctx, exec, params)
@@ -157,6 +196,104 @@ func UpdateArticle(
 	return rowToArticle(row), nil
 }
 
+// SetArticleCommentsOpen opens or closes an article for new comments.
+// Comments already posted stay visible either way.
+func SetArticleCommentsOpen(
+	ctx context.Context,
+	exec storage.Executor,
+	id int32,
+	open bool,
+) (Article, error) {
+	row, err := queries.UpdateArticleCommentsOpen(ctx, exec, db.UpdateArticleCommentsOpenParams{
+		ID:           id,
+		CommentsOpen: open,
+	})
+	if err != nil {
+		return Article{}, err
+	}
+
+	return rowToArticle(row), nil
+}
+
+// SetArticleTableOfContents turns the table of contents and heading
+// permalinks of an article on or off.
+func SetArticleTableOfContents(
+	ctx context.Context,
+	exec storage.Executor,
+	id int32,
+	enabled bool,
+) (Article, error) {
+	row, err := queries.UpdateArticleTableOfContents(ctx, exec, db.UpdateArticleTableOfContentsParams{
+		ID:              id,
+		TableOfContents: enabled,
+	})
+	if err != nil {
+		return Article{}, err
+	}
+
+	return rowToArticle(row), nil
+}
+
+// SetArticleRenderedContent stores the rendered content of an article. It
+// leaves updated_at alone, as the article itself has not changed.
+func SetArticleRenderedContent(
+	ctx context.Context,
+	exec storage.Executor,
+	id int32,
+	rendered RenderedContent,
+) (Article, error) {
+	headings, err := headingsToJSON(rendered.Headings)
+	if err != nil {
+		return Article{}, err
+	}
+
+	row, err := queries.UpdateArticleContentHTML(ctx, exec, db.UpdateArticleContentHTMLParams{
+		ID:              id,
+		ContentHtml:     rendered.HTML,
+		ContentHash:     rendered.Hash,
+		ContentHeadings: headings,
+	})
+	if err != nil {
+		return Article{}, err
+	}
+
+	return rowToArticle(row), nil
+}
+
+// RecountArticleStats counts the stats of every article again and stores
+// the ones that changed, leaving updated_at alone. Read times set by hand
+// are kept. It returns how many articles changed.
+func RecountArticleStats(
+	ctx context.Context,
+	exec storage.Executor,
+) (int, error) {
+	articles, err := AllArticles(ctx, exec)
+	if err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	for _, article := range articles {
+		stats := CountContentStats(article.Content)
+		if stats == article.Stats {
+			continue
+		}
+
+		if err := queries.UpdateArticleStats(ctx, exec, db.UpdateArticleStatsParams{
+			ID:                article.ID,
+			EstimatedReadTime: stats.ReadTime,
+			WordCount:         stats.WordCount,
+			ImageCount:        stats.ImageCount,
+			HeadingCount:      stats.HeadingCount,
+		}); err != nil {
+			return changed, err
+		}
+		changed++
+	}
+
+	return changed, nil
+}
+
 func DestroyArticle(
 	ctx context.Context,
 	exec storage.Executor,
@@ -276,20 +413,33 @@ func UpsertArticle(
 		firstPublishedAt = time.Now().UTC()
 	}
 
+	stats := CountContentStats(data.Content)
+	readTime, override := readTimeOrEstimate(data.ReadTime, stats)
+
 	params := db.UpsertArticleParams{
+		CreatedAt: pgtype.Timestamptz{Time: data.CreatedAt, Valid: !data.CreatedAt.IsZero()},
+		UpdatedAt: pgtype.Timestamptz{Time: data.UpdatedAt, Valid: !data.UpdatedAt.IsZero()},
 		FirstPublishedAt: pgtype.Timestamptz{
 			Time:  firstPublishedAt,
 			Valid: !firstPublishedAt.IsZero(),
 		},
-		Published:       data.Published,
-		Title:           data.Title,
-		Excerpt:         pgtype.Text{String: data.Excerpt, Valid: true},
-		MetaTitle:       pgtype.Text{String: data.MetaTitle, Valid: true},
-		MetaDescription: pgtype.Text{String: data.MetaDescription, Valid: true},
-		Slug:            data.Slug,
-		ImageLink:       pgtype.Text{String: data.ImageLink, Valid: true},
-		ReadTime:        pgtype.Int4{Int32: data.ReadTime, Valid: true},
-		Content:         pgtype.Text{String: data.Content, Valid: true},
+		Published:         data.Published,
+		Title:             data.Title,
+		Excerpt:           pgtype.Text{String: data.Excerpt, Valid: true},
+		MetaTitle:         pgtype.Text{String: data.MetaTitle, Valid: true},
+		MetaDescription:   pgtype.Text{String: data.MetaDescription, Valid: true},
+		Slug:              data.Slug,
+		ImageLink:         pgtype.Text{String: data.ImageLink, Valid: true},
+		ReadTime:          pgtype.Int4{Int32: readTime, Valid: true},
+		ReadTimeOverride:  override,
+		EstimatedReadTime: stats.ReadTime,
+		WordCount:         stats.WordCount,
+		ImageCount:        stats.ImageCount,
+		HeadingCount:      stats.HeadingCount,
+		Content:           pgtype.Text{String: data.Content, Valid: true},
+		AuthorID:          pgtype.UUID{Bytes: data.AuthorID, Valid: data.AuthorID != uuid.Nil},
+		CommentsOpen:      data.CommentsOpen,
+		TableOfContents:   data.TableOfContents,
 	}
 	row, err := queries.UpsertArticle(ctx, exec, params)
 	if err != nil {
@@ -306,6 +456,33 @@ func CountArticles(
 	return queries.CountArticles(ctx, exec)
 }
 
+// ArticlesChange marks the state of all articles. Creating, updating or
+// deleting an article gives a different mark.
+type ArticlesChange struct {
+	Count         int64
+	LastUpdatedAt time.Time
+}
+
+// Equal reports whether no article changed between the two marks.
+func (c ArticlesChange) Equal(other ArticlesChange) bool {
+	return c.Count == other.Count && c.LastUpdatedAt.Equal(other.LastUpdatedAt)
+}
+
+func FindArticlesChange(
+	ctx context.Context,
+	exec storage.Executor,
+) (ArticlesChange, error) {
+	row, err := queries.QueryArticlesChange(ctx, exec)
+	if err != nil {
+		return ArticlesChange{}, err
+	}
+
+	return ArticlesChange{
+		Count:         row.Total,
+		LastUpdatedAt: row.LastUpdatedAt.Time,
+	}, nil
+}
+
 func AttachTagsToArticle(
 	ctx context.Context,
 	exec storage.Executor,
@@ -358,6 +535,24 @@ func TagIDsForArticle(
 	return tagIDs, nil
 }
 
+// ArticleTagIDs maps every article with tags to the IDs of its tags.
+func ArticleTagIDs(
+	ctx context.Context,
+	exec storage.Executor,
+) (map[int32][]int32, error) {
+	connections, err := queries.QueryArticleTagConnection(ctx, exec)
+	if err != nil {
+		return nil, err
+	}
+
+	tagIDs := make(map[int32][]int32)
+	for _, connection := range connections {
+		tagIDs[connection.ArticleID] = append(tagIDs[connection.ArticleID], connection.TagID)
+	}
+
+	return tagIDs, nil
+}
+
 func ReplaceTagsForArticle(
 	ctx context.Context,
 	exec storage.Executor,
@@ -407,6 +602,21 @@ func rowToArticle(row db.Article) Article {
 		Slug:             row.Slug,
 		ImageLink:        row.ImageLink.String,
 		ReadTime:         row.ReadTime.Int32,
-		Content:          row.Content.String,
+		ReadTimeOverride: row.ReadTimeOverride,
+		Stats: ContentStats{
+			WordCount:    row.WordCount,
+			ImageCount:   row.ImageCount,
+			HeadingCount: row.HeadingCount,
+			ReadTime:     row.EstimatedReadTime,
+		},
+		Content:         row.Content.String,
+		AuthorID:        row.AuthorID.Bytes,
+		CommentsOpen:    row.CommentsOpen,
+		TableOfContents: row.TableOfContents,
+		Rendered: RenderedContent{
+			HTML:     row.ContentHtml,
+			Hash:     row.ContentHash,
+			Headings: headingsFromJSON(row.ContentHeadings),
+		},
 	}
 }
@@ -0,0 +1,219 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models/internal/db"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// AuditChange holds the value of a single field before and after an action.
+type AuditChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+type AuditLog struct {
+	ID         int64
+	CreatedAt  time.Time
+	ActorID    uuid.UUID
+	ActorEmail string
+	Action     string
+	TargetType string
+	TargetID   string
+	Changes    map[string]AuditChange
+	IPAddress  string
+	UserAgent  string
+	RequestID  string
+}
+
+type CreateAuditLogData struct {
+	ActorID    uuid.UUID
+	ActorEmail string `validate:"max=255"`
+	Action     string `validate:"required,max=100"`
+	TargetType string `validate:"required,max=50"`
+	TargetID   string `validate:"max=100"`
+	Changes    map[string]AuditChange
+	IPAddress  string `validate:"max=100"`
+	UserAgent  string
+	RequestID  string `validate:"max=100"`
+}
+
+func CreateAuditLog(
+	ctx context.Context,
+	exec storage.Executor,
+	data CreateAuditLogData,
+) (AuditLog, error) {
+	if err := Validate.Struct(data); err != nil {
+		return AuditLog{}, errors.Join(ErrDomainValidation, err)
+	}
+
+	changes := data.Changes
+	if changes == nil {
+		changes = map[string]AuditChange{}
+	}
+	encoded, err := json.Marshal(changes)
+	if err != nil {
+		return AuditLog{}, err
+	}
+
+	row, err := queries.InsertAuditLog(ctx, exec, db.InsertAuditLogParams{
+		ActorID:    pgtype.UUID{Bytes: data.ActorID, Valid: data.ActorID != uuid.Nil},
+		ActorEmail: data.ActorEmail,
+		Action:     data.Action,
+		TargetType: data.TargetType,
+		TargetID:   data.TargetID,
+		Changes:    encoded,
+		IpAddress:  data.IPAddress,
+		UserAgent:  data.UserAgent,
+		RequestID:  data.RequestID,
+	})
+	if err != nil {
+		return AuditLog{}, err
+	}
+
+	return rowToAuditLog(row), nil
+}
+
+// AuditLogFilter narrows the audit log listing. Zero values are ignored.
+type AuditLogFilter struct {
+	ActorEmail string
+	Action     string
+	TargetType string
+	TargetID   string
+	Since      time.Time
+	Until      time.Time
+}
+
+type PaginatedAuditLogs struct {
+	Logs       []AuditLog
+	TotalCount int64
+	Page       int64
+	PageSize   int64
+	TotalPages int64
+}
+
+func PaginateAuditLogs(
+	ctx context.Context,
+	exec storage.Executor,
+	filter AuditLogFilter,
+	page int64,
+	pageSize int64,
+) (PaginatedAuditLogs, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	offset := (page - 1) * pageSize
+
+	totalCount, err := queries.CountFilteredAuditLogs(
+		ctx,
+		exec,
+		db.CountFilteredAuditLogsParams{
+			ActorEmail: optionalText(filter.ActorEmail),
+			Action:     optionalText(filter.Action),
+			TargetType: optionalText(filter.TargetType),
+			TargetID:   optionalText(filter.TargetID),
+			Since:      optionalTimestamptz(filter.Since),
+			Until:      optionalTimestamptz(filter.Until),
+		},
+	)
+	if err != nil {
+		return PaginatedAuditLogs{}, err
+	}
+
+	rows, err := queries.QueryFilteredAuditLogs(
+		ctx,
+		exec,
+		db.QueryFilteredAuditLogsParams{
+			ActorEmail: optionalText(filter.ActorEmail),
+			Action:     optionalText(filter.Action),
+			TargetType: optionalText(filter.TargetType),
+			TargetID:   optionalText(filter.TargetID),
+			Since:      optionalTimestamptz(filter.Since),
+			Until:      optionalTimestamptz(filter.Until),
+			Limit:      pageSize,
+			Offset:     offset,
+		},
+	)
+	if err != nil {
+		return PaginatedAuditLogs{}, err
+	}
+
+	logs := make([]AuditLog, len(rows))
+	for i, row := range rows {
+		logs[i] = rowToAuditLog(row)
+	}
+
+	totalPages := (totalCount + int64(pageSize) - 1) / int64(pageSize)
+
+	return PaginatedAuditLogs{
+		Logs:       logs,
+		TotalCount: totalCount,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func AllAuditLogActions(
+	ctx context.Context,
+	exec storage.Executor,
+) ([]string, error) {
+	return queries.QueryAuditLogActions(ctx, exec)
+}
+
+// DestroyAuditLogsBefore removes entries older than the given time and
+// reports how many were deleted. It is the only way entries leave the log.
+func DestroyAuditLogsBefore(
+	ctx context.Context,
+	exec storage.Executor,
+	before time.Time,
+) (int64, error) {
+	return queries.DeleteAuditLogsBefore(
+		ctx,
+		exec,
+		pgtype.Timestamptz{Time: before, Valid: true},
+	)
+}
+
+func optionalText(value string) pgtype.Text {
+	return pgtype.Text{String: value, Valid: value != ""}
+}
+
+func optionalTimestamptz(value time.Time) pgtype.Timestamptz {
+	return pgtype.Timestamptz{Time: value, Valid: !value.IsZero()}
+}
+
+func rowToAuditLog(row db.AuditLog) AuditLog {
+	changes := map[string]AuditChange{}
+	if len(row.Changes) > 0 {
+		_ = json.Unmarshal(row.Changes, &changes)
+	}
+
+	return AuditLog{
+		ID:         row.ID,
+		CreatedAt:  row.CreatedAt.Time,
+		ActorID:    row.ActorID.Bytes,
+		ActorEmail: row.ActorEmail,
+		Action:     row.Action,
+		TargetType: row.TargetType,
+		TargetID:   row.TargetID,
+		Changes:    changes,
+		IPAddress:  row.IpAddress,
+		UserAgent:  row.UserAgent,
+		RequestID:  row.RequestID,
+	}
+}
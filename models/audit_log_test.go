@@ -0,0 +1,52 @@
+package models_test
+
+import (
+	"context"
+	"testing"
+
+	"mortenvistisen/database"
+	"mortenvistisen/models"
+	"mortenvistisen/models/factories"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func TestDestroyUserKeepsAuditLogs(t *testing.T) {
+	tdb := database.RequireTestDB(t)
+	ctx := context.Background()
+
+	tdb.WithTx(t, func(tx pgx.Tx) {
+		user, err := factories.CreateUser(ctx, tx)
+		if err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+
+		entry, err := models.CreateAuditLog(ctx, tx, models.CreateAuditLogData{
+			ActorID:    user.ID,
+			ActorEmail: user.Email,
+			Action:     "article.create",
+			TargetType: "article",
+			TargetID:   "1",
+		})
+		if err != nil {
+			t.Fatalf("create audit log: %v", err)
+		}
+
+		if err := models.DestroyUser(ctx, tx, user.ID); err != nil {
+			t.Fatalf("destroy user with audit entries: %v", err)
+		}
+
+		logs, err := models.PaginateAuditLogs(ctx, tx, models.AuditLogFilter{
+			ActorEmail: user.Email,
+		}, 1, 10)
+		if err != nil {
+			t.Fatalf("paginate audit logs: %v", err)
+		}
+		if len(logs.Logs) != 1 || logs.Logs[0].ID != entry.ID {
+			t.Fatalf("expected the audit entry to remain, got %+v", logs.Logs)
+		}
+		if logs.Logs[0].ActorEmail != user.Email {
+			t.Errorf("expected actor email %q, got %q", user.Email, logs.Logs[0].ActorEmail)
+		}
+	})
+}
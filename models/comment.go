@@ -0,0 +1,303 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models/internal/db"
+)
+
+var (
+	ErrInvalidCommentStatus = errors.New("invalid comment status")
+	ErrCommentParentInvalid = errors.New("comment parent must be an approved comment on the same article")
+)
+
+// CommentStatus tracks a comment from submission to moderation. Comments
+// start out unverified until the commenter confirms their email, then wait
+// as pending, or as spam when the heuristics flag them, until approved or
+// rejected.
+type CommentStatus string
+
+const (
+	CommentStatusUnverified CommentStatus = "unverified"
+	CommentStatusPending    CommentStatus = "pending"
+	CommentStatusSpam       CommentStatus = "spam"
+	CommentStatusApproved   CommentStatus = "approved"
+	CommentStatusRejected   CommentStatus = "rejected"
+)
+
+var CommentStatuses = []CommentStatus{
+	CommentStatusUnverified,
+	CommentStatusPending,
+	CommentStatusSpam,
+	CommentStatusApproved,
+	CommentStatusRejected,
+}
+
+func (s CommentStatus) Valid() bool {
+	return slices.Contains(CommentStatuses, s)
+}
+
+type Comment struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	ArticleID   int32
+	ParentID    uuid.UUID
+	AuthorName  string
+	AuthorEmail string
+	Content     string
+	Status      CommentStatus
+	SpamScore   int32
+	VerifiedAt  time.Time
+}
+
+// CommentThread is a comment with its replies, oldest first.
+type CommentThread struct {
+	Comment Comment
+	Replies []CommentThread
+}
+
+func FindComment(
+	ctx context.Context,
+	exec storage.Executor,
+	id uuid.UUID,
+) (Comment, error) {
+	row, err := queries.QueryCommentByID(ctx, exec, id)
+	if err != nil {
+		return Comment{}, err
+	}
+
+	return rowToComment(row), nil
+}
+
+// RecentComments lists the newest comments, optionally limited to one
+// status. An empty status lists every comment.
+func RecentComments(
+	ctx context.Context,
+	exec storage.Executor,
+	status CommentStatus,
+	limit int64,
+) ([]Comment, error) {
+	rows, err := queries.QueryCommentsByStatus(ctx, exec, db.QueryCommentsByStatusParams{
+		Status: pgtype.Text{String: string(status), Valid: status != ""},
+		Limit:  limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	comments := make([]Comment, len(rows))
+	for i, row := range rows {
+		comments[i] = rowToComment(row)
+	}
+
+	return comments, nil
+}
+
+func ApprovedCommentsForArticle(
+	ctx context.Context,
+	exec storage.Executor,
+	articleID int32,
+) ([]Comment, error) {
+	rows, err := queries.QueryCommentsByArticleIDAndStatus(
+		ctx,
+		exec,
+		db.QueryCommentsByArticleIDAndStatusParams{
+			ArticleID: articleID,
+			Status:    string(CommentStatusApproved),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	comments := make([]Comment, len(rows))
+	for i, row := range rows {
+		comments[i] = rowToComment(row)
+	}
+
+	return comments, nil
+}
+
+// CommentThreads nests comments under the comments they reply to. Replies
+// whose parent is missing from comments are left out, so a rejected comment
+// hides its answers too.
+func CommentThreads(comments []Comment) []CommentThread {
+	children := make(map[uuid.UUID][]Comment)
+	for _, comment := range comments {
+		children[comment.ParentID] = append(children[comment.ParentID], comment)
+	}
+
+	var build func(parentID uuid.UUID) []CommentThread
+	build = func(parentID uuid.UUID) []CommentThread {
+		var threads []CommentThread
+		for _, comment := range children[parentID] {
+			threads = append(threads, CommentThread{
+				Comment: comment,
+				Replies: build(comment.ID),
+			})
+		}
+		return threads
+	}
+
+	return build(uuid.Nil)
+}
+
+// CommentThreadOf returns the comments in the same thread as comment: the
+// top level comment it descends from and every reply below it.
+func CommentThreadOf(comments []Comment, comment Comment) []Comment {
+	byID := make(map[uuid.UUID]Comment, len(comments))
+	for _, c := range comments {
+		byID[c.ID] = c
+	}
+
+	rootID := comment.ID
+	parentID := comment.ParentID
+	for parentID != uuid.Nil {
+		parent, ok := byID[parentID]
+		if !ok {
+			break
+		}
+		rootID = parent.ID
+		parentID = parent.ParentID
+	}
+
+	var thread []Comment
+	for _, c := range comments {
+		id := c.ID
+		for id != uuid.Nil && id != rootID {
+			id = byID[id].ParentID
+		}
+		if id == rootID {
+			thread = append(thread, c)
+		}
+	}
+
+	return thread
+}
+
+type CreateCommentData struct {
+	ArticleID   int32 `validate:"required"`
+	ParentID    uuid.UUID
+	AuthorName  string `validate:"required,max=255"`
+	AuthorEmail string `validate:"required,email,max=255"`
+	Content     string `validate:"required,max=10000"`
+	SpamScore   int32
+}
+
+// CreateComment stores an unverified comment. A reply must answer an
+// approved comment on the same article.
+func CreateComment(
+	ctx context.Context,
+	exec storage.Executor,
+	data CreateCommentData,
+) (Comment, error) {
+	data.AuthorName = strings.TrimSpace(data.AuthorName)
+	data.AuthorEmail = strings.ToLower(strings.TrimSpace(data.AuthorEmail))
+	data.Content = strings.TrimSpace(data.Content)
+
+	if err := Validate.Struct(data); err != nil {
+		return Comment{}, errors.Join(ErrDomainValidation, err)
+	}
+
+	if data.ParentID != uuid.Nil {
+		parent, err := FindComment(ctx, exec, data.ParentID)
+		if err != nil || parent.ArticleID != data.ArticleID ||
+			parent.Status != CommentStatusApproved {
+			return Comment{}, errors.Join(ErrDomainValidation, ErrCommentParentInvalid)
+		}
+	}
+
+	row, err := queries.InsertComment(ctx, exec, db.InsertCommentParams{
+		ID:          uuid.New(),
+		ArticleID:   data.ArticleID,
+		ParentID:    pgtype.UUID{Bytes: data.ParentID, Valid: data.ParentID != uuid.Nil},
+		AuthorName:  data.AuthorName,
+		AuthorEmail: data.AuthorEmail,
+		Content:     data.Content,
+		SpamScore:   data.SpamScore,
+	})
+	if err != nil {
+		return Comment{}, err
+	}
+
+	return rowToComment(row), nil
+}
+
+// VerifyComment records that the commenter confirmed their email and moves
+// the comment into moderation with status. Comments verified before are
+// returned as pgx.ErrNoRows.
+func VerifyComment(
+	ctx context.Context,
+	exec storage.Executor,
+	id uuid.UUID,
+	status CommentStatus,
+) (Comment, error) {
+	if status != CommentStatusPending && status != CommentStatusSpam {
+		return Comment{}, errors.Join(ErrDomainValidation, ErrInvalidCommentStatus)
+	}
+
+	row, err := queries.UpdateCommentVerified(ctx, exec, db.UpdateCommentVerifiedParams{
+		ID:     id,
+		Status: string(status),
+	})
+	if err != nil {
+		return Comment{}, err
+	}
+
+	return rowToComment(row), nil
+}
+
+func UpdateCommentStatus(
+	ctx context.Context,
+	exec storage.Executor,
+	id uuid.UUID,
+	status CommentStatus,
+) (Comment, error) {
+	if !status.Valid() {
+		return Comment{}, errors.Join(ErrDomainValidation, ErrInvalidCommentStatus)
+	}
+
+	row, err := queries.UpdateCommentStatus(ctx, exec, db.UpdateCommentStatusParams{
+		ID:     id,
+		Status: string(status),
+	})
+	if err != nil {
+		return Comment{}, err
+	}
+
+	return rowToComment(row), nil
+}
+
+// DestroyComment deletes a comment and every reply below it.
+func DestroyComment(
+	ctx context.Context,
+	exec storage.Executor,
+	id uuid.UUID,
+) error {
+	return queries.DeleteComment(ctx, exec, id)
+}
+
+func rowToComment(row db.Comment) Comment {
+	return Comment{
+		ID:          row.ID,
+		CreatedAt:   row.CreatedAt.Time,
+		UpdatedAt:   row.UpdatedAt.Time,
+		ArticleID:   row.ArticleID,
+		ParentID:    row.ParentID.Bytes,
+		AuthorName:  row.AuthorName,
+		AuthorEmail: row.AuthorEmail,
+		Content:     row.Content,
+		Status:      CommentStatus(row.Status),
+		SpamScore:   row.SpamScore,
+		VerifiedAt:  row.VerifiedAt.Time,
+	}
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"math"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/text"
+)
+
+// Reading pace used to estimate read time. Code is read slower than prose,
+// and each image adds a pause of its own.
+const (
+	proseWordsPerMinute = 200
+	codeWordsPerMinute  = 100
+	secondsPerImage     = 12
+)
+
+var contentStatsParser = goldmark.New(
+	goldmark.WithExtensions(
+		extension.GFM,
+		extension.Footnote,
+		extension.DefinitionList,
+	),
+).Parser()
+
+// ContentStats are counted from an article's markdown whenever it is
+// saved. WordCount leaves out code blocks, which ReadTime weighs
+// separately.
+type ContentStats struct {
+	WordCount    int32
+	ImageCount   int32
+	HeadingCount int32
+	// ReadTime is the estimated read time in whole minutes, at least one.
+	ReadTime int32
+}
+
+// CountContentStats counts words, images and headings in markdown and
+// estimates how long it takes to read.
+func CountContentStats(content string) ContentStats {
+	source := []byte(content)
+	doc := contentStatsParser.Parse(text.NewReader(source))
+
+	var stats ContentStats
+	var prose strings.Builder
+	codeWords := 0
+
+	_ = ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		if node.Type() == ast.TypeBlock {
+			prose.WriteByte('\n')
+		}
+
+		switch n := node.(type) {
+		case *ast.FencedCodeBlock, *ast.CodeBlock:
+			lines := n.Lines()
+			for i := range lines.Len() {
+				segment := lines.At(i)
+				codeWords += len(strings.Fields(string(segment.Value(source))))
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.HTMLBlock, *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		case *ast.Image:
+			stats.ImageCount++
+			return ast.WalkSkipChildren, nil
+		case *ast.Heading:
+			stats.HeadingCount++
+		case *ast.AutoLink:
+			prose.WriteString(" link ")
+		case *ast.Text:
+			prose.Write(n.Segment.Value(source))
+			if n.SoftLineBreak() || n.HardLineBreak() {
+				prose.WriteByte('\n')
+			}
+		case *ast.String:
+			prose.Write(n.Value)
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	stats.WordCount = int32(len(strings.Fields(prose.String())))
+
+	minutes := float64(stats.WordCount)/proseWordsPerMinute +
+		float64(codeWords)/codeWordsPerMinute +
+		float64(stats.ImageCount*secondsPerImage)/60
+	stats.ReadTime = max(int32(math.Ceil(minutes)), 1)
+
+	return stats
+}
+
+// readTimeOrEstimate returns the read time to show: override when it is
+// above zero, otherwise the estimate. The bool reports an override.
+func readTimeOrEstimate(override int32, stats ContentStats) (int32, bool) {
+	if override > 0 {
+		return override, true
+	}
+
+	return stats.ReadTime, false
+}
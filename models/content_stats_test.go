@@ -0,0 +1,56 @@
+package models_test
+
+import (
+	"strings"
+	"testing"
+
+	"mortenvistisen/models"
+)
+
+func TestCountContentStats(t *testing.T) {
+	tests := map[string]struct {
+		content string
+		want    models.ContentStats
+	}{
+		"empty": {
+			content: "",
+			want:    models.ContentStats{ReadTime: 1},
+		},
+		"prose and headings": {
+			content: "# Title\n\nOne *two* three**four**\nfive [six seven](https://example.com).\n\n## Second\n\n- eight\n- nine `ten`",
+			want:    models.ContentStats{WordCount: 11, HeadingCount: 2, ReadTime: 1},
+		},
+		"images are counted but not their alt text": {
+			content: "![a long alt text](/a.png) and ![](/b.png)",
+			want:    models.ContentStats{WordCount: 1, ImageCount: 2, ReadTime: 1},
+		},
+		"code blocks are left out of the word count": {
+			content: "Intro words.\n\n```go\nfunc main() {}\n```\n\n    indented code",
+			want:    models.ContentStats{WordCount: 2, ReadTime: 1},
+		},
+		"html is skipped": {
+			content: "<div class=\"note\">\nhidden words\n</div>\n\nShown <span>words</span>.",
+			want:    models.ContentStats{WordCount: 2, ReadTime: 1},
+		},
+		"prose at 200 words a minute": {
+			content: strings.Repeat("word ", 401),
+			want:    models.ContentStats{WordCount: 401, ReadTime: 3},
+		},
+		"code at 100 words a minute": {
+			content: "```\n" + strings.Repeat("code ", 200) + "\n```",
+			want:    models.ContentStats{ReadTime: 2},
+		},
+		"images add 12 seconds each": {
+			content: strings.Repeat("word ", 200) + "\n\n" + strings.Repeat("![](/a.png)\n", 5),
+			want:    models.ContentStats{WordCount: 200, ImageCount: 5, ReadTime: 2},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := models.CountContentStats(tt.content); got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
@@ -99,6 +99,9 @@ func CreateUser(
 			ConfirmPassword: "password123",
 		},
 	}
+	if f.IsAdmin {
+		data.Role = models.RoleAdmin
+	}
 
 	user, err := models.CreateUser(ctx, exec, TestPepper, data)
 	if err != nil {
@@ -106,7 +109,7 @@ func CreateUser(
 	}
 
 	// Apply post-creation updates if needed (e.g., IsAdmin, EmailValidatedAt)
-	needsUpdate := f.IsAdmin || !f.EmailValidatedAt.IsZero()
+	needsUpdate := !f.EmailValidatedAt.IsZero()
 	if needsUpdate {
 		updateData := models.UpdateUserData{
 			ID:    user.ID,
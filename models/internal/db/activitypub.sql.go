@@ -0,0 +1,167 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: activitypub.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const countActivityPubFollowers = `-- name: CountActivityPubFollowers :one
+select count(*) from activitypub_followers
+`
+
+// CountActivityPubFollowers
+//
+//	select count(*) from activitypub_followers
+func (q *Queries) CountActivityPubFollowers(ctx context.Context, db DBTX) (int64, error) {
+	row := db.QueryRow(ctx, countActivityPubFollowers)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteActivityPubFollowerByActorID = `-- name: DeleteActivityPubFollowerByActorID :execrows
+delete from activitypub_followers where actor_id=$1
+`
+
+// DeleteActivityPubFollowerByActorID
+//
+//	delete from activitypub_followers where actor_id=$1
+func (q *Queries) DeleteActivityPubFollowerByActorID(ctx context.Context, db DBTX, actorID string) (int64, error) {
+	result, err := db.Exec(ctx, deleteActivityPubFollowerByActorID, actorID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const insertActivityPubKey = `-- name: InsertActivityPubKey :exec
+insert into
+    activitypub_keys (name, created_at, private_key_pem, public_key_pem)
+values
+    ($1, now(), $2, $3)
+on conflict (name) do nothing
+`
+
+type InsertActivityPubKeyParams struct {
+	Name          string
+	PrivateKeyPem string
+	PublicKeyPem  string
+}
+
+// InsertActivityPubKey
+//
+//	insert into
+//	    activitypub_keys (name, created_at, private_key_pem, public_key_pem)
+//	values
+//	    ($1, now(), $2, $3)
+//	on conflict (name) do nothing
+func (q *Queries) InsertActivityPubKey(ctx context.Context, db DBTX, arg InsertActivityPubKeyParams) error {
+	_, err := db.Exec(ctx, insertActivityPubKey, arg.Name, arg.PrivateKeyPem, arg.PublicKeyPem)
+	return err
+}
+
+const queryActivityPubFollowerInboxes = `-- name: QueryActivityPubFollowerInboxes :many
+select distinct coalesce(nullif(shared_inbox, ''), inbox)::text as inbox from activitypub_followers order by inbox
+`
+
+// QueryActivityPubFollowerInboxes
+//
+//	select distinct coalesce(nullif(shared_inbox, ''), inbox)::text as inbox from activitypub_followers order by inbox
+func (q *Queries) QueryActivityPubFollowerInboxes(ctx context.Context, db DBTX) ([]string, error) {
+	rows, err := db.Query(ctx, queryActivityPubFollowerInboxes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var inbox string
+		if err := rows.Scan(&inbox); err != nil {
+			return nil, err
+		}
+		items = append(items, inbox)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const queryActivityPubKeyByName = `-- name: QueryActivityPubKeyByName :one
+select name, created_at, private_key_pem, public_key_pem from activitypub_keys where name=$1
+`
+
+// QueryActivityPubKeyByName
+//
+//	select name, created_at, private_key_pem, public_key_pem from activitypub_keys where name=$1
+func (q *Queries) QueryActivityPubKeyByName(ctx context.Context, db DBTX, name string) (ActivitypubKey, error) {
+	row := db.QueryRow(ctx, queryActivityPubKeyByName, name)
+	var i ActivitypubKey
+	err := row.Scan(
+		&i.Name,
+		&i.CreatedAt,
+		&i.PrivateKeyPem,
+		&i.PublicKeyPem,
+	)
+	return i, err
+}
+
+const upsertActivityPubFollower = `-- name: UpsertActivityPubFollower :one
+insert into
+    activitypub_followers (id, created_at, updated_at, actor_id, inbox, shared_inbox, follow_activity_id)
+values
+    ($1, now(), now(), $2, $3, $4, $5)
+on conflict (actor_id) do update set
+    updated_at=now(),
+    inbox=excluded.inbox,
+    shared_inbox=excluded.shared_inbox,
+    follow_activity_id=excluded.follow_activity_id
+returning id, created_at, updated_at, actor_id, inbox, shared_inbox, follow_activity_id
+`
+
+type UpsertActivityPubFollowerParams struct {
+	ID               uuid.UUID
+	ActorID          string
+	Inbox            string
+	SharedInbox      string
+	FollowActivityID string
+}
+
+// UpsertActivityPubFollower
+//
+//	insert into
+//	    activitypub_followers (id, created_at, updated_at, actor_id, inbox, shared_inbox, follow_activity_id)
+//	values
+//	    ($1, now(), now(), $2, $3, $4, $5)
+//	on conflict (actor_id) do update set
+//	    updated_at=now(),
+//	    inbox=excluded.inbox,
+//	    shared_inbox=excluded.shared_inbox,
+//	    follow_activity_id=excluded.follow_activity_id
+//	returning id, created_at, updated_at, actor_id, inbox, shared_inbox, follow_activity_id
+func (q *Queries) UpsertActivityPubFollower(ctx context.Context, db DBTX, arg UpsertActivityPubFollowerParams) (ActivitypubFollower, error) {
+	row := db.QueryRow(ctx, upsertActivityPubFollower,
+		arg.ID,
+		arg.ActorID,
+		arg.Inbox,
+		arg.SharedInbox,
+		arg.FollowActivityID,
+	)
+	var i ActivitypubFollower
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ActorID,
+		&i.Inbox,
+		&i.SharedInbox,
+		&i.FollowActivityID,
+	)
+	return i, err
+}
@@ -39,32 +39,38 @@ func (q *Queries) DeleteArticle(ctx context.Context, db DBTX, id int32) error {
 
 const insertArticle = `-- name: InsertArticle :one
 insert into
-    articles (created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content)
+    articles (created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, read_time_override, estimated_read_time, word_count, image_count, heading_count, content, author_id)
 values
-    (now(), now(), $1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-returning id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content
+    (now(), now(), $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+returning id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content, author_id, comments_open, table_of_contents, content_html, content_hash, content_headings, word_count, image_count, heading_count, estimated_read_time, read_time_override
 `
 
 type InsertArticleParams struct {
-	FirstPublishedAt pgtype.Timestamptz
-	Published        bool
-	Title            string
-	Excerpt          pgtype.Text
-	MetaTitle        pgtype.Text
-	MetaDescription  pgtype.Text
-	Slug             string
-	ImageLink        pgtype.Text
-	ReadTime         pgtype.Int4
-	Content          pgtype.Text
+	FirstPublishedAt  pgtype.Timestamptz
+	Published         bool
+	Title             string
+	Excerpt           pgtype.Text
+	MetaTitle         pgtype.Text
+	MetaDescription   pgtype.Text
+	Slug              string
+	ImageLink         pgtype.Text
+	ReadTime          pgtype.Int4
+	ReadTimeOverride  bool
+	EstimatedReadTime int32
+	WordCount         int32
+	ImageCount        int32
+	HeadingCount      int32
+	Content           pgtype.Text
+	AuthorID          pgtype.UUID
 }
 
 // InsertArticle
 //
 //	insert into
-//	    articles (created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content)
+//	    articles (created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, read_time_override, estimated_read_time, word_count, image_count, heading_count, content, author_id)
 //	values
-//	    (now(), now(), $1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-//	returning id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content
+//	    (now(), now(), $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+//	returning id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content, author_id, comments_open, table_of_contents, content_html, content_hash, content_headings, word_count, image_count, heading_count, estimated_read_time, read_time_override
 func (q *Queries) InsertArticle(ctx context.Context, db DBTX, arg InsertArticleParams) (Article, error) {
 	row := db.QueryRow(ctx, insertArticle,
 		arg.FirstPublishedAt,
@@ -76,7 +82,13 @@ func (q *Queries) InsertArticle(ctx context.Context, db DBTX, arg InsertArticleP
 		arg.Slug,
 		arg.ImageLink,
 		arg.ReadTime,
+		arg.ReadTimeOverride,
+		arg.EstimatedReadTime,
+		arg.WordCount,
+		arg.ImageCount,
+		arg.HeadingCount,
 		arg.Content,
+		arg.AuthorID,
 	)
 	var i Article
 	err := row.Scan(
@@ -93,17 +105,28 @@ func (q *Queries) InsertArticle(ctx context.Context, db DBTX, arg InsertArticleP
 		&i.ImageLink,
 		&i.ReadTime,
 		&i.Content,
+		&i.AuthorID,
+		&i.CommentsOpen,
+		&i.TableOfContents,
+		&i.ContentHtml,
+		&i.ContentHash,
+		&i.ContentHeadings,
+		&i.WordCount,
+		&i.ImageCount,
+		&i.HeadingCount,
+		&i.EstimatedReadTime,
+		&i.ReadTimeOverride,
 	)
 	return i, err
 }
 
 const queryArticleByID = `-- name: QueryArticleByID :one
-select id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content from articles where id=$1
+select id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content, author_id, comments_open, table_of_contents, content_html, content_hash, content_headings, word_count, image_count, heading_count, estimated_read_time, read_time_override from articles where id=$1
 `
 
 // QueryArticleByID
 //
-//	select id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content from articles where id=$1
+//	select id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content, author_id, comments_open, table_of_contents, content_html, content_hash, content_headings, word_count, image_count, heading_count, estimated_read_time, read_time_override from articles where id=$1
 func (q *Queries) QueryArticleByID(ctx context.Context, db DBTX, id int32) (Article, error) {
 	row := db.QueryRow(ctx, queryArticleByID, id)
 	var i Article
@@ -121,17 +144,28 @@ func (q *Queries) QueryArticleByID(ctx context.Context, db DBTX, id int32) (Arti
 		&i.ImageLink,
 		&i.ReadTime,
 		&i.Content,
+		&i.AuthorID,
+		&i.CommentsOpen,
+		&i.TableOfContents,
+		&i.ContentHtml,
+		&i.ContentHash,
+		&i.ContentHeadings,
+		&i.WordCount,
+		&i.ImageCount,
+		&i.HeadingCount,
+		&i.EstimatedReadTime,
+		&i.ReadTimeOverride,
 	)
 	return i, err
 }
 
 const queryArticleBySlug = `-- name: QueryArticleBySlug :one
-select id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content from articles where slug=$1
+select id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content, author_id, comments_open, table_of_contents, content_html, content_hash, content_headings, word_count, image_count, heading_count, estimated_read_time, read_time_override from articles where slug=$1
 `
 
 // QueryArticleBySlug
 //
-//	select id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content from articles where slug=$1
+//	select id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content, author_id, comments_open, table_of_contents, content_html, content_hash, content_headings, word_count, image_count, heading_count, estimated_read_time, read_time_override from articles where slug=$1
 func (q *Queries) QueryArticleBySlug(ctx context.Context, db DBTX, slug string) (Article, error) {
 	row := db.QueryRow(ctx, queryArticleBySlug, slug)
 	var i Article
@@ -149,17 +183,28 @@ func (q *Queries) QueryArticleBySlug(ctx context.Context, db DBTX, slug string)
 		&i.ImageLink,
 		&i.ReadTime,
 		&i.Content,
+		&i.AuthorID,
+		&i.CommentsOpen,
+		&i.TableOfContents,
+		&i.ContentHtml,
+		&i.ContentHash,
+		&i.ContentHeadings,
+		&i.WordCount,
+		&i.ImageCount,
+		&i.HeadingCount,
+		&i.EstimatedReadTime,
+		&i.ReadTimeOverride,
 	)
 	return i, err
 }
 
 const queryArticles = `-- name: QueryArticles :many
-select id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content from articles
+select id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content, author_id, comments_open, table_of_contents, content_html, content_hash, content_headings, word_count, image_count, heading_count, estimated_read_time, read_time_override from articles
 `
 
 // QueryArticles
 //
-//	select id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content from articles
+//	select id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content, author_id, comments_open, table_of_contents, content_html, content_hash, content_headings, word_count, image_count, heading_count, estimated_read_time, read_time_override from articles
 func (q *Queries) QueryArticles(ctx context.Context, db DBTX) ([]Article, error) {
 	rows, err := db.Query(ctx, queryArticles)
 	if err != nil {
@@ -183,6 +228,17 @@ func (q *Queries) QueryArticles(ctx context.Context, db DBTX) ([]Article, error)
 			&i.ImageLink,
 			&i.ReadTime,
 			&i.Content,
+			&i.AuthorID,
+			&i.CommentsOpen,
+			&i.TableOfContents,
+			&i.ContentHtml,
+			&i.ContentHash,
+			&i.ContentHeadings,
+			&i.WordCount,
+			&i.ImageCount,
+			&i.HeadingCount,
+			&i.EstimatedReadTime,
+			&i.ReadTimeOverride,
 		); err != nil {
 			return nil, err
 		}
@@ -194,8 +250,33 @@ func (q *Queries) QueryArticles(ctx context.Context, db DBTX) ([]Article, error)
 	return items, nil
 }
 
+const queryArticlesChange = `-- name: QueryArticlesChange :one
+select
+    count(*)::bigint as total,
+    coalesce(max(updated_at), 'epoch'::timestamptz)::timestamptz as last_updated_at
+from articles
+`
+
+type QueryArticlesChangeRow struct {
+	Total         int64
+	LastUpdatedAt pgtype.Timestamptz
+}
+
+// QueryArticlesChange
+//
+//	select
+//	    count(*)::bigint as total,
+//	    coalesce(max(updated_at), 'epoch'::timestamptz)::timestamptz as last_updated_at
+//	from articles
+func (q *Queries) QueryArticlesChange(ctx context.Context, db DBTX) (QueryArticlesChangeRow, error) {
+	row := db.QueryRow(ctx, queryArticlesChange)
+	var i QueryArticlesChangeRow
+	err := row.Scan(&i.Total, &i.LastUpdatedAt)
+	return i, err
+}
+
 const queryPaginatedArticles = `-- name: QueryPaginatedArticles :many
-select id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content from articles
+select id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content, author_id, comments_open, table_of_contents, content_html, content_hash, content_headings, word_count, image_count, heading_count, estimated_read_time, read_time_override from articles
 order by created_at desc
 limit $2::bigint offset $1::bigint
 `
@@ -207,7 +288,7 @@ type QueryPaginatedArticlesParams struct {
 
 // QueryPaginatedArticles
 //
-//	select id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content from articles
+//	select id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content, author_id, comments_open, table_of_contents, content_html, content_hash, content_headings, word_count, image_count, heading_count, estimated_read_time, read_time_override from articles
 //	order by created_at desc
 //	limit $2::bigint offset $1::bigint
 func (q *Queries) QueryPaginatedArticles(ctx context.Context, db DBTX, arg QueryPaginatedArticlesParams) ([]Article, error) {
@@ -233,6 +314,17 @@ func (q *Queries) QueryPaginatedArticles(ctx context.Context, db DBTX, arg Query
 			&i.ImageLink,
 			&i.ReadTime,
 			&i.Content,
+			&i.AuthorID,
+			&i.CommentsOpen,
+			&i.TableOfContents,
+			&i.ContentHtml,
+			&i.ContentHash,
+			&i.ContentHeadings,
+			&i.WordCount,
+			&i.ImageCount,
+			&i.HeadingCount,
+			&i.EstimatedReadTime,
+			&i.ReadTimeOverride,
 		); err != nil {
 			return nil, err
 		}
@@ -245,12 +337,12 @@ func (q *Queries) QueryPaginatedArticles(ctx context.Context, db DBTX, arg Query
 }
 
 const queryPublishedArticles = `-- name: QueryPublishedArticles :many
-select id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content from articles where published=true order by first_published_at desc
+select id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content, author_id, comments_open, table_of_contents, content_html, content_hash, content_headings, word_count, image_count, heading_count, estimated_read_time, read_time_override from articles where published=true order by first_published_at desc
 `
 
 // QueryPublishedArticles
 //
-//	select id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content from articles where published=true order by first_published_at desc
+//	select id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content, author_id, comments_open, table_of_contents, content_html, content_hash, content_headings, word_count, image_count, heading_count, estimated_read_time, read_time_override from articles where published=true order by first_published_at desc
 func (q *Queries) QueryPublishedArticles(ctx context.Context, db DBTX) ([]Article, error) {
 	rows, err := db.Query(ctx, queryPublishedArticles)
 	if err != nil {
@@ -274,6 +366,17 @@ func (q *Queries) QueryPublishedArticles(ctx context.Context, db DBTX) ([]Articl
 			&i.ImageLink,
 			&i.ReadTime,
 			&i.Content,
+			&i.AuthorID,
+			&i.CommentsOpen,
+			&i.TableOfContents,
+			&i.ContentHtml,
+			&i.ContentHash,
+			&i.ContentHeadings,
+			&i.WordCount,
+			&i.ImageCount,
+			&i.HeadingCount,
+			&i.EstimatedReadTime,
+			&i.ReadTimeOverride,
 		); err != nil {
 			return nil, err
 		}
@@ -287,31 +390,36 @@ func (q *Queries) QueryPublishedArticles(ctx context.Context, db DBTX) ([]Articl
 
 const updateArticle = `-- name: UpdateArticle :one
 update articles
-    set updated_at=now(), first_published_at=$2, published=$3, title=$4, excerpt=$5, meta_title=$6, meta_description=$7, slug=$8, image_link=$9, read_time=$10, content=$11
+    set updated_at=now(), first_published_at=$2, published=$3, title=$4, excerpt=$5, meta_title=$6, meta_description=$7, slug=$8, image_link=$9, read_time=$10, read_time_override=$11, estimated_read_time=$12, word_count=$13, image_count=$14, heading_count=$15, content=$16
 where id = $1
-returning id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content
+returning id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content, author_id, comments_open, table_of_contents, content_html, content_hash, content_headings, word_count, image_count, heading_count, estimated_read_time, read_time_override
 `
 
 type UpdateArticleParams struct {
-	ID               int32
-	FirstPublishedAt pgtype.Timestamptz
-	Published        bool
-	Title            string
-	Excerpt          pgtype.Text
-	MetaTitle        pgtype.Text
-	MetaDescription  pgtype.Text
-	Slug             string
-	ImageLink        pgtype.Text
-	ReadTime         pgtype.Int4
-	Content          pgtype.Text
+	ID                int32
+	FirstPublishedAt  pgtype.Timestamptz
+	Published         bool
+	Title             string
+	Excerpt           pgtype.Text
+	MetaTitle         pgtype.Text
+	MetaDescription   pgtype.Text
+	Slug              string
+	ImageLink         pgtype.Text
+	ReadTime          pgtype.Int4
+	ReadTimeOverride  bool
+	EstimatedReadTime int32
+	WordCount         int32
+	ImageCount        int32
+	HeadingCount      int32
+	Content           pgtype.Text
 }
 
 // UpdateArticle
 //
 //	update articles
-//	    set updated_at=now(), first_published_at=$2, published=$3, title=$4, excerpt=$5, meta_title=$6, meta_description=$7, slug=$8, image_link=$9, read_time=$10, content=$11
+//	    set updated_at=now(), first_published_at=$2, published=$3, title=$4, excerpt=$5, meta_title=$6, meta_description=$7, slug=$8, image_link=$9, read_time=$10, read_time_override=$11, estimated_read_time=$12, word_count=$13, image_count=$14, heading_count=$15, content=$16
 //	where id = $1
-//	returning id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content
+//	returning id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content, author_id, comments_open, table_of_contents, content_html, content_hash, content_headings, word_count, image_count, heading_count, estimated_read_time, read_time_override
 func (q *Queries) UpdateArticle(ctx context.Context, db DBTX, arg UpdateArticleParams) (Article, error) {
 	row := db.QueryRow(ctx, updateArticle,
 		arg.ID,
@@ -324,6 +432,11 @@ func (q *Queries) UpdateArticle(ctx context.Context, db DBTX, arg UpdateArticleP
 		arg.Slug,
 		arg.ImageLink,
 		arg.ReadTime,
+		arg.ReadTimeOverride,
+		arg.EstimatedReadTime,
+		arg.WordCount,
+		arg.ImageCount,
+		arg.HeadingCount,
 		arg.Content,
 	)
 	var i Article
@@ -341,42 +454,278 @@ func (q *Queries) UpdateArticle(ctx context.Context, db DBTX, arg UpdateArticleP
 		&i.ImageLink,
 		&i.ReadTime,
 		&i.Content,
+		&i.AuthorID,
+		&i.CommentsOpen,
+		&i.TableOfContents,
+		&i.ContentHtml,
+		&i.ContentHash,
+		&i.ContentHeadings,
+		&i.WordCount,
+		&i.ImageCount,
+		&i.HeadingCount,
+		&i.EstimatedReadTime,
+		&i.ReadTimeOverride,
+	)
+	return i, err
+}
+
+const updateArticleCommentsOpen = `-- name: UpdateArticleCommentsOpen :one
+update articles set updated_at=now(), comments_open=$2 where id = $1 returning id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content, author_id, comments_open, table_of_contents, content_html, content_hash, content_headings, word_count, image_count, heading_count, estimated_read_time, read_time_override
+`
+
+type UpdateArticleCommentsOpenParams struct {
+	ID           int32
+	CommentsOpen bool
+}
+
+// UpdateArticleCommentsOpen
+//
+//	update articles set updated_at=now(), comments_open=$2 where id = $1 returning id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content, author_id, comments_open, table_of_contents, content_html, content_hash, content_headings, word_count, image_count, heading_count, estimated_read_time, read_time_override
+func (q *Queries) UpdateArticleCommentsOpen(ctx context.Context, db DBTX, arg UpdateArticleCommentsOpenParams) (Article, error) {
+	row := db.QueryRow(ctx, updateArticleCommentsOpen, arg.ID, arg.CommentsOpen)
+	var i Article
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.FirstPublishedAt,
+		&i.Published,
+		&i.Title,
+		&i.Excerpt,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.Slug,
+		&i.ImageLink,
+		&i.ReadTime,
+		&i.Content,
+		&i.AuthorID,
+		&i.CommentsOpen,
+		&i.TableOfContents,
+		&i.ContentHtml,
+		&i.ContentHash,
+		&i.ContentHeadings,
+		&i.WordCount,
+		&i.ImageCount,
+		&i.HeadingCount,
+		&i.EstimatedReadTime,
+		&i.ReadTimeOverride,
+	)
+	return i, err
+}
+
+const updateArticleContentHTML = `-- name: UpdateArticleContentHTML :one
+update articles set content_html=$2, content_hash=$3, content_headings=$4 where id = $1 returning id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content, author_id, comments_open, table_of_contents, content_html, content_hash, content_headings, word_count, image_count, heading_count, estimated_read_time, read_time_override
+`
+
+type UpdateArticleContentHTMLParams struct {
+	ID              int32
+	ContentHtml     string
+	ContentHash     string
+	ContentHeadings []byte
+}
+
+// UpdateArticleContentHTML
+//
+//	update articles set content_html=$2, content_hash=$3, content_headings=$4 where id = $1 returning id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content, author_id, comments_open, table_of_contents, content_html, content_hash, content_headings, word_count, image_count, heading_count, estimated_read_time, read_time_override
+func (q *Queries) UpdateArticleContentHTML(ctx context.Context, db DBTX, arg UpdateArticleContentHTMLParams) (Article, error) {
+	row := db.QueryRow(ctx, updateArticleContentHTML,
+		arg.ID,
+		arg.ContentHtml,
+		arg.ContentHash,
+		arg.ContentHeadings,
+	)
+	var i Article
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.FirstPublishedAt,
+		&i.Published,
+		&i.Title,
+		&i.Excerpt,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.Slug,
+		&i.ImageLink,
+		&i.ReadTime,
+		&i.Content,
+		&i.AuthorID,
+		&i.CommentsOpen,
+		&i.TableOfContents,
+		&i.ContentHtml,
+		&i.ContentHash,
+		&i.ContentHeadings,
+		&i.WordCount,
+		&i.ImageCount,
+		&i.HeadingCount,
+		&i.EstimatedReadTime,
+		&i.ReadTimeOverride,
+	)
+	return i, err
+}
+
+const updateArticleStats = `-- name: UpdateArticleStats :exec
+update articles
+    set estimated_read_time=$2, word_count=$3, image_count=$4, heading_count=$5,
+    read_time=case when read_time_override then read_time else $2 end
+where id = $1
+`
+
+type UpdateArticleStatsParams struct {
+	ID                int32
+	EstimatedReadTime int32
+	WordCount         int32
+	ImageCount        int32
+	HeadingCount      int32
+}
+
+// UpdateArticleStats
+//
+//	update articles
+//	    set estimated_read_time=$2, word_count=$3, image_count=$4, heading_count=$5,
+//	    read_time=case when read_time_override then read_time else $2 end
+//	where id = $1
+func (q *Queries) UpdateArticleStats(ctx context.Context, db DBTX, arg UpdateArticleStatsParams) error {
+	_, err := db.Exec(ctx, updateArticleStats,
+		arg.ID,
+		arg.EstimatedReadTime,
+		arg.WordCount,
+		arg.ImageCount,
+		arg.HeadingCount,
+	)
+	return err
+}
+
+const updateArticleTableOfContents = `-- name: UpdateArticleTableOfContents :one
+update articles set updated_at=now(), table_of_contents=$2 where id = $1 returning id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content, author_id, comments_open, table_of_contents, content_html, content_hash, content_headings, word_count, image_count, heading_count, estimated_read_time, read_time_override
+`
+
+type UpdateArticleTableOfContentsParams struct {
+	ID              int32
+	TableOfContents bool
+}
+
+// UpdateArticleTableOfContents
+//
+//	update articles set updated_at=now(), table_of_contents=$2 where id = $1 returning id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content, author_id, comments_open, table_of_contents, content_html, content_hash, content_headings, word_count, image_count, heading_count, estimated_read_time, read_time_override
+func (q *Queries) UpdateArticleTableOfContents(ctx context.Context, db DBTX, arg UpdateArticleTableOfContentsParams) (Article, error) {
+	row := db.QueryRow(ctx, updateArticleTableOfContents, arg.ID, arg.TableOfContents)
+	var i Article
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.FirstPublishedAt,
+		&i.Published,
+		&i.Title,
+		&i.Excerpt,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.Slug,
+		&i.ImageLink,
+		&i.ReadTime,
+		&i.Content,
+		&i.AuthorID,
+		&i.CommentsOpen,
+		&i.TableOfContents,
+		&i.ContentHtml,
+		&i.ContentHash,
+		&i.ContentHeadings,
+		&i.WordCount,
+		&i.ImageCount,
+		&i.HeadingCount,
+		&i.EstimatedReadTime,
+		&i.ReadTimeOverride,
 	)
 	return i, err
 }
 
 const upsertArticle = `-- name: UpsertArticle :one
 insert into
-    articles (created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content)
+    articles (created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, read_time_override, estimated_read_time, word_count, image_count, heading_count, content, author_id, comments_open, table_of_contents)
 values
-    (now(), now(), $1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-on conflict (id) do update set updated_at=now(), first_published_at=excluded.first_published_at, published=excluded.published, title=excluded.title, excerpt=excluded.excerpt, meta_title=excluded.meta_title, meta_description=excluded.meta_description, slug=excluded.slug, image_link=excluded.image_link, read_time=excluded.read_time, content=excluded.content
-returning id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content
+    (
+      coalesce($1::timestamptz, now()),
+      coalesce($2::timestamptz, now()),
+      $3,
+      $4,
+      $5,
+      $6,
+      $7,
+      $8,
+      $9,
+      $10,
+      $11,
+      $12,
+      $13,
+      $14,
+      $15,
+      $16,
+      $17,
+      $18,
+      $19,
+      $20
+    )
+on conflict (slug) do update set updated_at=now(), first_published_at=excluded.first_published_at, published=excluded.published, title=excluded.title, excerpt=excluded.excerpt, meta_title=excluded.meta_title, meta_description=excluded.meta_description, image_link=excluded.image_link, read_time=excluded.read_time, read_time_override=excluded.read_time_override, estimated_read_time=excluded.estimated_read_time, word_count=excluded.word_count, image_count=excluded.image_count, heading_count=excluded.heading_count, content=excluded.content, comments_open=excluded.comments_open, table_of_contents=excluded.table_of_contents
+returning id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content, author_id, comments_open, table_of_contents, content_html, content_hash, content_headings, word_count, image_count, heading_count, estimated_read_time, read_time_override
 `
 
 type UpsertArticleParams struct {
-	FirstPublishedAt pgtype.Timestamptz
-	Published        bool
-	Title            string
-	Excerpt          pgtype.Text
-	MetaTitle        pgtype.Text
-	MetaDescription  pgtype.Text
-	Slug             string
-	ImageLink        pgtype.Text
-	ReadTime         pgtype.Int4
-	Content          pgtype.Text
+	CreatedAt         pgtype.Timestamptz
+	UpdatedAt         pgtype.Timestamptz
+	FirstPublishedAt  pgtype.Timestamptz
+	Published         bool
+	Title             string
+	Excerpt           pgtype.Text
+	MetaTitle         pgtype.Text
+	MetaDescription   pgtype.Text
+	Slug              string
+	ImageLink         pgtype.Text
+	ReadTime          pgtype.Int4
+	ReadTimeOverride  bool
+	EstimatedReadTime int32
+	WordCount         int32
+	ImageCount        int32
+	HeadingCount      int32
+	Content           pgtype.Text
+	AuthorID          pgtype.UUID
+	CommentsOpen      bool
+	TableOfContents   bool
 }
 
 // UpsertArticle
 //
 //	insert into
-//	    articles (created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content)
+//	    articles (created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, read_time_override, estimated_read_time, word_count, image_count, heading_count, content, author_id, comments_open, table_of_contents)
 //	values
-//	    (now(), now(), $1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-//	on conflict (id) do update set updated_at=now(), first_published_at=excluded.first_published_at, published=excluded.published, title=excluded.title, excerpt=excluded.excerpt, meta_title=excluded.meta_title, meta_description=excluded.meta_description, slug=excluded.slug, image_link=excluded.image_link, read_time=excluded.read_time, content=excluded.content
-//	returning id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content
+//	    (
+//	      coalesce($1::timestamptz, now()),
+//	      coalesce($2::timestamptz, now()),
+//	      $3,
+//	      $4,
+//	      $5,
+//	      $6,
+//	      $7,
+//	      $8,
+//	      $9,
+//	      $10,
+//	      $11,
+//	      $12,
+//	      $13,
+//	      $14,
+//	      $15,
+//	      $16,
+//	      $17,
+//	      $18,
+//	      $19,
+//	      $20
+//	    )
+//	on conflict (slug) do update set updated_at=now(), first_published_at=excluded.first_published_at, published=excluded.published, title=excluded.title, excerpt=excluded.excerpt, meta_title=excluded.meta_title, meta_description=excluded.meta_description, image_link=excluded.image_link, read_time=excluded.read_time, read_time_override=excluded.read_time_override, estimated_read_time=excluded.estimated_read_time, word_count=excluded.word_count, image_count=excluded.image_count, heading_count=excluded.heading_count, content=excluded.content, comments_open=excluded.comments_open, table_of_contents=excluded.table_of_contents
+//	returning id, created_at, updated_at, first_published_at, published, title, excerpt, meta_title, meta_description, slug, image_link, read_time, content, author_id, comments_open, table_of_contents, content_html, content_hash, content_headings, word_count, image_count, heading_count, estimated_read_time, read_time_override
 func (q *Queries) UpsertArticle(ctx context.Context, db DBTX, arg UpsertArticleParams) (Article, error) {
 	row := db.QueryRow(ctx, upsertArticle,
+		arg.CreatedAt,
+		arg.UpdatedAt,
 		arg.FirstPublishedAt,
 		arg.Published,
 		arg.Title,
@@ -386,7 +735,15 @@ func (q *Queries) UpsertArticle(ctx context.Context, db DBTX, arg UpsertArticleP
 		arg.Slug,
 		arg.ImageLink,
 		arg.ReadTime,
+		arg.ReadTimeOverride,
+		arg.EstimatedReadTime,
+		arg.WordCount,
+		arg.ImageCount,
+		arg.HeadingCount,
 		arg.Content,
+		arg.AuthorID,
+		arg.CommentsOpen,
+		arg.TableOfContents,
 	)
 	var i Article
 	err := row.Scan(
@@ -403,6 +760,17 @@ func (q *Queries) UpsertArticle(ctx context.Context, db DBTX, arg UpsertArticleP
 		&i.ImageLink,
 		&i.ReadTime,
 		&i.Content,
+		&i.AuthorID,
+		&i.CommentsOpen,
+		&i.TableOfContents,
+		&i.ContentHtml,
+		&i.ContentHash,
+		&i.ContentHeadings,
+		&i.WordCount,
+		&i.ImageCount,
+		&i.HeadingCount,
+		&i.EstimatedReadTime,
+		&i.ReadTimeOverride,
 	)
 	return i, err
 }
@@ -0,0 +1,231 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: audit_logs.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const countFilteredAuditLogs = `-- name: CountFilteredAuditLogs :one
+select count(*) from audit_logs
+where
+    ($1::varchar is null or actor_email ilike '%' || $1::varchar || '%')
+    and ($2::varchar is null or action = $2::varchar)
+    and ($3::varchar is null or target_type = $3::varchar)
+    and ($4::varchar is null or target_id = $4::varchar)
+    and ($5::timestamptz is null or created_at >= $5::timestamptz)
+    and ($6::timestamptz is null or created_at < $6::timestamptz)
+`
+
+type CountFilteredAuditLogsParams struct {
+	ActorEmail pgtype.Text
+	Action     pgtype.Text
+	TargetType pgtype.Text
+	TargetID   pgtype.Text
+	Since      pgtype.Timestamptz
+	Until      pgtype.Timestamptz
+}
+
+// CountFilteredAuditLogs
+//
+//	select count(*) from audit_logs
+//	where
+//	    ($1::varchar is null or actor_email ilike '%' || $1::varchar || '%')
+//	    and ($2::varchar is null or action = $2::varchar)
+//	    and ($3::varchar is null or target_type = $3::varchar)
+//	    and ($4::varchar is null or target_id = $4::varchar)
+//	    and ($5::timestamptz is null or created_at >= $5::timestamptz)
+//	    and ($6::timestamptz is null or created_at < $6::timestamptz)
+func (q *Queries) CountFilteredAuditLogs(ctx context.Context, db DBTX, arg CountFilteredAuditLogsParams) (int64, error) {
+	row := db.QueryRow(ctx, countFilteredAuditLogs,
+		arg.ActorEmail,
+		arg.Action,
+		arg.TargetType,
+		arg.TargetID,
+		arg.Since,
+		arg.Until,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteAuditLogsBefore = `-- name: DeleteAuditLogsBefore :execrows
+delete from audit_logs where created_at < $1::timestamptz
+`
+
+// DeleteAuditLogsBefore
+//
+//	delete from audit_logs where created_at < $1::timestamptz
+func (q *Queries) DeleteAuditLogsBefore(ctx context.Context, db DBTX, before pgtype.Timestamptz) (int64, error) {
+	result, err := db.Exec(ctx, deleteAuditLogsBefore, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const insertAuditLog = `-- name: InsertAuditLog :one
+insert into
+    audit_logs (created_at, actor_id, actor_email, action, target_type, target_id, changes, ip_address, user_agent, request_id)
+values
+    (now(), $1, $2, $3, $4, $5, $6, $7, $8, $9)
+returning id, created_at, actor_id, actor_email, action, target_type, target_id, changes, ip_address, user_agent, request_id
+`
+
+type InsertAuditLogParams struct {
+	ActorID    pgtype.UUID
+	ActorEmail string
+	Action     string
+	TargetType string
+	TargetID   string
+	Changes    []byte
+	IpAddress  string
+	UserAgent  string
+	RequestID  string
+}
+
+// InsertAuditLog
+//
+//	insert into
+//	    audit_logs (created_at, actor_id, actor_email, action, target_type, target_id, changes, ip_address, user_agent, request_id)
+//	values
+//	    (now(), $1, $2, $3, $4, $5, $6, $7, $8, $9)
+//	returning id, created_at, actor_id, actor_email, action, target_type, target_id, changes, ip_address, user_agent, request_id
+func (q *Queries) InsertAuditLog(ctx context.Context, db DBTX, arg InsertAuditLogParams) (AuditLog, error) {
+	row := db.QueryRow(ctx, insertAuditLog,
+		arg.ActorID,
+		arg.ActorEmail,
+		arg.Action,
+		arg.TargetType,
+		arg.TargetID,
+		arg.Changes,
+		arg.IpAddress,
+		arg.UserAgent,
+		arg.RequestID,
+	)
+	var i AuditLog
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ActorID,
+		&i.ActorEmail,
+		&i.Action,
+		&i.TargetType,
+		&i.TargetID,
+		&i.Changes,
+		&i.IpAddress,
+		&i.UserAgent,
+		&i.RequestID,
+	)
+	return i, err
+}
+
+const queryAuditLogActions = `-- name: QueryAuditLogActions :many
+select distinct action from audit_logs order by action
+`
+
+// QueryAuditLogActions
+//
+//	select distinct action from audit_logs order by action
+func (q *Queries) QueryAuditLogActions(ctx context.Context, db DBTX) ([]string, error) {
+	rows, err := db.Query(ctx, queryAuditLogActions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var action string
+		if err := rows.Scan(&action); err != nil {
+			return nil, err
+		}
+		items = append(items, action)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const queryFilteredAuditLogs = `-- name: QueryFilteredAuditLogs :many
+select id, created_at, actor_id, actor_email, action, target_type, target_id, changes, ip_address, user_agent, request_id from audit_logs
+where
+    ($1::varchar is null or actor_email ilike '%' || $1::varchar || '%')
+    and ($2::varchar is null or action = $2::varchar)
+    and ($3::varchar is null or target_type = $3::varchar)
+    and ($4::varchar is null or target_id = $4::varchar)
+    and ($5::timestamptz is null or created_at >= $5::timestamptz)
+    and ($6::timestamptz is null or created_at < $6::timestamptz)
+order by created_at desc, id desc
+limit $8::bigint offset $7::bigint
+`
+
+type QueryFilteredAuditLogsParams struct {
+	ActorEmail pgtype.Text
+	Action     pgtype.Text
+	TargetType pgtype.Text
+	TargetID   pgtype.Text
+	Since      pgtype.Timestamptz
+	Until      pgtype.Timestamptz
+	Offset     int64
+	Limit      int64
+}
+
+// QueryFilteredAuditLogs
+//
+//	select id, created_at, actor_id, actor_email, action, target_type, target_id, changes, ip_address, user_agent, request_id from audit_logs
+//	where
+//	    ($1::varchar is null or actor_email ilike '%' || $1::varchar || '%')
+//	    and ($2::varchar is null or action = $2::varchar)
+//	    and ($3::varchar is null or target_type = $3::varchar)
+//	    and ($4::varchar is null or target_id = $4::varchar)
+//	    and ($5::timestamptz is null or created_at >= $5::timestamptz)
+//	    and ($6::timestamptz is null or created_at < $6::timestamptz)
+//	order by created_at desc, id desc
+//	limit $8::bigint offset $7::bigint
+func (q *Queries) QueryFilteredAuditLogs(ctx context.Context, db DBTX, arg QueryFilteredAuditLogsParams) ([]AuditLog, error) {
+	rows, err := db.Query(ctx, queryFilteredAuditLogs,
+		arg.ActorEmail,
+		arg.Action,
+		arg.TargetType,
+		arg.TargetID,
+		arg.Since,
+		arg.Until,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditLog
+	for rows.Next() {
+		var i AuditLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.ActorID,
+			&i.ActorEmail,
+			&i.Action,
+			&i.TargetType,
+			&i.TargetID,
+			&i.Changes,
+			&i.IpAddress,
+			&i.UserAgent,
+			&i.RequestID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
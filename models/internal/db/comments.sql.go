@@ -0,0 +1,265 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: comments.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const deleteComment = `-- name: DeleteComment :exec
+delete from comments where id=$1
+`
+
+// DeleteComment
+//
+//	delete from comments where id=$1
+func (q *Queries) DeleteComment(ctx context.Context, db DBTX, id uuid.UUID) error {
+	_, err := db.Exec(ctx, deleteComment, id)
+	return err
+}
+
+const insertComment = `-- name: InsertComment :one
+insert into
+    comments (id, created_at, updated_at, article_id, parent_id, author_name, author_email, content, status, spam_score)
+values
+    ($1, now(), now(), $2, $3, $4, $5, $6, 'unverified', $7)
+returning id, created_at, updated_at, article_id, parent_id, author_name, author_email, content, status, spam_score, verified_at
+`
+
+type InsertCommentParams struct {
+	ID          uuid.UUID
+	ArticleID   int32
+	ParentID    pgtype.UUID
+	AuthorName  string
+	AuthorEmail string
+	Content     string
+	SpamScore   int32
+}
+
+// InsertComment
+//
+//	insert into
+//	    comments (id, created_at, updated_at, article_id, parent_id, author_name, author_email, content, status, spam_score)
+//	values
+//	    ($1, now(), now(), $2, $3, $4, $5, $6, 'unverified', $7)
+//	returning id, created_at, updated_at, article_id, parent_id, author_name, author_email, content, status, spam_score, verified_at
+func (q *Queries) InsertComment(ctx context.Context, db DBTX, arg InsertCommentParams) (Comment, error) {
+	row := db.QueryRow(ctx, insertComment,
+		arg.ID,
+		arg.ArticleID,
+		arg.ParentID,
+		arg.AuthorName,
+		arg.AuthorEmail,
+		arg.Content,
+		arg.SpamScore,
+	)
+	var i Comment
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ArticleID,
+		&i.ParentID,
+		&i.AuthorName,
+		&i.AuthorEmail,
+		&i.Content,
+		&i.Status,
+		&i.SpamScore,
+		&i.VerifiedAt,
+	)
+	return i, err
+}
+
+const queryCommentByID = `-- name: QueryCommentByID :one
+select id, created_at, updated_at, article_id, parent_id, author_name, author_email, content, status, spam_score, verified_at from comments where id=$1
+`
+
+// QueryCommentByID
+//
+//	select id, created_at, updated_at, article_id, parent_id, author_name, author_email, content, status, spam_score, verified_at from comments where id=$1
+func (q *Queries) QueryCommentByID(ctx context.Context, db DBTX, id uuid.UUID) (Comment, error) {
+	row := db.QueryRow(ctx, queryCommentByID, id)
+	var i Comment
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ArticleID,
+		&i.ParentID,
+		&i.AuthorName,
+		&i.AuthorEmail,
+		&i.Content,
+		&i.Status,
+		&i.SpamScore,
+		&i.VerifiedAt,
+	)
+	return i, err
+}
+
+const queryCommentsByArticleIDAndStatus = `-- name: QueryCommentsByArticleIDAndStatus :many
+select id, created_at, updated_at, article_id, parent_id, author_name, author_email, content, status, spam_score, verified_at from comments where article_id=$1 and status=$2 order by created_at
+`
+
+type QueryCommentsByArticleIDAndStatusParams struct {
+	ArticleID int32
+	Status    string
+}
+
+// QueryCommentsByArticleIDAndStatus
+//
+//	select id, created_at, updated_at, article_id, parent_id, author_name, author_email, content, status, spam_score, verified_at from comments where article_id=$1 and status=$2 order by created_at
+func (q *Queries) QueryCommentsByArticleIDAndStatus(ctx context.Context, db DBTX, arg QueryCommentsByArticleIDAndStatusParams) ([]Comment, error) {
+	rows, err := db.Query(ctx, queryCommentsByArticleIDAndStatus, arg.ArticleID, arg.Status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Comment
+	for rows.Next() {
+		var i Comment
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ArticleID,
+			&i.ParentID,
+			&i.AuthorName,
+			&i.AuthorEmail,
+			&i.Content,
+			&i.Status,
+			&i.SpamScore,
+			&i.VerifiedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const queryCommentsByStatus = `-- name: QueryCommentsByStatus :many
+select id, created_at, updated_at, article_id, parent_id, author_name, author_email, content, status, spam_score, verified_at from comments
+where $1::varchar is null or status = $1::varchar
+order by created_at desc
+limit $2::bigint
+`
+
+type QueryCommentsByStatusParams struct {
+	Status pgtype.Text
+	Limit  int64
+}
+
+// QueryCommentsByStatus
+//
+//	select id, created_at, updated_at, article_id, parent_id, author_name, author_email, content, status, spam_score, verified_at from comments
+//	where $1::varchar is null or status = $1::varchar
+//	order by created_at desc
+//	limit $2::bigint
+func (q *Queries) QueryCommentsByStatus(ctx context.Context, db DBTX, arg QueryCommentsByStatusParams) ([]Comment, error) {
+	rows, err := db.Query(ctx, queryCommentsByStatus, arg.Status, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Comment
+	for rows.Next() {
+		var i Comment
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ArticleID,
+			&i.ParentID,
+			&i.AuthorName,
+			&i.AuthorEmail,
+			&i.Content,
+			&i.Status,
+			&i.SpamScore,
+			&i.VerifiedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateCommentStatus = `-- name: UpdateCommentStatus :one
+update comments set updated_at=now(), status=$2 where id = $1 returning id, created_at, updated_at, article_id, parent_id, author_name, author_email, content, status, spam_score, verified_at
+`
+
+type UpdateCommentStatusParams struct {
+	ID     uuid.UUID
+	Status string
+}
+
+// UpdateCommentStatus
+//
+//	update comments set updated_at=now(), status=$2 where id = $1 returning id, created_at, updated_at, article_id, parent_id, author_name, author_email, content, status, spam_score, verified_at
+func (q *Queries) UpdateCommentStatus(ctx context.Context, db DBTX, arg UpdateCommentStatusParams) (Comment, error) {
+	row := db.QueryRow(ctx, updateCommentStatus, arg.ID, arg.Status)
+	var i Comment
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ArticleID,
+		&i.ParentID,
+		&i.AuthorName,
+		&i.AuthorEmail,
+		&i.Content,
+		&i.Status,
+		&i.SpamScore,
+		&i.VerifiedAt,
+	)
+	return i, err
+}
+
+const updateCommentVerified = `-- name: UpdateCommentVerified :one
+update comments
+    set updated_at=now(), verified_at=now(), status=$2
+where id = $1 and status = 'unverified'
+returning id, created_at, updated_at, article_id, parent_id, author_name, author_email, content, status, spam_score, verified_at
+`
+
+type UpdateCommentVerifiedParams struct {
+	ID     uuid.UUID
+	Status string
+}
+
+// UpdateCommentVerified
+//
+//	update comments
+//	    set updated_at=now(), verified_at=now(), status=$2
+//	where id = $1 and status = 'unverified'
+//	returning id, created_at, updated_at, article_id, parent_id, author_name, author_email, content, status, spam_score, verified_at
+func (q *Queries) UpdateCommentVerified(ctx context.Context, db DBTX, arg UpdateCommentVerifiedParams) (Comment, error) {
+	row := db.QueryRow(ctx, updateCommentVerified, arg.ID, arg.Status)
+	var i Comment
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ArticleID,
+		&i.ParentID,
+		&i.AuthorName,
+		&i.AuthorEmail,
+		&i.Content,
+		&i.Status,
+		&i.SpamScore,
+		&i.VerifiedAt,
+	)
+	return i, err
+}
@@ -60,20 +60,48 @@ func (ns NullRiverJobState) Value() (driver.Value, error) {
 	return string(ns.RiverJobState), nil
 }
 
-type Article struct {
-	ID               int32
+type ActivitypubFollower struct {
+	ID               uuid.UUID
 	CreatedAt        pgtype.Timestamptz
 	UpdatedAt        pgtype.Timestamptz
-	FirstPublishedAt pgtype.Timestamptz
-	Published        bool
-	Title            string
-	Excerpt          pgtype.Text
-	MetaTitle        pgtype.Text
-	MetaDescription  pgtype.Text
-	Slug             string
-	ImageLink        pgtype.Text
-	ReadTime         pgtype.Int4
-	Content          pgtype.Text
+	ActorID          string
+	Inbox            string
+	SharedInbox      string
+	FollowActivityID string
+}
+
+type ActivitypubKey struct {
+	Name          string
+	CreatedAt     pgtype.Timestamptz
+	PrivateKeyPem string
+	PublicKeyPem  string
+}
+
+type Article struct {
+	ID                int32
+	CreatedAt         pgtype.Timestamptz
+	UpdatedAt         pgtype.Timestamptz
+	FirstPublishedAt  pgtype.Timestamptz
+	Published         bool
+	Title             string
+	Excerpt           pgtype.Text
+	MetaTitle         pgtype.Text
+	MetaDescription   pgtype.Text
+	Slug              string
+	ImageLink         pgtype.Text
+	ReadTime          pgtype.Int4
+	Content           pgtype.Text
+	AuthorID          pgtype.UUID
+	CommentsOpen      bool
+	TableOfContents   bool
+	ContentHtml       string
+	ContentHash       string
+	ContentHeadings   []byte
+	WordCount         int32
+	ImageCount        int32
+	HeadingCount      int32
+	EstimatedReadTime int32
+	ReadTimeOverride  bool
 }
 
 type ArticleTagConnection struct {
@@ -82,6 +110,68 @@ type ArticleTagConnection struct {
 	TagID     int32
 }
 
+type AuditLog struct {
+	ID         int64
+	CreatedAt  pgtype.Timestamptz
+	ActorID    pgtype.UUID
+	ActorEmail string
+	Action     string
+	TargetType string
+	TargetID   string
+	Changes    []byte
+	IpAddress  string
+	UserAgent  string
+	RequestID  string
+}
+
+type Comment struct {
+	ID          uuid.UUID
+	CreatedAt   pgtype.Timestamptz
+	UpdatedAt   pgtype.Timestamptz
+	ArticleID   int32
+	ParentID    pgtype.UUID
+	AuthorName  string
+	AuthorEmail string
+	Content     string
+	Status      string
+	SpamScore   int32
+	VerifiedAt  pgtype.Timestamptz
+}
+
+type LinkCheck struct {
+	ID            uuid.UUID
+	CreatedAt     pgtype.Timestamptz
+	UpdatedAt     pgtype.Timestamptz
+	Url           string
+	Internal      bool
+	Broken        bool
+	StatusCode    int32
+	Error         string
+	Sources       []byte
+	CheckedAt     pgtype.Timestamptz
+	FirstFailedAt pgtype.Timestamptz
+}
+
+type MediaVariant struct {
+	StorageKey  string
+	MediaID     uuid.UUID
+	ContentType string
+	Width       int32
+	Height      int32
+	ByteSize    int64
+}
+
+type Medium struct {
+	ID         uuid.UUID
+	CreatedAt  pgtype.Timestamptz
+	UpdatedAt  pgtype.Timestamptz
+	UploadedBy pgtype.UUID
+	Filename   string
+	AltText    string
+	Width      int32
+	Height     int32
+}
+
 type Newsletter struct {
 	ID              int32
 	CreatedAt       pgtype.Timestamptz
@@ -93,6 +183,30 @@ type Newsletter struct {
 	IsPublished     pgtype.Bool
 	ReleasedAt      pgtype.Timestamptz
 	Content         pgtype.Text
+	ContentHtml     string
+	ContentHash     string
+}
+
+type OgImage struct {
+	SubjectType string
+	SubjectID   int32
+	CreatedAt   pgtype.Timestamptz
+	UpdatedAt   pgtype.Timestamptz
+	Fingerprint string
+	Image       []byte
+}
+
+type PersonalAccessToken struct {
+	ID          uuid.UUID
+	CreatedAt   pgtype.Timestamptz
+	UpdatedAt   pgtype.Timestamptz
+	UserID      uuid.UUID
+	Name        string
+	TokenPrefix string
+	Hash        string
+	Scopes      []string
+	ExpiresAt   pgtype.Timestamptz
+	LastUsedAt  pgtype.Timestamptz
 }
 
 type Project struct {
@@ -107,6 +221,33 @@ type Project struct {
 	Description string
 	Content     string
 	ProjectUrl  pgtype.Text
+	AuthorID    pgtype.UUID
+	ContentHtml string
+	ContentHash string
+}
+
+type RateLimitBan struct {
+	ID        int32
+	CreatedAt pgtype.Timestamptz
+	UpdatedAt pgtype.Timestamptz
+	Scope     string
+	Key       string
+	Reason    string
+	ExpiresAt pgtype.Timestamptz
+}
+
+type RateLimitHit struct {
+	ID        int64
+	CreatedAt pgtype.Timestamptz
+	Scope     string
+	Key       string
+}
+
+type RelatedArticle struct {
+	ArticleID        int32
+	RelatedArticleID int32
+	Score            float64
+	ComputedAt       pgtype.Timestamptz
 }
 
 type RiverClient struct {
@@ -170,6 +311,22 @@ type RiverQueue struct {
 	UpdatedAt pgtype.Timestamptz
 }
 
+type Series struct {
+	ID          int32
+	CreatedAt   pgtype.Timestamptz
+	UpdatedAt   pgtype.Timestamptz
+	Title       string
+	Slug        string
+	Description string
+	Published   bool
+}
+
+type SeriesArticle struct {
+	SeriesID  int32
+	ArticleID int32
+	Position  int32
+}
+
 type Subscriber struct {
 	ID           int32
 	CreatedAt    pgtype.Timestamptz
@@ -181,10 +338,15 @@ type Subscriber struct {
 }
 
 type Tag struct {
-	ID        int32
-	CreatedAt pgtype.Timestamptz
-	UpdatedAt pgtype.Timestamptz
-	Title     string
+	ID              int32
+	CreatedAt       pgtype.Timestamptz
+	UpdatedAt       pgtype.Timestamptz
+	Title           string
+	Slug            string
+	Description     string
+	MetaTitle       string
+	MetaDescription string
+	ParentID        pgtype.Int4
 }
 
 type Token struct {
@@ -205,4 +367,46 @@ type User struct {
 	EmailValidatedAt pgtype.Timestamptz
 	Password         []byte
 	IsAdmin          bool
+	DeactivatedAt    pgtype.Timestamptz
+	Role             string
+}
+
+type WebhookDelivery struct {
+	ID             int64
+	CreatedAt      pgtype.Timestamptz
+	EndpointID     uuid.UUID
+	EventID        uuid.UUID
+	Event          string
+	Attempt        int32
+	Succeeded      bool
+	ResponseStatus pgtype.Int4
+	ResponseBody   string
+	Error          string
+	DurationMs     int32
+}
+
+type WebhookEndpoint struct {
+	ID        uuid.UUID
+	CreatedAt pgtype.Timestamptz
+	UpdatedAt pgtype.Timestamptz
+	Name      string
+	Url       string
+	Secret    string
+	Events    []string
+	IsActive  bool
+}
+
+type Webmention struct {
+	ID         uuid.UUID
+	CreatedAt  pgtype.Timestamptz
+	UpdatedAt  pgtype.Timestamptz
+	ArticleID  int32
+	Source     string
+	Target     string
+	Status     string
+	AuthorName string
+	AuthorUrl  string
+	Title      string
+	Excerpt    string
+	VerifiedAt pgtype.Timestamptz
 }
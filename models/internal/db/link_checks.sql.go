@@ -0,0 +1,168 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: link_checks.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const deleteLinkChecksCheckedBefore = `-- name: DeleteLinkChecksCheckedBefore :execrows
+delete from link_checks where checked_at < $1
+`
+
+// DeleteLinkChecksCheckedBefore
+//
+//	delete from link_checks where checked_at < $1
+func (q *Queries) DeleteLinkChecksCheckedBefore(ctx context.Context, db DBTX, checkedAt pgtype.Timestamptz) (int64, error) {
+	result, err := db.Exec(ctx, deleteLinkChecksCheckedBefore, checkedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const queryLinkChecks = `-- name: QueryLinkChecks :many
+select id, created_at, updated_at, url, internal, broken, status_code, error, sources, checked_at, first_failed_at from link_checks
+where $1::boolean is null or broken = $1::boolean
+order by broken desc, first_failed_at nulls last, url
+limit $2::bigint
+`
+
+type QueryLinkChecksParams struct {
+	Broken pgtype.Bool
+	Limit  int64
+}
+
+// QueryLinkChecks
+//
+//	select id, created_at, updated_at, url, internal, broken, status_code, error, sources, checked_at, first_failed_at from link_checks
+//	where $1::boolean is null or broken = $1::boolean
+//	order by broken desc, first_failed_at nulls last, url
+//	limit $2::bigint
+func (q *Queries) QueryLinkChecks(ctx context.Context, db DBTX, arg QueryLinkChecksParams) ([]LinkCheck, error) {
+	rows, err := db.Query(ctx, queryLinkChecks, arg.Broken, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LinkCheck
+	for rows.Next() {
+		var i LinkCheck
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Url,
+			&i.Internal,
+			&i.Broken,
+			&i.StatusCode,
+			&i.Error,
+			&i.Sources,
+			&i.CheckedAt,
+			&i.FirstFailedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertLinkCheck = `-- name: UpsertLinkCheck :one
+insert into
+    link_checks (
+        id, created_at, updated_at, url, internal, broken, status_code, error,
+        sources, checked_at, first_failed_at
+    )
+values
+    (
+        $1, now(), now(), $2, $3, $4, $5, $6, $7, $8,
+        case when $4::boolean then $8::timestamptz end
+    )
+on conflict (url) do update set
+    updated_at=now(),
+    internal=excluded.internal,
+    broken=excluded.broken,
+    status_code=excluded.status_code,
+    error=excluded.error,
+    sources=excluded.sources,
+    checked_at=excluded.checked_at,
+    first_failed_at=case
+        when not excluded.broken then null
+        else coalesce(link_checks.first_failed_at, excluded.checked_at)
+    end
+returning id, created_at, updated_at, url, internal, broken, status_code, error, sources, checked_at, first_failed_at
+`
+
+type UpsertLinkCheckParams struct {
+	ID         uuid.UUID
+	Url        string
+	Internal   bool
+	Broken     bool
+	StatusCode int32
+	Error      string
+	Sources    []byte
+	CheckedAt  pgtype.Timestamptz
+}
+
+// UpsertLinkCheck
+//
+//	insert into
+//	    link_checks (
+//	        id, created_at, updated_at, url, internal, broken, status_code, error,
+//	        sources, checked_at, first_failed_at
+//	    )
+//	values
+//	    (
+//	        $1, now(), now(), $2, $3, $4, $5, $6, $7, $8,
+//	        case when $4::boolean then $8::timestamptz end
+//	    )
+//	on conflict (url) do update set
+//	    updated_at=now(),
+//	    internal=excluded.internal,
+//	    broken=excluded.broken,
+//	    status_code=excluded.status_code,
+//	    error=excluded.error,
+//	    sources=excluded.sources,
+//	    checked_at=excluded.checked_at,
+//	    first_failed_at=case
+//	        when not excluded.broken then null
+//	        else coalesce(link_checks.first_failed_at, excluded.checked_at)
+//	    end
+//	returning id, created_at, updated_at, url, internal, broken, status_code, error, sources, checked_at, first_failed_at
+func (q *Queries) UpsertLinkCheck(ctx context.Context, db DBTX, arg UpsertLinkCheckParams) (LinkCheck, error) {
+	row := db.QueryRow(ctx, upsertLinkCheck,
+		arg.ID,
+		arg.Url,
+		arg.Internal,
+		arg.Broken,
+		arg.StatusCode,
+		arg.Error,
+		arg.Sources,
+		arg.CheckedAt,
+	)
+	var i LinkCheck
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Url,
+		&i.Internal,
+		&i.Broken,
+		&i.StatusCode,
+		&i.Error,
+		&i.Sources,
+		&i.CheckedAt,
+		&i.FirstFailedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,242 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: media.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const deleteMedia = `-- name: DeleteMedia :exec
+delete from media where id=$1
+`
+
+// DeleteMedia
+//
+//	delete from media where id=$1
+func (q *Queries) DeleteMedia(ctx context.Context, db DBTX, id uuid.UUID) error {
+	_, err := db.Exec(ctx, deleteMedia, id)
+	return err
+}
+
+const insertMedia = `-- name: InsertMedia :one
+insert into
+    media (id, created_at, updated_at, uploaded_by, filename, alt_text, width, height)
+values
+    ($1, now(), now(), $2, $3, $4, $5, $6)
+returning id, created_at, updated_at, uploaded_by, filename, alt_text, width, height
+`
+
+type InsertMediaParams struct {
+	ID         uuid.UUID
+	UploadedBy pgtype.UUID
+	Filename   string
+	AltText    string
+	Width      int32
+	Height     int32
+}
+
+// InsertMedia
+//
+//	insert into
+//	    media (id, created_at, updated_at, uploaded_by, filename, alt_text, width, height)
+//	values
+//	    ($1, now(), now(), $2, $3, $4, $5, $6)
+//	returning id, created_at, updated_at, uploaded_by, filename, alt_text, width, height
+func (q *Queries) InsertMedia(ctx context.Context, db DBTX, arg InsertMediaParams) (Medium, error) {
+	row := db.QueryRow(ctx, insertMedia,
+		arg.ID,
+		arg.UploadedBy,
+		arg.Filename,
+		arg.AltText,
+		arg.Width,
+		arg.Height,
+	)
+	var i Medium
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UploadedBy,
+		&i.Filename,
+		&i.AltText,
+		&i.Width,
+		&i.Height,
+	)
+	return i, err
+}
+
+const insertMediaVariant = `-- name: InsertMediaVariant :one
+insert into
+    media_variants (storage_key, media_id, content_type, width, height, byte_size)
+values
+    ($1, $2, $3, $4, $5, $6)
+returning storage_key, media_id, content_type, width, height, byte_size
+`
+
+type InsertMediaVariantParams struct {
+	StorageKey  string
+	MediaID     uuid.UUID
+	ContentType string
+	Width       int32
+	Height      int32
+	ByteSize    int64
+}
+
+// InsertMediaVariant
+//
+//	insert into
+//	    media_variants (storage_key, media_id, content_type, width, height, byte_size)
+//	values
+//	    ($1, $2, $3, $4, $5, $6)
+//	returning storage_key, media_id, content_type, width, height, byte_size
+func (q *Queries) InsertMediaVariant(ctx context.Context, db DBTX, arg InsertMediaVariantParams) (MediaVariant, error) {
+	row := db.QueryRow(ctx, insertMediaVariant,
+		arg.StorageKey,
+		arg.MediaID,
+		arg.ContentType,
+		arg.Width,
+		arg.Height,
+		arg.ByteSize,
+	)
+	var i MediaVariant
+	err := row.Scan(
+		&i.StorageKey,
+		&i.MediaID,
+		&i.ContentType,
+		&i.Width,
+		&i.Height,
+		&i.ByteSize,
+	)
+	return i, err
+}
+
+const queryMediaByID = `-- name: QueryMediaByID :one
+select id, created_at, updated_at, uploaded_by, filename, alt_text, width, height from media where id=$1
+`
+
+// QueryMediaByID
+//
+//	select id, created_at, updated_at, uploaded_by, filename, alt_text, width, height from media where id=$1
+func (q *Queries) QueryMediaByID(ctx context.Context, db DBTX, id uuid.UUID) (Medium, error) {
+	row := db.QueryRow(ctx, queryMediaByID, id)
+	var i Medium
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UploadedBy,
+		&i.Filename,
+		&i.AltText,
+		&i.Width,
+		&i.Height,
+	)
+	return i, err
+}
+
+const queryMediaVariantsByMediaIDs = `-- name: QueryMediaVariantsByMediaIDs :many
+select storage_key, media_id, content_type, width, height, byte_size from media_variants
+where media_id = any($1::uuid[])
+order by media_id, width, content_type
+`
+
+// QueryMediaVariantsByMediaIDs
+//
+//	select storage_key, media_id, content_type, width, height, byte_size from media_variants
+//	where media_id = any($1::uuid[])
+//	order by media_id, width, content_type
+func (q *Queries) QueryMediaVariantsByMediaIDs(ctx context.Context, db DBTX, mediaIds []uuid.UUID) ([]MediaVariant, error) {
+	rows, err := db.Query(ctx, queryMediaVariantsByMediaIDs, mediaIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MediaVariant
+	for rows.Next() {
+		var i MediaVariant
+		if err := rows.Scan(
+			&i.StorageKey,
+			&i.MediaID,
+			&i.ContentType,
+			&i.Width,
+			&i.Height,
+			&i.ByteSize,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const queryRecentMedia = `-- name: QueryRecentMedia :many
+select id, created_at, updated_at, uploaded_by, filename, alt_text, width, height from media order by created_at desc limit $1::bigint
+`
+
+// QueryRecentMedia
+//
+//	select id, created_at, updated_at, uploaded_by, filename, alt_text, width, height from media order by created_at desc limit $1::bigint
+func (q *Queries) QueryRecentMedia(ctx context.Context, db DBTX, limit int64) ([]Medium, error) {
+	rows, err := db.Query(ctx, queryRecentMedia, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Medium
+	for rows.Next() {
+		var i Medium
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UploadedBy,
+			&i.Filename,
+			&i.AltText,
+			&i.Width,
+			&i.Height,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateMediaAltText = `-- name: UpdateMediaAltText :one
+update media set updated_at=now(), alt_text=$2 where id = $1 returning id, created_at, updated_at, uploaded_by, filename, alt_text, width, height
+`
+
+type UpdateMediaAltTextParams struct {
+	ID      uuid.UUID
+	AltText string
+}
+
+// UpdateMediaAltText
+//
+//	update media set updated_at=now(), alt_text=$2 where id = $1 returning id, created_at, updated_at, uploaded_by, filename, alt_text, width, height
+func (q *Queries) UpdateMediaAltText(ctx context.Context, db DBTX, arg UpdateMediaAltTextParams) (Medium, error) {
+	row := db.QueryRow(ctx, updateMediaAltText, arg.ID, arg.AltText)
+	var i Medium
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UploadedBy,
+		&i.Filename,
+		&i.AltText,
+		&i.Width,
+		&i.Height,
+	)
+	return i, err
+}
@@ -42,7 +42,7 @@ insert into
     newsletters (created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content)
 values
     (now(), now(), $1, $2, $3, $4, $5, $6, $7)
-returning id, created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content
+returning id, created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content, content_html, content_hash
 `
 
 type InsertNewsletterParams struct {
@@ -61,7 +61,7 @@ type InsertNewsletterParams struct {
 //	    newsletters (created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content)
 //	values
 //	    (now(), now(), $1, $2, $3, $4, $5, $6, $7)
-//	returning id, created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content
+//	returning id, created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content, content_html, content_hash
 func (q *Queries) InsertNewsletter(ctx context.Context, db DBTX, arg InsertNewsletterParams) (Newsletter, error) {
 	row := db.QueryRow(ctx, insertNewsletter,
 		arg.Title,
@@ -84,17 +84,19 @@ func (q *Queries) InsertNewsletter(ctx context.Context, db DBTX, arg InsertNewsl
 		&i.IsPublished,
 		&i.ReleasedAt,
 		&i.Content,
+		&i.ContentHtml,
+		&i.ContentHash,
 	)
 	return i, err
 }
 
 const queryNewsletterByID = `-- name: QueryNewsletterByID :one
-select id, created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content from newsletters where id=$1
+select id, created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content, content_html, content_hash from newsletters where id=$1
 `
 
 // QueryNewsletterByID
 //
-//	select id, created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content from newsletters where id=$1
+//	select id, created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content, content_html, content_hash from newsletters where id=$1
 func (q *Queries) QueryNewsletterByID(ctx context.Context, db DBTX, id int32) (Newsletter, error) {
 	row := db.QueryRow(ctx, queryNewsletterByID, id)
 	var i Newsletter
@@ -109,17 +111,19 @@ func (q *Queries) QueryNewsletterByID(ctx context.Context, db DBTX, id int32) (N
 		&i.IsPublished,
 		&i.ReleasedAt,
 		&i.Content,
+		&i.ContentHtml,
+		&i.ContentHash,
 	)
 	return i, err
 }
 
 const queryNewsletters = `-- name: QueryNewsletters :many
-select id, created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content from newsletters
+select id, created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content, content_html, content_hash from newsletters
 `
 
 // QueryNewsletters
 //
-//	select id, created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content from newsletters
+//	select id, created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content, content_html, content_hash from newsletters
 func (q *Queries) QueryNewsletters(ctx context.Context, db DBTX) ([]Newsletter, error) {
 	rows, err := db.Query(ctx, queryNewsletters)
 	if err != nil {
@@ -140,6 +144,8 @@ func (q *Queries) QueryNewsletters(ctx context.Context, db DBTX) ([]Newsletter,
 			&i.IsPublished,
 			&i.ReleasedAt,
 			&i.Content,
+			&i.ContentHtml,
+			&i.ContentHash,
 		); err != nil {
 			return nil, err
 		}
@@ -152,7 +158,7 @@ func (q *Queries) QueryNewsletters(ctx context.Context, db DBTX) ([]Newsletter,
 }
 
 const queryPaginatedNewsletters = `-- name: QueryPaginatedNewsletters :many
-select id, created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content from newsletters
+select id, created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content, content_html, content_hash from newsletters
 order by created_at desc
 limit $2::bigint offset $1::bigint
 `
@@ -164,7 +170,7 @@ type QueryPaginatedNewslettersParams struct {
 
 // QueryPaginatedNewsletters
 //
-//	select id, created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content from newsletters
+//	select id, created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content, content_html, content_hash from newsletters
 //	order by created_at desc
 //	limit $2::bigint offset $1::bigint
 func (q *Queries) QueryPaginatedNewsletters(ctx context.Context, db DBTX, arg QueryPaginatedNewslettersParams) ([]Newsletter, error) {
@@ -187,6 +193,8 @@ func (q *Queries) QueryPaginatedNewsletters(ctx context.Context, db DBTX, arg Qu
 			&i.IsPublished,
 			&i.ReleasedAt,
 			&i.Content,
+			&i.ContentHtml,
+			&i.ContentHash,
 		); err != nil {
 			return nil, err
 		}
@@ -199,12 +207,12 @@ func (q *Queries) QueryPaginatedNewsletters(ctx context.Context, db DBTX, arg Qu
 }
 
 const queryPublishedNewsletters = `-- name: QueryPublishedNewsletters :many
-select id, created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content from newsletters where is_published=true order by released_at desc
+select id, created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content, content_html, content_hash from newsletters where is_published=true order by released_at desc
 `
 
 // QueryPublishedNewsletters
 //
-//	select id, created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content from newsletters where is_published=true order by released_at desc
+//	select id, created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content, content_html, content_hash from newsletters where is_published=true order by released_at desc
 func (q *Queries) QueryPublishedNewsletters(ctx context.Context, db DBTX) ([]Newsletter, error) {
 	rows, err := db.Query(ctx, queryPublishedNewsletters)
 	if err != nil {
@@ -225,6 +233,8 @@ func (q *Queries) QueryPublishedNewsletters(ctx context.Context, db DBTX) ([]New
 			&i.IsPublished,
 			&i.ReleasedAt,
 			&i.Content,
+			&i.ContentHtml,
+			&i.ContentHash,
 		); err != nil {
 			return nil, err
 		}
@@ -240,7 +250,7 @@ const updateNewsletter = `-- name: UpdateNewsletter :one
 update newsletters
     set updated_at=now(), title=$2, slug=$3, meta_title=$4, meta_description=$5, is_published=$6, released_at=$7, content=$8
 where id = $1
-returning id, created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content
+returning id, created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content, content_html, content_hash
 `
 
 type UpdateNewsletterParams struct {
@@ -259,7 +269,7 @@ type UpdateNewsletterParams struct {
 //	update newsletters
 //	    set updated_at=now(), title=$2, slug=$3, meta_title=$4, meta_description=$5, is_published=$6, released_at=$7, content=$8
 //	where id = $1
-//	returning id, created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content
+//	returning id, created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content, content_html, content_hash
 func (q *Queries) UpdateNewsletter(ctx context.Context, db DBTX, arg UpdateNewsletterParams) (Newsletter, error) {
 	row := db.QueryRow(ctx, updateNewsletter,
 		arg.ID,
@@ -283,6 +293,41 @@ func (q *Queries) UpdateNewsletter(ctx context.Context, db DBTX, arg UpdateNewsl
 		&i.IsPublished,
 		&i.ReleasedAt,
 		&i.Content,
+		&i.ContentHtml,
+		&i.ContentHash,
+	)
+	return i, err
+}
+
+const updateNewsletterContentHTML = `-- name: UpdateNewsletterContentHTML :one
+update newsletters set content_html=$2, content_hash=$3 where id = $1 returning id, created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content, content_html, content_hash
+`
+
+type UpdateNewsletterContentHTMLParams struct {
+	ID          int32
+	ContentHtml string
+	ContentHash string
+}
+
+// UpdateNewsletterContentHTML
+//
+//	update newsletters set content_html=$2, content_hash=$3 where id = $1 returning id, created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content, content_html, content_hash
+func (q *Queries) UpdateNewsletterContentHTML(ctx context.Context, db DBTX, arg UpdateNewsletterContentHTMLParams) (Newsletter, error) {
+	row := db.QueryRow(ctx, updateNewsletterContentHTML, arg.ID, arg.ContentHtml, arg.ContentHash)
+	var i Newsletter
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Title,
+		&i.Slug,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.IsPublished,
+		&i.ReleasedAt,
+		&i.Content,
+		&i.ContentHtml,
+		&i.ContentHash,
 	)
 	return i, err
 }
@@ -291,12 +336,24 @@ const upsertNewsletter = `-- name: UpsertNewsletter :one
 insert into
     newsletters (created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content)
 values
-    (now(), now(), $1, $2, $3, $4, $5, $6, $7)
-on conflict (id) do update set updated_at=now(), title=excluded.title, slug=excluded.slug, meta_title=excluded.meta_title, meta_description=excluded.meta_description, is_published=excluded.is_published, released_at=excluded.released_at, content=excluded.content
-returning id, created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content
+    (
+      coalesce($1::timestamptz, now()),
+      coalesce($2::timestamptz, now()),
+      $3,
+      $4,
+      $5,
+      $6,
+      $7,
+      $8,
+      $9
+    )
+on conflict (slug) do update set updated_at=now(), title=excluded.title, meta_title=excluded.meta_title, meta_description=excluded.meta_description, is_published=excluded.is_published, released_at=excluded.released_at, content=excluded.content
+returning id, created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content, content_html, content_hash
 `
 
 type UpsertNewsletterParams struct {
+	CreatedAt       pgtype.Timestamptz
+	UpdatedAt       pgtype.Timestamptz
 	Title           string
 	Slug            pgtype.Text
 	MetaTitle       string
@@ -311,11 +368,23 @@ type UpsertNewsletterParams struct {
 //	insert into
 //	    newsletters (created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content)
 //	values
-//	    (now(), now(), $1, $2, $3, $4, $5, $6, $7)
-//	on conflict (id) do update set updated_at=now(), title=excluded.title, slug=excluded.slug, meta_title=excluded.meta_title, meta_description=excluded.meta_description, is_published=excluded.is_published, released_at=excluded.released_at, content=excluded.content
-//	returning id, created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content
+//	    (
+//	      coalesce($1::timestamptz, now()),
+//	      coalesce($2::timestamptz, now()),
+//	      $3,
+//	      $4,
+//	      $5,
+//	      $6,
+//	      $7,
+//	      $8,
+//	      $9
+//	    )
+//	on conflict (slug) do update set updated_at=now(), title=excluded.title, meta_title=excluded.meta_title, meta_description=excluded.meta_description, is_published=excluded.is_published, released_at=excluded.released_at, content=excluded.content
+//	returning id, created_at, updated_at, title, slug, meta_title, meta_description, is_published, released_at, content, content_html, content_hash
 func (q *Queries) UpsertNewsletter(ctx context.Context, db DBTX, arg UpsertNewsletterParams) (Newsletter, error) {
 	row := db.QueryRow(ctx, upsertNewsletter,
+		arg.CreatedAt,
+		arg.UpdatedAt,
 		arg.Title,
 		arg.Slug,
 		arg.MetaTitle,
@@ -336,6 +405,8 @@ func (q *Queries) UpsertNewsletter(ctx context.Context, db DBTX, arg UpsertNewsl
 		&i.IsPublished,
 		&i.ReleasedAt,
 		&i.Content,
+		&i.ContentHtml,
+		&i.ContentHash,
 	)
 	return i, err
 }
@@ -0,0 +1,85 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: og_images.sql
+
+package db
+
+import (
+	"context"
+)
+
+const queryOGImage = `-- name: QueryOGImage :one
+select subject_type, subject_id, created_at, updated_at, fingerprint, image from og_images where subject_type=$1 and subject_id=$2
+`
+
+type QueryOGImageParams struct {
+	SubjectType string
+	SubjectID   int32
+}
+
+// QueryOGImage
+//
+//	select subject_type, subject_id, created_at, updated_at, fingerprint, image from og_images where subject_type=$1 and subject_id=$2
+func (q *Queries) QueryOGImage(ctx context.Context, db DBTX, arg QueryOGImageParams) (OgImage, error) {
+	row := db.QueryRow(ctx, queryOGImage, arg.SubjectType, arg.SubjectID)
+	var i OgImage
+	err := row.Scan(
+		&i.SubjectType,
+		&i.SubjectID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Fingerprint,
+		&i.Image,
+	)
+	return i, err
+}
+
+const upsertOGImage = `-- name: UpsertOGImage :one
+insert into
+    og_images (subject_type, subject_id, created_at, updated_at, fingerprint, image)
+values
+    ($1, $2, now(), now(), $3, $4)
+on conflict (subject_type, subject_id) do update set
+    updated_at=now(),
+    fingerprint=excluded.fingerprint,
+    image=excluded.image
+returning subject_type, subject_id, created_at, updated_at, fingerprint, image
+`
+
+type UpsertOGImageParams struct {
+	SubjectType string
+	SubjectID   int32
+	Fingerprint string
+	Image       []byte
+}
+
+// UpsertOGImage
+//
+//	insert into
+//	    og_images (subject_type, subject_id, created_at, updated_at, fingerprint, image)
+//	values
+//	    ($1, $2, now(), now(), $3, $4)
+//	on conflict (subject_type, subject_id) do update set
+//	    updated_at=now(),
+//	    fingerprint=excluded.fingerprint,
+//	    image=excluded.image
+//	returning subject_type, subject_id, created_at, updated_at, fingerprint, image
+func (q *Queries) UpsertOGImage(ctx context.Context, db DBTX, arg UpsertOGImageParams) (OgImage, error) {
+	row := db.QueryRow(ctx, upsertOGImage,
+		arg.SubjectType,
+		arg.SubjectID,
+		arg.Fingerprint,
+		arg.Image,
+	)
+	var i OgImage
+	err := row.Scan(
+		&i.SubjectType,
+		&i.SubjectID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Fingerprint,
+		&i.Image,
+	)
+	return i, err
+}
@@ -0,0 +1,156 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: personal_access_tokens.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const deletePersonalAccessToken = `-- name: DeletePersonalAccessToken :exec
+delete from personal_access_tokens where id=$1 and user_id=$2
+`
+
+type DeletePersonalAccessTokenParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+// DeletePersonalAccessToken
+//
+//	delete from personal_access_tokens where id=$1 and user_id=$2
+func (q *Queries) DeletePersonalAccessToken(ctx context.Context, db DBTX, arg DeletePersonalAccessTokenParams) error {
+	_, err := db.Exec(ctx, deletePersonalAccessToken, arg.ID, arg.UserID)
+	return err
+}
+
+const insertPersonalAccessToken = `-- name: InsertPersonalAccessToken :one
+insert into
+    personal_access_tokens (id, created_at, updated_at, user_id, name, token_prefix, hash, scopes, expires_at)
+values
+    ($1, now(), now(), $2, $3, $4, $5, $6, $7)
+returning id, created_at, updated_at, user_id, name, token_prefix, hash, scopes, expires_at, last_used_at
+`
+
+type InsertPersonalAccessTokenParams struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Name        string
+	TokenPrefix string
+	Hash        string
+	Scopes      []string
+	ExpiresAt   pgtype.Timestamptz
+}
+
+// InsertPersonalAccessToken
+//
+//	insert into
+//	    personal_access_tokens (id, created_at, updated_at, user_id, name, token_prefix, hash, scopes, expires_at)
+//	values
+//	    ($1, now(), now(), $2, $3, $4, $5, $6, $7)
+//	returning id, created_at, updated_at, user_id, name, token_prefix, hash, scopes, expires_at, last_used_at
+func (q *Queries) InsertPersonalAccessToken(ctx context.Context, db DBTX, arg InsertPersonalAccessTokenParams) (PersonalAccessToken, error) {
+	row := db.QueryRow(ctx, insertPersonalAccessToken,
+		arg.ID,
+		arg.UserID,
+		arg.Name,
+		arg.TokenPrefix,
+		arg.Hash,
+		arg.Scopes,
+		arg.ExpiresAt,
+	)
+	var i PersonalAccessToken
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.Name,
+		&i.TokenPrefix,
+		&i.Hash,
+		&i.Scopes,
+		&i.ExpiresAt,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
+
+const queryPersonalAccessTokenByHash = `-- name: QueryPersonalAccessTokenByHash :one
+select id, created_at, updated_at, user_id, name, token_prefix, hash, scopes, expires_at, last_used_at from personal_access_tokens where hash=$1
+`
+
+// QueryPersonalAccessTokenByHash
+//
+//	select id, created_at, updated_at, user_id, name, token_prefix, hash, scopes, expires_at, last_used_at from personal_access_tokens where hash=$1
+func (q *Queries) QueryPersonalAccessTokenByHash(ctx context.Context, db DBTX, hash string) (PersonalAccessToken, error) {
+	row := db.QueryRow(ctx, queryPersonalAccessTokenByHash, hash)
+	var i PersonalAccessToken
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.Name,
+		&i.TokenPrefix,
+		&i.Hash,
+		&i.Scopes,
+		&i.ExpiresAt,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
+
+const queryPersonalAccessTokensByUserID = `-- name: QueryPersonalAccessTokensByUserID :many
+select id, created_at, updated_at, user_id, name, token_prefix, hash, scopes, expires_at, last_used_at from personal_access_tokens where user_id=$1 order by created_at desc
+`
+
+// QueryPersonalAccessTokensByUserID
+//
+//	select id, created_at, updated_at, user_id, name, token_prefix, hash, scopes, expires_at, last_used_at from personal_access_tokens where user_id=$1 order by created_at desc
+func (q *Queries) QueryPersonalAccessTokensByUserID(ctx context.Context, db DBTX, userID uuid.UUID) ([]PersonalAccessToken, error) {
+	rows, err := db.Query(ctx, queryPersonalAccessTokensByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PersonalAccessToken
+	for rows.Next() {
+		var i PersonalAccessToken
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.Name,
+			&i.TokenPrefix,
+			&i.Hash,
+			&i.Scopes,
+			&i.ExpiresAt,
+			&i.LastUsedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updatePersonalAccessTokenLastUsedAt = `-- name: UpdatePersonalAccessTokenLastUsedAt :exec
+update personal_access_tokens set last_used_at=now() where id=$1
+`
+
+// UpdatePersonalAccessTokenLastUsedAt
+//
+//	update personal_access_tokens set last_used_at=now() where id=$1
+func (q *Queries) UpdatePersonalAccessTokenLastUsedAt(ctx context.Context, db DBTX, id uuid.UUID) error {
+	_, err := db.Exec(ctx, updatePersonalAccessTokenLastUsedAt, id)
+	return err
+}
@@ -49,7 +49,8 @@ insert into
       status,
       description,
       content,
-      project_url
+      project_url,
+      author_id
     )
 values
     (
@@ -62,9 +63,10 @@ values
       $5,
       $6,
       $7,
-      $8
+      $8,
+      $9
     )
-returning id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url
+returning id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url, author_id, content_html, content_hash
 `
 
 type InsertProjectParams struct {
@@ -76,6 +78,7 @@ type InsertProjectParams struct {
 	Description string
 	Content     string
 	ProjectUrl  pgtype.Text
+	AuthorID    pgtype.UUID
 }
 
 // InsertProject
@@ -91,7 +94,8 @@ type InsertProjectParams struct {
 //	      status,
 //	      description,
 //	      content,
-//	      project_url
+//	      project_url,
+//	      author_id
 //	    )
 //	values
 //	    (
@@ -104,9 +108,10 @@ type InsertProjectParams struct {
 //	      $5,
 //	      $6,
 //	      $7,
-//	      $8
+//	      $8,
+//	      $9
 //	    )
-//	returning id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url
+//	returning id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url, author_id, content_html, content_hash
 func (q *Queries) InsertProject(ctx context.Context, db DBTX, arg InsertProjectParams) (Project, error) {
 	row := db.QueryRow(ctx, insertProject,
 		arg.Published,
@@ -117,6 +122,7 @@ func (q *Queries) InsertProject(ctx context.Context, db DBTX, arg InsertProjectP
 		arg.Description,
 		arg.Content,
 		arg.ProjectUrl,
+		arg.AuthorID,
 	)
 	var i Project
 	err := row.Scan(
@@ -131,12 +137,15 @@ func (q *Queries) InsertProject(ctx context.Context, db DBTX, arg InsertProjectP
 		&i.Description,
 		&i.Content,
 		&i.ProjectUrl,
+		&i.AuthorID,
+		&i.ContentHtml,
+		&i.ContentHash,
 	)
 	return i, err
 }
 
 const queryPaginatedProjects = `-- name: QueryPaginatedProjects :many
-select id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url from projects
+select id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url, author_id, content_html, content_hash from projects
 order by created_at desc
 limit $2::bigint offset $1::bigint
 `
@@ -148,7 +157,7 @@ type QueryPaginatedProjectsParams struct {
 
 // QueryPaginatedProjects
 //
-//	select id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url from projects
+//	select id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url, author_id, content_html, content_hash from projects
 //	order by created_at desc
 //	limit $2::bigint offset $1::bigint
 func (q *Queries) QueryPaginatedProjects(ctx context.Context, db DBTX, arg QueryPaginatedProjectsParams) ([]Project, error) {
@@ -172,6 +181,9 @@ func (q *Queries) QueryPaginatedProjects(ctx context.Context, db DBTX, arg Query
 			&i.Description,
 			&i.Content,
 			&i.ProjectUrl,
+			&i.AuthorID,
+			&i.ContentHtml,
+			&i.ContentHash,
 		); err != nil {
 			return nil, err
 		}
@@ -184,12 +196,12 @@ func (q *Queries) QueryPaginatedProjects(ctx context.Context, db DBTX, arg Query
 }
 
 const queryProjectByID = `-- name: QueryProjectByID :one
-select id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url from projects where id=$1
+select id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url, author_id, content_html, content_hash from projects where id=$1
 `
 
 // QueryProjectByID
 //
-//	select id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url from projects where id=$1
+//	select id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url, author_id, content_html, content_hash from projects where id=$1
 func (q *Queries) QueryProjectByID(ctx context.Context, db DBTX, id int32) (Project, error) {
 	row := db.QueryRow(ctx, queryProjectByID, id)
 	var i Project
@@ -205,17 +217,20 @@ func (q *Queries) QueryProjectByID(ctx context.Context, db DBTX, id int32) (Proj
 		&i.Description,
 		&i.Content,
 		&i.ProjectUrl,
+		&i.AuthorID,
+		&i.ContentHtml,
+		&i.ContentHash,
 	)
 	return i, err
 }
 
 const queryProjectBySlug = `-- name: QueryProjectBySlug :one
-select id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url from projects where slug=$1
+select id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url, author_id, content_html, content_hash from projects where slug=$1
 `
 
 // QueryProjectBySlug
 //
-//	select id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url from projects where slug=$1
+//	select id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url, author_id, content_html, content_hash from projects where slug=$1
 func (q *Queries) QueryProjectBySlug(ctx context.Context, db DBTX, slug string) (Project, error) {
 	row := db.QueryRow(ctx, queryProjectBySlug, slug)
 	var i Project
@@ -231,17 +246,20 @@ func (q *Queries) QueryProjectBySlug(ctx context.Context, db DBTX, slug string)
 		&i.Description,
 		&i.Content,
 		&i.ProjectUrl,
+		&i.AuthorID,
+		&i.ContentHtml,
+		&i.ContentHash,
 	)
 	return i, err
 }
 
 const queryProjects = `-- name: QueryProjects :many
-select id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url from projects
+select id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url, author_id, content_html, content_hash from projects
 `
 
 // QueryProjects
 //
-//	select id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url from projects
+//	select id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url, author_id, content_html, content_hash from projects
 func (q *Queries) QueryProjects(ctx context.Context, db DBTX) ([]Project, error) {
 	rows, err := db.Query(ctx, queryProjects)
 	if err != nil {
@@ -263,6 +281,9 @@ func (q *Queries) QueryProjects(ctx context.Context, db DBTX) ([]Project, error)
 			&i.Description,
 			&i.Content,
 			&i.ProjectUrl,
+			&i.AuthorID,
+			&i.ContentHtml,
+			&i.ContentHash,
 		); err != nil {
 			return nil, err
 		}
@@ -275,12 +296,12 @@ func (q *Queries) QueryProjects(ctx context.Context, db DBTX) ([]Project, error)
 }
 
 const queryPublishedProjects = `-- name: QueryPublishedProjects :many
-select id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url from projects where published=true order by started_at desc
+select id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url, author_id, content_html, content_hash from projects where published=true order by started_at desc
 `
 
 // QueryPublishedProjects
 //
-//	select id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url from projects where published=true order by started_at desc
+//	select id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url, author_id, content_html, content_hash from projects where published=true order by started_at desc
 func (q *Queries) QueryPublishedProjects(ctx context.Context, db DBTX) ([]Project, error) {
 	rows, err := db.Query(ctx, queryPublishedProjects)
 	if err != nil {
@@ -302,6 +323,9 @@ func (q *Queries) QueryPublishedProjects(ctx context.Context, db DBTX) ([]Projec
 			&i.Description,
 			&i.Content,
 			&i.ProjectUrl,
+			&i.AuthorID,
+			&i.ContentHtml,
+			&i.ContentHash,
 		); err != nil {
 			return nil, err
 		}
@@ -326,7 +350,7 @@ set
     content=$8,
     project_url=$9
 where id = $1
-returning id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url
+returning id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url, author_id, content_html, content_hash
 `
 
 type UpdateProjectParams struct {
@@ -355,7 +379,7 @@ type UpdateProjectParams struct {
 //	    content=$8,
 //	    project_url=$9
 //	where id = $1
-//	returning id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url
+//	returning id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url, author_id, content_html, content_hash
 func (q *Queries) UpdateProject(ctx context.Context, db DBTX, arg UpdateProjectParams) (Project, error) {
 	row := db.QueryRow(ctx, updateProject,
 		arg.ID,
@@ -381,6 +405,44 @@ func (q *Queries) UpdateProject(ctx context.Context, db DBTX, arg UpdateProjectP
 		&i.Description,
 		&i.Content,
 		&i.ProjectUrl,
+		&i.AuthorID,
+		&i.ContentHtml,
+		&i.ContentHash,
+	)
+	return i, err
+}
+
+const updateProjectContentHTML = `-- name: UpdateProjectContentHTML :one
+update projects set content_html=$2, content_hash=$3 where id = $1 returning id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url, author_id, content_html, content_hash
+`
+
+type UpdateProjectContentHTMLParams struct {
+	ID          int32
+	ContentHtml string
+	ContentHash string
+}
+
+// UpdateProjectContentHTML
+//
+//	update projects set content_html=$2, content_hash=$3 where id = $1 returning id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url, author_id, content_html, content_hash
+func (q *Queries) UpdateProjectContentHTML(ctx context.Context, db DBTX, arg UpdateProjectContentHTMLParams) (Project, error) {
+	row := db.QueryRow(ctx, updateProjectContentHTML, arg.ID, arg.ContentHtml, arg.ContentHash)
+	var i Project
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Published,
+		&i.Title,
+		&i.Slug,
+		&i.StartedAt,
+		&i.Status,
+		&i.Description,
+		&i.Content,
+		&i.ProjectUrl,
+		&i.AuthorID,
+		&i.ContentHtml,
+		&i.ContentHash,
 	)
 	return i, err
 }
@@ -397,20 +459,22 @@ insert into
       status,
       description,
       content,
-      project_url
+      project_url,
+      author_id
     )
 values
     (
-      now(),
-      now(),
-      $1,
-      $2,
+      coalesce($1::timestamptz, now()),
+      coalesce($2::timestamptz, now()),
       $3,
       $4,
       $5,
       $6,
       $7,
-      $8
+      $8,
+      $9,
+      $10,
+      $11
     )
 on conflict (slug) do update
 set
@@ -422,10 +486,12 @@ set
     description=excluded.description,
     content=excluded.content,
     project_url=excluded.project_url
-returning id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url
+returning id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url, author_id, content_html, content_hash
 `
 
 type UpsertProjectParams struct {
+	CreatedAt   pgtype.Timestamptz
+	UpdatedAt   pgtype.Timestamptz
 	Published   bool
 	Title       string
 	Slug        string
@@ -434,6 +500,7 @@ type UpsertProjectParams struct {
 	Description string
 	Content     string
 	ProjectUrl  pgtype.Text
+	AuthorID    pgtype.UUID
 }
 
 // UpsertProject
@@ -449,20 +516,22 @@ type UpsertProjectParams struct {
 //	      status,
 //	      description,
 //	      content,
-//	      project_url
+//	      project_url,
+//	      author_id
 //	    )
 //	values
 //	    (
-//	      now(),
-//	      now(),
-//	      $1,
-//	      $2,
+//	      coalesce($1::timestamptz, now()),
+//	      coalesce($2::timestamptz, now()),
 //	      $3,
 //	      $4,
 //	      $5,
 //	      $6,
 //	      $7,
-//	      $8
+//	      $8,
+//	      $9,
+//	      $10,
+//	      $11
 //	    )
 //	on conflict (slug) do update
 //	set
@@ -474,9 +543,11 @@ type UpsertProjectParams struct {
 //	    description=excluded.description,
 //	    content=excluded.content,
 //	    project_url=excluded.project_url
-//	returning id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url
+//	returning id, created_at, updated_at, published, title, slug, started_at, status, description, content, project_url, author_id, content_html, content_hash
 func (q *Queries) UpsertProject(ctx context.Context, db DBTX, arg UpsertProjectParams) (Project, error) {
 	row := db.QueryRow(ctx, upsertProject,
+		arg.CreatedAt,
+		arg.UpdatedAt,
 		arg.Published,
 		arg.Title,
 		arg.Slug,
@@ -485,6 +556,7 @@ func (q *Queries) UpsertProject(ctx context.Context, db DBTX, arg UpsertProjectP
 		arg.Description,
 		arg.Content,
 		arg.ProjectUrl,
+		arg.AuthorID,
 	)
 	var i Project
 	err := row.Scan(
@@ -499,6 +571,9 @@ func (q *Queries) UpsertProject(ctx context.Context, db DBTX, arg UpsertProjectP
 		&i.Description,
 		&i.Content,
 		&i.ProjectUrl,
+		&i.AuthorID,
+		&i.ContentHtml,
+		&i.ContentHash,
 	)
 	return i, err
 }
@@ -0,0 +1,305 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: rate_limits.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const countActiveRateLimitBans = `-- name: CountActiveRateLimitBans :one
+select count(*) from rate_limit_bans where expires_at > now()
+`
+
+// CountActiveRateLimitBans
+//
+//	select count(*) from rate_limit_bans where expires_at > now()
+func (q *Queries) CountActiveRateLimitBans(ctx context.Context, db DBTX) (int64, error) {
+	row := db.QueryRow(ctx, countActiveRateLimitBans)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countRateLimitHitsSince = `-- name: CountRateLimitHitsSince :one
+select count(*) from rate_limit_hits
+where scope=$1 and key=$2 and created_at > $3::timestamptz
+`
+
+type CountRateLimitHitsSinceParams struct {
+	Scope string
+	Key   string
+	Since pgtype.Timestamptz
+}
+
+// CountRateLimitHitsSince
+//
+//	select count(*) from rate_limit_hits
+//	where scope=$1 and key=$2 and created_at > $3::timestamptz
+func (q *Queries) CountRateLimitHitsSince(ctx context.Context, db DBTX, arg CountRateLimitHitsSinceParams) (int64, error) {
+	row := db.QueryRow(ctx, countRateLimitHitsSince, arg.Scope, arg.Key, arg.Since)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteAllRateLimitHitsBefore = `-- name: DeleteAllRateLimitHitsBefore :execrows
+delete from rate_limit_hits where created_at < $1::timestamptz
+`
+
+// DeleteAllRateLimitHitsBefore
+//
+//	delete from rate_limit_hits where created_at < $1::timestamptz
+func (q *Queries) DeleteAllRateLimitHitsBefore(ctx context.Context, db DBTX, before pgtype.Timestamptz) (int64, error) {
+	result, err := db.Exec(ctx, deleteAllRateLimitHitsBefore, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteExpiredRateLimitBans = `-- name: DeleteExpiredRateLimitBans :execrows
+delete from rate_limit_bans where expires_at <= now()
+`
+
+// DeleteExpiredRateLimitBans
+//
+//	delete from rate_limit_bans where expires_at <= now()
+func (q *Queries) DeleteExpiredRateLimitBans(ctx context.Context, db DBTX) (int64, error) {
+	result, err := db.Exec(ctx, deleteExpiredRateLimitBans)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteRateLimitBan = `-- name: DeleteRateLimitBan :exec
+delete from rate_limit_bans where id=$1
+`
+
+// DeleteRateLimitBan
+//
+//	delete from rate_limit_bans where id=$1
+func (q *Queries) DeleteRateLimitBan(ctx context.Context, db DBTX, id int32) error {
+	_, err := db.Exec(ctx, deleteRateLimitBan, id)
+	return err
+}
+
+const deleteRateLimitHits = `-- name: DeleteRateLimitHits :exec
+delete from rate_limit_hits where scope=$1 and key=$2
+`
+
+type DeleteRateLimitHitsParams struct {
+	Scope string
+	Key   string
+}
+
+// DeleteRateLimitHits
+//
+//	delete from rate_limit_hits where scope=$1 and key=$2
+func (q *Queries) DeleteRateLimitHits(ctx context.Context, db DBTX, arg DeleteRateLimitHitsParams) error {
+	_, err := db.Exec(ctx, deleteRateLimitHits, arg.Scope, arg.Key)
+	return err
+}
+
+const deleteRateLimitHitsBefore = `-- name: DeleteRateLimitHitsBefore :exec
+delete from rate_limit_hits
+where scope=$1 and key=$2 and created_at <= $3::timestamptz
+`
+
+type DeleteRateLimitHitsBeforeParams struct {
+	Scope  string
+	Key    string
+	Before pgtype.Timestamptz
+}
+
+// DeleteRateLimitHitsBefore
+//
+//	delete from rate_limit_hits
+//	where scope=$1 and key=$2 and created_at <= $3::timestamptz
+func (q *Queries) DeleteRateLimitHitsBefore(ctx context.Context, db DBTX, arg DeleteRateLimitHitsBeforeParams) error {
+	_, err := db.Exec(ctx, deleteRateLimitHitsBefore, arg.Scope, arg.Key, arg.Before)
+	return err
+}
+
+const insertRateLimitHit = `-- name: InsertRateLimitHit :exec
+insert into
+    rate_limit_hits (created_at, scope, key)
+values
+    (now(), $1, $2)
+`
+
+type InsertRateLimitHitParams struct {
+	Scope string
+	Key   string
+}
+
+// InsertRateLimitHit
+//
+//	insert into
+//	    rate_limit_hits (created_at, scope, key)
+//	values
+//	    (now(), $1, $2)
+func (q *Queries) InsertRateLimitHit(ctx context.Context, db DBTX, arg InsertRateLimitHitParams) error {
+	_, err := db.Exec(ctx, insertRateLimitHit, arg.Scope, arg.Key)
+	return err
+}
+
+const queryActiveRateLimitBan = `-- name: QueryActiveRateLimitBan :one
+select id, created_at, updated_at, scope, key, reason, expires_at from rate_limit_bans
+where scope=$1 and key=$2 and expires_at > now()
+`
+
+type QueryActiveRateLimitBanParams struct {
+	Scope string
+	Key   string
+}
+
+// QueryActiveRateLimitBan
+//
+//	select id, created_at, updated_at, scope, key, reason, expires_at from rate_limit_bans
+//	where scope=$1 and key=$2 and expires_at > now()
+func (q *Queries) QueryActiveRateLimitBan(ctx context.Context, db DBTX, arg QueryActiveRateLimitBanParams) (RateLimitBan, error) {
+	row := db.QueryRow(ctx, queryActiveRateLimitBan, arg.Scope, arg.Key)
+	var i RateLimitBan
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Scope,
+		&i.Key,
+		&i.Reason,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const queryPaginatedActiveRateLimitBans = `-- name: QueryPaginatedActiveRateLimitBans :many
+select id, created_at, updated_at, scope, key, reason, expires_at from rate_limit_bans
+where expires_at > now()
+order by expires_at desc
+limit $2::bigint offset $1::bigint
+`
+
+type QueryPaginatedActiveRateLimitBansParams struct {
+	Offset int64
+	Limit  int64
+}
+
+// QueryPaginatedActiveRateLimitBans
+//
+//	select id, created_at, updated_at, scope, key, reason, expires_at from rate_limit_bans
+//	where expires_at > now()
+//	order by expires_at desc
+//	limit $2::bigint offset $1::bigint
+func (q *Queries) QueryPaginatedActiveRateLimitBans(ctx context.Context, db DBTX, arg QueryPaginatedActiveRateLimitBansParams) ([]RateLimitBan, error) {
+	rows, err := db.Query(ctx, queryPaginatedActiveRateLimitBans, arg.Offset, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RateLimitBan
+	for rows.Next() {
+		var i RateLimitBan
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Scope,
+			&i.Key,
+			&i.Reason,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const queryRateLimitBanByID = `-- name: QueryRateLimitBanByID :one
+select id, created_at, updated_at, scope, key, reason, expires_at from rate_limit_bans where id=$1
+`
+
+// QueryRateLimitBanByID
+//
+//	select id, created_at, updated_at, scope, key, reason, expires_at from rate_limit_bans where id=$1
+func (q *Queries) QueryRateLimitBanByID(ctx context.Context, db DBTX, id int32) (RateLimitBan, error) {
+	row := db.QueryRow(ctx, queryRateLimitBanByID, id)
+	var i RateLimitBan
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Scope,
+		&i.Key,
+		&i.Reason,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const upsertRateLimitBan = `-- name: UpsertRateLimitBan :one
+insert into
+    rate_limit_bans (created_at, updated_at, scope, key, reason, expires_at)
+values
+    (now(), now(), $1, $2, $3, $4)
+on conflict (scope, key) do update set
+    created_at=case
+        when rate_limit_bans.expires_at > now() then rate_limit_bans.created_at
+        else now()
+    end,
+    updated_at=now(),
+    reason=excluded.reason,
+    expires_at=excluded.expires_at
+returning id, created_at, updated_at, scope, key, reason, expires_at
+`
+
+type UpsertRateLimitBanParams struct {
+	Scope     string
+	Key       string
+	Reason    string
+	ExpiresAt pgtype.Timestamptz
+}
+
+// UpsertRateLimitBan
+//
+//	insert into
+//	    rate_limit_bans (created_at, updated_at, scope, key, reason, expires_at)
+//	values
+//	    (now(), now(), $1, $2, $3, $4)
+//	on conflict (scope, key) do update set
+//	    created_at=case
+//	        when rate_limit_bans.expires_at > now() then rate_limit_bans.created_at
+//	        else now()
+//	    end,
+//	    updated_at=now(),
+//	    reason=excluded.reason,
+//	    expires_at=excluded.expires_at
+//	returning id, created_at, updated_at, scope, key, reason, expires_at
+func (q *Queries) UpsertRateLimitBan(ctx context.Context, db DBTX, arg UpsertRateLimitBanParams) (RateLimitBan, error) {
+	row := db.QueryRow(ctx, upsertRateLimitBan,
+		arg.Scope,
+		arg.Key,
+		arg.Reason,
+		arg.ExpiresAt,
+	)
+	var i RateLimitBan
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Scope,
+		&i.Key,
+		&i.Reason,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
@@ -0,0 +1,119 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: related_articles.sql
+
+package db
+
+import (
+	"context"
+)
+
+const deleteAllRelatedArticles = `-- name: DeleteAllRelatedArticles :exec
+delete from related_articles
+`
+
+// DeleteAllRelatedArticles
+//
+//	delete from related_articles
+func (q *Queries) DeleteAllRelatedArticles(ctx context.Context, db DBTX) error {
+	_, err := db.Exec(ctx, deleteAllRelatedArticles)
+	return err
+}
+
+const insertRelatedArticle = `-- name: InsertRelatedArticle :exec
+insert into
+    related_articles (article_id, related_article_id, score, computed_at)
+values
+    ($1, $2, $3, now())
+`
+
+type InsertRelatedArticleParams struct {
+	ArticleID        int32
+	RelatedArticleID int32
+	Score            float64
+}
+
+// InsertRelatedArticle
+//
+//	insert into
+//	    related_articles (article_id, related_article_id, score, computed_at)
+//	values
+//	    ($1, $2, $3, now())
+func (q *Queries) InsertRelatedArticle(ctx context.Context, db DBTX, arg InsertRelatedArticleParams) error {
+	_, err := db.Exec(ctx, insertRelatedArticle, arg.ArticleID, arg.RelatedArticleID, arg.Score)
+	return err
+}
+
+const queryRelatedArticles = `-- name: QueryRelatedArticles :many
+select articles.id, articles.created_at, articles.updated_at, articles.first_published_at, articles.published, articles.title, articles.excerpt, articles.meta_title, articles.meta_description, articles.slug, articles.image_link, articles.read_time, articles.content, articles.author_id, articles.comments_open, articles.table_of_contents, articles.content_html, articles.content_hash, articles.content_headings, articles.word_count, articles.image_count, articles.heading_count, articles.estimated_read_time, articles.read_time_override, related_articles.score from related_articles
+join articles on articles.id = related_articles.related_article_id
+where related_articles.article_id = $1
+and articles.published = true
+order by related_articles.score desc, articles.first_published_at desc
+limit $2::bigint
+`
+
+type QueryRelatedArticlesParams struct {
+	ArticleID int32
+	Limit     int64
+}
+
+type QueryRelatedArticlesRow struct {
+	Article Article
+	Score   float64
+}
+
+// QueryRelatedArticles
+//
+//	select articles.id, articles.created_at, articles.updated_at, articles.first_published_at, articles.published, articles.title, articles.excerpt, articles.meta_title, articles.meta_description, articles.slug, articles.image_link, articles.read_time, articles.content, articles.author_id, articles.comments_open, articles.table_of_contents, articles.content_html, articles.content_hash, articles.content_headings, articles.word_count, articles.image_count, articles.heading_count, articles.estimated_read_time, articles.read_time_override, related_articles.score from related_articles
+//	join articles on articles.id = related_articles.related_article_id
+//	where related_articles.article_id = $1
+//	and articles.published = true
+//	order by related_articles.score desc, articles.first_published_at desc
+//	limit $2::bigint
+func (q *Queries) QueryRelatedArticles(ctx context.Context, db DBTX, arg QueryRelatedArticlesParams) ([]QueryRelatedArticlesRow, error) {
+	rows, err := db.Query(ctx, queryRelatedArticles, arg.ArticleID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []QueryRelatedArticlesRow
+	for rows.Next() {
+		var i QueryRelatedArticlesRow
+		if err := rows.Scan(
+			&i.Article.ID,
+			&i.Article.CreatedAt,
+			&i.Article.UpdatedAt,
+			&i.Article.FirstPublishedAt,
+			&i.Article.Published,
+			&i.Article.Title,
+			&i.Article.Excerpt,
+			&i.Article.MetaTitle,
+			&i.Article.MetaDescription,
+			&i.Article.Slug,
+			&i.Article.ImageLink,
+			&i.Article.ReadTime,
+			&i.Article.Content,
+			&i.Article.AuthorID,
+			&i.Article.CommentsOpen,
+			&i.Article.TableOfContents,
+			&i.Article.ContentHtml,
+			&i.Article.ContentHash,
+			&i.Article.ContentHeadings,
+			&i.Article.WordCount,
+			&i.Article.ImageCount,
+			&i.Article.HeadingCount,
+			&i.Article.EstimatedReadTime,
+			&i.Article.ReadTimeOverride,
+			&i.Score,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,376 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: series.sql
+
+package db
+
+import (
+	"context"
+)
+
+const deleteSeries = `-- name: DeleteSeries :exec
+delete from series where id=$1
+`
+
+// DeleteSeries
+//
+//	delete from series where id=$1
+func (q *Queries) DeleteSeries(ctx context.Context, db DBTX, id int32) error {
+	_, err := db.Exec(ctx, deleteSeries, id)
+	return err
+}
+
+const deleteSeriesArticle = `-- name: DeleteSeriesArticle :exec
+delete from series_articles where series_id=$1 and article_id=$2
+`
+
+type DeleteSeriesArticleParams struct {
+	SeriesID  int32
+	ArticleID int32
+}
+
+// DeleteSeriesArticle
+//
+//	delete from series_articles where series_id=$1 and article_id=$2
+func (q *Queries) DeleteSeriesArticle(ctx context.Context, db DBTX, arg DeleteSeriesArticleParams) error {
+	_, err := db.Exec(ctx, deleteSeriesArticle, arg.SeriesID, arg.ArticleID)
+	return err
+}
+
+const insertSeries = `-- name: InsertSeries :one
+insert into
+    series (created_at, updated_at, title, slug, description, published)
+values
+    (now(), now(), $1, $2, $3, $4)
+returning id, created_at, updated_at, title, slug, description, published
+`
+
+type InsertSeriesParams struct {
+	Title       string
+	Slug        string
+	Description string
+	Published   bool
+}
+
+// InsertSeries
+//
+//	insert into
+//	    series (created_at, updated_at, title, slug, description, published)
+//	values
+//	    (now(), now(), $1, $2, $3, $4)
+//	returning id, created_at, updated_at, title, slug, description, published
+func (q *Queries) InsertSeries(ctx context.Context, db DBTX, arg InsertSeriesParams) (Series, error) {
+	row := db.QueryRow(ctx, insertSeries,
+		arg.Title,
+		arg.Slug,
+		arg.Description,
+		arg.Published,
+	)
+	var i Series
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Title,
+		&i.Slug,
+		&i.Description,
+		&i.Published,
+	)
+	return i, err
+}
+
+const insertSeriesArticle = `-- name: InsertSeriesArticle :exec
+insert into
+    series_articles (series_id, article_id, position)
+values
+    (
+        $1,
+        $2,
+        (select coalesce(max(position), 0) + 1 from series_articles where series_id = $1)
+    )
+`
+
+type InsertSeriesArticleParams struct {
+	SeriesID  int32
+	ArticleID int32
+}
+
+// InsertSeriesArticle
+//
+//	insert into
+//	    series_articles (series_id, article_id, position)
+//	values
+//	    (
+//	        $1,
+//	        $2,
+//	        (select coalesce(max(position), 0) + 1 from series_articles where series_id = $1)
+//	    )
+func (q *Queries) InsertSeriesArticle(ctx context.Context, db DBTX, arg InsertSeriesArticleParams) error {
+	_, err := db.Exec(ctx, insertSeriesArticle, arg.SeriesID, arg.ArticleID)
+	return err
+}
+
+const queryPublishedSeries = `-- name: QueryPublishedSeries :many
+select id, created_at, updated_at, title, slug, description, published from series where published=true order by title
+`
+
+// QueryPublishedSeries
+//
+//	select id, created_at, updated_at, title, slug, description, published from series where published=true order by title
+func (q *Queries) QueryPublishedSeries(ctx context.Context, db DBTX) ([]Series, error) {
+	rows, err := db.Query(ctx, queryPublishedSeries)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Series
+	for rows.Next() {
+		var i Series
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Slug,
+			&i.Description,
+			&i.Published,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const querySeries = `-- name: QuerySeries :many
+select id, created_at, updated_at, title, slug, description, published from series order by created_at desc
+`
+
+// QuerySeries
+//
+//	select id, created_at, updated_at, title, slug, description, published from series order by created_at desc
+func (q *Queries) QuerySeries(ctx context.Context, db DBTX) ([]Series, error) {
+	rows, err := db.Query(ctx, querySeries)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Series
+	for rows.Next() {
+		var i Series
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Slug,
+			&i.Description,
+			&i.Published,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const querySeriesArticles = `-- name: QuerySeriesArticles :many
+select series_articles.position, articles.id, articles.created_at, articles.updated_at, articles.first_published_at, articles.published, articles.title, articles.excerpt, articles.meta_title, articles.meta_description, articles.slug, articles.image_link, articles.read_time, articles.content, articles.author_id, articles.comments_open, articles.table_of_contents, articles.content_html, articles.content_hash, articles.content_headings, articles.word_count, articles.image_count, articles.heading_count, articles.estimated_read_time, articles.read_time_override from series_articles
+join articles on articles.id = series_articles.article_id
+where series_articles.series_id=$1
+order by series_articles.position, articles.id
+`
+
+type QuerySeriesArticlesRow struct {
+	Position int32
+	Article  Article
+}
+
+// QuerySeriesArticles
+//
+//	select series_articles.position, articles.id, articles.created_at, articles.updated_at, articles.first_published_at, articles.published, articles.title, articles.excerpt, articles.meta_title, articles.meta_description, articles.slug, articles.image_link, articles.read_time, articles.content, articles.author_id, articles.comments_open, articles.table_of_contents, articles.content_html, articles.content_hash, articles.content_headings, articles.word_count, articles.image_count, articles.heading_count, articles.estimated_read_time, articles.read_time_override from series_articles
+//	join articles on articles.id = series_articles.article_id
+//	where series_articles.series_id=$1
+//	order by series_articles.position, articles.id
+func (q *Queries) QuerySeriesArticles(ctx context.Context, db DBTX, seriesID int32) ([]QuerySeriesArticlesRow, error) {
+	rows, err := db.Query(ctx, querySeriesArticles, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []QuerySeriesArticlesRow
+	for rows.Next() {
+		var i QuerySeriesArticlesRow
+		if err := rows.Scan(
+			&i.Position,
+			&i.Article.ID,
+			&i.Article.CreatedAt,
+			&i.Article.UpdatedAt,
+			&i.Article.FirstPublishedAt,
+			&i.Article.Published,
+			&i.Article.Title,
+			&i.Article.Excerpt,
+			&i.Article.MetaTitle,
+			&i.Article.MetaDescription,
+			&i.Article.Slug,
+			&i.Article.ImageLink,
+			&i.Article.ReadTime,
+			&i.Article.Content,
+			&i.Article.AuthorID,
+			&i.Article.CommentsOpen,
+			&i.Article.TableOfContents,
+			&i.Article.ContentHtml,
+			&i.Article.ContentHash,
+			&i.Article.ContentHeadings,
+			&i.Article.WordCount,
+			&i.Article.ImageCount,
+			&i.Article.HeadingCount,
+			&i.Article.EstimatedReadTime,
+			&i.Article.ReadTimeOverride,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const querySeriesByID = `-- name: QuerySeriesByID :one
+select id, created_at, updated_at, title, slug, description, published from series where id=$1
+`
+
+// QuerySeriesByID
+//
+//	select id, created_at, updated_at, title, slug, description, published from series where id=$1
+func (q *Queries) QuerySeriesByID(ctx context.Context, db DBTX, id int32) (Series, error) {
+	row := db.QueryRow(ctx, querySeriesByID, id)
+	var i Series
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Title,
+		&i.Slug,
+		&i.Description,
+		&i.Published,
+	)
+	return i, err
+}
+
+const querySeriesBySlug = `-- name: QuerySeriesBySlug :one
+select id, created_at, updated_at, title, slug, description, published from series where slug=$1
+`
+
+// QuerySeriesBySlug
+//
+//	select id, created_at, updated_at, title, slug, description, published from series where slug=$1
+func (q *Queries) QuerySeriesBySlug(ctx context.Context, db DBTX, slug string) (Series, error) {
+	row := db.QueryRow(ctx, querySeriesBySlug, slug)
+	var i Series
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Title,
+		&i.Slug,
+		&i.Description,
+		&i.Published,
+	)
+	return i, err
+}
+
+const querySeriesForArticle = `-- name: QuerySeriesForArticle :one
+select series.id, series.created_at, series.updated_at, series.title, series.slug, series.description, series.published from series
+join series_articles on series_articles.series_id = series.id
+where series_articles.article_id=$1
+`
+
+// QuerySeriesForArticle
+//
+//	select series.id, series.created_at, series.updated_at, series.title, series.slug, series.description, series.published from series
+//	join series_articles on series_articles.series_id = series.id
+//	where series_articles.article_id=$1
+func (q *Queries) QuerySeriesForArticle(ctx context.Context, db DBTX, articleID int32) (Series, error) {
+	row := db.QueryRow(ctx, querySeriesForArticle, articleID)
+	var i Series
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Title,
+		&i.Slug,
+		&i.Description,
+		&i.Published,
+	)
+	return i, err
+}
+
+const updateSeries = `-- name: UpdateSeries :one
+update series
+    set updated_at=now(), title=$2, slug=$3, description=$4, published=$5
+where id = $1
+returning id, created_at, updated_at, title, slug, description, published
+`
+
+type UpdateSeriesParams struct {
+	ID          int32
+	Title       string
+	Slug        string
+	Description string
+	Published   bool
+}
+
+// UpdateSeries
+//
+//	update series
+//	    set updated_at=now(), title=$2, slug=$3, description=$4, published=$5
+//	where id = $1
+//	returning id, created_at, updated_at, title, slug, description, published
+func (q *Queries) UpdateSeries(ctx context.Context, db DBTX, arg UpdateSeriesParams) (Series, error) {
+	row := db.QueryRow(ctx, updateSeries,
+		arg.ID,
+		arg.Title,
+		arg.Slug,
+		arg.Description,
+		arg.Published,
+	)
+	var i Series
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Title,
+		&i.Slug,
+		&i.Description,
+		&i.Published,
+	)
+	return i, err
+}
+
+const updateSeriesArticlePosition = `-- name: UpdateSeriesArticlePosition :exec
+update series_articles set position=$3 where series_id=$1 and article_id=$2
+`
+
+type UpdateSeriesArticlePositionParams struct {
+	SeriesID  int32
+	ArticleID int32
+	Position  int32
+}
+
+// UpdateSeriesArticlePosition
+//
+//	update series_articles set position=$3 where series_id=$1 and article_id=$2
+func (q *Queries) UpdateSeriesArticlePosition(ctx context.Context, db DBTX, arg UpdateSeriesArticlePositionParams) error {
+	_, err := db.Exec(ctx, updateSeriesArticlePosition, arg.SeriesID, arg.ArticleID, arg.Position)
+	return err
+}
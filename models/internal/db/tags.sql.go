@@ -7,7 +7,47 @@ package db
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const countPublishedArticlesForTag = `-- name: CountPublishedArticlesForTag :one
+with recursive tree (tag_id) as (
+    select $1::integer
+    union
+    select tags.id from tags
+    join tree on tags.parent_id = tree.tag_id
 )
+select count(*) from articles
+where articles.published = true
+and exists (
+    select 1 from article_tag_connections
+    join tree on tree.tag_id = article_tag_connections.tag_id
+    where article_tag_connections.article_id = articles.id
+)
+`
+
+// CountPublishedArticlesForTag
+//
+//	with recursive tree (tag_id) as (
+//	    select $1::integer
+//	    union
+//	    select tags.id from tags
+//	    join tree on tags.parent_id = tree.tag_id
+//	)
+//	select count(*) from articles
+//	where articles.published = true
+//	and exists (
+//	    select 1 from article_tag_connections
+//	    join tree on tree.tag_id = article_tag_connections.tag_id
+//	    where article_tag_connections.article_id = articles.id
+//	)
+func (q *Queries) CountPublishedArticlesForTag(ctx context.Context, db DBTX, tagID int32) (int64, error) {
+	row := db.QueryRow(ctx, countPublishedArticlesForTag, tagID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
 
 const countTags = `-- name: CountTags :one
 select count(*) from tags
@@ -23,6 +63,18 @@ func (q *Queries) CountTags(ctx context.Context, db DBTX) (int64, error) {
 	return count, err
 }
 
+const deleteArticleTagConnectionsForTag = `-- name: DeleteArticleTagConnectionsForTag :exec
+delete from article_tag_connections where tag_id=$1
+`
+
+// DeleteArticleTagConnectionsForTag
+//
+//	delete from article_tag_connections where tag_id=$1
+func (q *Queries) DeleteArticleTagConnectionsForTag(ctx context.Context, db DBTX, tagID int32) error {
+	_, err := db.Exec(ctx, deleteArticleTagConnectionsForTag, tagID)
+	return err
+}
+
 const deleteTag = `-- name: DeleteTag :exec
 delete from tags where id=$1
 `
@@ -37,33 +89,200 @@ func (q *Queries) DeleteTag(ctx context.Context, db DBTX, id int32) error {
 
 const insertTag = `-- name: InsertTag :one
 insert into
-    tags (created_at, updated_at, title)
+    tags (created_at, updated_at, title, slug, description, meta_title, meta_description, parent_id)
 values
-    (now(), now(), $1)
-returning id, created_at, updated_at, title
+    (now(), now(), $1, $2, $3, $4, $5, $6)
+returning id, created_at, updated_at, title, slug, description, meta_title, meta_description, parent_id
 `
 
+type InsertTagParams struct {
+	Title           string
+	Slug            string
+	Description     string
+	MetaTitle       string
+	MetaDescription string
+	ParentID        pgtype.Int4
+}
+
 // InsertTag
 //
 //	insert into
-//	    tags (created_at, updated_at, title)
+//	    tags (created_at, updated_at, title, slug, description, meta_title, meta_description, parent_id)
 //	values
-//	    (now(), now(), $1)
-//	returning id, created_at, updated_at, title
-func (q *Queries) InsertTag(ctx context.Context, db DBTX, title string) (Tag, error) {
-	row := db.QueryRow(ctx, insertTag, title)
+//	    (now(), now(), $1, $2, $3, $4, $5, $6)
+//	returning id, created_at, updated_at, title, slug, description, meta_title, meta_description, parent_id
+func (q *Queries) InsertTag(ctx context.Context, db DBTX, arg InsertTagParams) (Tag, error) {
+	row := db.QueryRow(ctx, insertTag,
+		arg.Title,
+		arg.Slug,
+		arg.Description,
+		arg.MetaTitle,
+		arg.MetaDescription,
+		arg.ParentID,
+	)
 	var i Tag
 	err := row.Scan(
 		&i.ID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Title,
+		&i.Slug,
+		&i.Description,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.ParentID,
 	)
 	return i, err
 }
 
+const moveArticleTagConnections = `-- name: MoveArticleTagConnections :exec
+insert into article_tag_connections (article_id, tag_id)
+select article_id, $1::integer from article_tag_connections
+where tag_id = $2::integer
+on conflict (article_id, tag_id) do nothing
+`
+
+type MoveArticleTagConnectionsParams struct {
+	TargetID int32
+	SourceID int32
+}
+
+// MoveArticleTagConnections
+//
+//	insert into article_tag_connections (article_id, tag_id)
+//	select article_id, $1::integer from article_tag_connections
+//	where tag_id = $2::integer
+//	on conflict (article_id, tag_id) do nothing
+func (q *Queries) MoveArticleTagConnections(ctx context.Context, db DBTX, arg MoveArticleTagConnectionsParams) error {
+	_, err := db.Exec(ctx, moveArticleTagConnections, arg.TargetID, arg.SourceID)
+	return err
+}
+
+const queryChildTags = `-- name: QueryChildTags :many
+select id, created_at, updated_at, title, slug, description, meta_title, meta_description, parent_id from tags where parent_id=$1 order by title
+`
+
+// QueryChildTags
+//
+//	select id, created_at, updated_at, title, slug, description, meta_title, meta_description, parent_id from tags where parent_id=$1 order by title
+func (q *Queries) QueryChildTags(ctx context.Context, db DBTX, parentID pgtype.Int4) ([]Tag, error) {
+	rows, err := db.Query(ctx, queryChildTags, parentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Tag
+	for rows.Next() {
+		var i Tag
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Slug,
+			&i.Description,
+			&i.MetaTitle,
+			&i.MetaDescription,
+			&i.ParentID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const queryPaginatedPublishedArticlesForTag = `-- name: QueryPaginatedPublishedArticlesForTag :many
+with recursive tree (tag_id) as (
+    select $3::integer
+    union
+    select tags.id from tags
+    join tree on tags.parent_id = tree.tag_id
+)
+select articles.id, articles.created_at, articles.updated_at, articles.first_published_at, articles.published, articles.title, articles.excerpt, articles.meta_title, articles.meta_description, articles.slug, articles.image_link, articles.read_time, articles.content, articles.author_id, articles.comments_open, articles.table_of_contents, articles.content_html, articles.content_hash, articles.content_headings, articles.word_count, articles.image_count, articles.heading_count, articles.estimated_read_time, articles.read_time_override from articles
+where articles.published = true
+and exists (
+    select 1 from article_tag_connections
+    join tree on tree.tag_id = article_tag_connections.tag_id
+    where article_tag_connections.article_id = articles.id
+)
+order by articles.first_published_at desc
+limit $2::bigint offset $1::bigint
+`
+
+type QueryPaginatedPublishedArticlesForTagParams struct {
+	Offset int64
+	Limit  int64
+	TagID  int32
+}
+
+// QueryPaginatedPublishedArticlesForTag
+//
+//	with recursive tree (tag_id) as (
+//	    select $3::integer
+//	    union
+//	    select tags.id from tags
+//	    join tree on tags.parent_id = tree.tag_id
+//	)
+//	select articles.id, articles.created_at, articles.updated_at, articles.first_published_at, articles.published, articles.title, articles.excerpt, articles.meta_title, articles.meta_description, articles.slug, articles.image_link, articles.read_time, articles.content, articles.author_id, articles.comments_open, articles.table_of_contents, articles.content_html, articles.content_hash, articles.content_headings, articles.word_count, articles.image_count, articles.heading_count, articles.estimated_read_time, articles.read_time_override from articles
+//	where articles.published = true
+//	and exists (
+//	    select 1 from article_tag_connections
+//	    join tree on tree.tag_id = article_tag_connections.tag_id
+//	    where article_tag_connections.article_id = articles.id
+//	)
+//	order by articles.first_published_at desc
+//	limit $2::bigint offset $1::bigint
+func (q *Queries) QueryPaginatedPublishedArticlesForTag(ctx context.Context, db DBTX, arg QueryPaginatedPublishedArticlesForTagParams) ([]Article, error) {
+	rows, err := db.Query(ctx, queryPaginatedPublishedArticlesForTag, arg.Offset, arg.Limit, arg.TagID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Article
+	for rows.Next() {
+		var i Article
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.FirstPublishedAt,
+			&i.Published,
+			&i.Title,
+			&i.Excerpt,
+			&i.MetaTitle,
+			&i.MetaDescription,
+			&i.Slug,
+			&i.ImageLink,
+			&i.ReadTime,
+			&i.Content,
+			&i.AuthorID,
+			&i.CommentsOpen,
+			&i.TableOfContents,
+			&i.ContentHtml,
+			&i.ContentHash,
+			&i.ContentHeadings,
+			&i.WordCount,
+			&i.ImageCount,
+			&i.HeadingCount,
+			&i.EstimatedReadTime,
+			&i.ReadTimeOverride,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const queryPaginatedTags = `-- name: QueryPaginatedTags :many
-select id, created_at, updated_at, title from tags
+select id, created_at, updated_at, title, slug, description, meta_title, meta_description, parent_id from tags
 order by created_at desc
 limit $2::bigint offset $1::bigint
 `
@@ -75,7 +294,7 @@ type QueryPaginatedTagsParams struct {
 
 // QueryPaginatedTags
 //
-//	select id, created_at, updated_at, title from tags
+//	select id, created_at, updated_at, title, slug, description, meta_title, meta_description, parent_id from tags
 //	order by created_at desc
 //	limit $2::bigint offset $1::bigint
 func (q *Queries) QueryPaginatedTags(ctx context.Context, db DBTX, arg QueryPaginatedTagsParams) ([]Tag, error) {
@@ -92,6 +311,78 @@ func (q *Queries) QueryPaginatedTags(ctx context.Context, db DBTX, arg QueryPagi
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Title,
+			&i.Slug,
+			&i.Description,
+			&i.MetaTitle,
+			&i.MetaDescription,
+			&i.ParentID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const queryPublishedTags = `-- name: QueryPublishedTags :many
+with recursive tree (root_id, tag_id) as (
+    select id, id from tags
+    union
+    select tree.root_id, tags.id from tags
+    join tree on tags.parent_id = tree.tag_id
+)
+select tags.id, tags.created_at, tags.updated_at, tags.title, tags.slug, tags.description, tags.meta_title, tags.meta_description, tags.parent_id, max(articles.updated_at)::timestamptz as last_article_update from tags
+join tree on tree.root_id = tags.id
+join article_tag_connections on article_tag_connections.tag_id = tree.tag_id
+join articles on articles.id = article_tag_connections.article_id
+where articles.published = true
+group by tags.id
+order by tags.title
+`
+
+type QueryPublishedTagsRow struct {
+	Tag               Tag
+	LastArticleUpdate pgtype.Timestamptz
+}
+
+// QueryPublishedTags
+//
+//	with recursive tree (root_id, tag_id) as (
+//	    select id, id from tags
+//	    union
+//	    select tree.root_id, tags.id from tags
+//	    join tree on tags.parent_id = tree.tag_id
+//	)
+//	select tags.id, tags.created_at, tags.updated_at, tags.title, tags.slug, tags.description, tags.meta_title, tags.meta_description, tags.parent_id, max(articles.updated_at)::timestamptz as last_article_update from tags
+//	join tree on tree.root_id = tags.id
+//	join article_tag_connections on article_tag_connections.tag_id = tree.tag_id
+//	join articles on articles.id = article_tag_connections.article_id
+//	where articles.published = true
+//	group by tags.id
+//	order by tags.title
+func (q *Queries) QueryPublishedTags(ctx context.Context, db DBTX) ([]QueryPublishedTagsRow, error) {
+	rows, err := db.Query(ctx, queryPublishedTags)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []QueryPublishedTagsRow
+	for rows.Next() {
+		var i QueryPublishedTagsRow
+		if err := rows.Scan(
+			&i.Tag.ID,
+			&i.Tag.CreatedAt,
+			&i.Tag.UpdatedAt,
+			&i.Tag.Title,
+			&i.Tag.Slug,
+			&i.Tag.Description,
+			&i.Tag.MetaTitle,
+			&i.Tag.MetaDescription,
+			&i.Tag.ParentID,
+			&i.LastArticleUpdate,
 		); err != nil {
 			return nil, err
 		}
@@ -103,13 +394,52 @@ func (q *Queries) QueryPaginatedTags(ctx context.Context, db DBTX, arg QueryPagi
 	return items, nil
 }
 
+const queryTagAncestorIDs = `-- name: QueryTagAncestorIDs :many
+with recursive ancestors (id, parent_id) as (
+    select tags.id, tags.parent_id from tags where tags.id = $1
+    union
+    select tags.id, tags.parent_id from tags
+    join ancestors on tags.id = ancestors.parent_id
+)
+select ancestors.id::integer from ancestors
+`
+
+// QueryTagAncestorIDs
+//
+//	with recursive ancestors (id, parent_id) as (
+//	    select tags.id, tags.parent_id from tags where tags.id = $1
+//	    union
+//	    select tags.id, tags.parent_id from tags
+//	    join ancestors on tags.id = ancestors.parent_id
+//	)
+//	select ancestors.id::integer from ancestors
+func (q *Queries) QueryTagAncestorIDs(ctx context.Context, db DBTX, id int32) ([]int32, error) {
+	rows, err := db.Query(ctx, queryTagAncestorIDs, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var ancestors_id int32
+		if err := rows.Scan(&ancestors_id); err != nil {
+			return nil, err
+		}
+		items = append(items, ancestors_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const queryTagByID = `-- name: QueryTagByID :one
-select id, created_at, updated_at, title from tags where id=$1
+select id, created_at, updated_at, title, slug, description, meta_title, meta_description, parent_id from tags where id=$1
 `
 
 // QueryTagByID
 //
-//	select id, created_at, updated_at, title from tags where id=$1
+//	select id, created_at, updated_at, title, slug, description, meta_title, meta_description, parent_id from tags where id=$1
 func (q *Queries) QueryTagByID(ctx context.Context, db DBTX, id int32) (Tag, error) {
 	row := db.QueryRow(ctx, queryTagByID, id)
 	var i Tag
@@ -118,17 +448,46 @@ func (q *Queries) QueryTagByID(ctx context.Context, db DBTX, id int32) (Tag, err
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Title,
+		&i.Slug,
+		&i.Description,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.ParentID,
+	)
+	return i, err
+}
+
+const queryTagBySlug = `-- name: QueryTagBySlug :one
+select id, created_at, updated_at, title, slug, description, meta_title, meta_description, parent_id from tags where slug=$1
+`
+
+// QueryTagBySlug
+//
+//	select id, created_at, updated_at, title, slug, description, meta_title, meta_description, parent_id from tags where slug=$1
+func (q *Queries) QueryTagBySlug(ctx context.Context, db DBTX, slug string) (Tag, error) {
+	row := db.QueryRow(ctx, queryTagBySlug, slug)
+	var i Tag
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Title,
+		&i.Slug,
+		&i.Description,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.ParentID,
 	)
 	return i, err
 }
 
 const queryTags = `-- name: QueryTags :many
-select id, created_at, updated_at, title from tags
+select id, created_at, updated_at, title, slug, description, meta_title, meta_description, parent_id from tags
 `
 
 // QueryTags
 //
-//	select id, created_at, updated_at, title from tags
+//	select id, created_at, updated_at, title, slug, description, meta_title, meta_description, parent_id from tags
 func (q *Queries) QueryTags(ctx context.Context, db DBTX) ([]Tag, error) {
 	rows, err := db.Query(ctx, queryTags)
 	if err != nil {
@@ -143,6 +502,11 @@ func (q *Queries) QueryTags(ctx context.Context, db DBTX) ([]Tag, error) {
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Title,
+			&i.Slug,
+			&i.Description,
+			&i.MetaTitle,
+			&i.MetaDescription,
+			&i.ParentID,
 		); err != nil {
 			return nil, err
 		}
@@ -154,61 +518,164 @@ func (q *Queries) QueryTags(ctx context.Context, db DBTX) ([]Tag, error) {
 	return items, nil
 }
 
+const queryTagsForArticle = `-- name: QueryTagsForArticle :many
+select distinct tags.id, tags.created_at, tags.updated_at, tags.title, tags.slug, tags.description, tags.meta_title, tags.meta_description, tags.parent_id from tags
+join article_tag_connections on article_tag_connections.tag_id = tags.id
+where article_tag_connections.article_id = $1
+order by tags.title
+`
+
+// QueryTagsForArticle
+//
+//	select distinct tags.id, tags.created_at, tags.updated_at, tags.title, tags.slug, tags.description, tags.meta_title, tags.meta_description, tags.parent_id from tags
+//	join article_tag_connections on article_tag_connections.tag_id = tags.id
+//	where article_tag_connections.article_id = $1
+//	order by tags.title
+func (q *Queries) QueryTagsForArticle(ctx context.Context, db DBTX, articleID int32) ([]Tag, error) {
+	rows, err := db.Query(ctx, queryTagsForArticle, articleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Tag
+	for rows.Next() {
+		var i Tag
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Slug,
+			&i.Description,
+			&i.MetaTitle,
+			&i.MetaDescription,
+			&i.ParentID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const reparentTags = `-- name: ReparentTags :exec
+update tags
+    set updated_at=now(), parent_id=$1
+where parent_id = $2::integer
+and id <> coalesce($1, 0)
+`
+
+type ReparentTagsParams struct {
+	NewParentID pgtype.Int4
+	OldParentID int32
+}
+
+// ReparentTags
+//
+//	update tags
+//	    set updated_at=now(), parent_id=$1
+//	where parent_id = $2::integer
+//	and id <> coalesce($1, 0)
+func (q *Queries) ReparentTags(ctx context.Context, db DBTX, arg ReparentTagsParams) error {
+	_, err := db.Exec(ctx, reparentTags, arg.NewParentID, arg.OldParentID)
+	return err
+}
+
 const updateTag = `-- name: UpdateTag :one
 update tags
-    set updated_at=now(), title=$2
+    set updated_at=now(), title=$2, slug=$3, description=$4, meta_title=$5, meta_description=$6, parent_id=$7
 where id = $1
-returning id, created_at, updated_at, title
+returning id, created_at, updated_at, title, slug, description, meta_title, meta_description, parent_id
 `
 
 type UpdateTagParams struct {
-	ID    int32
-	Title string
+	ID              int32
+	Title           string
+	Slug            string
+	Description     string
+	MetaTitle       string
+	MetaDescription string
+	ParentID        pgtype.Int4
 }
 
 // UpdateTag
 //
 //	update tags
-//	    set updated_at=now(), title=$2
+//	    set updated_at=now(), title=$2, slug=$3, description=$4, meta_title=$5, meta_description=$6, parent_id=$7
 //	where id = $1
-//	returning id, created_at, updated_at, title
+//	returning id, created_at, updated_at, title, slug, description, meta_title, meta_description, parent_id
 func (q *Queries) UpdateTag(ctx context.Context, db DBTX, arg UpdateTagParams) (Tag, error) {
-	row := db.QueryRow(ctx, updateTag, arg.ID, arg.Title)
+	row := db.QueryRow(ctx, updateTag,
+		arg.ID,
+		arg.Title,
+		arg.Slug,
+		arg.Description,
+		arg.MetaTitle,
+		arg.MetaDescription,
+		arg.ParentID,
+	)
 	var i Tag
 	err := row.Scan(
 		&i.ID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Title,
+		&i.Slug,
+		&i.Description,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.ParentID,
 	)
 	return i, err
 }
 
 const upsertTag = `-- name: UpsertTag :one
 insert into
-    tags (created_at, updated_at, title)
+    tags (created_at, updated_at, title, slug, description, meta_title, meta_description)
 values
-    (now(), now(), $1)
+    (now(), now(), $1, $2, $3, $4, $5)
 on conflict (id) do update set updated_at=now(), title=excluded.title
-returning id, created_at, updated_at, title
+returning id, created_at, updated_at, title, slug, description, meta_title, meta_description, parent_id
 `
 
+type UpsertTagParams struct {
+	Title           string
+	Slug            string
+	Description     string
+	MetaTitle       string
+	MetaDescription string
+}
+
 // UpsertTag
 //
 //	insert into
-//	    tags (created_at, updated_at, title)
+//	    tags (created_at, updated_at, title, slug, description, meta_title, meta_description)
 //	values
-//	    (now(), now(), $1)
+//	    (now(), now(), $1, $2, $3, $4, $5)
 //	on conflict (id) do update set updated_at=now(), title=excluded.title
-//	returning id, created_at, updated_at, title
-func (q *Queries) UpsertTag(ctx context.Context, db DBTX, title string) (Tag, error) {
-	row := db.QueryRow(ctx, upsertTag, title)
+//	returning id, created_at, updated_at, title, slug, description, meta_title, meta_description, parent_id
+func (q *Queries) UpsertTag(ctx context.Context, db DBTX, arg UpsertTagParams) (Tag, error) {
+	row := db.QueryRow(ctx, upsertTag,
+		arg.Title,
+		arg.Slug,
+		arg.Description,
+		arg.MetaTitle,
+		arg.MetaDescription,
+	)
 	var i Tag
 	err := row.Scan(
 		&i.ID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Title,
+		&i.Slug,
+		&i.Description,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.ParentID,
 	)
 	return i, err
 }
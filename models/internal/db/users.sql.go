@@ -40,10 +40,10 @@ func (q *Queries) DeleteUser(ctx context.Context, db DBTX, id uuid.UUID) error {
 
 const insertUser = `-- name: InsertUser :one
 insert into
-    users (id, created_at, updated_at, email, email_validated_at, password, is_admin)
+    users (id, created_at, updated_at, email, email_validated_at, password, is_admin, role)
 values
-    ($1, now(), now(), $2, $3, $4, $5)
-returning id, created_at, updated_at, email, email_validated_at, password, is_admin
+    ($1, now(), now(), $2, $3, $4, $5, $6)
+returning id, created_at, updated_at, email, email_validated_at, password, is_admin, deactivated_at, role
 `
 
 type InsertUserParams struct {
@@ -52,15 +52,16 @@ type InsertUserParams struct {
 	EmailValidatedAt pgtype.Timestamptz
 	Password         []byte
 	IsAdmin          bool
+	Role             string
 }
 
 // InsertUser
 //
 //	insert into
-//	    users (id, created_at, updated_at, email, email_validated_at, password, is_admin)
+//	    users (id, created_at, updated_at, email, email_validated_at, password, is_admin, role)
 //	values
-//	    ($1, now(), now(), $2, $3, $4, $5)
-//	returning id, created_at, updated_at, email, email_validated_at, password, is_admin
+//	    ($1, now(), now(), $2, $3, $4, $5, $6)
+//	returning id, created_at, updated_at, email, email_validated_at, password, is_admin, deactivated_at, role
 func (q *Queries) InsertUser(ctx context.Context, db DBTX, arg InsertUserParams) (User, error) {
 	row := db.QueryRow(ctx, insertUser,
 		arg.ID,
@@ -68,6 +69,7 @@ func (q *Queries) InsertUser(ctx context.Context, db DBTX, arg InsertUserParams)
 		arg.EmailValidatedAt,
 		arg.Password,
 		arg.IsAdmin,
+		arg.Role,
 	)
 	var i User
 	err := row.Scan(
@@ -78,12 +80,14 @@ func (q *Queries) InsertUser(ctx context.Context, db DBTX, arg InsertUserParams)
 		&i.EmailValidatedAt,
 		&i.Password,
 		&i.IsAdmin,
+		&i.DeactivatedAt,
+		&i.Role,
 	)
 	return i, err
 }
 
 const queryPaginatedUsers = `-- name: QueryPaginatedUsers :many
-select id, created_at, updated_at, email, email_validated_at, password, is_admin from users
+select id, created_at, updated_at, email, email_validated_at, password, is_admin, deactivated_at, role from users
 order by created_at desc
 limit $2::bigint offset $1::bigint
 `
@@ -95,7 +99,7 @@ type QueryPaginatedUsersParams struct {
 
 // QueryPaginatedUsers
 //
-//	select id, created_at, updated_at, email, email_validated_at, password, is_admin from users
+//	select id, created_at, updated_at, email, email_validated_at, password, is_admin, deactivated_at, role from users
 //	order by created_at desc
 //	limit $2::bigint offset $1::bigint
 func (q *Queries) QueryPaginatedUsers(ctx context.Context, db DBTX, arg QueryPaginatedUsersParams) ([]User, error) {
@@ -115,6 +119,8 @@ func (q *Queries) QueryPaginatedUsers(ctx context.Context, db DBTX, arg QueryPag
 			&i.EmailValidatedAt,
 			&i.Password,
 			&i.IsAdmin,
+			&i.DeactivatedAt,
+			&i.Role,
 		); err != nil {
 			return nil, err
 		}
@@ -127,12 +133,12 @@ func (q *Queries) QueryPaginatedUsers(ctx context.Context, db DBTX, arg QueryPag
 }
 
 const queryUserByEmail = `-- name: QueryUserByEmail :one
-select id, created_at, updated_at, email, email_validated_at, password, is_admin from users where email=$1
+select id, created_at, updated_at, email, email_validated_at, password, is_admin, deactivated_at, role from users where email=$1
 `
 
 // QueryUserByEmail
 //
-//	select id, created_at, updated_at, email, email_validated_at, password, is_admin from users where email=$1
+//	select id, created_at, updated_at, email, email_validated_at, password, is_admin, deactivated_at, role from users where email=$1
 func (q *Queries) QueryUserByEmail(ctx context.Context, db DBTX, email string) (User, error) {
 	row := db.QueryRow(ctx, queryUserByEmail, email)
 	var i User
@@ -144,17 +150,19 @@ func (q *Queries) QueryUserByEmail(ctx context.Context, db DBTX, email string) (
 		&i.EmailValidatedAt,
 		&i.Password,
 		&i.IsAdmin,
+		&i.DeactivatedAt,
+		&i.Role,
 	)
 	return i, err
 }
 
 const queryUserByID = `-- name: QueryUserByID :one
-select id, created_at, updated_at, email, email_validated_at, password, is_admin from users where id=$1
+select id, created_at, updated_at, email, email_validated_at, password, is_admin, deactivated_at, role from users where id=$1
 `
 
 // QueryUserByID
 //
-//	select id, created_at, updated_at, email, email_validated_at, password, is_admin from users where id=$1
+//	select id, created_at, updated_at, email, email_validated_at, password, is_admin, deactivated_at, role from users where id=$1
 func (q *Queries) QueryUserByID(ctx context.Context, db DBTX, id uuid.UUID) (User, error) {
 	row := db.QueryRow(ctx, queryUserByID, id)
 	var i User
@@ -166,17 +174,19 @@ func (q *Queries) QueryUserByID(ctx context.Context, db DBTX, id uuid.UUID) (Use
 		&i.EmailValidatedAt,
 		&i.Password,
 		&i.IsAdmin,
+		&i.DeactivatedAt,
+		&i.Role,
 	)
 	return i, err
 }
 
 const queryUsers = `-- name: QueryUsers :many
-select id, created_at, updated_at, email, email_validated_at, password, is_admin from users
+select id, created_at, updated_at, email, email_validated_at, password, is_admin, deactivated_at, role from users
 `
 
 // QueryUsers
 //
-//	select id, created_at, updated_at, email, email_validated_at, password, is_admin from users
+//	select id, created_at, updated_at, email, email_validated_at, password, is_admin, deactivated_at, role from users
 func (q *Queries) QueryUsers(ctx context.Context, db DBTX) ([]User, error) {
 	rows, err := db.Query(ctx, queryUsers)
 	if err != nil {
@@ -194,6 +204,8 @@ func (q *Queries) QueryUsers(ctx context.Context, db DBTX) ([]User, error) {
 			&i.EmailValidatedAt,
 			&i.Password,
 			&i.IsAdmin,
+			&i.DeactivatedAt,
+			&i.Role,
 		); err != nil {
 			return nil, err
 		}
@@ -209,7 +221,7 @@ const updateUser = `-- name: UpdateUser :one
 update users
     set updated_at=now(), email=$2, email_validated_at=$3, password=$4, is_admin=$5
 where id = $1
-returning id, created_at, updated_at, email, email_validated_at, password, is_admin
+returning id, created_at, updated_at, email, email_validated_at, password, is_admin, deactivated_at, role
 `
 
 type UpdateUserParams struct {
@@ -225,7 +237,7 @@ type UpdateUserParams struct {
 //	update users
 //	    set updated_at=now(), email=$2, email_validated_at=$3, password=$4, is_admin=$5
 //	where id = $1
-//	returning id, created_at, updated_at, email, email_validated_at, password, is_admin
+//	returning id, created_at, updated_at, email, email_validated_at, password, is_admin, deactivated_at, role
 func (q *Queries) UpdateUser(ctx context.Context, db DBTX, arg UpdateUserParams) (User, error) {
 	row := db.QueryRow(ctx, updateUser,
 		arg.ID,
@@ -243,6 +255,78 @@ func (q *Queries) UpdateUser(ctx context.Context, db DBTX, arg UpdateUserParams)
 		&i.EmailValidatedAt,
 		&i.Password,
 		&i.IsAdmin,
+		&i.DeactivatedAt,
+		&i.Role,
+	)
+	return i, err
+}
+
+const updateUserDeactivatedAt = `-- name: UpdateUserDeactivatedAt :one
+update users
+    set updated_at=now(), deactivated_at=$2
+where id = $1
+returning id, created_at, updated_at, email, email_validated_at, password, is_admin, deactivated_at, role
+`
+
+type UpdateUserDeactivatedAtParams struct {
+	ID            uuid.UUID
+	DeactivatedAt pgtype.Timestamptz
+}
+
+// UpdateUserDeactivatedAt
+//
+//	update users
+//	    set updated_at=now(), deactivated_at=$2
+//	where id = $1
+//	returning id, created_at, updated_at, email, email_validated_at, password, is_admin, deactivated_at, role
+func (q *Queries) UpdateUserDeactivatedAt(ctx context.Context, db DBTX, arg UpdateUserDeactivatedAtParams) (User, error) {
+	row := db.QueryRow(ctx, updateUserDeactivatedAt, arg.ID, arg.DeactivatedAt)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Email,
+		&i.EmailValidatedAt,
+		&i.Password,
+		&i.IsAdmin,
+		&i.DeactivatedAt,
+		&i.Role,
+	)
+	return i, err
+}
+
+const updateUserRole = `-- name: UpdateUserRole :one
+update users
+    set updated_at=now(), role=$2, is_admin=$2::varchar = 'admin'
+where id = $1
+returning id, created_at, updated_at, email, email_validated_at, password, is_admin, deactivated_at, role
+`
+
+type UpdateUserRoleParams struct {
+	ID   uuid.UUID
+	Role string
+}
+
+// UpdateUserRole
+//
+//	update users
+//	    set updated_at=now(), role=$2, is_admin=$2::varchar = 'admin'
+//	where id = $1
+//	returning id, created_at, updated_at, email, email_validated_at, password, is_admin, deactivated_at, role
+func (q *Queries) UpdateUserRole(ctx context.Context, db DBTX, arg UpdateUserRoleParams) (User, error) {
+	row := db.QueryRow(ctx, updateUserRole, arg.ID, arg.Role)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Email,
+		&i.EmailValidatedAt,
+		&i.Password,
+		&i.IsAdmin,
+		&i.DeactivatedAt,
+		&i.Role,
 	)
 	return i, err
 }
@@ -0,0 +1,310 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: webhooks.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const deleteWebhookEndpoint = `-- name: DeleteWebhookEndpoint :exec
+delete from webhook_endpoints where id=$1
+`
+
+// DeleteWebhookEndpoint
+//
+//	delete from webhook_endpoints where id=$1
+func (q *Queries) DeleteWebhookEndpoint(ctx context.Context, db DBTX, id uuid.UUID) error {
+	_, err := db.Exec(ctx, deleteWebhookEndpoint, id)
+	return err
+}
+
+const insertWebhookDelivery = `-- name: InsertWebhookDelivery :one
+insert into
+    webhook_deliveries (created_at, endpoint_id, event_id, event, attempt, succeeded, response_status, response_body, error, duration_ms)
+values
+    (now(), $1, $2, $3, $4, $5, $6, $7, $8, $9)
+returning id, created_at, endpoint_id, event_id, event, attempt, succeeded, response_status, response_body, error, duration_ms
+`
+
+type InsertWebhookDeliveryParams struct {
+	EndpointID     uuid.UUID
+	EventID        uuid.UUID
+	Event          string
+	Attempt        int32
+	Succeeded      bool
+	ResponseStatus pgtype.Int4
+	ResponseBody   string
+	Error          string
+	DurationMs     int32
+}
+
+// InsertWebhookDelivery
+//
+//	insert into
+//	    webhook_deliveries (created_at, endpoint_id, event_id, event, attempt, succeeded, response_status, response_body, error, duration_ms)
+//	values
+//	    (now(), $1, $2, $3, $4, $5, $6, $7, $8, $9)
+//	returning id, created_at, endpoint_id, event_id, event, attempt, succeeded, response_status, response_body, error, duration_ms
+func (q *Queries) InsertWebhookDelivery(ctx context.Context, db DBTX, arg InsertWebhookDeliveryParams) (WebhookDelivery, error) {
+	row := db.QueryRow(ctx, insertWebhookDelivery,
+		arg.EndpointID,
+		arg.EventID,
+		arg.Event,
+		arg.Attempt,
+		arg.Succeeded,
+		arg.ResponseStatus,
+		arg.ResponseBody,
+		arg.Error,
+		arg.DurationMs,
+	)
+	var i WebhookDelivery
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.EndpointID,
+		&i.EventID,
+		&i.Event,
+		&i.Attempt,
+		&i.Succeeded,
+		&i.ResponseStatus,
+		&i.ResponseBody,
+		&i.Error,
+		&i.DurationMs,
+	)
+	return i, err
+}
+
+const insertWebhookEndpoint = `-- name: InsertWebhookEndpoint :one
+insert into
+    webhook_endpoints (id, created_at, updated_at, name, url, secret, events, is_active)
+values
+    ($1, now(), now(), $2, $3, $4, $5, $6)
+returning id, created_at, updated_at, name, url, secret, events, is_active
+`
+
+type InsertWebhookEndpointParams struct {
+	ID       uuid.UUID
+	Name     string
+	Url      string
+	Secret   string
+	Events   []string
+	IsActive bool
+}
+
+// InsertWebhookEndpoint
+//
+//	insert into
+//	    webhook_endpoints (id, created_at, updated_at, name, url, secret, events, is_active)
+//	values
+//	    ($1, now(), now(), $2, $3, $4, $5, $6)
+//	returning id, created_at, updated_at, name, url, secret, events, is_active
+func (q *Queries) InsertWebhookEndpoint(ctx context.Context, db DBTX, arg InsertWebhookEndpointParams) (WebhookEndpoint, error) {
+	row := db.QueryRow(ctx, insertWebhookEndpoint,
+		arg.ID,
+		arg.Name,
+		arg.Url,
+		arg.Secret,
+		arg.Events,
+		arg.IsActive,
+	)
+	var i WebhookEndpoint
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.Secret,
+		&i.Events,
+		&i.IsActive,
+	)
+	return i, err
+}
+
+const queryActiveWebhookEndpointsForEvent = `-- name: QueryActiveWebhookEndpointsForEvent :many
+select id, created_at, updated_at, name, url, secret, events, is_active from webhook_endpoints where is_active and $1::text = any(events) order by created_at
+`
+
+// QueryActiveWebhookEndpointsForEvent
+//
+//	select id, created_at, updated_at, name, url, secret, events, is_active from webhook_endpoints where is_active and $1::text = any(events) order by created_at
+func (q *Queries) QueryActiveWebhookEndpointsForEvent(ctx context.Context, db DBTX, event string) ([]WebhookEndpoint, error) {
+	rows, err := db.Query(ctx, queryActiveWebhookEndpointsForEvent, event)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookEndpoint
+	for rows.Next() {
+		var i WebhookEndpoint
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Url,
+			&i.Secret,
+			&i.Events,
+			&i.IsActive,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const queryWebhookDeliveriesByEndpointID = `-- name: QueryWebhookDeliveriesByEndpointID :many
+select id, created_at, endpoint_id, event_id, event, attempt, succeeded, response_status, response_body, error, duration_ms from webhook_deliveries where endpoint_id=$1 order by created_at desc, id desc limit $2
+`
+
+type QueryWebhookDeliveriesByEndpointIDParams struct {
+	EndpointID uuid.UUID
+	Limit      int32
+}
+
+// QueryWebhookDeliveriesByEndpointID
+//
+//	select id, created_at, endpoint_id, event_id, event, attempt, succeeded, response_status, response_body, error, duration_ms from webhook_deliveries where endpoint_id=$1 order by created_at desc, id desc limit $2
+func (q *Queries) QueryWebhookDeliveriesByEndpointID(ctx context.Context, db DBTX, arg QueryWebhookDeliveriesByEndpointIDParams) ([]WebhookDelivery, error) {
+	rows, err := db.Query(ctx, queryWebhookDeliveriesByEndpointID, arg.EndpointID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDelivery
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.EndpointID,
+			&i.EventID,
+			&i.Event,
+			&i.Attempt,
+			&i.Succeeded,
+			&i.ResponseStatus,
+			&i.ResponseBody,
+			&i.Error,
+			&i.DurationMs,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const queryWebhookEndpointByID = `-- name: QueryWebhookEndpointByID :one
+select id, created_at, updated_at, name, url, secret, events, is_active from webhook_endpoints where id=$1
+`
+
+// QueryWebhookEndpointByID
+//
+//	select id, created_at, updated_at, name, url, secret, events, is_active from webhook_endpoints where id=$1
+func (q *Queries) QueryWebhookEndpointByID(ctx context.Context, db DBTX, id uuid.UUID) (WebhookEndpoint, error) {
+	row := db.QueryRow(ctx, queryWebhookEndpointByID, id)
+	var i WebhookEndpoint
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.Secret,
+		&i.Events,
+		&i.IsActive,
+	)
+	return i, err
+}
+
+const queryWebhookEndpoints = `-- name: QueryWebhookEndpoints :many
+select id, created_at, updated_at, name, url, secret, events, is_active from webhook_endpoints order by created_at desc
+`
+
+// QueryWebhookEndpoints
+//
+//	select id, created_at, updated_at, name, url, secret, events, is_active from webhook_endpoints order by created_at desc
+func (q *Queries) QueryWebhookEndpoints(ctx context.Context, db DBTX) ([]WebhookEndpoint, error) {
+	rows, err := db.Query(ctx, queryWebhookEndpoints)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookEndpoint
+	for rows.Next() {
+		var i WebhookEndpoint
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Url,
+			&i.Secret,
+			&i.Events,
+			&i.IsActive,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateWebhookEndpoint = `-- name: UpdateWebhookEndpoint :one
+update webhook_endpoints
+    set updated_at=now(), name=$2, url=$3, events=$4, is_active=$5
+where id = $1
+returning id, created_at, updated_at, name, url, secret, events, is_active
+`
+
+type UpdateWebhookEndpointParams struct {
+	ID       uuid.UUID
+	Name     string
+	Url      string
+	Events   []string
+	IsActive bool
+}
+
+// UpdateWebhookEndpoint
+//
+//	update webhook_endpoints
+//	    set updated_at=now(), name=$2, url=$3, events=$4, is_active=$5
+//	where id = $1
+//	returning id, created_at, updated_at, name, url, secret, events, is_active
+func (q *Queries) UpdateWebhookEndpoint(ctx context.Context, db DBTX, arg UpdateWebhookEndpointParams) (WebhookEndpoint, error) {
+	row := db.QueryRow(ctx, updateWebhookEndpoint,
+		arg.ID,
+		arg.Name,
+		arg.Url,
+		arg.Events,
+		arg.IsActive,
+	)
+	var i WebhookEndpoint
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.Secret,
+		&i.Events,
+		&i.IsActive,
+	)
+	return i, err
+}
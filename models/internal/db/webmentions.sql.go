@@ -0,0 +1,292 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: webmentions.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const deleteWebmention = `-- name: DeleteWebmention :exec
+delete from webmentions where id=$1
+`
+
+// DeleteWebmention
+//
+//	delete from webmentions where id=$1
+func (q *Queries) DeleteWebmention(ctx context.Context, db DBTX, id uuid.UUID) error {
+	_, err := db.Exec(ctx, deleteWebmention, id)
+	return err
+}
+
+const queryWebmentionByID = `-- name: QueryWebmentionByID :one
+select id, created_at, updated_at, article_id, source, target, status, author_name, author_url, title, excerpt, verified_at from webmentions where id=$1
+`
+
+// QueryWebmentionByID
+//
+//	select id, created_at, updated_at, article_id, source, target, status, author_name, author_url, title, excerpt, verified_at from webmentions where id=$1
+func (q *Queries) QueryWebmentionByID(ctx context.Context, db DBTX, id uuid.UUID) (Webmention, error) {
+	row := db.QueryRow(ctx, queryWebmentionByID, id)
+	var i Webmention
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ArticleID,
+		&i.Source,
+		&i.Target,
+		&i.Status,
+		&i.AuthorName,
+		&i.AuthorUrl,
+		&i.Title,
+		&i.Excerpt,
+		&i.VerifiedAt,
+	)
+	return i, err
+}
+
+const queryWebmentionsByArticleIDAndStatus = `-- name: QueryWebmentionsByArticleIDAndStatus :many
+select id, created_at, updated_at, article_id, source, target, status, author_name, author_url, title, excerpt, verified_at from webmentions where article_id=$1 and status=$2 order by created_at
+`
+
+type QueryWebmentionsByArticleIDAndStatusParams struct {
+	ArticleID int32
+	Status    string
+}
+
+// QueryWebmentionsByArticleIDAndStatus
+//
+//	select id, created_at, updated_at, article_id, source, target, status, author_name, author_url, title, excerpt, verified_at from webmentions where article_id=$1 and status=$2 order by created_at
+func (q *Queries) QueryWebmentionsByArticleIDAndStatus(ctx context.Context, db DBTX, arg QueryWebmentionsByArticleIDAndStatusParams) ([]Webmention, error) {
+	rows, err := db.Query(ctx, queryWebmentionsByArticleIDAndStatus, arg.ArticleID, arg.Status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Webmention
+	for rows.Next() {
+		var i Webmention
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ArticleID,
+			&i.Source,
+			&i.Target,
+			&i.Status,
+			&i.AuthorName,
+			&i.AuthorUrl,
+			&i.Title,
+			&i.Excerpt,
+			&i.VerifiedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const queryWebmentionsByStatus = `-- name: QueryWebmentionsByStatus :many
+select id, created_at, updated_at, article_id, source, target, status, author_name, author_url, title, excerpt, verified_at from webmentions
+where $1::varchar is null or status = $1::varchar
+order by created_at desc
+limit $2::bigint
+`
+
+type QueryWebmentionsByStatusParams struct {
+	Status pgtype.Text
+	Limit  int64
+}
+
+// QueryWebmentionsByStatus
+//
+//	select id, created_at, updated_at, article_id, source, target, status, author_name, author_url, title, excerpt, verified_at from webmentions
+//	where $1::varchar is null or status = $1::varchar
+//	order by created_at desc
+//	limit $2::bigint
+func (q *Queries) QueryWebmentionsByStatus(ctx context.Context, db DBTX, arg QueryWebmentionsByStatusParams) ([]Webmention, error) {
+	rows, err := db.Query(ctx, queryWebmentionsByStatus, arg.Status, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Webmention
+	for rows.Next() {
+		var i Webmention
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ArticleID,
+			&i.Source,
+			&i.Target,
+			&i.Status,
+			&i.AuthorName,
+			&i.AuthorUrl,
+			&i.Title,
+			&i.Excerpt,
+			&i.VerifiedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateWebmentionStatus = `-- name: UpdateWebmentionStatus :one
+update webmentions set updated_at=now(), status=$2 where id = $1 returning id, created_at, updated_at, article_id, source, target, status, author_name, author_url, title, excerpt, verified_at
+`
+
+type UpdateWebmentionStatusParams struct {
+	ID     uuid.UUID
+	Status string
+}
+
+// UpdateWebmentionStatus
+//
+//	update webmentions set updated_at=now(), status=$2 where id = $1 returning id, created_at, updated_at, article_id, source, target, status, author_name, author_url, title, excerpt, verified_at
+func (q *Queries) UpdateWebmentionStatus(ctx context.Context, db DBTX, arg UpdateWebmentionStatusParams) (Webmention, error) {
+	row := db.QueryRow(ctx, updateWebmentionStatus, arg.ID, arg.Status)
+	var i Webmention
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ArticleID,
+		&i.Source,
+		&i.Target,
+		&i.Status,
+		&i.AuthorName,
+		&i.AuthorUrl,
+		&i.Title,
+		&i.Excerpt,
+		&i.VerifiedAt,
+	)
+	return i, err
+}
+
+const updateWebmentionVerified = `-- name: UpdateWebmentionVerified :one
+update webmentions
+    set updated_at=now(),
+        verified_at=now(),
+        status=case when status in ('approved', 'rejected') then status else 'review' end,
+        author_name=$2,
+        author_url=$3,
+        title=$4,
+        excerpt=$5
+where id = $1
+returning id, created_at, updated_at, article_id, source, target, status, author_name, author_url, title, excerpt, verified_at
+`
+
+type UpdateWebmentionVerifiedParams struct {
+	ID         uuid.UUID
+	AuthorName string
+	AuthorUrl  string
+	Title      string
+	Excerpt    string
+}
+
+// UpdateWebmentionVerified
+//
+//	update webmentions
+//	    set updated_at=now(),
+//	        verified_at=now(),
+//	        status=case when status in ('approved', 'rejected') then status else 'review' end,
+//	        author_name=$2,
+//	        author_url=$3,
+//	        title=$4,
+//	        excerpt=$5
+//	where id = $1
+//	returning id, created_at, updated_at, article_id, source, target, status, author_name, author_url, title, excerpt, verified_at
+func (q *Queries) UpdateWebmentionVerified(ctx context.Context, db DBTX, arg UpdateWebmentionVerifiedParams) (Webmention, error) {
+	row := db.QueryRow(ctx, updateWebmentionVerified,
+		arg.ID,
+		arg.AuthorName,
+		arg.AuthorUrl,
+		arg.Title,
+		arg.Excerpt,
+	)
+	var i Webmention
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ArticleID,
+		&i.Source,
+		&i.Target,
+		&i.Status,
+		&i.AuthorName,
+		&i.AuthorUrl,
+		&i.Title,
+		&i.Excerpt,
+		&i.VerifiedAt,
+	)
+	return i, err
+}
+
+const upsertWebmention = `-- name: UpsertWebmention :one
+insert into
+    webmentions (id, created_at, updated_at, article_id, source, target, status)
+values
+    ($1, now(), now(), $2, $3, $4, 'pending')
+on conflict (source, target) do update set
+    updated_at=now(),
+    article_id=excluded.article_id
+returning id, created_at, updated_at, article_id, source, target, status, author_name, author_url, title, excerpt, verified_at
+`
+
+type UpsertWebmentionParams struct {
+	ID        uuid.UUID
+	ArticleID int32
+	Source    string
+	Target    string
+}
+
+// UpsertWebmention
+//
+//	insert into
+//	    webmentions (id, created_at, updated_at, article_id, source, target, status)
+//	values
+//	    ($1, now(), now(), $2, $3, $4, 'pending')
+//	on conflict (source, target) do update set
+//	    updated_at=now(),
+//	    article_id=excluded.article_id
+//	returning id, created_at, updated_at, article_id, source, target, status, author_name, author_url, title, excerpt, verified_at
+func (q *Queries) UpsertWebmention(ctx context.Context, db DBTX, arg UpsertWebmentionParams) (Webmention, error) {
+	row := db.QueryRow(ctx, upsertWebmention,
+		arg.ID,
+		arg.ArticleID,
+		arg.Source,
+		arg.Target,
+	)
+	var i Webmention
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ArticleID,
+		&i.Source,
+		&i.Target,
+		&i.Status,
+		&i.AuthorName,
+		&i.AuthorUrl,
+		&i.Title,
+		&i.Excerpt,
+		&i.VerifiedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,151 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models/internal/db"
+)
+
+// LinkSourceType is the kind of content a checked link was found in.
+type LinkSourceType string
+
+const (
+	LinkSourceArticle    LinkSourceType = "article"
+	LinkSourceProject    LinkSourceType = "project"
+	LinkSourceNewsletter LinkSourceType = "newsletter"
+)
+
+// LinkSource is a place a link was found: the content and the line of its
+// markdown, counted from 1.
+type LinkSource struct {
+	Type  LinkSourceType `json:"type"`
+	ID    int32          `json:"id"`
+	Title string         `json:"title"`
+	Line  int            `json:"line"`
+}
+
+// LinkCheck is the last result of checking a link from published content.
+// FirstFailedAt is when the link started failing and is reset once it works
+// again. Internal links are paths on this site.
+type LinkCheck struct {
+	ID            uuid.UUID
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	URL           string
+	Internal      bool
+	Broken        bool
+	StatusCode    int
+	Error         string
+	Sources       []LinkSource
+	CheckedAt     time.Time
+	FirstFailedAt time.Time
+}
+
+// LinkChecks lists checked links, broken links first and the longest broken
+// at the top. With brokenOnly the working links are left out.
+func LinkChecks(
+	ctx context.Context,
+	exec storage.Executor,
+	brokenOnly bool,
+	limit int64,
+) ([]LinkCheck, error) {
+	rows, err := queries.QueryLinkChecks(ctx, exec, db.QueryLinkChecksParams{
+		Broken: pgtype.Bool{Bool: true, Valid: brokenOnly},
+		Limit:  limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	checks := make([]LinkCheck, len(rows))
+	for i, row := range rows {
+		checks[i] = rowToLinkCheck(row)
+	}
+
+	return checks, nil
+}
+
+type RecordLinkCheckData struct {
+	URL        string `validate:"required"`
+	Internal   bool
+	Broken     bool
+	StatusCode int
+	Error      string
+	Sources    []LinkSource
+	CheckedAt  time.Time `validate:"required"`
+}
+
+// RecordLinkCheck stores the result of checking a link, keeping the time it
+// first failed while it stays broken.
+func RecordLinkCheck(
+	ctx context.Context,
+	exec storage.Executor,
+	data RecordLinkCheckData,
+) (LinkCheck, error) {
+	if err := Validate.Struct(data); err != nil {
+		return LinkCheck{}, errors.Join(ErrDomainValidation, err)
+	}
+
+	sources := data.Sources
+	if sources == nil {
+		sources = []LinkSource{}
+	}
+	encoded, err := json.Marshal(sources)
+	if err != nil {
+		return LinkCheck{}, err
+	}
+
+	row, err := queries.UpsertLinkCheck(ctx, exec, db.UpsertLinkCheckParams{
+		ID:         uuid.New(),
+		Url:        data.URL,
+		Internal:   data.Internal,
+		Broken:     data.Broken,
+		StatusCode: int32(data.StatusCode),
+		Error:      data.Error,
+		Sources:    encoded,
+		CheckedAt:  pgtype.Timestamptz{Time: data.CheckedAt, Valid: true},
+	})
+	if err != nil {
+		return LinkCheck{}, err
+	}
+
+	return rowToLinkCheck(row), nil
+}
+
+// DestroyLinkChecksBefore removes links not checked since before, which
+// are no longer in any published content.
+func DestroyLinkChecksBefore(
+	ctx context.Context,
+	exec storage.Executor,
+	before time.Time,
+) (int64, error) {
+	return queries.DeleteLinkChecksCheckedBefore(ctx, exec, pgtype.Timestamptz{Time: before, Valid: true})
+}
+
+func rowToLinkCheck(row db.LinkCheck) LinkCheck {
+	var sources []LinkSource
+	if len(row.Sources) > 0 {
+		_ = json.Unmarshal(row.Sources, &sources)
+	}
+
+	return LinkCheck{
+		ID:            row.ID,
+		CreatedAt:     row.CreatedAt.Time,
+		UpdatedAt:     row.UpdatedAt.Time,
+		URL:           row.Url,
+		Internal:      row.Internal,
+		Broken:        row.Broken,
+		StatusCode:    int(row.StatusCode),
+		Error:         row.Error,
+		Sources:       sources,
+		CheckedAt:     row.CheckedAt.Time,
+		FirstFailedAt: row.FirstFailedAt.Time,
+	}
+}
@@ -0,0 +1,14 @@
+package models_test
+
+import (
+	"os"
+	"testing"
+
+	"mortenvistisen/database"
+)
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	database.CloseSharedTestDB()
+	os.Exit(code)
+}
@@ -0,0 +1,282 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models/internal/db"
+)
+
+// Media is an uploaded image. The upload itself is not kept; Variants
+// holds the re-encoded files that are served in its place.
+type Media struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	UploadedBy uuid.UUID
+	Filename   string
+	AltText    string
+	Width      int32
+	Height     int32
+	Variants   []MediaVariant
+}
+
+// MediaVariant is one stored file of a Media, identified by its storage
+// key.
+type MediaVariant struct {
+	StorageKey  string `validate:"required,max=255"`
+	ContentType string `validate:"required,max=50"`
+	Width       int32  `validate:"gt=0"`
+	Height      int32  `validate:"gt=0"`
+	ByteSize    int64  `validate:"gt=0"`
+}
+
+// IsWebP reports whether the variant is one of the generated WebP files.
+func (v MediaVariant) IsWebP() bool {
+	return v.ContentType == "image/webp"
+}
+
+// Original returns the full size variant in the uploaded format.
+func (m Media) Original() MediaVariant {
+	var original MediaVariant
+	for _, variant := range m.Variants {
+		if variant.Width > original.Width ||
+			(variant.Width == original.Width && original.IsWebP() && !variant.IsWebP()) {
+			original = variant
+		}
+	}
+
+	return original
+}
+
+// Display returns the variant to show where at most maxWidth pixels are
+// needed: the widest one that fits, or the narrowest if none does. Of the
+// formats at that width the smallest file wins, since lossless WebP is not
+// always smaller than the JPEG it replaces.
+func (m Media) Display(maxWidth int32) MediaVariant {
+	var width int32
+	for _, variant := range m.Variants {
+		if variant.Width <= maxWidth && variant.Width > width {
+			width = variant.Width
+		}
+	}
+	if width == 0 {
+		for _, variant := range m.Variants {
+			if width == 0 || variant.Width < width {
+				width = variant.Width
+			}
+		}
+	}
+
+	var display MediaVariant
+	for _, variant := range m.Variants {
+		if variant.Width != width {
+			continue
+		}
+		if display.StorageKey == "" || variant.ByteSize < display.ByteSize {
+			display = variant
+		}
+	}
+
+	return display
+}
+
+// StorageKeys lists the keys of every stored file of the media.
+func (m Media) StorageKeys() []string {
+	keys := make([]string, len(m.Variants))
+	for i, variant := range m.Variants {
+		keys[i] = variant.StorageKey
+	}
+
+	return keys
+}
+
+func FindMedia(
+	ctx context.Context,
+	exec storage.Executor,
+	id uuid.UUID,
+) (Media, error) {
+	row, err := queries.QueryMediaByID(ctx, exec, id)
+	if err != nil {
+		return Media{}, err
+	}
+
+	media, err := withMediaVariants(ctx, exec, []db.Medium{row})
+	if err != nil {
+		return Media{}, err
+	}
+
+	return media[0], nil
+}
+
+// RecentMedia lists the newest uploads first.
+func RecentMedia(
+	ctx context.Context,
+	exec storage.Executor,
+	limit int64,
+) ([]Media, error) {
+	rows, err := queries.QueryRecentMedia(ctx, exec, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return withMediaVariants(ctx, exec, rows)
+}
+
+type CreateMediaData struct {
+	ID         uuid.UUID `validate:"required"`
+	UploadedBy uuid.UUID
+	Filename   string         `validate:"required,max=255"`
+	AltText    string         `validate:"max=500"`
+	Width      int32          `validate:"gt=0"`
+	Height     int32          `validate:"gt=0"`
+	Variants   []MediaVariant `validate:"required,min=1,dive"`
+}
+
+// CreateMedia records an upload whose variants are already stored. The
+// ID is chosen by the caller because the storage keys are derived from
+// it.
+func CreateMedia(
+	ctx context.Context,
+	exec storage.Executor,
+	data CreateMediaData,
+) (Media, error) {
+	data.Filename = strings.TrimSpace(data.Filename)
+	data.AltText = strings.TrimSpace(data.AltText)
+
+	if err := Validate.Struct(data); err != nil {
+		return Media{}, errors.Join(ErrDomainValidation, err)
+	}
+
+	row, err := queries.InsertMedia(ctx, exec, db.InsertMediaParams{
+		ID:         data.ID,
+		UploadedBy: pgtype.UUID{Bytes: data.UploadedBy, Valid: data.UploadedBy != uuid.Nil},
+		Filename:   data.Filename,
+		AltText:    data.AltText,
+		Width:      data.Width,
+		Height:     data.Height,
+	})
+	if err != nil {
+		return Media{}, err
+	}
+
+	media := rowToMedia(row)
+	for _, variant := range data.Variants {
+		variantRow, err := queries.InsertMediaVariant(ctx, exec, db.InsertMediaVariantParams{
+			StorageKey:  variant.StorageKey,
+			MediaID:     media.ID,
+			ContentType: variant.ContentType,
+			Width:       variant.Width,
+			Height:      variant.Height,
+			ByteSize:    variant.ByteSize,
+		})
+		if err != nil {
+			return Media{}, err
+		}
+		media.Variants = append(media.Variants, rowToMediaVariant(variantRow))
+	}
+
+	return media, nil
+}
+
+type UpdateMediaAltTextData struct {
+	ID      uuid.UUID `validate:"required"`
+	AltText string    `validate:"max=500"`
+}
+
+func UpdateMediaAltText(
+	ctx context.Context,
+	exec storage.Executor,
+	data UpdateMediaAltTextData,
+) (Media, error) {
+	data.AltText = strings.TrimSpace(data.AltText)
+
+	if err := Validate.Struct(data); err != nil {
+		return Media{}, errors.Join(ErrDomainValidation, err)
+	}
+
+	row, err := queries.UpdateMediaAltText(ctx, exec, db.UpdateMediaAltTextParams{
+		ID:      data.ID,
+		AltText: data.AltText,
+	})
+	if err != nil {
+		return Media{}, err
+	}
+
+	media, err := withMediaVariants(ctx, exec, []db.Medium{row})
+	if err != nil {
+		return Media{}, err
+	}
+
+	return media[0], nil
+}
+
+// DestroyMedia deletes the media and its variant records. The stored files
+// are left for the caller to remove.
+func DestroyMedia(
+	ctx context.Context,
+	exec storage.Executor,
+	id uuid.UUID,
+) error {
+	return queries.DeleteMedia(ctx, exec, id)
+}
+
+func withMediaVariants(
+	ctx context.Context,
+	exec storage.Executor,
+	rows []db.Medium,
+) ([]Media, error) {
+	media := make([]Media, len(rows))
+	ids := make([]uuid.UUID, len(rows))
+	index := make(map[uuid.UUID]int, len(rows))
+	for i, row := range rows {
+		media[i] = rowToMedia(row)
+		ids[i] = row.ID
+		index[row.ID] = i
+	}
+
+	if len(ids) == 0 {
+		return media, nil
+	}
+
+	variants, err := queries.QueryMediaVariantsByMediaIDs(ctx, exec, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, variant := range variants {
+		i := index[variant.MediaID]
+		media[i].Variants = append(media[i].Variants, rowToMediaVariant(variant))
+	}
+
+	return media, nil
+}
+
+func rowToMedia(row db.Medium) Media {
+	return Media{
+		ID:         row.ID,
+		CreatedAt:  row.CreatedAt.Time,
+		UpdatedAt:  row.UpdatedAt.Time,
+		UploadedBy: row.UploadedBy.Bytes,
+		Filename:   row.Filename,
+		AltText:    row.AltText,
+		Width:      row.Width,
+		Height:     row.Height,
+	}
+}
+
+func rowToMediaVariant(row db.MediaVariant) MediaVariant {
+	return MediaVariant{
+		StorageKey:  row.StorageKey,
+		ContentType: row.ContentType,
+		Width:       row.Width,
+		Height:      row.Height,
+		ByteSize:    row.ByteSize,
+	}
+}
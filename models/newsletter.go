@@ -26,6 +26,7 @@ type Newsletter struct {
 	IsPublished     bool
 	ReleasedAt      time.Time
 	Content         string
+	Rendered        RenderedContent
 }
 
 func FindNewsletter(
@@ -68,6 +69,10 @@ type CreateNewsletterData struct {
 	IsPublished     bool
 	ReleasedAt      time.Time
 	Content         string
+	// CreatedAt and UpdatedAt are only read by UpsertNewsletter, so restored
+	// content keeps its original timestamps. Zero means now.
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
 
 func CreateNewsletter(
@@ -147,6 +152,26 @@ func UpdateNewsletter(
 	return rowToNewsletter(row), nil
 }
 
+// SetNewsletterRenderedContent stores the rendered content of a newsletter. It
+// leaves updated_at alone, as the newsletter itself has not changed.
+func SetNewsletterRenderedContent(
+	ctx context.Context,
+	exec storage.Executor,
+	id int32,
+	rendered RenderedContent,
+) (Newsletter, error) {
+	row, err := queries.UpdateNewsletterContentHTML(ctx, exec, db.UpdateNewsletterContentHTMLParams{
+		ID:          id,
+		ContentHtml: rendered.HTML,
+		ContentHash: rendered.Hash,
+	})
+	if err != nil {
+		return Newsletter{}, err
+	}
+
+	return rowToNewsletter(row), nil
+}
+
 func DestroyNewsletter(
 	ctx context.Context,
 	exec storage.Executor,
@@ -256,9 +281,15 @@ func UpsertNewsletter(
 	if err := Validate.Struct(data); err != nil {
 		return Newsletter{}, errors.Join(ErrDomainValidation, err)
 	}
+	newsletterSlug := data.Slug
+	if newsletterSlug == "" {
+		newsletterSlug = slug.Make(data.Title)
+	}
 	params := db.UpsertNewsletterParams{
+		CreatedAt:       pgtype.Timestamptz{Time: data.CreatedAt, Valid: !data.CreatedAt.IsZero()},
+		UpdatedAt:       pgtype.Timestamptz{Time: data.UpdatedAt, Valid: !data.UpdatedAt.IsZero()},
 		Title:           data.Title,
-		Slug:            pgtype.Text{String: slug.Make(data.Title), Valid: data.Title != ""},
+		Slug:            pgtype.Text{String: newsletterSlug, Valid: newsletterSlug != ""},
 		MetaTitle:       data.MetaTitle,
 		MetaDescription: data.MetaDescription,
 		IsPublished:     pgtype.Bool{Bool: data.IsPublished, Valid: true},
@@ -292,5 +323,6 @@ func rowToNewsletter(row db.Newsletter) Newsletter {
 		IsPublished:     row.IsPublished.Bool,
 		ReleasedAt:      row.ReleasedAt.Time,
 		Content:         row.Content.String,
+		Rendered:        RenderedContent{HTML: row.ContentHtml, Hash: row.ContentHash},
 	}
 }
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models/internal/db"
+)
+
+// Subject types of generated Open Graph images.
+const (
+	OGImageSubjectArticle    = "article"
+	OGImageSubjectProject    = "project"
+	OGImageSubjectNewsletter = "newsletter"
+)
+
+// OGImage is a generated Open Graph image. Fingerprint identifies the
+// content it was rendered from.
+type OGImage struct {
+	SubjectType string
+	SubjectID   int32
+	UpdatedAt   time.Time
+	Fingerprint string
+	Image       []byte
+}
+
+func FindOGImage(
+	ctx context.Context,
+	exec storage.Executor,
+	subjectType string,
+	subjectID int32,
+) (OGImage, error) {
+	row, err := queries.QueryOGImage(ctx, exec, db.QueryOGImageParams{
+		SubjectType: subjectType,
+		SubjectID:   subjectID,
+	})
+	if err != nil {
+		return OGImage{}, err
+	}
+
+	return rowToOGImage(row), nil
+}
+
+type UpsertOGImageData struct {
+	SubjectType string `validate:"required,oneof=article project newsletter"`
+	SubjectID   int32  `validate:"gt=0"`
+	Fingerprint string `validate:"required,max=64"`
+	Image       []byte `validate:"required"`
+}
+
+// UpsertOGImage stores the image of a subject, replacing the one rendered
+// before it.
+func UpsertOGImage(
+	ctx context.Context,
+	exec storage.Executor,
+	data UpsertOGImageData,
+) (OGImage, error) {
+	if err := Validate.Struct(data); err != nil {
+		return OGImage{}, errors.Join(ErrDomainValidation, err)
+	}
+
+	row, err := queries.UpsertOGImage(ctx, exec, db.UpsertOGImageParams{
+		SubjectType: data.SubjectType,
+		SubjectID:   data.SubjectID,
+		Fingerprint: data.Fingerprint,
+		Image:       data.Image,
+	})
+	if err != nil {
+		return OGImage{}, err
+	}
+
+	return rowToOGImage(row), nil
+}
+
+func rowToOGImage(row db.OgImage) OGImage {
+	return OGImage{
+		SubjectType: row.SubjectType,
+		SubjectID:   row.SubjectID,
+		UpdatedAt:   row.UpdatedAt.Time,
+		Fingerprint: row.Fingerprint,
+		Image:       row.Image,
+	}
+}
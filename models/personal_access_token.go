@@ -0,0 +1,228 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models/internal/db"
+)
+
+// personalAccessTokenPrefix marks plaintext tokens so they are easy to spot in
+// logs and secret scanners.
+const personalAccessTokenPrefix = "mvpat_"
+
+var ErrInvalidAPIScope = errors.New("invalid api scope")
+
+// APIScope limits what a personal access token may do. The token owner's role
+// must also grant the matching permission at request time.
+type APIScope string
+
+const (
+	APIScopeArticlesRead     APIScope = "articles:read"
+	APIScopeArticlesWrite    APIScope = "articles:write"
+	APIScopeTagsRead         APIScope = "tags:read"
+	APIScopeTagsWrite        APIScope = "tags:write"
+	APIScopeProjectsRead     APIScope = "projects:read"
+	APIScopeProjectsWrite    APIScope = "projects:write"
+	APIScopeNewslettersRead  APIScope = "newsletters:read"
+	APIScopeNewslettersWrite APIScope = "newsletters:write"
+)
+
+var APIScopes = []APIScope{
+	APIScopeArticlesRead,
+	APIScopeArticlesWrite,
+	APIScopeTagsRead,
+	APIScopeTagsWrite,
+	APIScopeProjectsRead,
+	APIScopeProjectsWrite,
+	APIScopeNewslettersRead,
+	APIScopeNewslettersWrite,
+}
+
+var apiScopePermissions = map[APIScope]Permission{
+	APIScopeArticlesRead:     PermissionWriteArticles,
+	APIScopeArticlesWrite:    PermissionWriteArticles,
+	APIScopeTagsRead:         PermissionAccessAdmin,
+	APIScopeTagsWrite:        PermissionManageTags,
+	APIScopeProjectsRead:     PermissionWriteProjects,
+	APIScopeProjectsWrite:    PermissionWriteProjects,
+	APIScopeNewslettersRead:  PermissionManageNewsletters,
+	APIScopeNewslettersWrite: PermissionManageNewsletters,
+}
+
+func (s APIScope) Valid() bool {
+	return slices.Contains(APIScopes, s)
+}
+
+// Permission is the role permission a user needs for the scope to take effect.
+func (s APIScope) Permission() Permission {
+	return apiScopePermissions[s]
+}
+
+type PersonalAccessToken struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	UserID     uuid.UUID
+	Name       string
+	Prefix     string
+	Hash       string
+	Scopes     []APIScope
+	ExpiresAt  time.Time
+	LastUsedAt time.Time
+}
+
+func (t PersonalAccessToken) IsExpired() bool {
+	return !t.ExpiresAt.IsZero() && !time.Now().Before(t.ExpiresAt)
+}
+
+func (t PersonalAccessToken) HasScope(scope APIScope) bool {
+	return slices.Contains(t.Scopes, scope)
+}
+
+func FindPersonalAccessToken(
+	ctx context.Context,
+	exec storage.Executor,
+	pepper string,
+	plain string,
+) (PersonalAccessToken, error) {
+	row, err := queries.QueryPersonalAccessTokenByHash(
+		ctx,
+		exec,
+		HashForStorage(plain, pepper),
+	)
+	if err != nil {
+		return PersonalAccessToken{}, err
+	}
+
+	return rowToPersonalAccessToken(row), nil
+}
+
+func AllPersonalAccessTokensForUser(
+	ctx context.Context,
+	exec storage.Executor,
+	userID uuid.UUID,
+) ([]PersonalAccessToken, error) {
+	rows, err := queries.QueryPersonalAccessTokensByUserID(ctx, exec, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make([]PersonalAccessToken, len(rows))
+	for i, row := range rows {
+		tokens[i] = rowToPersonalAccessToken(row)
+	}
+
+	return tokens, nil
+}
+
+type CreatePersonalAccessTokenData struct {
+	UserID    uuid.UUID  `validate:"required"`
+	Name      string     `validate:"required,max=100"`
+	Scopes    []APIScope `validate:"required,min=1"`
+	ExpiresAt time.Time
+}
+
+// CreatePersonalAccessToken stores a new token and returns it together with
+// the plaintext value, which is only available at creation time.
+func CreatePersonalAccessToken(
+	ctx context.Context,
+	exec storage.Executor,
+	pepper string,
+	data CreatePersonalAccessTokenData,
+) (PersonalAccessToken, string, error) {
+	if err := Validate.Struct(data); err != nil {
+		return PersonalAccessToken{}, "", errors.Join(ErrDomainValidation, err)
+	}
+
+	scopes := make([]string, 0, len(data.Scopes))
+	for _, scope := range data.Scopes {
+		if !scope.Valid() {
+			return PersonalAccessToken{}, "", errors.Join(ErrDomainValidation, ErrInvalidAPIScope)
+		}
+		if slices.Contains(scopes, string(scope)) {
+			continue
+		}
+		scopes = append(scopes, string(scope))
+	}
+
+	secret, err := GenerateSecureToken()
+	if err != nil {
+		return PersonalAccessToken{}, "", err
+	}
+	plain := personalAccessTokenPrefix + strings.ToLower(secret)
+
+	row, err := queries.InsertPersonalAccessToken(
+		ctx,
+		exec,
+		db.InsertPersonalAccessTokenParams{
+			ID:          uuid.New(),
+			UserID:      data.UserID,
+			Name:        strings.TrimSpace(data.Name),
+			TokenPrefix: plain[:len(personalAccessTokenPrefix)+4],
+			Hash:        HashForStorage(plain, pepper),
+			Scopes:      scopes,
+			ExpiresAt: pgtype.Timestamptz{
+				Time:  data.ExpiresAt,
+				Valid: !data.ExpiresAt.IsZero(),
+			},
+		},
+	)
+	if err != nil {
+		return PersonalAccessToken{}, "", err
+	}
+
+	return rowToPersonalAccessToken(row), plain, nil
+}
+
+func TouchPersonalAccessToken(
+	ctx context.Context,
+	exec storage.Executor,
+	id uuid.UUID,
+) error {
+	return queries.UpdatePersonalAccessTokenLastUsedAt(ctx, exec, id)
+}
+
+// DestroyPersonalAccessToken revokes a token. Only the owner's tokens match.
+func DestroyPersonalAccessToken(
+	ctx context.Context,
+	exec storage.Executor,
+	id uuid.UUID,
+	userID uuid.UUID,
+) error {
+	return queries.DeletePersonalAccessToken(
+		ctx,
+		exec,
+		db.DeletePersonalAccessTokenParams{
+			ID:     id,
+			UserID: userID,
+		},
+	)
+}
+
+func rowToPersonalAccessToken(row db.PersonalAccessToken) PersonalAccessToken {
+	scopes := make([]APIScope, len(row.Scopes))
+	for i, scope := range row.Scopes {
+		scopes[i] = APIScope(scope)
+	}
+
+	return PersonalAccessToken{
+		ID:         row.ID,
+		CreatedAt:  row.CreatedAt.Time,
+		UpdatedAt:  row.UpdatedAt.Time,
+		UserID:     row.UserID,
+		Name:       row.Name,
+		Prefix:     row.TokenPrefix,
+		Hash:       row.Hash,
+		Scopes:     scopes,
+		ExpiresAt:  row.ExpiresAt.Time,
+		LastUsedAt: row.LastUsedAt.Time,
+	}
+}
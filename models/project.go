@@ -5,6 +5,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 
 	"mortenvistisen/internal/storage"
@@ -23,6 +24,8 @@ type Project struct {
 	Description string
 	Content     string
 	ProjectURL  string
+	AuthorID    uuid.UUID
+	Rendered    RenderedContent
 }
 
 func FindProject(
@@ -60,6 +63,11 @@ type CreateProjectData struct {
 	Description string
 	Content     string
 	ProjectURL  string
+	AuthorID    uuid.UUID
+	// CreatedAt and UpdatedAt are only read by UpsertProject, so restored
+	// content keeps its original timestamps. Zero means now.
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
 
 func CreateProject(
@@ -80,6 +88,7 @@ func CreateProject(
 		Description: data.Description,
 		Content:     data.Content,
 		ProjectUrl:  pgtype.Text{String: data.ProjectURL, Valid: data.ProjectURL != ""},
+		AuthorID:    pgtype.UUID{Bytes: data.AuthorID, Valid: data.AuthorID != uuid.Nil},
 	}
 
 	row, err := queries.InsertProject(ctx, exec, params)
@@ -131,6 +140,26 @@ func UpdateProject(
 	return rowToProject(row), nil
 }
 
+// SetProjectRenderedContent stores the rendered content of a project. It
+// leaves updated_at alone, as the project itself has not changed.
+func SetProjectRenderedContent(
+	ctx context.Context,
+	exec storage.Executor,
+	id int32,
+	rendered RenderedContent,
+) (Project, error) {
+	row, err := queries.UpdateProjectContentHTML(ctx, exec, db.UpdateProjectContentHTMLParams{
+		ID:          id,
+		ContentHtml: rendered.HTML,
+		ContentHash: rendered.Hash,
+	})
+	if err != nil {
+		return Project{}, err
+	}
+
+	return rowToProject(row), nil
+}
+
 func DestroyProject(
 	ctx context.Context,
 	exec storage.Executor,
@@ -242,6 +271,8 @@ func UpsertProject(
 	}
 
 	params := db.UpsertProjectParams{
+		CreatedAt:   pgtype.Timestamptz{Time: data.CreatedAt, Valid: !data.CreatedAt.IsZero()},
+		UpdatedAt:   pgtype.Timestamptz{Time: data.UpdatedAt, Valid: !data.UpdatedAt.IsZero()},
 		Published:   data.Published,
 		Title:       data.Title,
 		Slug:        data.Slug,
@@ -250,6 +281,7 @@ func UpsertProject(
 		Description: data.Description,
 		Content:     data.Content,
 		ProjectUrl:  pgtype.Text{String: data.ProjectURL, Valid: data.ProjectURL != ""},
+		AuthorID:    pgtype.UUID{Bytes: data.AuthorID, Valid: data.AuthorID != uuid.Nil},
 	}
 
 	row, err := queries.UpsertProject(ctx, exec, params)
@@ -280,5 +312,7 @@ func rowToProject(row db.Project) Project {
 		Description: row.Description,
 		Content:     row.Content,
 		ProjectURL:  row.ProjectUrl.String,
+		AuthorID:    row.AuthorID.Bytes,
+		Rendered:    RenderedContent{HTML: row.ContentHtml, Hash: row.ContentHash},
 	}
 }
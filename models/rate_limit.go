@@ -0,0 +1,255 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models/internal/db"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type RateLimitBan struct {
+	ID        int32
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Scope     string
+	Key       string
+	Reason    string
+	ExpiresAt time.Time
+}
+
+type RecordRateLimitHitData struct {
+	Scope string `validate:"required,max=100"`
+	Key   string `validate:"required,max=255"`
+}
+
+func RecordRateLimitHit(
+	ctx context.Context,
+	exec storage.Executor,
+	data RecordRateLimitHitData,
+) error {
+	if err := Validate.Struct(data); err != nil {
+		return errors.Join(ErrDomainValidation, err)
+	}
+
+	return queries.InsertRateLimitHit(ctx, exec, db.InsertRateLimitHitParams{
+		Scope: data.Scope,
+		Key:   data.Key,
+	})
+}
+
+func CountRateLimitHitsSince(
+	ctx context.Context,
+	exec storage.Executor,
+	scope string,
+	key string,
+	since time.Time,
+) (int64, error) {
+	return queries.CountRateLimitHitsSince(
+		ctx,
+		exec,
+		db.CountRateLimitHitsSinceParams{
+			Scope: scope,
+			Key:   key,
+			Since: pgtype.Timestamptz{Time: since, Valid: true},
+		},
+	)
+}
+
+func PruneRateLimitHits(
+	ctx context.Context,
+	exec storage.Executor,
+	scope string,
+	key string,
+	before time.Time,
+) error {
+	return queries.DeleteRateLimitHitsBefore(
+		ctx,
+		exec,
+		db.DeleteRateLimitHitsBeforeParams{
+			Scope:  scope,
+			Key:    key,
+			Before: pgtype.Timestamptz{Time: before, Valid: true},
+		},
+	)
+}
+
+// DestroyRateLimitHitsBefore removes the hits of every scope and key older
+// than the given time and reports how many were deleted.
+func DestroyRateLimitHitsBefore(
+	ctx context.Context,
+	exec storage.Executor,
+	before time.Time,
+) (int64, error) {
+	return queries.DeleteAllRateLimitHitsBefore(
+		ctx,
+		exec,
+		pgtype.Timestamptz{Time: before, Valid: true},
+	)
+}
+
+func ClearRateLimitHits(
+	ctx context.Context,
+	exec storage.Executor,
+	scope string,
+	key string,
+) error {
+	return queries.DeleteRateLimitHits(ctx, exec, db.DeleteRateLimitHitsParams{
+		Scope: scope,
+		Key:   key,
+	})
+}
+
+func FindRateLimitBan(
+	ctx context.Context,
+	exec storage.Executor,
+	id int32,
+) (RateLimitBan, error) {
+	row, err := queries.QueryRateLimitBanByID(ctx, exec, id)
+	if err != nil {
+		return RateLimitBan{}, err
+	}
+
+	return rowToRateLimitBan(row), nil
+}
+
+func FindActiveRateLimitBan(
+	ctx context.Context,
+	exec storage.Executor,
+	scope string,
+	key string,
+) (RateLimitBan, error) {
+	row, err := queries.QueryActiveRateLimitBan(
+		ctx,
+		exec,
+		db.QueryActiveRateLimitBanParams{
+			Scope: scope,
+			Key:   key,
+		},
+	)
+	if err != nil {
+		return RateLimitBan{}, err
+	}
+
+	return rowToRateLimitBan(row), nil
+}
+
+type UpsertRateLimitBanData struct {
+	Scope     string    `validate:"required,max=100"`
+	Key       string    `validate:"required,max=255"`
+	Reason    string    `validate:"required,max=255"`
+	ExpiresAt time.Time `validate:"required"`
+}
+
+func UpsertRateLimitBan(
+	ctx context.Context,
+	exec storage.Executor,
+	data UpsertRateLimitBanData,
+) (RateLimitBan, error) {
+	if err := Validate.Struct(data); err != nil {
+		return RateLimitBan{}, errors.Join(ErrDomainValidation, err)
+	}
+
+	row, err := queries.UpsertRateLimitBan(ctx, exec, db.UpsertRateLimitBanParams{
+		Scope:     data.Scope,
+		Key:       data.Key,
+		Reason:    data.Reason,
+		ExpiresAt: pgtype.Timestamptz{Time: data.ExpiresAt, Valid: true},
+	})
+	if err != nil {
+		return RateLimitBan{}, err
+	}
+
+	return rowToRateLimitBan(row), nil
+}
+
+func DestroyRateLimitBan(
+	ctx context.Context,
+	exec storage.Executor,
+	id int32,
+) error {
+	return queries.DeleteRateLimitBan(ctx, exec, id)
+}
+
+// DestroyExpiredRateLimitBans removes bans that have run out and reports
+// how many were deleted.
+func DestroyExpiredRateLimitBans(
+	ctx context.Context,
+	exec storage.Executor,
+) (int64, error) {
+	return queries.DeleteExpiredRateLimitBans(ctx, exec)
+}
+
+type PaginatedRateLimitBans struct {
+	Bans       []RateLimitBan
+	TotalCount int64
+	Page       int64
+	PageSize   int64
+	TotalPages int64
+}
+
+func PaginateActiveRateLimitBans(
+	ctx context.Context,
+	exec storage.Executor,
+	page int64,
+	pageSize int64,
+) (PaginatedRateLimitBans, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	offset := (page - 1) * pageSize
+
+	totalCount, err := queries.CountActiveRateLimitBans(ctx, exec)
+	if err != nil {
+		return PaginatedRateLimitBans{}, err
+	}
+
+	rows, err := queries.QueryPaginatedActiveRateLimitBans(
+		ctx,
+		exec,
+		db.QueryPaginatedActiveRateLimitBansParams{
+			Limit:  pageSize,
+			Offset: offset,
+		},
+	)
+	if err != nil {
+		return PaginatedRateLimitBans{}, err
+	}
+
+	bans := make([]RateLimitBan, len(rows))
+	for i, row := range rows {
+		bans[i] = rowToRateLimitBan(row)
+	}
+
+	totalPages := (totalCount + int64(pageSize) - 1) / int64(pageSize)
+
+	return PaginatedRateLimitBans{
+		Bans:       bans,
+		TotalCount: totalCount,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func rowToRateLimitBan(row db.RateLimitBan) RateLimitBan {
+	return RateLimitBan{
+		ID:        row.ID,
+		CreatedAt: row.CreatedAt.Time,
+		UpdatedAt: row.UpdatedAt.Time,
+		Scope:     row.Scope,
+		Key:       row.Key,
+		Reason:    row.Reason,
+		ExpiresAt: row.ExpiresAt.Time,
+	}
+}
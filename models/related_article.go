@@ -0,0 +1,64 @@
+package models
+
+import (
+	"context"
+
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models/internal/db"
+)
+
+// RelatedArticle is a precomputed recommendation from one article to
+// another. Higher scores are closer matches.
+type RelatedArticle struct {
+	ArticleID        int32
+	RelatedArticleID int32
+	Score            float64
+}
+
+// ReplaceRelatedArticles swaps every stored recommendation for relations.
+// Run it in a transaction so readers never see a half written set.
+func ReplaceRelatedArticles(
+	ctx context.Context,
+	exec storage.Executor,
+	relations []RelatedArticle,
+) error {
+	if err := queries.DeleteAllRelatedArticles(ctx, exec); err != nil {
+		return err
+	}
+
+	for _, relation := range relations {
+		if err := queries.InsertRelatedArticle(ctx, exec, db.InsertRelatedArticleParams{
+			ArticleID:        relation.ArticleID,
+			RelatedArticleID: relation.RelatedArticleID,
+			Score:            relation.Score,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RelatedArticlesFor returns up to limit published articles recommended for
+// the article, best match first.
+func RelatedArticlesFor(
+	ctx context.Context,
+	exec storage.Executor,
+	articleID int32,
+	limit int64,
+) ([]Article, error) {
+	rows, err := queries.QueryRelatedArticles(ctx, exec, db.QueryRelatedArticlesParams{
+		ArticleID: articleID,
+		Limit:     limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	articles := make([]Article, len(rows))
+	for i, row := range rows {
+		articles[i] = rowToArticle(row.Article)
+	}
+
+	return articles, nil
+}
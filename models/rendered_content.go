@@ -0,0 +1,50 @@
+package models
+
+import (
+	"encoding/json"
+	"log/slog"
+)
+
+// RenderedContent is the markdown content of an article, project or
+// newsletter rendered to HTML ahead of time. Hash identifies the content
+// and renderer it was rendered from; it is empty until the content has
+// been rendered once.
+type RenderedContent struct {
+	HTML string
+	Hash string
+	// Headings are kept for articles only, which show them as a table of
+	// contents.
+	Headings []Heading
+}
+
+// Heading is an entry in the table of contents of rendered content.
+// Children are the headings nested under it.
+type Heading struct {
+	Level    int       `json:"level"`
+	ID       string    `json:"id"`
+	Text     string    `json:"text"`
+	Children []Heading `json:"children,omitempty"`
+}
+
+func headingsToJSON(headings []Heading) ([]byte, error) {
+	if headings == nil {
+		headings = []Heading{}
+	}
+
+	return json.Marshal(headings)
+}
+
+// headingsFromJSON reads stored headings. Headings that cannot be read are
+// dropped; the next render stores them again.
+func headingsFromJSON(data []byte) []Heading {
+	var headings []Heading
+	if err := json.Unmarshal(data, &headings); err != nil {
+		slog.Error("failed to read stored headings", "error", err)
+		return nil
+	}
+	if len(headings) == 0 {
+		return nil
+	}
+
+	return headings
+}
@@ -0,0 +1,139 @@
+package models
+
+import (
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+type Role string
+
+// RoleMember is the default for self registered accounts and grants no
+// access to the admin area.
+const (
+	RoleAdmin  Role = "admin"
+	RoleEditor Role = "editor"
+	RoleAuthor Role = "author"
+	RoleMember Role = "member"
+)
+
+var Roles = []Role{RoleAdmin, RoleEditor, RoleAuthor, RoleMember}
+
+type Permission string
+
+const (
+	PermissionAccessAdmin         Permission = "admin:access"
+	PermissionWriteArticles       Permission = "articles:write"
+	PermissionPublishArticles     Permission = "articles:publish"
+	PermissionManageAllArticles   Permission = "articles:manage_all"
+	PermissionWriteProjects       Permission = "projects:write"
+	PermissionPublishProjects     Permission = "projects:publish"
+	PermissionManageAllProjects   Permission = "projects:manage_all"
+	PermissionManageTags          Permission = "tags:manage"
+	PermissionManageNewsletters   Permission = "newsletters:manage"
+	PermissionManageSubscribers   Permission = "subscribers:manage"
+	PermissionManageUsers         Permission = "users:manage"
+	PermissionViewAuditLog        Permission = "audit_log:view"
+	PermissionExportContent       Permission = "content:export"
+	PermissionManageWebhooks      Permission = "webhooks:manage"
+	PermissionModerateWebmentions Permission = "webmentions:moderate"
+	PermissionManageSeries        Permission = "series:manage"
+	PermissionModerateComments    Permission = "comments:moderate"
+	PermissionUploadMedia         Permission = "media:upload"
+	PermissionManageAllMedia      Permission = "media:manage_all"
+	PermissionCheckLinks          Permission = "links:check"
+)
+
+var rolePermissions = map[Role][]Permission{
+	RoleAdmin: {
+		PermissionAccessAdmin,
+		PermissionWriteArticles,
+		PermissionPublishArticles,
+		PermissionManageAllArticles,
+		PermissionWriteProjects,
+		PermissionPublishProjects,
+		PermissionManageAllProjects,
+		PermissionManageTags,
+		PermissionManageNewsletters,
+		PermissionManageSubscribers,
+		PermissionManageUsers,
+		PermissionViewAuditLog,
+		PermissionExportContent,
+		PermissionManageWebhooks,
+		PermissionModerateWebmentions,
+		PermissionManageSeries,
+		PermissionModerateComments,
+		PermissionUploadMedia,
+		PermissionManageAllMedia,
+		PermissionCheckLinks,
+	},
+	RoleEditor: {
+		PermissionAccessAdmin,
+		PermissionWriteArticles,
+		PermissionPublishArticles,
+		PermissionManageAllArticles,
+		PermissionWriteProjects,
+		PermissionPublishProjects,
+		PermissionManageAllProjects,
+		PermissionManageTags,
+		PermissionManageNewsletters,
+		PermissionManageSubscribers,
+		PermissionExportContent,
+		PermissionModerateWebmentions,
+		PermissionManageSeries,
+		PermissionModerateComments,
+		PermissionUploadMedia,
+		PermissionManageAllMedia,
+		PermissionCheckLinks,
+	},
+	RoleAuthor: {
+		PermissionAccessAdmin,
+		PermissionWriteArticles,
+		PermissionWriteProjects,
+		PermissionUploadMedia,
+	},
+}
+
+func (r Role) Valid() bool {
+	return slices.Contains(Roles, r)
+}
+
+func (r Role) Can(permission Permission) bool {
+	return slices.Contains(rolePermissions[r], permission)
+}
+
+// CanManageArticle reports whether a user may edit or delete the article.
+// Authors are limited to their own articles.
+func CanManageArticle(role Role, userID uuid.UUID, article Article) bool {
+	if role.Can(PermissionManageAllArticles) {
+		return true
+	}
+
+	return role.Can(PermissionWriteArticles) &&
+		article.AuthorID != uuid.Nil &&
+		article.AuthorID == userID
+}
+
+// CanManageProject reports whether a user may edit or delete the project.
+// Authors are limited to their own projects.
+func CanManageProject(role Role, userID uuid.UUID, project Project) bool {
+	if role.Can(PermissionManageAllProjects) {
+		return true
+	}
+
+	return role.Can(PermissionWriteProjects) &&
+		project.AuthorID != uuid.Nil &&
+		project.AuthorID == userID
+}
+
+// CanManageMedia reports whether a user may edit or delete the media.
+// Authors are limited to their own uploads.
+func CanManageMedia(role Role, userID uuid.UUID, media Media) bool {
+	if role.Can(PermissionManageAllMedia) {
+		return true
+	}
+
+	return role.Can(PermissionUploadMedia) &&
+		media.UploadedBy != uuid.Nil &&
+		media.UploadedBy == userID
+}
@@ -0,0 +1,118 @@
+package models_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"mortenvistisen/models"
+)
+
+var allPermissions = []models.Permission{
+	models.PermissionAccessAdmin,
+	models.PermissionWriteArticles,
+	models.PermissionPublishArticles,
+	models.PermissionManageAllArticles,
+	models.PermissionWriteProjects,
+	models.PermissionPublishProjects,
+	models.PermissionManageAllProjects,
+	models.PermissionManageTags,
+	models.PermissionManageNewsletters,
+	models.PermissionManageSubscribers,
+	models.PermissionManageUsers,
+	models.PermissionViewAuditLog,
+	models.PermissionExportContent,
+	models.PermissionManageWebhooks,
+	models.PermissionModerateWebmentions,
+	models.PermissionManageSeries,
+	models.PermissionModerateComments,
+	models.PermissionUploadMedia,
+	models.PermissionManageAllMedia,
+	models.PermissionCheckLinks,
+}
+
+func TestRoleCan(t *testing.T) {
+	granted := map[models.Role][]models.Permission{
+		models.RoleAdmin: allPermissions,
+		models.RoleEditor: {
+			models.PermissionAccessAdmin,
+			models.PermissionWriteArticles,
+			models.PermissionPublishArticles,
+			models.PermissionManageAllArticles,
+			models.PermissionWriteProjects,
+			models.PermissionPublishProjects,
+			models.PermissionManageAllProjects,
+			models.PermissionManageTags,
+			models.PermissionManageNewsletters,
+			models.PermissionManageSubscribers,
+			models.PermissionExportContent,
+			models.PermissionModerateWebmentions,
+			models.PermissionManageSeries,
+			models.PermissionModerateComments,
+			models.PermissionUploadMedia,
+			models.PermissionManageAllMedia,
+			models.PermissionCheckLinks,
+		},
+		models.RoleAuthor: {
+			models.PermissionAccessAdmin,
+			models.PermissionWriteArticles,
+			models.PermissionWriteProjects,
+			models.PermissionUploadMedia,
+		},
+		models.RoleMember:      nil,
+		models.Role("unknown"): nil,
+	}
+
+	for role, permissions := range granted {
+		for _, permission := range allPermissions {
+			t.Run(string(role)+"/"+string(permission), func(t *testing.T) {
+				want := slices.Contains(permissions, permission)
+				if got := role.Can(permission); got != want {
+					t.Errorf("expected %v, got %v", want, got)
+				}
+			})
+		}
+	}
+}
+
+func TestCanManageArticle(t *testing.T) {
+	userID := uuid.New()
+	own := models.Article{AuthorID: userID}
+	others := models.Article{AuthorID: uuid.New()}
+	unowned := models.Article{}
+
+	tests := map[string]struct {
+		role    models.Role
+		userID  uuid.UUID
+		article models.Article
+		want    bool
+	}{
+		"admin manages another author's article": {
+			role: models.RoleAdmin, userID: userID, article: others, want: true,
+		},
+		"editor manages another author's article": {
+			role: models.RoleEditor, userID: userID, article: others, want: true,
+		},
+		"author manages their own article": {
+			role: models.RoleAuthor, userID: userID, article: own, want: true,
+		},
+		"author cannot manage another author's article": {
+			role: models.RoleAuthor, userID: userID, article: others,
+		},
+		"author cannot manage an article without an author": {
+			role: models.RoleAuthor, userID: uuid.Nil, article: unowned,
+		},
+		"member cannot manage their own article": {
+			role: models.RoleMember, userID: userID, article: own,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := models.CanManageArticle(tt.role, tt.userID, tt.article); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,383 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gosimple/slug"
+	"github.com/jackc/pgx/v5"
+
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models/internal/db"
+)
+
+var ErrArticleNotInSeries = errors.New("article is not part of the series")
+
+// Series groups articles into an ordered, multi-part collection.
+type Series struct {
+	ID          int32
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Title       string
+	Slug        string
+	Description string
+	Published   bool
+}
+
+// SeriesEntry is an article at its position in a series.
+type SeriesEntry struct {
+	Position int32
+	Article  Article
+}
+
+// SeriesNavigation places an article within its series for readers: which
+// part it is and what comes before and after. Only published articles count.
+type SeriesNavigation struct {
+	Series   Series
+	Part     int
+	Total    int
+	Previous *Article
+	Next     *Article
+}
+
+func FindSeries(
+	ctx context.Context,
+	exec storage.Executor,
+	id int32,
+) (Series, error) {
+	row, err := queries.QuerySeriesByID(ctx, exec, id)
+	if err != nil {
+		return Series{}, err
+	}
+
+	return rowToSeries(row), nil
+}
+
+func FindSeriesBySlug(
+	ctx context.Context,
+	exec storage.Executor,
+	slug string,
+) (Series, error) {
+	row, err := queries.QuerySeriesBySlug(ctx, exec, slug)
+	if err != nil {
+		return Series{}, err
+	}
+
+	return rowToSeries(row), nil
+}
+
+func AllSeries(
+	ctx context.Context,
+	exec storage.Executor,
+) ([]Series, error) {
+	rows, err := queries.QuerySeries(ctx, exec)
+	if err != nil {
+		return nil, err
+	}
+
+	series := make([]Series, len(rows))
+	for i, row := range rows {
+		series[i] = rowToSeries(row)
+	}
+
+	return series, nil
+}
+
+func AllPublishedSeries(
+	ctx context.Context,
+	exec storage.Executor,
+) ([]Series, error) {
+	rows, err := queries.QueryPublishedSeries(ctx, exec)
+	if err != nil {
+		return nil, err
+	}
+
+	series := make([]Series, len(rows))
+	for i, row := range rows {
+		series[i] = rowToSeries(row)
+	}
+
+	return series, nil
+}
+
+type CreateSeriesData struct {
+	Title       string `validate:"required,max=255"`
+	Slug        string `validate:"max=255"`
+	Description string
+	Published   bool
+}
+
+func CreateSeries(
+	ctx context.Context,
+	exec storage.Executor,
+	data CreateSeriesData,
+) (Series, error) {
+	if err := Validate.Struct(data); err != nil {
+		return Series{}, errors.Join(ErrDomainValidation, err)
+	}
+
+	row, err := queries.InsertSeries(ctx, exec, db.InsertSeriesParams{
+		Title:       strings.TrimSpace(data.Title),
+		Slug:        seriesSlug(data.Slug, data.Title),
+		Description: strings.TrimSpace(data.Description),
+		Published:   data.Published,
+	})
+	if err != nil {
+		return Series{}, err
+	}
+
+	return rowToSeries(row), nil
+}
+
+type UpdateSeriesData struct {
+	ID          int32  `validate:"required"`
+	Title       string `validate:"required,max=255"`
+	Slug        string `validate:"max=255"`
+	Description string
+	Published   bool
+}
+
+func UpdateSeries(
+	ctx context.Context,
+	exec storage.Executor,
+	data UpdateSeriesData,
+) (Series, error) {
+	if err := Validate.Struct(data); err != nil {
+		return Series{}, errors.Join(ErrDomainValidation, err)
+	}
+
+	row, err := queries.UpdateSeries(ctx, exec, db.UpdateSeriesParams{
+		ID:          data.ID,
+		Title:       strings.TrimSpace(data.Title),
+		Slug:        seriesSlug(data.Slug, data.Title),
+		Description: strings.TrimSpace(data.Description),
+		Published:   data.Published,
+	})
+	if err != nil {
+		return Series{}, err
+	}
+
+	return rowToSeries(row), nil
+}
+
+func DestroySeries(
+	ctx context.Context,
+	exec storage.Executor,
+	id int32,
+) error {
+	return queries.DeleteSeries(ctx, exec, id)
+}
+
+// SeriesArticles returns every article in the series in reading order,
+// published or not.
+func SeriesArticles(
+	ctx context.Context,
+	exec storage.Executor,
+	seriesID int32,
+) ([]SeriesEntry, error) {
+	rows, err := queries.QuerySeriesArticles(ctx, exec, seriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]SeriesEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = SeriesEntry{
+			Position: row.Position,
+			Article:  rowToArticle(row.Article),
+		}
+	}
+
+	return entries, nil
+}
+
+// PublishedSeriesArticles returns the published articles of the series in
+// reading order.
+func PublishedSeriesArticles(
+	ctx context.Context,
+	exec storage.Executor,
+	seriesID int32,
+) ([]Article, error) {
+	entries, err := SeriesArticles(ctx, exec, seriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	articles := make([]Article, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Article.Published {
+			articles = append(articles, entry.Article)
+		}
+	}
+
+	return articles, nil
+}
+
+// FindSeriesForArticle returns the series the article belongs to.
+func FindSeriesForArticle(
+	ctx context.Context,
+	exec storage.Executor,
+	articleID int32,
+) (Series, error) {
+	row, err := queries.QuerySeriesForArticle(ctx, exec, articleID)
+	if err != nil {
+		return Series{}, err
+	}
+
+	return rowToSeries(row), nil
+}
+
+// ArticleSeriesNavigation reports where a published article sits in its
+// published series. ok is false when there is no navigation to show.
+func ArticleSeriesNavigation(
+	ctx context.Context,
+	exec storage.Executor,
+	articleID int32,
+) (SeriesNavigation, bool, error) {
+	series, err := FindSeriesForArticle(ctx, exec, articleID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return SeriesNavigation{}, false, nil
+		}
+		return SeriesNavigation{}, false, err
+	}
+	if !series.Published {
+		return SeriesNavigation{}, false, nil
+	}
+
+	articles, err := PublishedSeriesArticles(ctx, exec, series.ID)
+	if err != nil {
+		return SeriesNavigation{}, false, err
+	}
+
+	index := slices.IndexFunc(articles, func(a Article) bool { return a.ID == articleID })
+	if index < 0 {
+		return SeriesNavigation{}, false, nil
+	}
+
+	nav := SeriesNavigation{
+		Series: series,
+		Part:   index + 1,
+		Total:  len(articles),
+	}
+	if index > 0 {
+		nav.Previous = &articles[index-1]
+	}
+	if index < len(articles)-1 {
+		nav.Next = &articles[index+1]
+	}
+
+	return nav, true, nil
+}
+
+// AddArticleToSeries appends the article as the last part of the series.
+func AddArticleToSeries(
+	ctx context.Context,
+	exec storage.Executor,
+	seriesID int32,
+	articleID int32,
+) error {
+	return queries.InsertSeriesArticle(ctx, exec, db.InsertSeriesArticleParams{
+		SeriesID:  seriesID,
+		ArticleID: articleID,
+	})
+}
+
+// RemoveArticleFromSeries takes the article out of the series and closes the
+// gap it leaves in the numbering.
+func RemoveArticleFromSeries(
+	ctx context.Context,
+	exec storage.Executor,
+	seriesID int32,
+	articleID int32,
+) error {
+	if err := queries.DeleteSeriesArticle(ctx, exec, db.DeleteSeriesArticleParams{
+		SeriesID:  seriesID,
+		ArticleID: articleID,
+	}); err != nil {
+		return err
+	}
+
+	entries, err := SeriesArticles(ctx, exec, seriesID)
+	if err != nil {
+		return err
+	}
+
+	return writeSeriesOrder(ctx, exec, seriesID, entries)
+}
+
+// MoveSeriesArticle moves the article offset places through the series,
+// negative towards the start. Moves past either end stop there.
+func MoveSeriesArticle(
+	ctx context.Context,
+	exec storage.Executor,
+	seriesID int32,
+	articleID int32,
+	offset int,
+) error {
+	entries, err := SeriesArticles(ctx, exec, seriesID)
+	if err != nil {
+		return err
+	}
+
+	from := slices.IndexFunc(entries, func(e SeriesEntry) bool { return e.Article.ID == articleID })
+	if from < 0 {
+		return ErrArticleNotInSeries
+	}
+	to := min(max(from+offset, 0), len(entries)-1)
+
+	entry := entries[from]
+	entries = slices.Delete(entries, from, from+1)
+	entries = slices.Insert(entries, to, entry)
+
+	return writeSeriesOrder(ctx, exec, seriesID, entries)
+}
+
+// writeSeriesOrder numbers entries from one in the order given, touching
+// only the rows whose position changes.
+func writeSeriesOrder(
+	ctx context.Context,
+	exec storage.Executor,
+	seriesID int32,
+	entries []SeriesEntry,
+) error {
+	for i, entry := range entries {
+		position := int32(i + 1)
+		if entry.Position == position {
+			continue
+		}
+
+		if err := queries.UpdateSeriesArticlePosition(ctx, exec, db.UpdateSeriesArticlePositionParams{
+			SeriesID:  seriesID,
+			ArticleID: entry.Article.ID,
+			Position:  position,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func seriesSlug(explicit string, title string) string {
+	if s := strings.TrimSpace(explicit); s != "" {
+		return slug.Make(s)
+	}
+
+	return slug.Make(title)
+}
+
+func rowToSeries(row db.Series) Series {
+	return Series{
+		ID:          row.ID,
+		CreatedAt:   row.CreatedAt.Time,
+		UpdatedAt:   row.UpdatedAt.Time,
+		Title:       row.Title,
+		Slug:        row.Slug,
+		Description: row.Description,
+		Published:   row.Published,
+	}
+}
@@ -3,17 +3,42 @@ package models
 import (
 	"context"
 	"errors"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/gosimple/slug"
+	"github.com/jackc/pgx/v5/pgtype"
+
 	"mortenvistisen/internal/storage"
 	"mortenvistisen/models/internal/db"
 )
 
+var (
+	ErrTagCycle          = errors.New("a tag cannot be nested under itself or its descendants")
+	ErrTagParentNotFound = errors.New("parent tag does not exist")
+	ErrMergeTagIntoSelf  = errors.New("a tag cannot be merged into itself")
+)
+
 type Tag struct {
-	ID        int32
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	Title     string
+	ID              int32
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	Title           string
+	Slug            string
+	Description     string
+	MetaTitle       string
+	MetaDescription string
+	// ParentID is zero for top level tags. A tag's page also lists the
+	// articles of the tags nested under it.
+	ParentID int32
+}
+
+// PublishedTag is a tag with at least one published article. LastArticleUpdate
+// is when the newest of those articles last changed.
+type PublishedTag struct {
+	Tag
+	LastArticleUpdate time.Time
 }
 
 func FindTag(
@@ -29,8 +54,86 @@ func FindTag(
 	return rowToTag(row), nil
 }
 
+func FindTagBySlug(
+	ctx context.Context,
+	exec storage.Executor,
+	slug string,
+) (Tag, error) {
+	row, err := queries.QueryTagBySlug(ctx, exec, slug)
+	if err != nil {
+		return Tag{}, err
+	}
+
+	return rowToTag(row), nil
+}
+
+// TagsForArticle returns the article's tags ordered by title.
+func TagsForArticle(
+	ctx context.Context,
+	exec storage.Executor,
+	articleID int32,
+) ([]Tag, error) {
+	rows, err := queries.QueryTagsForArticle(ctx, exec, articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]Tag, len(rows))
+	for i, row := range rows {
+		tags[i] = rowToTag(row)
+	}
+
+	return tags, nil
+}
+
+// AllPublishedTags returns the tags that have a public page, which are the
+// ones with published articles.
+func AllPublishedTags(
+	ctx context.Context,
+	exec storage.Executor,
+) ([]PublishedTag, error) {
+	rows, err := queries.QueryPublishedTags(ctx, exec)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]PublishedTag, len(rows))
+	for i, row := range rows {
+		tags[i] = PublishedTag{
+			Tag:               rowToTag(row.Tag),
+			LastArticleUpdate: row.LastArticleUpdate.Time,
+		}
+	}
+
+	return tags, nil
+}
+
+// ChildTags returns the tags nested directly under the tag, ordered by title.
+func ChildTags(
+	ctx context.Context,
+	exec storage.Executor,
+	parentID int32,
+) ([]Tag, error) {
+	rows, err := queries.QueryChildTags(ctx, exec, pgtype.Int4{Int32: parentID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]Tag, len(rows))
+	for i, row := range rows {
+		tags[i] = rowToTag(row)
+	}
+
+	return tags, nil
+}
+
 type CreateTagData struct {
-	Title string
+	Title           string `validate:"required,max=255"`
+	Slug            string `validate:"max=255"`
+	Description     string
+	MetaTitle       string `validate:"max=255"`
+	MetaDescription string `validate:"max=255"`
+	ParentID        int32
 }
 
 func CreateTag(
@@ -41,7 +144,18 @@ func CreateTag(
 	if err := Validate.Struct(data); err != nil {
 		return Tag{}, errors.Join(ErrDomainValidation, err)
 	}
-	row, err := queries.InsertTag(ctx, exec, data.Title)
+	if err := validateTagParent(ctx, exec, 0, data.ParentID); err != nil {
+		return Tag{}, err
+	}
+
+	row, err := queries.InsertTag(ctx, exec, db.InsertTagParams{
+		Title:           strings.TrimSpace(data.Title),
+		Slug:            tagSlug(data.Slug, data.Title),
+		Description:     strings.TrimSpace(data.Description),
+		MetaTitle:       strings.TrimSpace(data.MetaTitle),
+		MetaDescription: strings.TrimSpace(data.MetaDescription),
+		ParentID:        tagParentID(data.ParentID),
+	})
 	if err != nil {
 		return Tag{}, err
 	}
@@ -49,10 +163,17 @@ func CreateTag(
 	return rowToTag(row), nil
 }
 
+// UpdateTagData replaces every field of the tag. An empty Slug keeps the
+// current one so renaming a tag does not break links to its page.
 type UpdateTagData struct {
-	ID        int32
-	UpdatedAt time.Time
-	Title     string
+	ID              int32
+	UpdatedAt       time.Time
+	Title           string `validate:"required,max=255"`
+	Slug            string `validate:"max=255"`
+	Description     string
+	MetaTitle       string `validate:"max=255"`
+	MetaDescription string `validate:"max=255"`
+	ParentID        int32
 }
 
 func UpdateTag(
@@ -64,9 +185,27 @@ func UpdateTag(
 		return Tag{}, errors.Join(ErrDomainValidation, err)
 	}
 
+	current, err := queries.QueryTagByID(ctx, exec, data.ID)
+	if err != nil {
+		return Tag{}, err
+	}
+	if err := validateTagParent(ctx, exec, data.ID, data.ParentID); err != nil {
+		return Tag{}, err
+	}
+
+	newSlug := current.Slug
+	if strings.TrimSpace(data.Slug) != "" {
+		newSlug = slug.Make(data.Slug)
+	}
+
 	params := db.UpdateTagParams{
-		ID:    data.ID,
-		Title: data.Title,
+		ID:              data.ID,
+		Title:           strings.TrimSpace(data.Title),
+		Slug:            newSlug,
+		Description:     strings.TrimSpace(data.Description),
+		MetaTitle:       strings.TrimSpace(data.MetaTitle),
+		MetaDescription: strings.TrimSpace(data.MetaDescription),
+		ParentID:        tagParentID(data.ParentID),
 	}
 
 	row, err := queries.UpdateTag(ctx, exec, params)
@@ -77,6 +216,8 @@ func UpdateTag(
 	return rowToTag(row), nil
 }
 
+// DestroyTag deletes the tag. Its article connections go with it, and the
+// tags nested under it move to the top level.
 func DestroyTag(
 	ctx context.Context,
 	exec storage.Executor,
@@ -85,6 +226,105 @@ func DestroyTag(
 	return queries.DeleteTag(ctx, exec, id)
 }
 
+// MergeTags folds source into target: source's articles are tagged with
+// target, articles that had both keep a single connection, tags nested
+// under source move under target, and source is deleted. Run it in a
+// transaction.
+func MergeTags(
+	ctx context.Context,
+	exec storage.Executor,
+	sourceID int32,
+	targetID int32,
+) (Tag, error) {
+	if sourceID == targetID {
+		return Tag{}, ErrMergeTagIntoSelf
+	}
+
+	source, err := queries.QueryTagByID(ctx, exec, sourceID)
+	if err != nil {
+		return Tag{}, err
+	}
+	target, err := queries.QueryTagByID(ctx, exec, targetID)
+	if err != nil {
+		return Tag{}, err
+	}
+
+	// A target nested somewhere under source takes source's place first, or
+	// moving source's children under it could close a loop.
+	ancestors, err := queries.QueryTagAncestorIDs(ctx, exec, targetID)
+	if err != nil {
+		return Tag{}, err
+	}
+	if slices.Contains(ancestors, sourceID) {
+		target, err = queries.UpdateTag(ctx, exec, db.UpdateTagParams{
+			ID:              target.ID,
+			Title:           target.Title,
+			Slug:            target.Slug,
+			Description:     target.Description,
+			MetaTitle:       target.MetaTitle,
+			MetaDescription: target.MetaDescription,
+			ParentID:        source.ParentID,
+		})
+		if err != nil {
+			return Tag{}, err
+		}
+	}
+
+	if err := queries.MoveArticleTagConnections(ctx, exec, db.MoveArticleTagConnectionsParams{
+		SourceID: sourceID,
+		TargetID: targetID,
+	}); err != nil {
+		return Tag{}, err
+	}
+	if err := queries.DeleteArticleTagConnectionsForTag(ctx, exec, sourceID); err != nil {
+		return Tag{}, err
+	}
+	if err := queries.ReparentTags(ctx, exec, db.ReparentTagsParams{
+		NewParentID: tagParentID(targetID),
+		OldParentID: sourceID,
+	}); err != nil {
+		return Tag{}, err
+	}
+	if err := queries.DeleteTag(ctx, exec, sourceID); err != nil {
+		return Tag{}, err
+	}
+
+	return rowToTag(target), nil
+}
+
+// validateTagParent checks that tagID can be nested under parentID. tagID is
+// zero for tags not created yet.
+func validateTagParent(
+	ctx context.Context,
+	exec storage.Executor,
+	tagID int32,
+	parentID int32,
+) error {
+	if parentID == 0 {
+		return nil
+	}
+	if parentID == tagID {
+		return ErrTagCycle
+	}
+
+	ancestors, err := queries.QueryTagAncestorIDs(ctx, exec, parentID)
+	if err != nil {
+		return err
+	}
+	if len(ancestors) == 0 {
+		return ErrTagParentNotFound
+	}
+	if tagID != 0 && slices.Contains(ancestors, tagID) {
+		return ErrTagCycle
+	}
+
+	return nil
+}
+
+func tagParentID(parentID int32) pgtype.Int4 {
+	return pgtype.Int4{Int32: parentID, Valid: parentID != 0}
+}
+
 func AllTags(
 	ctx context.Context,
 	exec storage.Executor,
@@ -169,7 +409,13 @@ func UpsertTag(
 	if err := Validate.Struct(data); err != nil {
 		return Tag{}, errors.Join(ErrDomainValidation, err)
 	}
-	row, err := queries.UpsertTag(ctx, exec, data.Title)
+	row, err := queries.UpsertTag(ctx, exec, db.UpsertTagParams{
+		Title:           strings.TrimSpace(data.Title),
+		Slug:            tagSlug(data.Slug, data.Title),
+		Description:     strings.TrimSpace(data.Description),
+		MetaTitle:       strings.TrimSpace(data.MetaTitle),
+		MetaDescription: strings.TrimSpace(data.MetaDescription),
+	})
 	if err != nil {
 		return Tag{}, err
 	}
@@ -177,11 +423,89 @@ func UpsertTag(
 	return rowToTag(row), nil
 }
 
+type PaginatedTagArticles struct {
+	Tag        Tag
+	Articles   []Article
+	TotalCount int64
+	Page       int64
+	PageSize   int64
+	TotalPages int64
+}
+
+// PaginateTagArticles pages through the tag's published articles, newest
+// first.
+func PaginateTagArticles(
+	ctx context.Context,
+	exec storage.Executor,
+	tag Tag,
+	page int64,
+	pageSize int64,
+) (PaginatedTagArticles, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	offset := (page - 1) * pageSize
+
+	totalCount, err := queries.CountPublishedArticlesForTag(ctx, exec, tag.ID)
+	if err != nil {
+		return PaginatedTagArticles{}, err
+	}
+
+	rows, err := queries.QueryPaginatedPublishedArticlesForTag(
+		ctx,
+		exec,
+		db.QueryPaginatedPublishedArticlesForTagParams{
+			TagID:  tag.ID,
+			Limit:  pageSize,
+			Offset: offset,
+		},
+	)
+	if err != nil {
+		return PaginatedTagArticles{}, err
+	}
+
+	articles := make([]Article, len(rows))
+	for i, row := range rows {
+		articles[i] = rowToArticle(row)
+	}
+
+	totalPages := (totalCount + int64(pageSize) - 1) / int64(pageSize)
+
+	return PaginatedTagArticles{
+		Tag:        tag,
+		Articles:   articles,
+		TotalCount: totalCount,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func tagSlug(explicit string, title string) string {
+	if s := strings.TrimSpace(explicit); s != "" {
+		return slug.Make(s)
+	}
+
+	return slug.Make(title)
+}
+
 func rowToTag(row db.Tag) Tag {
 	return Tag{
-		ID:        row.ID,
-		CreatedAt: row.CreatedAt.Time,
-		UpdatedAt: row.UpdatedAt.Time,
-		Title:     row.Title,
+		ID:              row.ID,
+		CreatedAt:       row.CreatedAt.Time,
+		UpdatedAt:       row.UpdatedAt.Time,
+		Title:           row.Title,
+		Slug:            row.Slug,
+		Description:     row.Description,
+		MetaTitle:       row.MetaTitle,
+		MetaDescription: row.MetaDescription,
+		ParentID:        row.ParentID.Int32,
 	}
 }
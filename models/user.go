@@ -27,12 +27,18 @@ type User struct {
 	EmailValidatedAt time.Time
 	Password         []byte
 	IsAdmin          bool
+	DeactivatedAt    time.Time
+	Role             Role
 }
 
 func (u User) HasValidatedEmail() bool {
 	return !u.EmailValidatedAt.IsZero()
 }
 
+func (u User) IsDeactivated() bool {
+	return !u.DeactivatedAt.IsZero()
+}
+
 func (u User) ValidPassword(providedPassword, pepper string) (bool, error) {
 	parts := strings.Split(string(u.Password), ":")
 	if len(parts) != 2 {
@@ -125,8 +131,10 @@ type PasswordPair struct {
 }
 
 type CreateUserData struct {
-	Email        string `validate:"required,email,max=255"`
-	PasswordPair PasswordPair
+	Email            string `validate:"required,email,max=255"`
+	PasswordPair     PasswordPair
+	EmailValidatedAt time.Time
+	Role             Role
 }
 
 func CreateUser(
@@ -144,12 +152,24 @@ func CreateUser(
 		return User{}, err
 	}
 
+	role := data.Role
+	if role == "" {
+		role = RoleMember
+	}
+	if !role.Valid() {
+		return User{}, errors.Join(ErrDomainValidation, fmt.Errorf("invalid role %q", role))
+	}
+
 	params := db.InsertUserParams{
-		ID:               uuid.New(),
-		Email:            strings.ToLower(data.Email),
-		EmailValidatedAt: pgtype.Timestamptz{},
-		Password:         []byte(hashedPassword),
-		IsAdmin:          false,
+		ID:    uuid.New(),
+		Email: strings.ToLower(data.Email),
+		EmailValidatedAt: pgtype.Timestamptz{
+			Time:  data.EmailValidatedAt,
+			Valid: !data.EmailValidatedAt.IsZero(),
+		},
+		Password: []byte(hashedPassword),
+		IsAdmin:  role == RoleAdmin,
+		Role:     string(role),
 	}
 	row, err := queries.InsertUser(ctx, exec, params)
 	if err != nil {
@@ -223,6 +243,67 @@ func UpdateUser(
 	return rowToUser(row)
 }
 
+func UpdateUserRole(
+	ctx context.Context,
+	exec storage.Executor,
+	id uuid.UUID,
+	role Role,
+) (User, error) {
+	if !role.Valid() {
+		return User{}, errors.Join(ErrDomainValidation, fmt.Errorf("invalid role %q", role))
+	}
+
+	row, err := queries.UpdateUserRole(ctx, exec, db.UpdateUserRoleParams{
+		ID:   id,
+		Role: string(role),
+	})
+	if err != nil {
+		return User{}, err
+	}
+
+	return rowToUser(row)
+}
+
+func DeactivateUser(
+	ctx context.Context,
+	exec storage.Executor,
+	id uuid.UUID,
+) (User, error) {
+	row, err := queries.UpdateUserDeactivatedAt(
+		ctx,
+		exec,
+		db.UpdateUserDeactivatedAtParams{
+			ID:            id,
+			DeactivatedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		},
+	)
+	if err != nil {
+		return User{}, err
+	}
+
+	return rowToUser(row)
+}
+
+func ReactivateUser(
+	ctx context.Context,
+	exec storage.Executor,
+	id uuid.UUID,
+) (User, error) {
+	row, err := queries.UpdateUserDeactivatedAt(
+		ctx,
+		exec,
+		db.UpdateUserDeactivatedAtParams{
+			ID:            id,
+			DeactivatedAt: pgtype.Timestamptz{},
+		},
+	)
+	if err != nil {
+		return User{}, err
+	}
+
+	return rowToUser(row)
+}
+
 func DestroyUser(
 	ctx context.Context,
 	exec storage.Executor,
@@ -303,5 +384,7 @@ func rowToUser(row db.User) (User, error) {
 		EmailValidatedAt: row.EmailValidatedAt.Time,
 		Password:         row.Password,
 		IsAdmin:          row.IsAdmin,
+		DeactivatedAt:    row.DeactivatedAt.Time,
+		Role:             Role(row.Role),
 	}, nil
 }
@@ -0,0 +1,347 @@
+package models
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models/internal/db"
+)
+
+// webhookSecretPrefix marks signing secrets so they are easy to spot in logs
+// and secret scanners.
+const webhookSecretPrefix = "whsec_"
+
+// maxWebhookResponseBody is how much of an endpoint's response is kept in the
+// delivery log.
+const maxWebhookResponseBody = 2048
+
+var ErrInvalidWebhookEvent = errors.New("invalid webhook event")
+
+// WebhookEvent names something that happened which endpoints can subscribe to.
+type WebhookEvent string
+
+const (
+	WebhookEventArticlePublished   WebhookEvent = "article.published"
+	WebhookEventNewsletterReleased WebhookEvent = "newsletter.released"
+	WebhookEventSubscriberVerified WebhookEvent = "subscriber.verified"
+)
+
+var WebhookEvents = []WebhookEvent{
+	WebhookEventArticlePublished,
+	WebhookEventNewsletterReleased,
+	WebhookEventSubscriberVerified,
+}
+
+func (e WebhookEvent) Valid() bool {
+	return slices.Contains(WebhookEvents, e)
+}
+
+type WebhookEndpoint struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Name      string
+	URL       string
+	Secret    string
+	Events    []WebhookEvent
+	IsActive  bool
+}
+
+func (e WebhookEndpoint) Subscribes(event WebhookEvent) bool {
+	return slices.Contains(e.Events, event)
+}
+
+// Sign returns the X-Webhook-Signature header value for body sent at t. The
+// signature is a hex HMAC-SHA256 of "<unix timestamp>.<body>" keyed with the
+// endpoint secret, so receivers can reject replays by checking the timestamp.
+func (e WebhookEndpoint) Sign(t time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(t.Unix(), 10)
+
+	m := hmac.New(sha256.New, []byte(e.Secret))
+	m.Write([]byte(timestamp))
+	m.Write([]byte("."))
+	m.Write(body)
+
+	return fmt.Sprintf("t=%s,v1=%s", timestamp, hex.EncodeToString(m.Sum(nil)))
+}
+
+type WebhookDelivery struct {
+	ID             int64
+	CreatedAt      time.Time
+	EndpointID     uuid.UUID
+	EventID        uuid.UUID
+	Event          WebhookEvent
+	Attempt        int32
+	Succeeded      bool
+	ResponseStatus int32
+	ResponseBody   string
+	Error          string
+	Duration       time.Duration
+}
+
+func FindWebhookEndpoint(
+	ctx context.Context,
+	exec storage.Executor,
+	id uuid.UUID,
+) (WebhookEndpoint, error) {
+	row, err := queries.QueryWebhookEndpointByID(ctx, exec, id)
+	if err != nil {
+		return WebhookEndpoint{}, err
+	}
+
+	return rowToWebhookEndpoint(row), nil
+}
+
+func AllWebhookEndpoints(
+	ctx context.Context,
+	exec storage.Executor,
+) ([]WebhookEndpoint, error) {
+	rows, err := queries.QueryWebhookEndpoints(ctx, exec)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := make([]WebhookEndpoint, len(rows))
+	for i, row := range rows {
+		endpoints[i] = rowToWebhookEndpoint(row)
+	}
+
+	return endpoints, nil
+}
+
+func ActiveWebhookEndpointsForEvent(
+	ctx context.Context,
+	exec storage.Executor,
+	event WebhookEvent,
+) ([]WebhookEndpoint, error) {
+	rows, err := queries.QueryActiveWebhookEndpointsForEvent(ctx, exec, string(event))
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := make([]WebhookEndpoint, len(rows))
+	for i, row := range rows {
+		endpoints[i] = rowToWebhookEndpoint(row)
+	}
+
+	return endpoints, nil
+}
+
+type CreateWebhookEndpointData struct {
+	Name     string         `validate:"required,max=100"`
+	URL      string         `validate:"required,url,startswith=http"`
+	Events   []WebhookEvent `validate:"required,min=1"`
+	IsActive bool
+}
+
+// CreateWebhookEndpoint stores a new endpoint with a freshly generated
+// signing secret.
+func CreateWebhookEndpoint(
+	ctx context.Context,
+	exec storage.Executor,
+	data CreateWebhookEndpointData,
+) (WebhookEndpoint, error) {
+	if err := Validate.Struct(data); err != nil {
+		return WebhookEndpoint{}, errors.Join(ErrDomainValidation, err)
+	}
+
+	events, err := webhookEventStrings(data.Events)
+	if err != nil {
+		return WebhookEndpoint{}, err
+	}
+
+	secret, err := GenerateSecureToken()
+	if err != nil {
+		return WebhookEndpoint{}, err
+	}
+
+	row, err := queries.InsertWebhookEndpoint(ctx, exec, db.InsertWebhookEndpointParams{
+		ID:       uuid.New(),
+		Name:     strings.TrimSpace(data.Name),
+		Url:      strings.TrimSpace(data.URL),
+		Secret:   webhookSecretPrefix + strings.ToLower(secret),
+		Events:   events,
+		IsActive: data.IsActive,
+	})
+	if err != nil {
+		return WebhookEndpoint{}, err
+	}
+
+	return rowToWebhookEndpoint(row), nil
+}
+
+type UpdateWebhookEndpointData struct {
+	ID       uuid.UUID      `validate:"required"`
+	Name     string         `validate:"required,max=100"`
+	URL      string         `validate:"required,url,startswith=http"`
+	Events   []WebhookEvent `validate:"required,min=1"`
+	IsActive bool
+}
+
+func UpdateWebhookEndpoint(
+	ctx context.Context,
+	exec storage.Executor,
+	data UpdateWebhookEndpointData,
+) (WebhookEndpoint, error) {
+	if err := Validate.Struct(data); err != nil {
+		return WebhookEndpoint{}, errors.Join(ErrDomainValidation, err)
+	}
+
+	events, err := webhookEventStrings(data.Events)
+	if err != nil {
+		return WebhookEndpoint{}, err
+	}
+
+	row, err := queries.UpdateWebhookEndpoint(ctx, exec, db.UpdateWebhookEndpointParams{
+		ID:       data.ID,
+		Name:     strings.TrimSpace(data.Name),
+		Url:      strings.TrimSpace(data.URL),
+		Events:   events,
+		IsActive: data.IsActive,
+	})
+	if err != nil {
+		return WebhookEndpoint{}, err
+	}
+
+	return rowToWebhookEndpoint(row), nil
+}
+
+func DestroyWebhookEndpoint(
+	ctx context.Context,
+	exec storage.Executor,
+	id uuid.UUID,
+) error {
+	return queries.DeleteWebhookEndpoint(ctx, exec, id)
+}
+
+type CreateWebhookDeliveryData struct {
+	EndpointID     uuid.UUID
+	EventID        uuid.UUID
+	Event          WebhookEvent
+	Attempt        int
+	Succeeded      bool
+	ResponseStatus int
+	ResponseBody   string
+	Error          string
+	Duration       time.Duration
+}
+
+// CreateWebhookDelivery records one delivery attempt. Response bodies are
+// truncated so a chatty endpoint cannot bloat the log.
+func CreateWebhookDelivery(
+	ctx context.Context,
+	exec storage.Executor,
+	data CreateWebhookDeliveryData,
+) (WebhookDelivery, error) {
+	body := data.ResponseBody
+	if len(body) > maxWebhookResponseBody {
+		body = strings.ToValidUTF8(body[:maxWebhookResponseBody], "")
+	}
+
+	row, err := queries.InsertWebhookDelivery(ctx, exec, db.InsertWebhookDeliveryParams{
+		EndpointID: data.EndpointID,
+		EventID:    data.EventID,
+		Event:      string(data.Event),
+		Attempt:    int32(data.Attempt),
+		Succeeded:  data.Succeeded,
+		ResponseStatus: pgtype.Int4{
+			Int32: int32(data.ResponseStatus),
+			Valid: data.ResponseStatus != 0,
+		},
+		ResponseBody: body,
+		Error:        data.Error,
+		DurationMs:   int32(data.Duration.Milliseconds()),
+	})
+	if err != nil {
+		return WebhookDelivery{}, err
+	}
+
+	return rowToWebhookDelivery(row), nil
+}
+
+func RecentWebhookDeliveries(
+	ctx context.Context,
+	exec storage.Executor,
+	endpointID uuid.UUID,
+	limit int32,
+) ([]WebhookDelivery, error) {
+	rows, err := queries.QueryWebhookDeliveriesByEndpointID(
+		ctx,
+		exec,
+		db.QueryWebhookDeliveriesByEndpointIDParams{
+			EndpointID: endpointID,
+			Limit:      limit,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]WebhookDelivery, len(rows))
+	for i, row := range rows {
+		deliveries[i] = rowToWebhookDelivery(row)
+	}
+
+	return deliveries, nil
+}
+
+func webhookEventStrings(events []WebhookEvent) ([]string, error) {
+	out := make([]string, 0, len(events))
+	for _, event := range events {
+		if !event.Valid() {
+			return nil, errors.Join(ErrDomainValidation, ErrInvalidWebhookEvent)
+		}
+		if slices.Contains(out, string(event)) {
+			continue
+		}
+		out = append(out, string(event))
+	}
+
+	return out, nil
+}
+
+func rowToWebhookEndpoint(row db.WebhookEndpoint) WebhookEndpoint {
+	events := make([]WebhookEvent, len(row.Events))
+	for i, event := range row.Events {
+		events[i] = WebhookEvent(event)
+	}
+
+	return WebhookEndpoint{
+		ID:        row.ID,
+		CreatedAt: row.CreatedAt.Time,
+		UpdatedAt: row.UpdatedAt.Time,
+		Name:      row.Name,
+		URL:       row.Url,
+		Secret:    row.Secret,
+		Events:    events,
+		IsActive:  row.IsActive,
+	}
+}
+
+func rowToWebhookDelivery(row db.WebhookDelivery) WebhookDelivery {
+	return WebhookDelivery{
+		ID:             row.ID,
+		CreatedAt:      row.CreatedAt.Time,
+		EndpointID:     row.EndpointID,
+		EventID:        row.EventID,
+		Event:          WebhookEvent(row.Event),
+		Attempt:        row.Attempt,
+		Succeeded:      row.Succeeded,
+		ResponseStatus: row.ResponseStatus.Int32,
+		ResponseBody:   row.ResponseBody,
+		Error:          row.Error,
+		Duration:       time.Duration(row.DurationMs) * time.Millisecond,
+	}
+}
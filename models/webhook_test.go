@@ -0,0 +1,42 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"mortenvistisen/models"
+)
+
+func TestWebhookEndpointSign(t *testing.T) {
+	sentAt := time.Unix(1700000000, 0)
+
+	tests := map[string]struct {
+		secret string
+		body   string
+		want   string
+	}{
+		"body": {
+			secret: "whsec_test",
+			body:   `{"event":"article.published"}`,
+			want:   "t=1700000000,v1=3c5b8966daae25d209f07b25128db87e936f754c07fa202d4ce8ca5b553fb06c",
+		},
+		"empty body": {
+			secret: "whsec_test",
+			want:   "t=1700000000,v1=5967f3c560522fa40cf2876ebc3c3a08551dd6959aaade3b413460591895bdcc",
+		},
+		"other secret": {
+			secret: "other",
+			body:   `{"event":"article.published"}`,
+			want:   "t=1700000000,v1=29af54b5c24dcad6396a3a02bd5c36d1b80908c3a6e7d3faff0b1ecb5d3cecca",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			endpoint := models.WebhookEndpoint{Secret: tt.secret}
+			if got := endpoint.Sign(sentAt, []byte(tt.body)); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
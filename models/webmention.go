@@ -0,0 +1,227 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models/internal/db"
+)
+
+var ErrInvalidWebmentionStatus = errors.New("invalid webmention status")
+
+// WebmentionStatus tracks a received mention from arrival to moderation.
+// Mentions start out pending until the source has been fetched and shown to
+// link to the article, then wait in review until approved or rejected.
+type WebmentionStatus string
+
+const (
+	WebmentionStatusPending  WebmentionStatus = "pending"
+	WebmentionStatusReview   WebmentionStatus = "review"
+	WebmentionStatusApproved WebmentionStatus = "approved"
+	WebmentionStatusRejected WebmentionStatus = "rejected"
+)
+
+var WebmentionStatuses = []WebmentionStatus{
+	WebmentionStatusPending,
+	WebmentionStatusReview,
+	WebmentionStatusApproved,
+	WebmentionStatusRejected,
+}
+
+func (s WebmentionStatus) Valid() bool {
+	return slices.Contains(WebmentionStatuses, s)
+}
+
+type Webmention struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	ArticleID  int32
+	Source     string
+	Target     string
+	Status     WebmentionStatus
+	AuthorName string
+	AuthorURL  string
+	Title      string
+	Excerpt    string
+	VerifiedAt time.Time
+}
+
+func FindWebmention(
+	ctx context.Context,
+	exec storage.Executor,
+	id uuid.UUID,
+) (Webmention, error) {
+	row, err := queries.QueryWebmentionByID(ctx, exec, id)
+	if err != nil {
+		return Webmention{}, err
+	}
+
+	return rowToWebmention(row), nil
+}
+
+// RecentWebmentions lists the newest mentions, optionally limited to one
+// status. An empty status lists every mention.
+func RecentWebmentions(
+	ctx context.Context,
+	exec storage.Executor,
+	status WebmentionStatus,
+	limit int64,
+) ([]Webmention, error) {
+	rows, err := queries.QueryWebmentionsByStatus(ctx, exec, db.QueryWebmentionsByStatusParams{
+		Status: pgtype.Text{String: string(status), Valid: status != ""},
+		Limit:  limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	mentions := make([]Webmention, len(rows))
+	for i, row := range rows {
+		mentions[i] = rowToWebmention(row)
+	}
+
+	return mentions, nil
+}
+
+func ApprovedWebmentionsForArticle(
+	ctx context.Context,
+	exec storage.Executor,
+	articleID int32,
+) ([]Webmention, error) {
+	rows, err := queries.QueryWebmentionsByArticleIDAndStatus(
+		ctx,
+		exec,
+		db.QueryWebmentionsByArticleIDAndStatusParams{
+			ArticleID: articleID,
+			Status:    string(WebmentionStatusApproved),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	mentions := make([]Webmention, len(rows))
+	for i, row := range rows {
+		mentions[i] = rowToWebmention(row)
+	}
+
+	return mentions, nil
+}
+
+type ReceiveWebmentionData struct {
+	ArticleID int32  `validate:"required"`
+	Source    string `validate:"required,url"`
+	Target    string `validate:"required,url"`
+}
+
+// ReceiveWebmention stores an incoming mention. Receiving the same source and
+// target again keeps the existing moderation decision, so an updated post
+// does not need to be approved twice.
+func ReceiveWebmention(
+	ctx context.Context,
+	exec storage.Executor,
+	data ReceiveWebmentionData,
+) (Webmention, error) {
+	if err := Validate.Struct(data); err != nil {
+		return Webmention{}, errors.Join(ErrDomainValidation, err)
+	}
+
+	row, err := queries.UpsertWebmention(ctx, exec, db.UpsertWebmentionParams{
+		ID:        uuid.New(),
+		ArticleID: data.ArticleID,
+		Source:    data.Source,
+		Target:    data.Target,
+	})
+	if err != nil {
+		return Webmention{}, err
+	}
+
+	return rowToWebmention(row), nil
+}
+
+type VerifyWebmentionData struct {
+	ID         uuid.UUID `validate:"required"`
+	AuthorName string    `validate:"max=255"`
+	AuthorURL  string
+	Title      string
+	Excerpt    string
+}
+
+// VerifyWebmention records what was found at the source and moves pending
+// mentions into review.
+func VerifyWebmention(
+	ctx context.Context,
+	exec storage.Executor,
+	data VerifyWebmentionData,
+) (Webmention, error) {
+	if err := Validate.Struct(data); err != nil {
+		return Webmention{}, errors.Join(ErrDomainValidation, err)
+	}
+
+	row, err := queries.UpdateWebmentionVerified(ctx, exec, db.UpdateWebmentionVerifiedParams{
+		ID:         data.ID,
+		AuthorName: strings.TrimSpace(data.AuthorName),
+		AuthorUrl:  strings.TrimSpace(data.AuthorURL),
+		Title:      strings.TrimSpace(data.Title),
+		Excerpt:    strings.TrimSpace(data.Excerpt),
+	})
+	if err != nil {
+		return Webmention{}, err
+	}
+
+	return rowToWebmention(row), nil
+}
+
+func UpdateWebmentionStatus(
+	ctx context.Context,
+	exec storage.Executor,
+	id uuid.UUID,
+	status WebmentionStatus,
+) (Webmention, error) {
+	if !status.Valid() {
+		return Webmention{}, errors.Join(ErrDomainValidation, ErrInvalidWebmentionStatus)
+	}
+
+	row, err := queries.UpdateWebmentionStatus(ctx, exec, db.UpdateWebmentionStatusParams{
+		ID:     id,
+		Status: string(status),
+	})
+	if err != nil {
+		return Webmention{}, err
+	}
+
+	return rowToWebmention(row), nil
+}
+
+func DestroyWebmention(
+	ctx context.Context,
+	exec storage.Executor,
+	id uuid.UUID,
+) error {
+	return queries.DeleteWebmention(ctx, exec, id)
+}
+
+func rowToWebmention(row db.Webmention) Webmention {
+	return Webmention{
+		ID:         row.ID,
+		CreatedAt:  row.CreatedAt.Time,
+		UpdatedAt:  row.UpdatedAt.Time,
+		ArticleID:  row.ArticleID,
+		Source:     row.Source,
+		Target:     row.Target,
+		Status:     WebmentionStatus(row.Status),
+		AuthorName: row.AuthorName,
+		AuthorURL:  row.AuthorUrl,
+		Title:      row.Title,
+		Excerpt:    row.Excerpt,
+		VerifiedAt: row.VerifiedAt.Time,
+	}
+}
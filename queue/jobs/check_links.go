@@ -0,0 +1,29 @@
+package jobs
+
+import (
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+)
+
+// CheckLinksArgs checks every link in published content and updates the
+// broken link report.
+type CheckLinksArgs struct{}
+
+func (CheckLinksArgs) Kind() string { return "check_links" }
+
+// InsertOpts keeps a single run waiting or in progress at a time, whether
+// it was scheduled or requested from the admin.
+func (CheckLinksArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		UniqueOpts: river.UniqueOpts{
+			ByArgs: true,
+			ByState: []rivertype.JobState{
+				rivertype.JobStateAvailable,
+				rivertype.JobStatePending,
+				rivertype.JobStateRetryable,
+				rivertype.JobStateRunning,
+				rivertype.JobStateScheduled,
+			},
+		},
+	}
+}
@@ -0,0 +1,18 @@
+package jobs
+
+import "github.com/riverqueue/river"
+
+// DeliverActivityArgs carries an ActivityPub activity to one remote inbox.
+// KeyID names the key it is signed with; like webhook payloads the activity
+// is kept as bytes so the signed digest matches what was queued.
+type DeliverActivityArgs struct {
+	Inbox    string `json:"inbox"`
+	KeyID    string `json:"key_id"`
+	Activity []byte `json:"activity"`
+}
+
+func (DeliverActivityArgs) Kind() string { return "deliver_activity" }
+
+func (DeliverActivityArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{MaxAttempts: 8}
+}
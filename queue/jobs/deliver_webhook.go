@@ -0,0 +1,22 @@
+package jobs
+
+import (
+	"github.com/google/uuid"
+	"github.com/riverqueue/river"
+)
+
+// DeliverWebhookArgs carries the exact body to POST. Payload is kept as bytes
+// rather than raw JSON so the jsonb args column cannot reformat it between
+// attempts.
+type DeliverWebhookArgs struct {
+	EndpointID uuid.UUID `json:"endpoint_id"`
+	EventID    uuid.UUID `json:"event_id"`
+	Event      string    `json:"event"`
+	Payload    []byte    `json:"payload"`
+}
+
+func (DeliverWebhookArgs) Kind() string { return "deliver_webhook" }
+
+func (DeliverWebhookArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{MaxAttempts: 8}
+}
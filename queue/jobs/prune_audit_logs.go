@@ -0,0 +1,5 @@
+package jobs
+
+type PruneAuditLogsArgs struct{}
+
+func (PruneAuditLogsArgs) Kind() string { return "prune_audit_logs" }
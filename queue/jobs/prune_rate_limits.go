@@ -0,0 +1,5 @@
+package jobs
+
+type PruneRateLimitsArgs struct{}
+
+func (PruneRateLimitsArgs) Kind() string { return "prune_rate_limits" }
@@ -0,0 +1,30 @@
+package jobs
+
+import (
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+)
+
+// RefreshRelatedArticlesArgs recomputes the related articles of every
+// published article.
+type RefreshRelatedArticlesArgs struct{}
+
+func (RefreshRelatedArticlesArgs) Kind() string { return "refresh_related_articles" }
+
+// InsertOpts keeps a single refresh waiting at a time, however many articles
+// are saved before it starts. River requires running in ByState, so a save
+// during a run is not queued; the worker refreshes again instead.
+func (RefreshRelatedArticlesArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		UniqueOpts: river.UniqueOpts{
+			ByArgs: true,
+			ByState: []rivertype.JobState{
+				rivertype.JobStateAvailable,
+				rivertype.JobStatePending,
+				rivertype.JobStateRetryable,
+				rivertype.JobStateRunning,
+				rivertype.JobStateScheduled,
+			},
+		},
+	}
+}
@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"github.com/google/uuid"
+	"github.com/riverqueue/river"
+)
+
+type VerifyWebmentionArgs struct {
+	WebmentionID uuid.UUID `json:"webmention_id"`
+}
+
+func (VerifyWebmentionArgs) Kind() string { return "verify_webmention" }
+
+func (VerifyWebmentionArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{MaxAttempts: 5}
+}
+
+type SendWebmentionArgs struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+func (SendWebmentionArgs) Kind() string { return "send_webmention" }
+
+func (SendWebmentionArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{MaxAttempts: 5}
+}
@@ -0,0 +1,38 @@
+package queue
+
+import (
+	"time"
+
+	"github.com/riverqueue/river"
+
+	"mortenvistisen/queue/jobs"
+)
+
+// periodicJobs are scheduled by the processor's leader. Housekeeping jobs run
+// once on start so a long-stopped instance catches up immediately. The link
+// checker does not, as it requests every external link in the content.
+func periodicJobs() []*river.PeriodicJob {
+	return []*river.PeriodicJob{
+		river.NewPeriodicJob(
+			river.PeriodicInterval(24*time.Hour),
+			func() (river.JobArgs, *river.InsertOpts) {
+				return jobs.PruneAuditLogsArgs{}, nil
+			},
+			&river.PeriodicJobOpts{RunOnStart: true},
+		),
+		river.NewPeriodicJob(
+			river.PeriodicInterval(time.Hour),
+			func() (river.JobArgs, *river.InsertOpts) {
+				return jobs.PruneRateLimitsArgs{}, nil
+			},
+			&river.PeriodicJobOpts{RunOnStart: true},
+		),
+		river.NewPeriodicJob(
+			river.PeriodicInterval(24*time.Hour),
+			func() (river.JobArgs, *river.InsertOpts) {
+				return jobs.CheckLinksArgs{}, nil
+			},
+			nil,
+		),
+	}
+}
@@ -40,8 +40,9 @@ func NewProcessor(
 		Queues: map[string]river.QueueConfig{
 			river.QueueDefault: {MaxWorkers: 100},
 		},
-		Logger:  slog.Default(),
-		Workers: workers,
+		Logger:       slog.Default(),
+		Workers:      workers,
+		PeriodicJobs: periodicJobs(),
 	})
 	if err != nil {
 		return Processor{}, err
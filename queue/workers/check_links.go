@@ -0,0 +1,66 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/riverqueue/river"
+
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/queue/jobs"
+	"mortenvistisen/services"
+)
+
+// checkLinksTimeout bounds a run of the link checker. Runs are slow on
+// purpose: every host is only asked once per interval.
+const checkLinksTimeout = time.Hour
+
+type CheckLinksWorker struct {
+	river.WorkerDefaults[jobs.CheckLinksArgs]
+	db         storage.Pool
+	client     *http.Client
+	siteRoutes *services.SiteRoutes
+}
+
+func NewCheckLinksWorker(
+	db storage.Pool,
+	client *http.Client,
+	siteRoutes *services.SiteRoutes,
+) *CheckLinksWorker {
+	return &CheckLinksWorker{
+		db:         db,
+		client:     client,
+		siteRoutes: siteRoutes,
+	}
+}
+
+func (w *CheckLinksWorker) Timeout(*river.Job[jobs.CheckLinksArgs]) time.Duration {
+	return checkLinksTimeout
+}
+
+func (w *CheckLinksWorker) Work(ctx context.Context, job *river.Job[jobs.CheckLinksArgs]) error {
+	siteRoutes := w.siteRoutes.Routes()
+	if len(siteRoutes) == 0 {
+		return errors.New("site routes are not registered yet")
+	}
+
+	summary, err := services.RunLinkCheck(ctx, w.db.Conn(), siteRoutes, services.LinkCheckOptions{
+		Client: w.client,
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.InfoContext(
+		ctx,
+		"checked links",
+		"checked", summary.Checked,
+		"broken", summary.Broken,
+		"removed", summary.Removed,
+	)
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/riverqueue/river"
+
+	"mortenvistisen/internal/activitypub"
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models"
+	"mortenvistisen/queue/jobs"
+)
+
+type DeliverActivityWorker struct {
+	river.WorkerDefaults[jobs.DeliverActivityArgs]
+	db     storage.Pool
+	client *http.Client
+}
+
+func NewDeliverActivityWorker(db storage.Pool, client *http.Client) *DeliverActivityWorker {
+	return &DeliverActivityWorker{
+		db:     db,
+		client: client,
+	}
+}
+
+// NextRetry uses the webhook backoff; remote instances go down for hours at a
+// time and should not be hammered when they come back.
+func (w *DeliverActivityWorker) NextRetry(job *river.Job[jobs.DeliverActivityArgs]) time.Time {
+	backoff := webhookMaxBackoff
+	if attempt := max(job.Attempt, 1); attempt < 16 {
+		backoff = min(webhookBaseBackoff<<(attempt-1), webhookMaxBackoff)
+	}
+
+	return time.Now().Add(backoff)
+}
+
+func (w *DeliverActivityWorker) Work(ctx context.Context, job *river.Job[jobs.DeliverActivityArgs]) error {
+	key, err := models.FindOrCreateActivityPubKey(ctx, w.db.Conn(), models.ActivityPubSiteKey)
+	if err != nil {
+		return err
+	}
+
+	err = activitypub.Deliver(
+		ctx,
+		w.client,
+		activitypub.Signer{KeyID: job.Args.KeyID, Key: key.PrivateKey},
+		job.Args.Inbox,
+		job.Args.Activity,
+	)
+
+	var statusErr *activitypub.StatusError
+	if errors.As(err, &statusErr) && statusErr.Permanent() {
+		return river.JobCancel(fmt.Errorf("delivering activity: %w", err))
+	}
+
+	return err
+}
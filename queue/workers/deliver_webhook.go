@@ -0,0 +1,127 @@
+package workers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/riverqueue/river"
+
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models"
+	"mortenvistisen/queue/jobs"
+	"mortenvistisen/services"
+)
+
+const (
+	webhookTimeout      = 10 * time.Second
+	webhookBaseBackoff  = 30 * time.Second
+	webhookMaxBackoff   = 6 * time.Hour
+	webhookResponseRead = 4096
+)
+
+type DeliverWebhookWorker struct {
+	river.WorkerDefaults[jobs.DeliverWebhookArgs]
+	db     storage.Pool
+	client *http.Client
+}
+
+// NewDeliverWebhookWorker delivers through the external client, so an
+// endpoint URL, or a redirect from it, cannot reach internal services.
+func NewDeliverWebhookWorker(db storage.Pool) *DeliverWebhookWorker {
+	client := services.NewExternalClient()
+	client.Timeout = webhookTimeout
+
+	return &DeliverWebhookWorker{
+		db:     db,
+		client: client,
+	}
+}
+
+func (w *DeliverWebhookWorker) Timeout(*river.Job[jobs.DeliverWebhookArgs]) time.Duration {
+	return webhookTimeout + 5*time.Second
+}
+
+// NextRetry backs off exponentially from 30 seconds, capped at six hours, so
+// an endpoint that is down for a while is not hammered.
+func (w *DeliverWebhookWorker) NextRetry(job *river.Job[jobs.DeliverWebhookArgs]) time.Time {
+	backoff := webhookMaxBackoff
+	if attempt := max(job.Attempt, 1); attempt < 16 {
+		backoff = min(webhookBaseBackoff<<(attempt-1), webhookMaxBackoff)
+	}
+
+	return time.Now().Add(backoff)
+}
+
+func (w *DeliverWebhookWorker) Work(ctx context.Context, job *river.Job[jobs.DeliverWebhookArgs]) error {
+	endpoint, err := models.FindWebhookEndpoint(ctx, w.db.Conn(), job.Args.EndpointID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return river.JobCancel(fmt.Errorf("webhook endpoint %s no longer exists", job.Args.EndpointID))
+	}
+	if err != nil {
+		return err
+	}
+	if !endpoint.IsActive {
+		return river.JobCancel(fmt.Errorf("webhook endpoint %s is disabled", endpoint.ID))
+	}
+
+	delivery := models.CreateWebhookDeliveryData{
+		EndpointID: endpoint.ID,
+		EventID:    job.Args.EventID,
+		Event:      models.WebhookEvent(job.Args.Event),
+		Attempt:    job.Attempt,
+	}
+
+	start := time.Now()
+	status, body, deliverErr := w.deliver(ctx, endpoint, job.Args)
+	delivery.Duration = time.Since(start)
+	delivery.ResponseStatus = status
+	delivery.ResponseBody = body
+
+	if deliverErr == nil && (status < 200 || status > 299) {
+		deliverErr = fmt.Errorf("webhook endpoint responded with status %d", status)
+	}
+	if deliverErr != nil {
+		delivery.Error = deliverErr.Error()
+	} else {
+		delivery.Succeeded = true
+	}
+
+	if _, err := models.CreateWebhookDelivery(ctx, w.db.Conn(), delivery); err != nil {
+		slog.ErrorContext(ctx, "could not record webhook delivery", "endpoint_id", endpoint.ID, "error", err)
+	}
+
+	return deliverErr
+}
+
+func (w *DeliverWebhookWorker) deliver(
+	ctx context.Context,
+	endpoint models.WebhookEndpoint,
+	args jobs.DeliverWebhookArgs,
+) (int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(args.Payload))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "mortenvistisen-webhooks/1.0")
+	req.Header.Set("X-Webhook-ID", args.EventID.String())
+	req.Header.Set("X-Webhook-Event", args.Event)
+	req.Header.Set("X-Webhook-Signature", endpoint.Sign(time.Now(), args.Payload))
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer res.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(res.Body, webhookResponseRead))
+
+	return res.StatusCode, string(body), nil
+}
@@ -0,0 +1,53 @@
+package workers_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+
+	"mortenvistisen/queue/jobs"
+	"mortenvistisen/queue/workers"
+)
+
+func TestDeliverWebhookMaxAttempts(t *testing.T) {
+	if got := (jobs.DeliverWebhookArgs{}).InsertOpts().MaxAttempts; got != 8 {
+		t.Errorf("expected 8 attempts, got %d", got)
+	}
+}
+
+func TestDeliverWebhookNextRetry(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 0, want: 30 * time.Second},
+		{attempt: 1, want: 30 * time.Second},
+		{attempt: 2, want: time.Minute},
+		{attempt: 3, want: 2 * time.Minute},
+		{attempt: 4, want: 4 * time.Minute},
+		{attempt: 5, want: 8 * time.Minute},
+		{attempt: 6, want: 16 * time.Minute},
+		{attempt: 7, want: 32 * time.Minute},
+		{attempt: 8, want: 64 * time.Minute},
+		{attempt: 10, want: 256 * time.Minute},
+		{attempt: 11, want: 6 * time.Hour},
+		{attempt: 64, want: 6 * time.Hour},
+	}
+
+	worker := workers.NewDeliverWebhookWorker(nil)
+	for _, tt := range tests {
+		job := &river.Job[jobs.DeliverWebhookArgs]{
+			JobRow: &rivertype.JobRow{Attempt: tt.attempt},
+		}
+
+		before := time.Now()
+		next := worker.NextRetry(job)
+		after := time.Now()
+
+		if next.Before(before.Add(tt.want)) || next.After(after.Add(tt.want)) {
+			t.Errorf("attempt %d: expected a retry in %s, got %s", tt.attempt, tt.want, next.Sub(before))
+		}
+	}
+}
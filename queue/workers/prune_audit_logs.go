@@ -0,0 +1,41 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/riverqueue/river"
+
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models"
+	"mortenvistisen/queue/jobs"
+)
+
+type PruneAuditLogsWorker struct {
+	river.WorkerDefaults[jobs.PruneAuditLogsArgs]
+	db        storage.Pool
+	retention time.Duration
+}
+
+func NewPruneAuditLogsWorker(db storage.Pool, retention time.Duration) *PruneAuditLogsWorker {
+	return &PruneAuditLogsWorker{
+		db:        db,
+		retention: retention,
+	}
+}
+
+func (w *PruneAuditLogsWorker) Work(ctx context.Context, job *river.Job[jobs.PruneAuditLogsArgs]) error {
+	if w.retention <= 0 {
+		return nil
+	}
+
+	deleted, err := models.DestroyAuditLogsBefore(ctx, w.db.Conn(), time.Now().Add(-w.retention))
+	if err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "pruned audit logs", "deleted", deleted, "retention", w.retention)
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/riverqueue/river"
+
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/queue/jobs"
+	"mortenvistisen/services"
+)
+
+type PruneRateLimitsWorker struct {
+	river.WorkerDefaults[jobs.PruneRateLimitsArgs]
+	db storage.Pool
+}
+
+func NewPruneRateLimitsWorker(db storage.Pool) *PruneRateLimitsWorker {
+	return &PruneRateLimitsWorker{db: db}
+}
+
+func (w *PruneRateLimitsWorker) Work(ctx context.Context, job *river.Job[jobs.PruneRateLimitsArgs]) error {
+	hits, bans, err := services.PruneRateLimits(ctx, w.db.Conn())
+	if err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "pruned rate limits", "hits", hits, "bans", bans)
+
+	return nil
+}
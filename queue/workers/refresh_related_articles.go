@@ -0,0 +1,94 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/riverqueue/river"
+
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models"
+	"mortenvistisen/queue/jobs"
+	"mortenvistisen/services"
+)
+
+// relatedArticlesPasses caps how many times one job refreshes.
+const relatedArticlesPasses = 3
+
+type RefreshRelatedArticlesWorker struct {
+	river.WorkerDefaults[jobs.RefreshRelatedArticlesArgs]
+	db storage.Pool
+}
+
+func NewRefreshRelatedArticlesWorker(db storage.Pool) *RefreshRelatedArticlesWorker {
+	return &RefreshRelatedArticlesWorker{
+		db: db,
+	}
+}
+
+// Work refreshes again when articles changed during a pass, as saves made
+// while the job runs do not queue another one. It gives up after
+// relatedArticlesPasses so constant editing cannot keep it running.
+func (w *RefreshRelatedArticlesWorker) Work(ctx context.Context, job *river.Job[jobs.RefreshRelatedArticlesArgs]) error {
+	for pass := 1; ; pass++ {
+		before, err := models.FindArticlesChange(ctx, w.db.Conn())
+		if err != nil {
+			return err
+		}
+
+		if err := w.refresh(ctx); err != nil {
+			return err
+		}
+
+		after, err := models.FindArticlesChange(ctx, w.db.Conn())
+		if err != nil {
+			return err
+		}
+		if after.Equal(before) || pass == relatedArticlesPasses {
+			return nil
+		}
+
+		slog.InfoContext(ctx, "articles changed during related articles refresh, refreshing again", "pass", pass)
+	}
+}
+
+func (w *RefreshRelatedArticlesWorker) refresh(ctx context.Context) error {
+	articles, err := models.AllPublishedArticles(ctx, w.db.Conn())
+	if err != nil {
+		return err
+	}
+	tagIDs, err := models.ArticleTagIDs(ctx, w.db.Conn())
+	if err != nil {
+		return err
+	}
+
+	docs := make([]services.RelatedDocument, len(articles))
+	for i, article := range articles {
+		docs[i] = services.RelatedDocument{
+			ID:      article.ID,
+			Title:   article.Title,
+			Excerpt: article.Excerpt,
+			Content: article.Content,
+			TagIDs:  tagIDs[article.ID],
+		}
+	}
+
+	relations := services.ScoreRelatedArticles(docs, services.RelatedArticlesPerArticle)
+
+	tx, err := w.db.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := models.ReplaceRelatedArticles(ctx, tx, relations); err != nil {
+		return err
+	}
+	if err := w.db.CommitTx(ctx, tx); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "refreshed related articles", "articles", len(articles), "relations", len(relations))
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/riverqueue/river"
+
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models"
+	"mortenvistisen/queue/jobs"
+	"mortenvistisen/services"
+)
+
+type VerifyWebmentionWorker struct {
+	river.WorkerDefaults[jobs.VerifyWebmentionArgs]
+	db     storage.Pool
+	client *http.Client
+}
+
+func NewVerifyWebmentionWorker(db storage.Pool, client *http.Client) *VerifyWebmentionWorker {
+	return &VerifyWebmentionWorker{
+		db:     db,
+		client: client,
+	}
+}
+
+func (w *VerifyWebmentionWorker) Work(ctx context.Context, job *river.Job[jobs.VerifyWebmentionArgs]) error {
+	mention, err := models.FindWebmention(ctx, w.db.Conn(), job.Args.WebmentionID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	source, err := services.FetchWebmentionSource(ctx, w.client, mention.Source, mention.Target)
+	if errors.Is(err, services.ErrWebmentionSourceGone) ||
+		errors.Is(err, services.ErrWebmentionSourceMissingLink) {
+		slog.InfoContext(ctx, "dropping webmention", "source", mention.Source, "target", mention.Target, "reason", err)
+		return models.DestroyWebmention(ctx, w.db.Conn(), mention.ID)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = models.VerifyWebmention(ctx, w.db.Conn(), models.VerifyWebmentionData{
+		ID:         mention.ID,
+		AuthorName: source.AuthorName,
+		AuthorURL:  source.AuthorURL,
+		Title:      source.Title,
+		Excerpt:    source.Excerpt,
+	})
+
+	return err
+}
+
+type SendWebmentionWorker struct {
+	river.WorkerDefaults[jobs.SendWebmentionArgs]
+	client *http.Client
+}
+
+func NewSendWebmentionWorker(client *http.Client) *SendWebmentionWorker {
+	return &SendWebmentionWorker{
+		client: client,
+	}
+}
+
+func (w *SendWebmentionWorker) Work(ctx context.Context, job *river.Job[jobs.SendWebmentionArgs]) error {
+	endpoint, err := services.DiscoverWebmentionEndpoint(ctx, w.client, job.Args.Target)
+	if errors.Is(err, services.ErrNoWebmentionEndpoint) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return services.SendWebmention(ctx, w.client, endpoint, job.Args.Source, job.Args.Target)
+}
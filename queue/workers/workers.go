@@ -2,12 +2,22 @@
 package workers
 
 import (
+	"time"
+
 	"github.com/riverqueue/river"
 
 	"mortenvistisen/email"
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/services"
 )
 
-func Register(transactionalSender email.TransactionalSender, marketingSender email.MarketingSender) (*river.Workers, error) {
+func Register(
+	db storage.Pool,
+	transactionalSender email.TransactionalSender,
+	marketingSender email.MarketingSender,
+	auditLogRetention time.Duration,
+	siteRoutes *services.SiteRoutes,
+) (*river.Workers, error) {
 	wrks := river.NewWorkers()
 
 	if err := river.AddWorkerSafely(wrks, NewSendTransactionalEmailWorker(transactionalSender)); err != nil {
@@ -18,5 +28,39 @@ func Register(transactionalSender email.TransactionalSender, marketingSender ema
 		return nil, err
 	}
 
+	if err := river.AddWorkerSafely(wrks, NewPruneAuditLogsWorker(db, auditLogRetention)); err != nil {
+		return nil, err
+	}
+
+	if err := river.AddWorkerSafely(wrks, NewPruneRateLimitsWorker(db)); err != nil {
+		return nil, err
+	}
+
+	if err := river.AddWorkerSafely(wrks, NewDeliverWebhookWorker(db)); err != nil {
+		return nil, err
+	}
+
+	if err := river.AddWorkerSafely(wrks, NewRefreshRelatedArticlesWorker(db)); err != nil {
+		return nil, err
+	}
+
+	externalClient := services.NewExternalClient()
+
+	if err := river.AddWorkerSafely(wrks, NewVerifyWebmentionWorker(db, externalClient)); err != nil {
+		return nil, err
+	}
+
+	if err := river.AddWorkerSafely(wrks, NewSendWebmentionWorker(externalClient)); err != nil {
+		return nil, err
+	}
+
+	if err := river.AddWorkerSafely(wrks, NewDeliverActivityWorker(db, externalClient)); err != nil {
+		return nil, err
+	}
+
+	if err := river.AddWorkerSafely(wrks, NewCheckLinksWorker(db, externalClient, siteRoutes)); err != nil {
+		return nil, err
+	}
+
 	return wrks, nil
 }
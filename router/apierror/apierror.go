@@ -0,0 +1,52 @@
+// Package apierror writes the JSON error bodies returned by the content API so
+// middleware and controllers respond with the same shape.
+package apierror
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v5"
+)
+
+const (
+	CodeBadRequest      = "bad_request"
+	CodeUnauthenticated = "unauthenticated"
+	CodeForbidden       = "forbidden"
+	CodeNotFound        = "not_found"
+	CodeValidation      = "validation_failed"
+	CodeInternal        = "internal_error"
+)
+
+type Body struct {
+	Error Detail `json:"error"`
+}
+
+type Detail struct {
+	Code    string            `json:"code"`
+	Message string            `json:"message"`
+	Fields  map[string]string `json:"fields,omitempty"`
+}
+
+func Write(c *echo.Context, status int, code string, message string) error {
+	return c.JSON(status, Body{Error: Detail{Code: code, Message: message}})
+}
+
+// Validation reports the failing field rules from a models validation error.
+func Validation(c *echo.Context, err error) error {
+	detail := Detail{
+		Code:    CodeValidation,
+		Message: "the provided payload failed validations",
+	}
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		detail.Fields = make(map[string]string, len(validationErrs))
+		for _, fieldErr := range validationErrs {
+			detail.Fields[fieldErr.Field()] = fieldErr.Tag()
+		}
+	}
+
+	return c.JSON(http.StatusUnprocessableEntity, Body{Error: detail})
+}
@@ -0,0 +1,55 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+
+	"mortenvistisen/controllers"
+	"mortenvistisen/models"
+	"mortenvistisen/router/middleware"
+	"mortenvistisen/router/routes"
+
+	"github.com/labstack/echo/v5"
+)
+
+func (r Router) RegisterAccessTokenRoutes(accessTokens controllers.AccessTokens) error {
+	errs := []error{}
+	permitted := []echo.MiddlewareFunc{
+		middleware.RequirePermission(models.PermissionAccessAdmin),
+	}
+
+	_, err := r.e.AddRoute(echo.Route{
+		Method:      http.MethodGet,
+		Path:        routes.AccessTokenIndex.Path(),
+		Name:        routes.AccessTokenIndex.Name(),
+		Handler:     accessTokens.Index,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodPost,
+		Path:        routes.AccessTokenCreate.Path(),
+		Name:        routes.AccessTokenCreate.Name(),
+		Handler:     accessTokens.Create,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodDelete,
+		Path:        routes.AccessTokenDestroy.Path(),
+		Name:        routes.AccessTokenDestroy.Name(),
+		Handler:     accessTokens.Destroy,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
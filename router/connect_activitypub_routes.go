@@ -0,0 +1,90 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"mortenvistisen/controllers"
+	"mortenvistisen/router/routes"
+	"mortenvistisen/services"
+
+	"github.com/labstack/echo/v5"
+)
+
+func (r Router) RegisterActivityPubRoutes(activityPub controllers.ActivityPub) error {
+	errs := []error{}
+
+	_, err := r.e.AddRoute(echo.Route{
+		Method:  http.MethodGet,
+		Path:    routes.WebFinger.Path(),
+		Name:    routes.WebFinger.Name(),
+		Handler: activityPub.WebFinger,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:  http.MethodGet,
+		Path:    routes.ActivityPubActor.Path(),
+		Name:    routes.ActivityPubActor.Name(),
+		Handler: activityPub.Actor,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:  http.MethodPost,
+		Path:    routes.ActivityPubInbox.Path(),
+		Name:    routes.ActivityPubInbox.Name(),
+		Handler: activityPub.Inbox,
+		Middlewares: []echo.MiddlewareFunc{
+			r.mw.IPRateLimiter(
+				services.RateLimitPolicy{
+					Scope:       "activitypub.inbox",
+					Limit:       300,
+					Window:      time.Hour,
+					BanDuration: time.Hour,
+				},
+				routes.HomePage,
+			),
+		},
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:  http.MethodGet,
+		Path:    routes.ActivityPubOutbox.Path(),
+		Name:    routes.ActivityPubOutbox.Name(),
+		Handler: activityPub.Outbox,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:  http.MethodGet,
+		Path:    routes.ActivityPubFollowers.Path(),
+		Name:    routes.ActivityPubFollowers.Name(),
+		Handler: activityPub.Followers,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:  http.MethodGet,
+		Path:    routes.ActivityPubArticle.Path(),
+		Name:    routes.ActivityPubArticle.Name(),
+		Handler: activityPub.Article,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
@@ -5,6 +5,8 @@ import (
 	"net/http"
 
 	"mortenvistisen/controllers"
+	"mortenvistisen/models"
+	"mortenvistisen/router/middleware"
 	"mortenvistisen/router/routes"
 
 	"github.com/labstack/echo/v5"
@@ -25,3 +27,244 @@ func (r Router) RegisterAPIRoutes(api controllers.API) error {
 
 	return errors.Join(errs...)
 }
+
+func (r Router) RegisterContentAPIRoutes(api controllers.ContentAPI) error {
+	errs := []error{}
+
+	scoped := func(scope models.APIScope) []echo.MiddlewareFunc {
+		return []echo.MiddlewareFunc{
+			r.mw.APIAuthentication,
+			middleware.RequireAPIScope(scope),
+		}
+	}
+	readArticles := scoped(models.APIScopeArticlesRead)
+	writeArticles := scoped(models.APIScopeArticlesWrite)
+	readTags := scoped(models.APIScopeTagsRead)
+	writeTags := scoped(models.APIScopeTagsWrite)
+	readProjects := scoped(models.APIScopeProjectsRead)
+	writeProjects := scoped(models.APIScopeProjectsWrite)
+	readNewsletters := scoped(models.APIScopeNewslettersRead)
+	writeNewsletters := scoped(models.APIScopeNewslettersWrite)
+
+	_, err := r.e.AddRoute(echo.Route{
+		Method:      http.MethodGet,
+		Path:        routes.APIArticleIndex.Path(),
+		Name:        routes.APIArticleIndex.Name(),
+		Handler:     api.ArticleIndex,
+		Middlewares: readArticles,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodGet,
+		Path:        routes.APIArticleShow.Path(),
+		Name:        routes.APIArticleShow.Name(),
+		Handler:     api.ArticleShow,
+		Middlewares: readArticles,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodPost,
+		Path:        routes.APIArticleCreate.Path(),
+		Name:        routes.APIArticleCreate.Name(),
+		Handler:     api.ArticleCreate,
+		Middlewares: writeArticles,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodPut,
+		Path:        routes.APIArticleUpdate.Path(),
+		Name:        routes.APIArticleUpdate.Name(),
+		Handler:     api.ArticleUpdate,
+		Middlewares: writeArticles,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodDelete,
+		Path:        routes.APIArticleDestroy.Path(),
+		Name:        routes.APIArticleDestroy.Name(),
+		Handler:     api.ArticleDestroy,
+		Middlewares: writeArticles,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodGet,
+		Path:        routes.APITagIndex.Path(),
+		Name:        routes.APITagIndex.Name(),
+		Handler:     api.TagIndex,
+		Middlewares: readTags,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodGet,
+		Path:        routes.APITagShow.Path(),
+		Name:        routes.APITagShow.Name(),
+		Handler:     api.TagShow,
+		Middlewares: readTags,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodPost,
+		Path:        routes.APITagCreate.Path(),
+		Name:        routes.APITagCreate.Name(),
+		Handler:     api.TagCreate,
+		Middlewares: writeTags,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodPut,
+		Path:        routes.APITagUpdate.Path(),
+		Name:        routes.APITagUpdate.Name(),
+		Handler:     api.TagUpdate,
+		Middlewares: writeTags,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodDelete,
+		Path:        routes.APITagDestroy.Path(),
+		Name:        routes.APITagDestroy.Name(),
+		Handler:     api.TagDestroy,
+		Middlewares: writeTags,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodGet,
+		Path:        routes.APIProjectIndex.Path(),
+		Name:        routes.APIProjectIndex.Name(),
+		Handler:     api.ProjectIndex,
+		Middlewares: readProjects,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodGet,
+		Path:        routes.APIProjectShow.Path(),
+		Name:        routes.APIProjectShow.Name(),
+		Handler:     api.ProjectShow,
+		Middlewares: readProjects,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodPost,
+		Path:        routes.APIProjectCreate.Path(),
+		Name:        routes.APIProjectCreate.Name(),
+		Handler:     api.ProjectCreate,
+		Middlewares: writeProjects,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodPut,
+		Path:        routes.APIProjectUpdate.Path(),
+		Name:        routes.APIProjectUpdate.Name(),
+		Handler:     api.ProjectUpdate,
+		Middlewares: writeProjects,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodDelete,
+		Path:        routes.APIProjectDestroy.Path(),
+		Name:        routes.APIProjectDestroy.Name(),
+		Handler:     api.ProjectDestroy,
+		Middlewares: writeProjects,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodGet,
+		Path:        routes.APINewsletterIndex.Path(),
+		Name:        routes.APINewsletterIndex.Name(),
+		Handler:     api.NewsletterIndex,
+		Middlewares: readNewsletters,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodGet,
+		Path:        routes.APINewsletterShow.Path(),
+		Name:        routes.APINewsletterShow.Name(),
+		Handler:     api.NewsletterShow,
+		Middlewares: readNewsletters,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodPost,
+		Path:        routes.APINewsletterCreate.Path(),
+		Name:        routes.APINewsletterCreate.Name(),
+		Handler:     api.NewsletterCreate,
+		Middlewares: writeNewsletters,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodPut,
+		Path:        routes.APINewsletterUpdate.Path(),
+		Name:        routes.APINewsletterUpdate.Name(),
+		Handler:     api.NewsletterUpdate,
+		Middlewares: writeNewsletters,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodDelete,
+		Path:        routes.APINewsletterDestroy.Path(),
+		Name:        routes.APINewsletterDestroy.Name(),
+		Handler:     api.NewsletterDestroy,
+		Middlewares: writeNewsletters,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
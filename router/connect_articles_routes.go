@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"mortenvistisen/controllers"
+	"mortenvistisen/models"
 	"mortenvistisen/router/middleware"
 	"mortenvistisen/router/routes"
 
@@ -14,14 +15,16 @@ import (
 func (r Router) RegisterArticleRoutes(article controllers.Articles) error {
 	errs := []error{}
 
-	adminOnly := []echo.MiddlewareFunc{middleware.AdminOnly}
+	permitted := []echo.MiddlewareFunc{
+		middleware.RequirePermission(models.PermissionWriteArticles),
+	}
 
 	_, err := r.e.AddRoute(echo.Route{
 		Method:      http.MethodGet,
 		Path:        routes.ArticleIndex.Path(),
 		Name:        routes.ArticleIndex.Name(),
 		Handler:     article.Index,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -32,7 +35,7 @@ func (r Router) RegisterArticleRoutes(article controllers.Articles) error {
 		Path:        routes.ArticleShow.Path(),
 		Name:        routes.ArticleShow.Name(),
 		Handler:     article.Show,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -43,7 +46,7 @@ func (r Router) RegisterArticleRoutes(article controllers.Articles) error {
 		Path:        routes.ArticleNew.Path(),
 		Name:        routes.ArticleNew.Name(),
 		Handler:     article.New,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -54,7 +57,7 @@ func (r Router) RegisterArticleRoutes(article controllers.Articles) error {
 		Path:        routes.ArticleCreate.Path(),
 		Name:        routes.ArticleCreate.Name(),
 		Handler:     article.Create,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -65,7 +68,7 @@ func (r Router) RegisterArticleRoutes(article controllers.Articles) error {
 		Path:        routes.ArticleEdit.Path(),
 		Name:        routes.ArticleEdit.Name(),
 		Handler:     article.Edit,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -76,7 +79,7 @@ func (r Router) RegisterArticleRoutes(article controllers.Articles) error {
 		Path:        routes.ArticleUpdate.Path(),
 		Name:        routes.ArticleUpdate.Name(),
 		Handler:     article.Update,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -87,7 +90,18 @@ func (r Router) RegisterArticleRoutes(article controllers.Articles) error {
 		Path:        routes.ArticleDestroy.Path(),
 		Name:        routes.ArticleDestroy.Name(),
 		Handler:     article.Destroy,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodPut,
+		Path:        routes.ArticleTableOfContentsUpdate.Path(),
+		Name:        routes.ArticleTableOfContentsUpdate.Name(),
+		Handler:     article.UpdateTableOfContents,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -13,7 +13,7 @@ import (
 func (r Router) RegisterAssetsRoutes(assets controllers.Assets) error {
 	errs := []error{}
 
-	_, err := r.e.AddRoute(echo.Route{
+	err := r.addStaticRoute(echo.Route{
 		Method:  http.MethodGet,
 		Path:    routes.Robots.Path(),
 		Name:    routes.Robots.Name(),
@@ -23,7 +23,7 @@ func (r Router) RegisterAssetsRoutes(assets controllers.Assets) error {
 		errs = append(errs, err)
 	}
 
-	_, err = r.e.AddRoute(echo.Route{
+	err = r.addStaticRoute(echo.Route{
 		Method:  http.MethodGet,
 		Path:    routes.Sitemap.Path(),
 		Name:    routes.Sitemap.Name(),
@@ -33,7 +33,7 @@ func (r Router) RegisterAssetsRoutes(assets controllers.Assets) error {
 		errs = append(errs, err)
 	}
 
-	_, err = r.e.AddRoute(echo.Route{
+	err = r.addStaticRoute(echo.Route{
 		Method:  http.MethodGet,
 		Path:    routes.Stylesheet.Path(),
 		Name:    routes.Stylesheet.Name(),
@@ -42,7 +42,7 @@ func (r Router) RegisterAssetsRoutes(assets controllers.Assets) error {
 	if err != nil {
 		errs = append(errs, err)
 	}
-	_, err = r.e.AddRoute(echo.Route{
+	err = r.addStaticRoute(echo.Route{
 		Method:  http.MethodGet,
 		Path:    routes.Scripts.Path(),
 		Name:    routes.Scripts.Name(),
@@ -52,7 +52,7 @@ func (r Router) RegisterAssetsRoutes(assets controllers.Assets) error {
 		errs = append(errs, err)
 	}
 
-	_, err = r.e.AddRoute(echo.Route{
+	err = r.addStaticRoute(echo.Route{
 		Method:  http.MethodGet,
 		Path:    routes.Script.Path(),
 		Name:    routes.Script.Name(),
@@ -62,7 +62,7 @@ func (r Router) RegisterAssetsRoutes(assets controllers.Assets) error {
 		errs = append(errs, err)
 	}
 
-	_, err = r.e.AddRoute(echo.Route{
+	err = r.addStaticRoute(echo.Route{
 		Method:  http.MethodGet,
 		Path:    routes.Style.Path(),
 		Name:    routes.Style.Name(),
@@ -0,0 +1,33 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+
+	"mortenvistisen/controllers"
+	"mortenvistisen/models"
+	"mortenvistisen/router/middleware"
+	"mortenvistisen/router/routes"
+
+	"github.com/labstack/echo/v5"
+)
+
+func (r Router) RegisterAuditLogRoutes(auditLogs controllers.AuditLogs) error {
+	errs := []error{}
+	permitted := []echo.MiddlewareFunc{
+		middleware.RequirePermission(models.PermissionViewAuditLog),
+	}
+
+	_, err := r.e.AddRoute(echo.Route{
+		Method:      http.MethodGet,
+		Path:        routes.AuditLogIndex.Path(),
+		Name:        routes.AuditLogIndex.Name(),
+		Handler:     auditLogs.Index,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,142 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"mortenvistisen/controllers"
+	"mortenvistisen/models"
+	"mortenvistisen/router/middleware"
+	"mortenvistisen/router/routes"
+	"mortenvistisen/services"
+
+	"github.com/labstack/echo/v5"
+)
+
+func (r Router) RegisterCommentRoutes(comments controllers.Comments) error {
+	errs := []error{}
+	permitted := []echo.MiddlewareFunc{
+		middleware.RequirePermission(models.PermissionModerateComments),
+	}
+
+	_, err := r.e.AddRoute(echo.Route{
+		Method:  http.MethodPost,
+		Path:    routes.CommentCreate.Path(),
+		Name:    routes.CommentCreate.Name(),
+		Handler: comments.Create,
+		Middlewares: []echo.MiddlewareFunc{
+			r.mw.IPRateLimiter(
+				services.RateLimitPolicy{
+					Scope:       "comments.create",
+					Limit:       10,
+					Window:      time.Hour,
+					BanDuration: 24 * time.Hour,
+				},
+				routes.HomePage,
+			),
+		},
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:  http.MethodGet,
+		Path:    routes.CommentVerificationNew.Path(),
+		Name:    routes.CommentVerificationNew.Name(),
+		Handler: comments.VerificationNew,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:  http.MethodPost,
+		Path:    routes.CommentVerificationCreate.Path(),
+		Name:    routes.CommentVerificationCreate.Name(),
+		Handler: comments.VerificationCreate,
+		Middlewares: []echo.MiddlewareFunc{
+			r.mw.IPRateLimiter(
+				services.RateLimitPolicy{
+					Scope:       "comments.verification_create",
+					Limit:       10,
+					Window:      time.Hour,
+					BanDuration: 24 * time.Hour,
+				},
+				routes.CommentVerificationNew,
+			),
+		},
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodGet,
+		Path:        routes.CommentIndex.Path(),
+		Name:        routes.CommentIndex.Name(),
+		Handler:     comments.Index,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodPut,
+		Path:        routes.CommentApprove.Path(),
+		Name:        routes.CommentApprove.Name(),
+		Handler:     comments.Approve,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodPut,
+		Path:        routes.CommentReject.Path(),
+		Name:        routes.CommentReject.Name(),
+		Handler:     comments.Reject,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodPut,
+		Path:        routes.CommentMarkSpam.Path(),
+		Name:        routes.CommentMarkSpam.Name(),
+		Handler:     comments.MarkSpam,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodDelete,
+		Path:        routes.CommentDestroy.Path(),
+		Name:        routes.CommentDestroy.Name(),
+		Handler:     comments.Destroy,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodPut,
+		Path:        routes.ArticleCommentsUpdate.Path(),
+		Name:        routes.ArticleCommentsUpdate.Name(),
+		Handler:     comments.UpdateArticle,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
@@ -3,9 +3,11 @@ package router
 import (
 	"errors"
 	"net/http"
+	"time"
 
 	"mortenvistisen/controllers"
 	"mortenvistisen/router/routes"
+	"mortenvistisen/services"
 
 	"github.com/labstack/echo/v5"
 )
@@ -28,6 +30,17 @@ func (r Router) RegisterConfirmationsRoutes(confirmations controllers.Confirmati
 		Path:    routes.ConfirmationCreate.Path(),
 		Name:    routes.ConfirmationCreate.Name(),
 		Handler: confirmations.Create,
+		Middlewares: []echo.MiddlewareFunc{
+			r.mw.IPRateLimiter(
+				services.RateLimitPolicy{
+					Scope:       "confirmations.create",
+					Limit:       10,
+					Window:      time.Hour,
+					BanDuration: time.Hour,
+				},
+				routes.ConfirmationNew,
+			),
+		},
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -0,0 +1,33 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+
+	"mortenvistisen/controllers"
+	"mortenvistisen/models"
+	"mortenvistisen/router/middleware"
+	"mortenvistisen/router/routes"
+
+	"github.com/labstack/echo/v5"
+)
+
+func (r Router) RegisterContentExportRoutes(contentExports controllers.ContentExports) error {
+	errs := []error{}
+	permitted := []echo.MiddlewareFunc{
+		middleware.RequirePermission(models.PermissionExportContent),
+	}
+
+	_, err := r.e.AddRoute(echo.Route{
+		Method:      http.MethodGet,
+		Path:        routes.ContentExportDownload.Path(),
+		Name:        routes.ContentExportDownload.Name(),
+		Handler:     contentExports.Download,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
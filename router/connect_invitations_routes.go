@@ -0,0 +1,37 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+
+	"mortenvistisen/controllers"
+	"mortenvistisen/router/routes"
+
+	"github.com/labstack/echo/v5"
+)
+
+func (r Router) RegisterInvitationsRoutes(invitations controllers.Invitations) error {
+	errs := []error{}
+
+	_, err := r.e.AddRoute(echo.Route{
+		Method:  http.MethodGet,
+		Path:    routes.InvitationEdit.Path(),
+		Name:    routes.InvitationEdit.Name(),
+		Handler: invitations.Edit,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:  http.MethodPut,
+		Path:    routes.InvitationUpdate.Path(),
+		Name:    routes.InvitationUpdate.Name(),
+		Handler: invitations.Update,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
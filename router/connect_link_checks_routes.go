@@ -0,0 +1,44 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+
+	"mortenvistisen/controllers"
+	"mortenvistisen/models"
+	"mortenvistisen/router/middleware"
+	"mortenvistisen/router/routes"
+
+	"github.com/labstack/echo/v5"
+)
+
+func (r Router) RegisterLinkCheckRoutes(linkChecks controllers.LinkChecks) error {
+	errs := []error{}
+	permitted := []echo.MiddlewareFunc{
+		middleware.RequirePermission(models.PermissionCheckLinks),
+	}
+
+	_, err := r.e.AddRoute(echo.Route{
+		Method:      http.MethodGet,
+		Path:        routes.LinkCheckIndex.Path(),
+		Name:        routes.LinkCheckIndex.Name(),
+		Handler:     linkChecks.Index,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodPost,
+		Path:        routes.LinkCheckRun.Path(),
+		Name:        routes.LinkCheckRun.Name(),
+		Handler:     linkChecks.Run,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,78 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+
+	"mortenvistisen/controllers"
+	"mortenvistisen/models"
+	"mortenvistisen/router/middleware"
+	"mortenvistisen/router/routes"
+
+	"github.com/labstack/echo/v5"
+)
+
+func (r Router) RegisterMediaRoutes(media controllers.Media) error {
+	errs := []error{}
+	permitted := []echo.MiddlewareFunc{
+		middleware.RequirePermission(models.PermissionUploadMedia),
+	}
+
+	// Media files are part of the public site, so build-static copies the
+	// ones published pages link to.
+	err := r.addStaticRoute(echo.Route{
+		Method:  http.MethodGet,
+		Path:    routes.MediaFile.Path(),
+		Name:    routes.MediaFile.Name(),
+		Handler: media.File,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodGet,
+		Path:        routes.MediaIndex.Path(),
+		Name:        routes.MediaIndex.Name(),
+		Handler:     media.Index,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodPost,
+		Path:        routes.MediaCreate.Path(),
+		Name:        routes.MediaCreate.Name(),
+		Handler:     media.Create,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodPut,
+		Path:        routes.MediaUpdate.Path(),
+		Name:        routes.MediaUpdate.Name(),
+		Handler:     media.Update,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodDelete,
+		Path:        routes.MediaDestroy.Path(),
+		Name:        routes.MediaDestroy.Name(),
+		Handler:     media.Destroy,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"mortenvistisen/controllers"
+	"mortenvistisen/models"
 	"mortenvistisen/router/middleware"
 	"mortenvistisen/router/routes"
 
@@ -13,14 +14,16 @@ import (
 
 func (r Router) RegisterNewsletterRoutes(newsletter controllers.Newsletters) error {
 	errs := []error{}
-	adminOnly := []echo.MiddlewareFunc{middleware.AdminOnly}
+	permitted := []echo.MiddlewareFunc{
+		middleware.RequirePermission(models.PermissionManageNewsletters),
+	}
 
 	_, err := r.e.AddRoute(echo.Route{
 		Method:      http.MethodGet,
 		Path:        routes.NewsletterIndex.Path(),
 		Name:        routes.NewsletterIndex.Name(),
 		Handler:     newsletter.Index,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -31,7 +34,7 @@ func (r Router) RegisterNewsletterRoutes(newsletter controllers.Newsletters) err
 		Path:        routes.NewsletterShow.Path(),
 		Name:        routes.NewsletterShow.Name(),
 		Handler:     newsletter.Show,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -42,7 +45,7 @@ func (r Router) RegisterNewsletterRoutes(newsletter controllers.Newsletters) err
 		Path:        routes.NewsletterNew.Path(),
 		Name:        routes.NewsletterNew.Name(),
 		Handler:     newsletter.New,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -53,7 +56,7 @@ func (r Router) RegisterNewsletterRoutes(newsletter controllers.Newsletters) err
 		Path:        routes.NewsletterCreate.Path(),
 		Name:        routes.NewsletterCreate.Name(),
 		Handler:     newsletter.Create,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -64,7 +67,7 @@ func (r Router) RegisterNewsletterRoutes(newsletter controllers.Newsletters) err
 		Path:        routes.NewsletterEdit.Path(),
 		Name:        routes.NewsletterEdit.Name(),
 		Handler:     newsletter.Edit,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -75,7 +78,7 @@ func (r Router) RegisterNewsletterRoutes(newsletter controllers.Newsletters) err
 		Path:        routes.NewsletterUpdate.Path(),
 		Name:        routes.NewsletterUpdate.Name(),
 		Handler:     newsletter.Update,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -86,7 +89,7 @@ func (r Router) RegisterNewsletterRoutes(newsletter controllers.Newsletters) err
 		Path:        routes.NewsletterDestroy.Path(),
 		Name:        routes.NewsletterDestroy.Name(),
 		Handler:     newsletter.Destroy,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -0,0 +1,47 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+
+	"mortenvistisen/controllers"
+	"mortenvistisen/router/routes"
+
+	"github.com/labstack/echo/v5"
+)
+
+func (r Router) RegisterOGImageRoutes(ogImages controllers.OGImages) error {
+	errs := []error{}
+
+	err := r.addStaticRoute(echo.Route{
+		Method:  http.MethodGet,
+		Path:    routes.ArticleOGImage.Path(),
+		Name:    routes.ArticleOGImage.Name(),
+		Handler: ogImages.Article,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	err = r.addStaticRoute(echo.Route{
+		Method:  http.MethodGet,
+		Path:    routes.ProjectOGImage.Path(),
+		Name:    routes.ProjectOGImage.Name(),
+		Handler: ogImages.Project,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	err = r.addStaticRoute(echo.Route{
+		Method:  http.MethodGet,
+		Path:    routes.NewsletterOGImage.Path(),
+		Name:    routes.NewsletterOGImage.Name(),
+		Handler: ogImages.Newsletter,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
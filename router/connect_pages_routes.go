@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"mortenvistisen/controllers"
+	"mortenvistisen/models"
 	"mortenvistisen/router/middleware"
 	"mortenvistisen/router/routes"
 
@@ -14,7 +15,7 @@ import (
 func (r Router) RegisterPagesRoutes(pages controllers.Pages) error {
 	errs := []error{}
 
-	_, err := r.e.AddRoute(echo.Route{
+	err := r.addStaticRoute(echo.Route{
 		Method:  http.MethodGet,
 		Path:    routes.HomePage.Path(),
 		Name:    routes.HomePage.Name(),
@@ -33,7 +34,7 @@ func (r Router) RegisterPagesRoutes(pages controllers.Pages) error {
 		errs = append(errs, err)
 	}
 
-	_, err = r.e.AddRoute(echo.Route{
+	err = r.addStaticRoute(echo.Route{
 		Method:  http.MethodGet,
 		Path:    routes.AboutPage.Path(),
 		Name:    routes.AboutPage.Name(),
@@ -43,7 +44,7 @@ func (r Router) RegisterPagesRoutes(pages controllers.Pages) error {
 		errs = append(errs, err)
 	}
 
-	_, err = r.e.AddRoute(echo.Route{
+	err = r.addStaticRoute(echo.Route{
 		Method:  http.MethodGet,
 		Path:    routes.ArticleOverview.Path(),
 		Name:    routes.ArticleOverview.Name(),
@@ -53,7 +54,7 @@ func (r Router) RegisterPagesRoutes(pages controllers.Pages) error {
 		errs = append(errs, err)
 	}
 
-	_, err = r.e.AddRoute(echo.Route{
+	err = r.addStaticRoute(echo.Route{
 		Method:  http.MethodGet,
 		Path:    routes.Article.Path(),
 		Name:    routes.Article.Name(),
@@ -63,7 +64,47 @@ func (r Router) RegisterPagesRoutes(pages controllers.Pages) error {
 		errs = append(errs, err)
 	}
 
-	_, err = r.e.AddRoute(echo.Route{
+	err = r.addStaticRoute(echo.Route{
+		Method:  http.MethodGet,
+		Path:    routes.Series.Path(),
+		Name:    routes.Series.Name(),
+		Handler: pages.Series,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	err = r.addStaticRoute(echo.Route{
+		Method:  http.MethodGet,
+		Path:    routes.SeriesFeed.Path(),
+		Name:    routes.SeriesFeed.Name(),
+		Handler: pages.SeriesFeed,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	err = r.addStaticRoute(echo.Route{
+		Method:  http.MethodGet,
+		Path:    routes.Tag.Path(),
+		Name:    routes.Tag.Name(),
+		Handler: pages.Tag,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	err = r.addStaticRoute(echo.Route{
+		Method:  http.MethodGet,
+		Path:    routes.TagPage.Path(),
+		Name:    routes.TagPage.Name(),
+		Handler: pages.Tag,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	err = r.addStaticRoute(echo.Route{
 		Method:  http.MethodGet,
 		Path:    routes.NewsletterOverview.Path(),
 		Name:    routes.NewsletterOverview.Name(),
@@ -73,7 +114,7 @@ func (r Router) RegisterPagesRoutes(pages controllers.Pages) error {
 		errs = append(errs, err)
 	}
 
-	_, err = r.e.AddRoute(echo.Route{
+	err = r.addStaticRoute(echo.Route{
 		Method:  http.MethodGet,
 		Path:    routes.Newsletter.Path(),
 		Name:    routes.Newsletter.Name(),
@@ -83,7 +124,7 @@ func (r Router) RegisterPagesRoutes(pages controllers.Pages) error {
 		errs = append(errs, err)
 	}
 
-	_, err = r.e.AddRoute(echo.Route{
+	err = r.addStaticRoute(echo.Route{
 		Method:  http.MethodGet,
 		Path:    routes.ProjectOverview.Path(),
 		Name:    routes.ProjectOverview.Name(),
@@ -93,7 +134,7 @@ func (r Router) RegisterPagesRoutes(pages controllers.Pages) error {
 		errs = append(errs, err)
 	}
 
-	_, err = r.e.AddRoute(echo.Route{
+	err = r.addStaticRoute(echo.Route{
 		Method:  http.MethodGet,
 		Path:    routes.Project.Path(),
 		Name:    routes.Project.Name(),
@@ -109,7 +150,7 @@ func (r Router) RegisterPagesRoutes(pages controllers.Pages) error {
 		Name:        routes.AdminHome.Name(),
 		Handler: pages.AdminHome,
 		Middlewares: []echo.MiddlewareFunc{
-			middleware.AdminOnly,
+			middleware.RequirePermission(models.PermissionAccessAdmin),
 		},
 	})
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"mortenvistisen/controllers"
+	"mortenvistisen/models"
 	"mortenvistisen/router/middleware"
 	"mortenvistisen/router/routes"
 
@@ -13,14 +14,16 @@ import (
 
 func (r Router) RegisterProjectRoutes(project controllers.Projects) error {
 	errs := []error{}
-	adminOnly := []echo.MiddlewareFunc{middleware.AdminOnly}
+	permitted := []echo.MiddlewareFunc{
+		middleware.RequirePermission(models.PermissionWriteProjects),
+	}
 
 	_, err := r.e.AddRoute(echo.Route{
 		Method:      http.MethodGet,
 		Path:        routes.ProjectIndex.Path(),
 		Name:        routes.ProjectIndex.Name(),
 		Handler:     project.Index,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -31,7 +34,7 @@ func (r Router) RegisterProjectRoutes(project controllers.Projects) error {
 		Path:        routes.ProjectShow.Path(),
 		Name:        routes.ProjectShow.Name(),
 		Handler:     project.Show,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -42,7 +45,7 @@ func (r Router) RegisterProjectRoutes(project controllers.Projects) error {
 		Path:        routes.ProjectNew.Path(),
 		Name:        routes.ProjectNew.Name(),
 		Handler:     project.New,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -53,7 +56,7 @@ func (r Router) RegisterProjectRoutes(project controllers.Projects) error {
 		Path:        routes.ProjectCreate.Path(),
 		Name:        routes.ProjectCreate.Name(),
 		Handler:     project.Create,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -64,7 +67,7 @@ func (r Router) RegisterProjectRoutes(project controllers.Projects) error {
 		Path:        routes.ProjectEdit.Path(),
 		Name:        routes.ProjectEdit.Name(),
 		Handler:     project.Edit,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -75,7 +78,7 @@ func (r Router) RegisterProjectRoutes(project controllers.Projects) error {
 		Path:        routes.ProjectUpdate.Path(),
 		Name:        routes.ProjectUpdate.Name(),
 		Handler:     project.Update,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -86,7 +89,7 @@ func (r Router) RegisterProjectRoutes(project controllers.Projects) error {
 		Path:        routes.ProjectDestroy.Path(),
 		Name:        routes.ProjectDestroy.Name(),
 		Handler:     project.Destroy,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
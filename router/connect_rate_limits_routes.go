@@ -0,0 +1,44 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+
+	"mortenvistisen/controllers"
+	"mortenvistisen/models"
+	"mortenvistisen/router/middleware"
+	"mortenvistisen/router/routes"
+
+	"github.com/labstack/echo/v5"
+)
+
+func (r Router) RegisterRateLimitRoutes(rateLimits controllers.RateLimits) error {
+	errs := []error{}
+	permitted := []echo.MiddlewareFunc{
+		middleware.RequirePermission(models.PermissionManageUsers),
+	}
+
+	_, err := r.e.AddRoute(echo.Route{
+		Method:      http.MethodGet,
+		Path:        routes.RateLimitBanIndex.Path(),
+		Name:        routes.RateLimitBanIndex.Name(),
+		Handler:     rateLimits.Index,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodDelete,
+		Path:        routes.RateLimitBanDestroy.Path(),
+		Name:        routes.RateLimitBanDestroy.Name(),
+		Handler:     rateLimits.Destroy,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
@@ -3,10 +3,11 @@ package router
 import (
 	"errors"
 	"net/http"
+	"time"
 
 	"mortenvistisen/controllers"
-	"mortenvistisen/router/middleware"
 	"mortenvistisen/router/routes"
+	"mortenvistisen/services"
 
 	"github.com/labstack/echo/v5"
 )
@@ -30,7 +31,15 @@ func (r Router) RegisterRegistrationsRoutes(registrations controllers.Registrati
 		Name:    routes.RegistrationCreate.Name(),
 		Handler: registrations.Create,
 		Middlewares: []echo.MiddlewareFunc{
-			middleware.IPRateLimiter(5, routes.RegistrationNew),
+			r.mw.IPRateLimiter(
+				services.RateLimitPolicy{
+					Scope:       "registrations.create",
+					Limit:       5,
+					Window:      time.Hour,
+					BanDuration: 24 * time.Hour,
+				},
+				routes.RegistrationNew,
+			),
 		},
 	})
 	if err != nil {
@@ -0,0 +1,132 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+
+	"mortenvistisen/controllers"
+	"mortenvistisen/models"
+	"mortenvistisen/router/middleware"
+	"mortenvistisen/router/routes"
+
+	"github.com/labstack/echo/v5"
+)
+
+func (r Router) RegisterSeriesRoutes(series controllers.Series) error {
+	errs := []error{}
+	permitted := []echo.MiddlewareFunc{
+		middleware.RequirePermission(models.PermissionManageSeries),
+	}
+
+	_, err := r.e.AddRoute(echo.Route{
+		Method:      http.MethodGet,
+		Path:        routes.SeriesIndex.Path(),
+		Name:        routes.SeriesIndex.Name(),
+		Handler:     series.Index,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodGet,
+		Path:        routes.SeriesShow.Path(),
+		Name:        routes.SeriesShow.Name(),
+		Handler:     series.Show,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodGet,
+		Path:        routes.SeriesNew.Path(),
+		Name:        routes.SeriesNew.Name(),
+		Handler:     series.New,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodPost,
+		Path:        routes.SeriesCreate.Path(),
+		Name:        routes.SeriesCreate.Name(),
+		Handler:     series.Create,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodGet,
+		Path:        routes.SeriesEdit.Path(),
+		Name:        routes.SeriesEdit.Name(),
+		Handler:     series.Edit,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodPut,
+		Path:        routes.SeriesUpdate.Path(),
+		Name:        routes.SeriesUpdate.Name(),
+		Handler:     series.Update,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodDelete,
+		Path:        routes.SeriesDestroy.Path(),
+		Name:        routes.SeriesDestroy.Name(),
+		Handler:     series.Destroy,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodPost,
+		Path:        routes.SeriesAddArticle.Path(),
+		Name:        routes.SeriesAddArticle.Name(),
+		Handler:     series.AddArticle,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodDelete,
+		Path:        routes.SeriesRemoveArticle.Path(),
+		Name:        routes.SeriesRemoveArticle.Name(),
+		Handler:     series.RemoveArticle,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodPut,
+		Path:        routes.SeriesMoveArticle.Path(),
+		Name:        routes.SeriesMoveArticle.Name(),
+		Handler:     series.MoveArticle,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
@@ -3,10 +3,11 @@ package router
 import (
 	"errors"
 	"net/http"
+	"time"
 
 	"mortenvistisen/controllers"
-	"mortenvistisen/router/middleware"
 	"mortenvistisen/router/routes"
+	"mortenvistisen/services"
 
 	"github.com/labstack/echo/v5"
 )
@@ -30,7 +31,20 @@ func (r Router) RegisterSessionsRoutes(sessions controllers.Sessions) error {
 		Name:    routes.SessionCreate.Name(),
 		Handler: sessions.Create,
 		Middlewares: []echo.MiddlewareFunc{
-			middleware.IPRateLimiter(5, routes.SessionNew),
+			r.mw.IPRateLimiter(
+				services.RateLimitPolicy{
+					Scope:       "sessions.create",
+					Limit:       10,
+					Window:      10 * time.Minute,
+					BanDuration: 30 * time.Minute,
+				},
+				routes.SessionNew,
+			),
+			r.mw.AccountLockout(services.LockoutPolicy{
+				MaxFailures: 5,
+				Window:      15 * time.Minute,
+				Duration:    30 * time.Minute,
+			}),
 		},
 	})
 	if err != nil {
@@ -6,15 +6,19 @@ import (
 	"time"
 
 	"mortenvistisen/controllers"
+	"mortenvistisen/models"
 	"mortenvistisen/router/middleware"
 	"mortenvistisen/router/routes"
+	"mortenvistisen/services"
 
 	"github.com/labstack/echo/v5"
 )
 
 func (r Router) RegisterSubscriberRoutes(subscriber controllers.Subscribers) error {
 	errs := []error{}
-	adminOnly := []echo.MiddlewareFunc{middleware.AdminOnly}
+	permitted := []echo.MiddlewareFunc{
+		middleware.RequirePermission(models.PermissionManageSubscribers),
+	}
 
 	_, err := r.e.AddRoute(echo.Route{
 		Method:  http.MethodPost,
@@ -22,7 +26,15 @@ func (r Router) RegisterSubscriberRoutes(subscriber controllers.Subscribers) err
 		Name:    routes.SubscriberSignup.Name(),
 		Handler: subscriber.Signup,
 		Middlewares: []echo.MiddlewareFunc{
-			middleware.IPRateLimiterWithBan(3, time.Hour, 24*time.Hour, routes.HomePage),
+			r.mw.IPRateLimiter(
+				services.RateLimitPolicy{
+					Scope:       "subscribers.signup",
+					Limit:       3,
+					Window:      time.Hour,
+					BanDuration: 24 * time.Hour,
+				},
+				routes.HomePage,
+			),
 		},
 	})
 	if err != nil {
@@ -55,10 +67,13 @@ func (r Router) RegisterSubscriberRoutes(subscriber controllers.Subscribers) err
 		Name:    routes.SubscriberVerificationCreate.Name(),
 		Handler: subscriber.VerificationCreate,
 		Middlewares: []echo.MiddlewareFunc{
-			middleware.IPRateLimiterWithBan(
-				5,
-				time.Hour,
-				24*time.Hour,
+			r.mw.IPRateLimiter(
+				services.RateLimitPolicy{
+					Scope:       "subscribers.verification_create",
+					Limit:       5,
+					Window:      time.Hour,
+					BanDuration: 24 * time.Hour,
+				},
 				routes.SubscriberVerificationNew,
 			),
 		},
@@ -72,7 +87,7 @@ func (r Router) RegisterSubscriberRoutes(subscriber controllers.Subscribers) err
 		Path:        routes.SubscriberIndex.Path(),
 		Name:        routes.SubscriberIndex.Name(),
 		Handler:     subscriber.Index,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -83,7 +98,7 @@ func (r Router) RegisterSubscriberRoutes(subscriber controllers.Subscribers) err
 		Path:        routes.SubscriberShow.Path(),
 		Name:        routes.SubscriberShow.Name(),
 		Handler:     subscriber.Show,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -94,7 +109,7 @@ func (r Router) RegisterSubscriberRoutes(subscriber controllers.Subscribers) err
 		Path:        routes.SubscriberNew.Path(),
 		Name:        routes.SubscriberNew.Name(),
 		Handler:     subscriber.New,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -105,7 +120,7 @@ func (r Router) RegisterSubscriberRoutes(subscriber controllers.Subscribers) err
 		Path:        routes.SubscriberCreate.Path(),
 		Name:        routes.SubscriberCreate.Name(),
 		Handler:     subscriber.Create,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -116,7 +131,7 @@ func (r Router) RegisterSubscriberRoutes(subscriber controllers.Subscribers) err
 		Path:        routes.SubscriberEdit.Path(),
 		Name:        routes.SubscriberEdit.Name(),
 		Handler:     subscriber.Edit,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -127,7 +142,7 @@ func (r Router) RegisterSubscriberRoutes(subscriber controllers.Subscribers) err
 		Path:        routes.SubscriberUpdate.Path(),
 		Name:        routes.SubscriberUpdate.Name(),
 		Handler:     subscriber.Update,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -138,7 +153,7 @@ func (r Router) RegisterSubscriberRoutes(subscriber controllers.Subscribers) err
 		Path:        routes.SubscriberDestroy.Path(),
 		Name:        routes.SubscriberDestroy.Name(),
 		Handler:     subscriber.Destroy,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"mortenvistisen/controllers"
+	"mortenvistisen/models"
 	"mortenvistisen/router/middleware"
 	"mortenvistisen/router/routes"
 
@@ -13,14 +14,16 @@ import (
 
 func (r Router) RegisterTagRoutes(tag controllers.Tags) error {
 	errs := []error{}
-	adminOnly := []echo.MiddlewareFunc{middleware.AdminOnly}
+	permitted := []echo.MiddlewareFunc{
+		middleware.RequirePermission(models.PermissionManageTags),
+	}
 
 	_, err := r.e.AddRoute(echo.Route{
 		Method:      http.MethodGet,
 		Path:        routes.TagIndex.Path(),
 		Name:        routes.TagIndex.Name(),
 		Handler:     tag.Index,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -31,7 +34,7 @@ func (r Router) RegisterTagRoutes(tag controllers.Tags) error {
 		Path:        routes.TagShow.Path(),
 		Name:        routes.TagShow.Name(),
 		Handler:     tag.Show,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -42,7 +45,7 @@ func (r Router) RegisterTagRoutes(tag controllers.Tags) error {
 		Path:        routes.TagNew.Path(),
 		Name:        routes.TagNew.Name(),
 		Handler:     tag.New,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -53,7 +56,7 @@ func (r Router) RegisterTagRoutes(tag controllers.Tags) error {
 		Path:        routes.TagCreate.Path(),
 		Name:        routes.TagCreate.Name(),
 		Handler:     tag.Create,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -64,7 +67,7 @@ func (r Router) RegisterTagRoutes(tag controllers.Tags) error {
 		Path:        routes.TagEdit.Path(),
 		Name:        routes.TagEdit.Name(),
 		Handler:     tag.Edit,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -75,7 +78,7 @@ func (r Router) RegisterTagRoutes(tag controllers.Tags) error {
 		Path:        routes.TagUpdate.Path(),
 		Name:        routes.TagUpdate.Name(),
 		Handler:     tag.Update,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -86,7 +89,18 @@ func (r Router) RegisterTagRoutes(tag controllers.Tags) error {
 		Path:        routes.TagDestroy.Path(),
 		Name:        routes.TagDestroy.Name(),
 		Handler:     tag.Destroy,
-		Middlewares: adminOnly,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodPost,
+		Path:        routes.TagMerge.Path(),
+		Name:        routes.TagMerge.Name(),
+		Handler:     tag.Merge,
+		Middlewares: permitted,
 	})
 	if err != nil {
 		errs = append(errs, err)
@@ -0,0 +1,99 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+
+	"mortenvistisen/controllers"
+	"mortenvistisen/models"
+	"mortenvistisen/router/middleware"
+	"mortenvistisen/router/routes"
+
+	"github.com/labstack/echo/v5"
+)
+
+func (r Router) RegisterUserRoutes(users controllers.Users) error {
+	errs := []error{}
+	permitted := []echo.MiddlewareFunc{
+		middleware.RequirePermission(models.PermissionManageUsers),
+	}
+
+	_, err := r.e.AddRoute(echo.Route{
+		Method:      http.MethodGet,
+		Path:        routes.AdminUserIndex.Path(),
+		Name:        routes.AdminUserIndex.Name(),
+		Handler:     users.Index,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodGet,
+		Path:        routes.AdminUserInvitationNew.Path(),
+		Name:        routes.AdminUserInvitationNew.Name(),
+		Handler:     users.InvitationNew,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodPost,
+		Path:        routes.AdminUserInvitationCreate.Path(),
+		Name:        routes.AdminUserInvitationCreate.Name(),
+		Handler:     users.InvitationCreate,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodPost,
+		Path:        routes.AdminUserRoleUpdate.Path(),
+		Name:        routes.AdminUserRoleUpdate.Name(),
+		Handler:     users.UpdateRole,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodPost,
+		Path:        routes.AdminUserDeactivate.Path(),
+		Name:        routes.AdminUserDeactivate.Name(),
+		Handler:     users.Deactivate,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodPost,
+		Path:        routes.AdminUserReactivate.Path(),
+		Name:        routes.AdminUserReactivate.Name(),
+		Handler:     users.Reactivate,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodDelete,
+		Path:        routes.AdminUserDestroy.Path(),
+		Name:        routes.AdminUserDestroy.Name(),
+		Handler:     users.Destroy,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,99 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+
+	"mortenvistisen/controllers"
+	"mortenvistisen/models"
+	"mortenvistisen/router/middleware"
+	"mortenvistisen/router/routes"
+
+	"github.com/labstack/echo/v5"
+)
+
+func (r Router) RegisterWebhookRoutes(webhooks controllers.Webhooks) error {
+	errs := []error{}
+	permitted := []echo.MiddlewareFunc{
+		middleware.RequirePermission(models.PermissionManageWebhooks),
+	}
+
+	_, err := r.e.AddRoute(echo.Route{
+		Method:      http.MethodGet,
+		Path:        routes.WebhookIndex.Path(),
+		Name:        routes.WebhookIndex.Name(),
+		Handler:     webhooks.Index,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodGet,
+		Path:        routes.WebhookNew.Path(),
+		Name:        routes.WebhookNew.Name(),
+		Handler:     webhooks.New,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodPost,
+		Path:        routes.WebhookCreate.Path(),
+		Name:        routes.WebhookCreate.Name(),
+		Handler:     webhooks.Create,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodGet,
+		Path:        routes.WebhookShow.Path(),
+		Name:        routes.WebhookShow.Name(),
+		Handler:     webhooks.Show,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodGet,
+		Path:        routes.WebhookEdit.Path(),
+		Name:        routes.WebhookEdit.Name(),
+		Handler:     webhooks.Edit,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodPut,
+		Path:        routes.WebhookUpdate.Path(),
+		Name:        routes.WebhookUpdate.Name(),
+		Handler:     webhooks.Update,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodDelete,
+		Path:        routes.WebhookDestroy.Path(),
+		Name:        routes.WebhookDestroy.Name(),
+		Handler:     webhooks.Destroy,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,89 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"mortenvistisen/controllers"
+	"mortenvistisen/models"
+	"mortenvistisen/router/middleware"
+	"mortenvistisen/router/routes"
+	"mortenvistisen/services"
+
+	"github.com/labstack/echo/v5"
+)
+
+func (r Router) RegisterWebmentionRoutes(webmentions controllers.Webmentions) error {
+	errs := []error{}
+	permitted := []echo.MiddlewareFunc{
+		middleware.RequirePermission(models.PermissionModerateWebmentions),
+	}
+
+	_, err := r.e.AddRoute(echo.Route{
+		Method:  http.MethodPost,
+		Path:    routes.WebmentionReceive.Path(),
+		Name:    routes.WebmentionReceive.Name(),
+		Handler: webmentions.Receive,
+		Middlewares: []echo.MiddlewareFunc{
+			r.mw.IPRateLimiter(
+				services.RateLimitPolicy{
+					Scope:       "webmentions.receive",
+					Limit:       30,
+					Window:      time.Hour,
+					BanDuration: time.Hour,
+				},
+				routes.HomePage,
+			),
+		},
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodGet,
+		Path:        routes.WebmentionIndex.Path(),
+		Name:        routes.WebmentionIndex.Name(),
+		Handler:     webmentions.Index,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodPut,
+		Path:        routes.WebmentionApprove.Path(),
+		Name:        routes.WebmentionApprove.Name(),
+		Handler:     webmentions.Approve,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodPut,
+		Path:        routes.WebmentionReject.Path(),
+		Name:        routes.WebmentionReject.Name(),
+		Handler:     webmentions.Reject,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	_, err = r.e.AddRoute(echo.Route{
+		Method:      http.MethodDelete,
+		Path:        routes.WebmentionDestroy.Path(),
+		Name:        routes.WebmentionDestroy.Name(),
+		Handler:     webmentions.Destroy,
+		Middlewares: permitted,
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
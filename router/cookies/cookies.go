@@ -17,14 +17,20 @@ var AppKey renderer.CookieKey = "app_cookie_context"
 const (
 	isAuthenticated = "is_authenticated"
 	isAdmin = "is_admin"
+	role = "role"
 	userID = "user_id"
 )
 
 type App struct {
 	UserID uuid.UUID
 	IsAdmin bool
+	Role models.Role
 	IsAuthenticated bool
 }
+
+func (a App) Can(permission models.Permission) bool {
+	return a.IsAuthenticated && a.Role.Can(permission)
+}
 func CreateAppSession(c *echo.Context, user models.User) error {
 	sess, err := session.Get(config.AppCookieSessionName, c)
 	if err != nil {
@@ -32,7 +38,8 @@ func CreateAppSession(c *echo.Context, user models.User) error {
 	}
 
 	sess.Values[isAuthenticated] = true
-	sess.Values[isAdmin] = user.IsAdmin
+	sess.Values[isAdmin] = user.Role == models.RoleAdmin
+	sess.Values[role] = string(user.Role)
 	sess.Values[userID] = user.ID.String()
 
 	return sess.Save(c.Request(), c.Response())
@@ -44,6 +51,11 @@ func DestroyAppSession(c *echo.Context) error {
 		return err
 	}
 
+	delete(sess.Values, isAuthenticated)
+	delete(sess.Values, isAdmin)
+	delete(sess.Values, role)
+	delete(sess.Values, userID)
+
 	sess.Options.MaxAge = -1
 	return sess.Save(c.Request(), c.Response())
 }
@@ -71,6 +83,9 @@ func GetApp(c *echo.Context) App {
 	if v, ok := sess.Values[isAdmin].(bool); ok {
 		app.IsAdmin = v
 	}
+	if v, ok := sess.Values[role].(string); ok {
+		app.Role = models.Role(v)
+	}
 	if v, ok := sess.Values[userID].(string); ok {
 		app.UserID, _ = uuid.Parse(v)
 	}
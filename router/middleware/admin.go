@@ -3,6 +3,7 @@ package middleware
 import (
 	"net/http"
 
+	"mortenvistisen/models"
 	"mortenvistisen/router/cookies"
 	"mortenvistisen/router/routes"
 
@@ -12,10 +13,24 @@ import (
 func AdminOnly(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c *echo.Context) error {
 		app := cookies.GetApp(c)
-		if app.IsAuthenticated && app.IsAdmin {
+		if app.IsAuthenticated && app.Role == models.RoleAdmin {
 			return next(c)
 		}
 
 		return c.Redirect(http.StatusSeeOther, routes.HomePage.URL())
 	}
 }
+
+// RequirePermission only lets the request through when the session role
+// grants permission.
+func RequirePermission(permission models.Permission) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			if cookies.GetApp(c).Can(permission) {
+				return next(c)
+			}
+
+			return c.Redirect(http.StatusSeeOther, routes.HomePage.URL())
+		}
+	}
+}
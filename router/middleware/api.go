@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"mortenvistisen/models"
+	"mortenvistisen/router/apierror"
+
+	"github.com/labstack/echo/v5"
+)
+
+const (
+	apiTokenKey = "api_token"
+	apiUserKey  = "api_user"
+)
+
+// APIAuthentication resolves the bearer personal access token on the request
+// and stores the token and its owner in the context.
+func (m Middleware) APIAuthentication(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c *echo.Context) error {
+		ctx := c.Request().Context()
+
+		header := c.Request().Header.Get(echo.HeaderAuthorization)
+		plain, found := strings.CutPrefix(header, "Bearer ")
+		plain = strings.TrimSpace(plain)
+		if !found || plain == "" {
+			return apierror.Write(c, http.StatusUnauthorized, apierror.CodeUnauthenticated, "missing bearer token")
+		}
+
+		token, err := models.FindPersonalAccessToken(ctx, m.db.Conn(), m.cfg.Auth.Pepper, plain)
+		if err != nil {
+			if !errors.Is(err, sql.ErrNoRows) {
+				slog.ErrorContext(ctx, "could not look up personal access token", "error", err)
+				return apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "could not authenticate request")
+			}
+			return apierror.Write(c, http.StatusUnauthorized, apierror.CodeUnauthenticated, "invalid token")
+		}
+		if token.IsExpired() {
+			return apierror.Write(c, http.StatusUnauthorized, apierror.CodeUnauthenticated, "token has expired")
+		}
+
+		user, err := models.FindUser(ctx, m.db.Conn(), token.UserID)
+		if err != nil || user.IsDeactivated() {
+			return apierror.Write(c, http.StatusUnauthorized, apierror.CodeUnauthenticated, "invalid token")
+		}
+
+		if err := models.TouchPersonalAccessToken(ctx, m.db.Conn(), token.ID); err != nil {
+			slog.ErrorContext(ctx, "could not record token usage", "token_id", token.ID, "error", err)
+		}
+
+		c.Set(apiTokenKey, token)
+		c.Set(apiUserKey, user)
+
+		return next(c)
+	}
+}
+
+// RequireAPIScope only lets the request through when the token carries scope
+// and the owner's role still grants the matching permission.
+func RequireAPIScope(scope models.APIScope) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			token, hasToken := c.Get(apiTokenKey).(models.PersonalAccessToken)
+			user, hasUser := GetAPIUser(c)
+			if !hasToken || !hasUser {
+				return apierror.Write(c, http.StatusUnauthorized, apierror.CodeUnauthenticated, "missing bearer token")
+			}
+
+			if !token.HasScope(scope) || !user.Role.Can(scope.Permission()) {
+				return apierror.Write(c, http.StatusForbidden, apierror.CodeForbidden, "token is missing the "+string(scope)+" scope")
+			}
+
+			return next(c)
+		}
+	}
+}
+
+func GetAPIUser(c *echo.Context) (models.User, bool) {
+	user, ok := c.Get(apiUserKey).(models.User)
+	return user, ok
+}
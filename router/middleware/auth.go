@@ -1,21 +1,18 @@
 package middleware
 
 import (
+	"log/slog"
 	"net/http"
-	"time"
 
 	"mortenvistisen/internal/routing"
 	"mortenvistisen/router/cookies"
 	"mortenvistisen/router/routes"
+	"mortenvistisen/services"
 
 	"github.com/labstack/echo/v5"
-	"github.com/maypok86/otter/v2"
 )
 
-type ipRateLimitState struct {
-	Hits   int32
-	Banned bool
-}
+const lockoutPolicyKey = "account_lockout_policy"
 
 func AuthOnly(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c *echo.Context) error {
@@ -27,69 +24,72 @@ func AuthOnly(next echo.HandlerFunc) echo.HandlerFunc {
 	}
 }
 
-func IPRateLimiter(
-	limit int32,
-	redirectURL routing.Route,
-) func(next echo.HandlerFunc) echo.HandlerFunc {
-	return ipRateLimiter(limit, 10*time.Minute, 10*time.Minute, redirectURL)
-}
-
-func IPRateLimiterWithBan(
-	limit int32,
-	window time.Duration,
-	banDuration time.Duration,
-	redirectURL routing.Route,
-) func(next echo.HandlerFunc) echo.HandlerFunc {
-	return ipRateLimiter(limit, window, banDuration, redirectURL)
-}
-
-func ipRateLimiter(
-	limit int32,
-	window time.Duration,
-	banDuration time.Duration,
+// IPRateLimiter limits requests per client IP using the Postgres backed
+// sliding window described by policy. Banned clients are redirected to
+// redirectURL with a flash message.
+func (m Middleware) IPRateLimiter(
+	policy services.RateLimitPolicy,
 	redirectURL routing.Route,
-) func(next echo.HandlerFunc) echo.HandlerFunc {
-	cache := otter.Must(&otter.Options[string, ipRateLimitState]{
-		MaximumSize: 1000,
-		ExpiryCalculator: otter.ExpiryCreatingFunc(
-			func(entry otter.Entry[string, ipRateLimitState]) time.Duration {
-				if entry.Value.Banned {
-					return banDuration
-				}
-
-				return window
-			},
-		),
-	})
+) echo.MiddlewareFunc {
+	// Policies are fixed when routes are registered, so a policy the prune
+	// job would weaken stops the app from starting.
+	if err := policy.Validate(); err != nil {
+		panic(err)
+	}
 
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c *echo.Context) error {
-			ip := c.RealIP()
+			allowed, err := services.CheckRateLimit(
+				c.Request().Context(),
+				m.db,
+				policy,
+				c.RealIP(),
+			)
+			if err != nil {
+				slog.ErrorContext(
+					c.Request().Context(),
+					"could not check rate limit",
+					"scope",
+					policy.Scope,
+					"error",
+					err,
+				)
 
-			hits, ok := cache.GetIfPresent(ip)
-			if !ok {
-				cache.Set(ip, ipRateLimitState{
-					Hits: 1,
-				})
 				return next(c)
 			}
 
-			if hits.Banned {
-				return c.Redirect(http.StatusTooManyRequests, redirectURL.URL())
+			if allowed {
+				return next(c)
 			}
 
-			if hits.Hits >= limit {
-				cache.Set(ip, ipRateLimitState{
-					Hits:   hits.Hits + 1,
-					Banned: true,
-				})
-				return c.Redirect(http.StatusTooManyRequests, redirectURL.URL())
+			if flashErr := cookies.AddFlash(c, cookies.FlashError, "Too many attempts. Please try again later."); flashErr != nil {
+				return c.NoContent(http.StatusTooManyRequests)
 			}
 
-			hits.Hits++
-			cache.Set(ip, hits)
+			return c.Redirect(http.StatusSeeOther, redirectURL.URL())
+		}
+	}
+}
+
+// AccountLockout makes the lockout policy available to the route handler,
+// which applies it when authenticating.
+func (m Middleware) AccountLockout(
+	policy services.LockoutPolicy,
+) echo.MiddlewareFunc {
+	if err := policy.Validate(); err != nil {
+		panic(err)
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			c.Set(lockoutPolicyKey, policy)
 
 			return next(c)
 		}
 	}
 }
+
+func GetLockoutPolicy(c *echo.Context) (services.LockoutPolicy, bool) {
+	policy, ok := c.Get(lockoutPolicyKey).(services.LockoutPolicy)
+	return policy, ok
+}
@@ -2,6 +2,7 @@
 package middleware
 
 import (
+	"database/sql"
 	"errors"
 	"log/slog"
 	"net/http"
@@ -11,6 +12,7 @@ import (
 	"mortenvistisen/config"
 	"mortenvistisen/internal/server"
 	"mortenvistisen/internal/storage"
+	"mortenvistisen/models"
 	"mortenvistisen/router/cookies"
 	"mortenvistisen/router/routes"
 	"mortenvistisen/telemetry"
@@ -24,11 +26,12 @@ import (
 )
 
 type Middleware struct {
-	db storage.Pool
+	db  storage.Pool
+	cfg config.Config
 }
 
-func New(db storage.Pool) Middleware {
-	return Middleware{db: db}
+func New(db storage.Pool, cfg config.Config) Middleware {
+	return Middleware{db: db, cfg: cfg}
 }
 
 func (m Middleware) RegisterAppContext(
@@ -78,6 +81,38 @@ func (m Middleware) ValidateSession(
 			return next(c)
 		}
 
+		app := cookies.GetApp(c)
+		if !app.IsAuthenticated {
+			return next(c)
+		}
+
+		// Re-check the account so deactivations and role changes take
+		// effect without waiting for the session to expire.
+		user, err := models.FindUser(c.Request().Context(), m.db.Conn(), app.UserID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			slog.ErrorContext(
+				c.Request().Context(),
+				"could not validate session user",
+				"error",
+				err,
+			)
+			return next(c)
+		}
+
+		if err != nil || user.IsDeactivated() {
+			if err := cookies.DestroyAppSession(c); err != nil {
+				return err
+			}
+
+			return next(c)
+		}
+
+		if user.Role != app.Role {
+			if err := cookies.CreateAppSession(c, user); err != nil {
+				return err
+			}
+		}
+
 		return next(c)
 	}
 }
@@ -188,6 +223,16 @@ func (m Middleware) TraceRouteAttributes(tel *telemetry.Telemetry) echo.Middlewa
 	}
 }
 
+// skipCSRF reports whether path is exempt from CSRF checks: the token
+// authenticated API, static assets, and the webmention endpoint and
+// ActivityPub inbox, which other sites POST to by design.
+func skipCSRF(path string) bool {
+	return strings.Contains(path, routes.APIPrefix) ||
+		strings.Contains(path, routes.AssetsPrefix) ||
+		path == routes.WebmentionReceive.URL() ||
+		path == routes.ActivityPubInbox.URL()
+}
+
 func (m Middleware) CSRFMiddleware(cfg config.Config, csrfName string) (echo.MiddlewareFunc, error) {
 	strategy := strings.TrimSpace(cfg.App.CSRFStrategy)
 
@@ -211,8 +256,7 @@ func (m Middleware) CSRFMiddleware(cfg config.Config, csrfName string) (echo.Mid
 
 	csrfConfig := echomw.CSRFConfig{
 		Skipper: func(c *echo.Context) bool {
-			return strings.Contains(c.Request().URL.Path, routes.APIPrefix) ||
-				strings.Contains(c.Request().URL.Path, routes.AssetsPrefix)
+			return skipCSRF(c.Request().URL.Path)
 		},
 		TokenLookup:    tokenLookup,
 		CookiePath:     "/",
@@ -233,9 +277,7 @@ func (m Middleware) CSRFMiddleware(cfg config.Config, csrfName string) (echo.Mid
 
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c *echo.Context) error {
-			path := c.Request().URL.Path
-			if strings.Contains(path, routes.APIPrefix) ||
-				strings.Contains(path, routes.AssetsPrefix) {
+			if skipCSRF(c.Request().URL.Path) {
 				return next(c)
 			}
 
@@ -20,12 +20,15 @@ import (
 
 type Router struct {
 	e       *echo.Echo
+	mw      middleware.Middleware
+	static  *[]echo.RouteInfo
 	Handler http.Handler
 }
 
 func New(
 	enableHTTPInstrumentation bool,
 	globalMiddleware []echo.MiddlewareFunc,
+	mw middleware.Middleware,
 ) (*Router, error) {
 	gob.Register(uuid.UUID{})
 	gob.Register(cookies.FlashMessage{})
@@ -41,10 +44,29 @@ func New(
 
 	return &Router{
 		e:       router,
+		mw:      mw,
+		static:  &[]echo.RouteInfo{},
 		Handler: handler,
 	}, nil
 }
 
+// addStaticRoute registers a GET route whose response depends only on
+// published content, so build-static can render it to a file.
+func (r Router) addStaticRoute(route echo.Route) error {
+	info, err := r.e.AddRoute(route)
+	if err != nil {
+		return err
+	}
+
+	*r.static = append(*r.static, info)
+	return nil
+}
+
+// StaticRoutes returns the routes registered through addStaticRoute.
+func (r *Router) StaticRoutes() []echo.RouteInfo {
+	return *r.static
+}
+
 func SetupGlobalMiddleware(
 	cfg config.Config,
 	tel *telemetry.Telemetry,
@@ -61,6 +83,7 @@ func SetupGlobalMiddleware(
 	// Order matters: middlewares execute in the order listed, with Recover last
 	// to catch panics from all preceding middlewares.
 	middlewares := []echo.MiddlewareFunc{
+		echomw.RequestID(),
 		mw.TraceRouteAttributes(tel),
 		mw.Logger(tel),
 		session.Middleware(
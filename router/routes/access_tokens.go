@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"mortenvistisen/internal/routing"
+)
+
+const AccessTokenPrefix = "/access-tokens"
+
+var AccessTokenIndex = routing.NewSimpleRoute(
+	"",
+	"access_tokens.index",
+	AdminPrefix+AccessTokenPrefix,
+)
+
+var AccessTokenCreate = routing.NewSimpleRoute(
+	"",
+	"access_tokens.create",
+	AdminPrefix+AccessTokenPrefix,
+)
+
+var AccessTokenDestroy = routing.NewRouteWithUUIDID(
+	"/:id",
+	"access_tokens.destroy",
+	AdminPrefix+AccessTokenPrefix,
+)
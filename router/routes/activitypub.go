@@ -0,0 +1,45 @@
+package routes
+
+import (
+	"mortenvistisen/internal/routing"
+)
+
+const ActivityPubPrefix = "/ap"
+
+var WebFinger = routing.NewSimpleRoute(
+	"/.well-known/webfinger",
+	"activitypub.webfinger",
+	"",
+)
+
+var ActivityPubActor = routing.NewSimpleRoute(
+	"/actor",
+	"activitypub.actor",
+	ActivityPubPrefix,
+)
+
+// ActivityPubInbox is where remote servers POST activities. It is exempt
+// from CSRF checks and authenticated with HTTP signatures instead.
+var ActivityPubInbox = routing.NewSimpleRoute(
+	"/inbox",
+	"activitypub.inbox",
+	ActivityPubPrefix,
+)
+
+var ActivityPubOutbox = routing.NewSimpleRoute(
+	"/outbox",
+	"activitypub.outbox",
+	ActivityPubPrefix,
+)
+
+var ActivityPubFollowers = routing.NewSimpleRoute(
+	"/followers",
+	"activitypub.followers",
+	ActivityPubPrefix,
+)
+
+var ActivityPubArticle = routing.NewRouteWithSlug(
+	"/articles/:slug",
+	"activitypub.article",
+	ActivityPubPrefix,
+)
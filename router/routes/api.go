@@ -11,3 +11,125 @@ var Health = routing.NewSimpleRoute(
 	"api.health",
 	APIPrefix,
 )
+
+const APIV1Prefix = APIPrefix + "/v1"
+
+var APIArticleIndex = routing.NewSimpleRoute(
+	"/articles",
+	"api.v1.articles.index",
+	APIV1Prefix,
+)
+
+var APIArticleShow = routing.NewRouteWithSerialID(
+	"/articles/:id",
+	"api.v1.articles.show",
+	APIV1Prefix,
+)
+
+var APIArticleCreate = routing.NewSimpleRoute(
+	"/articles",
+	"api.v1.articles.create",
+	APIV1Prefix,
+)
+
+var APIArticleUpdate = routing.NewRouteWithSerialID(
+	"/articles/:id",
+	"api.v1.articles.update",
+	APIV1Prefix,
+)
+
+var APIArticleDestroy = routing.NewRouteWithSerialID(
+	"/articles/:id",
+	"api.v1.articles.destroy",
+	APIV1Prefix,
+)
+
+var APITagIndex = routing.NewSimpleRoute(
+	"/tags",
+	"api.v1.tags.index",
+	APIV1Prefix,
+)
+
+var APITagShow = routing.NewRouteWithSerialID(
+	"/tags/:id",
+	"api.v1.tags.show",
+	APIV1Prefix,
+)
+
+var APITagCreate = routing.NewSimpleRoute(
+	"/tags",
+	"api.v1.tags.create",
+	APIV1Prefix,
+)
+
+var APITagUpdate = routing.NewRouteWithSerialID(
+	"/tags/:id",
+	"api.v1.tags.update",
+	APIV1Prefix,
+)
+
+var APITagDestroy = routing.NewRouteWithSerialID(
+	"/tags/:id",
+	"api.v1.tags.destroy",
+	APIV1Prefix,
+)
+
+var APIProjectIndex = routing.NewSimpleRoute(
+	"/projects",
+	"api.v1.projects.index",
+	APIV1Prefix,
+)
+
+var APIProjectShow = routing.NewRouteWithSerialID(
+	"/projects/:id",
+	"api.v1.projects.show",
+	APIV1Prefix,
+)
+
+var APIProjectCreate = routing.NewSimpleRoute(
+	"/projects",
+	"api.v1.projects.create",
+	APIV1Prefix,
+)
+
+var APIProjectUpdate = routing.NewRouteWithSerialID(
+	"/projects/:id",
+	"api.v1.projects.update",
+	APIV1Prefix,
+)
+
+var APIProjectDestroy = routing.NewRouteWithSerialID(
+	"/projects/:id",
+	"api.v1.projects.destroy",
+	APIV1Prefix,
+)
+
+var APINewsletterIndex = routing.NewSimpleRoute(
+	"/newsletters",
+	"api.v1.newsletters.index",
+	APIV1Prefix,
+)
+
+var APINewsletterShow = routing.NewRouteWithSerialID(
+	"/newsletters/:id",
+	"api.v1.newsletters.show",
+	APIV1Prefix,
+)
+
+var APINewsletterCreate = routing.NewSimpleRoute(
+	"/newsletters",
+	"api.v1.newsletters.create",
+	APIV1Prefix,
+)
+
+var APINewsletterUpdate = routing.NewRouteWithSerialID(
+	"/newsletters/:id",
+	"api.v1.newsletters.update",
+	APIV1Prefix,
+)
+
+var APINewsletterDestroy = routing.NewRouteWithSerialID(
+	"/newsletters/:id",
+	"api.v1.newsletters.destroy",
+	APIV1Prefix,
+)
@@ -60,3 +60,11 @@ var ArticleDestroy = routing.NewRouteWithSerialID(
 	"articles.destroy",
 	AdminPrefix+ArticlePrefix,
 )
+
+// ArticleTableOfContentsUpdate turns an article's table of contents on or
+// off.
+var ArticleTableOfContentsUpdate = routing.NewRouteWithSerialID(
+	"/:id/table-of-contents",
+	"articles.table_of_contents.update",
+	AdminPrefix+ArticlePrefix,
+)
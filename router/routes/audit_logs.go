@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"mortenvistisen/internal/routing"
+)
+
+const AuditLogPrefix = "/audit-log"
+
+var AuditLogIndex = routing.NewSimpleRoute(
+	"",
+	"audit_logs.index",
+	AdminPrefix+AuditLogPrefix,
+)
@@ -0,0 +1,63 @@
+package routes
+
+import (
+	"mortenvistisen/internal/routing"
+)
+
+const CommentPrefix = "/comments"
+
+// CommentCreate is where readers post comments on an article.
+var CommentCreate = routing.NewRouteWithSlug(
+	"/:slug/comments",
+	"comments.create",
+	"/posts",
+)
+
+var CommentVerificationNew = routing.NewSimpleRoute(
+	"/verify",
+	"comments.verify.new",
+	CommentPrefix,
+)
+
+var CommentVerificationCreate = routing.NewSimpleRoute(
+	"/verify",
+	"comments.verify.create",
+	CommentPrefix,
+)
+
+var CommentIndex = routing.NewSimpleRoute(
+	"",
+	"comments.index",
+	AdminPrefix+CommentPrefix,
+)
+
+var CommentApprove = routing.NewRouteWithUUIDID(
+	"/:id/approve",
+	"comments.approve",
+	AdminPrefix+CommentPrefix,
+)
+
+var CommentReject = routing.NewRouteWithUUIDID(
+	"/:id/reject",
+	"comments.reject",
+	AdminPrefix+CommentPrefix,
+)
+
+var CommentMarkSpam = routing.NewRouteWithUUIDID(
+	"/:id/spam",
+	"comments.spam",
+	AdminPrefix+CommentPrefix,
+)
+
+var CommentDestroy = routing.NewRouteWithUUIDID(
+	"/:id",
+	"comments.destroy",
+	AdminPrefix+CommentPrefix,
+)
+
+// ArticleCommentsUpdate opens or closes an article for comments.
+var ArticleCommentsUpdate = routing.NewRouteWithSerialID(
+	"/:id/comments",
+	"articles.comments.update",
+	AdminPrefix+ArticlePrefix,
+)
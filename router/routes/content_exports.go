@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"mortenvistisen/internal/routing"
+)
+
+const ContentExportPrefix = "/export"
+
+var ContentExportDownload = routing.NewSimpleRoute(
+	"",
+	"content_exports.download",
+	AdminPrefix+ContentExportPrefix,
+)
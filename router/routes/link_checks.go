@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"mortenvistisen/internal/routing"
+)
+
+const LinkCheckPrefix = "/links"
+
+// LinkCheckIndex is the broken link report.
+var LinkCheckIndex = routing.NewSimpleRoute(
+	"",
+	"link_checks.index",
+	AdminPrefix+LinkCheckPrefix,
+)
+
+// LinkCheckRun queues a check of every link in published content.
+var LinkCheckRun = routing.NewSimpleRoute(
+	"/check",
+	"link_checks.run",
+	AdminPrefix+LinkCheckPrefix,
+)
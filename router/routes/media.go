@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"mortenvistisen/internal/routing"
+)
+
+const MediaPrefix = "/media"
+
+// MediaFile serves a stored media variant by its storage key.
+var MediaFile = routing.NewRouteWithFile(
+	"/:file",
+	"media.file",
+	MediaPrefix,
+)
+
+var MediaIndex = routing.NewSimpleRoute(
+	"",
+	"media.index",
+	AdminPrefix+MediaPrefix,
+)
+
+var MediaCreate = routing.NewSimpleRoute(
+	"",
+	"media.create",
+	AdminPrefix+MediaPrefix,
+)
+
+var MediaUpdate = routing.NewRouteWithUUIDID(
+	"/:id",
+	"media.update",
+	AdminPrefix+MediaPrefix,
+)
+
+var MediaDestroy = routing.NewRouteWithUUIDID(
+	"/:id",
+	"media.destroy",
+	AdminPrefix+MediaPrefix,
+)
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"mortenvistisen/internal/routing"
+)
+
+// ArticleOGImage is the generated Open Graph image of an article.
+var ArticleOGImage = routing.NewRouteWithSlug(
+	"/:slug/og.png",
+	"articles.og_image",
+	"/posts",
+)
+
+var ProjectOGImage = routing.NewRouteWithSlug(
+	"/:slug/og.png",
+	"projects.og_image",
+	ProjectPrefix,
+)
+
+var NewsletterOGImage = routing.NewRouteWithSlug(
+	"/:slug/og.png",
+	"newsletters.og_image",
+	NewsletterPrefix,
+)
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"mortenvistisen/internal/routing"
+)
+
+const RateLimitPrefix = "/rate-limits"
+
+var RateLimitBanIndex = routing.NewSimpleRoute(
+	"",
+	"rate_limit_bans.index",
+	AdminPrefix+RateLimitPrefix,
+)
+
+var RateLimitBanDestroy = routing.NewRouteWithSerialID(
+	"/:id",
+	"rate_limit_bans.destroy",
+	AdminPrefix+RateLimitPrefix,
+)
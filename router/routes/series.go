@@ -0,0 +1,81 @@
+package routes
+
+import (
+	"mortenvistisen/internal/routing"
+)
+
+const SeriesPrefix = "/series"
+
+var Series = routing.NewRouteWithSlug(
+	"/:slug",
+	"series.show.slug",
+	"/series",
+)
+
+var SeriesFeed = routing.NewRouteWithSlug(
+	"/:slug/feed.xml",
+	"series.feed",
+	"/series",
+)
+
+var SeriesIndex = routing.NewSimpleRoute(
+	"",
+	"series.index",
+	AdminPrefix+SeriesPrefix,
+)
+
+var SeriesShow = routing.NewRouteWithSerialID(
+	"/:id",
+	"series.show",
+	AdminPrefix+SeriesPrefix,
+)
+
+var SeriesNew = routing.NewSimpleRoute(
+	"/new",
+	"series.new",
+	AdminPrefix+SeriesPrefix,
+)
+
+var SeriesCreate = routing.NewSimpleRoute(
+	"",
+	"series.create",
+	AdminPrefix+SeriesPrefix,
+)
+
+var SeriesEdit = routing.NewRouteWithSerialID(
+	"/:id/edit",
+	"series.edit",
+	AdminPrefix+SeriesPrefix,
+)
+
+var SeriesUpdate = routing.NewRouteWithSerialID(
+	"/:id",
+	"series.update",
+	AdminPrefix+SeriesPrefix,
+)
+
+var SeriesDestroy = routing.NewRouteWithSerialID(
+	"/:id",
+	"series.destroy",
+	AdminPrefix+SeriesPrefix,
+)
+
+// SeriesAddArticle, SeriesRemoveArticle and SeriesMoveArticle take the
+// article as an article_id query parameter.
+var SeriesAddArticle = routing.NewRouteWithSerialID(
+	"/:id/articles",
+	"series.articles.add",
+	AdminPrefix+SeriesPrefix,
+)
+
+var SeriesRemoveArticle = routing.NewRouteWithSerialID(
+	"/:id/articles",
+	"series.articles.remove",
+	AdminPrefix+SeriesPrefix,
+)
+
+var SeriesMoveArticle = routing.NewRouteWithSerialID(
+	"/:id/articles/move",
+	"series.articles.move",
+	AdminPrefix+SeriesPrefix,
+)
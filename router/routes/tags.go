@@ -6,6 +6,20 @@ import (
 
 const TagPrefix = "/tags"
 
+var Tag = routing.NewRouteWithSlug(
+	"/:slug",
+	"tags.show.slug",
+	TagPrefix,
+)
+
+// TagPage is a later page of a public tag archive. Pages are part of the
+// path so the static build writes each one to a file of its own.
+var TagPage = routing.NewRouteWithSlug(
+	"/:slug/page/:page",
+	"tags.show.slug.page",
+	TagPrefix,
+)
+
 var TagIndex = routing.NewSimpleRoute(
 	"",
 	"tags.index",
@@ -47,3 +61,9 @@ var TagDestroy = routing.NewRouteWithSerialID(
 	"tags.destroy",
 	AdminPrefix+TagPrefix,
 )
+
+var TagMerge = routing.NewRouteWithSerialID(
+	"/:id/merge",
+	"tags.merge",
+	AdminPrefix+TagPrefix,
+)
@@ -71,3 +71,57 @@ var ConfirmationCreate = routing.NewSimpleRoute(
 	"users.user_confirmation",
 	UserPrefix,
 )
+
+var InvitationEdit = routing.NewRouteWithToken(
+	"/invitation/:token/accept",
+	"users.edit_user_invitation",
+	UserPrefix,
+)
+
+var InvitationUpdate = routing.NewSimpleRoute(
+	"/invitation",
+	"users.user_invitation",
+	UserPrefix,
+)
+
+var AdminUserIndex = routing.NewSimpleRoute(
+	"",
+	"admin_users.index",
+	AdminPrefix+UserPrefix,
+)
+
+var AdminUserInvitationNew = routing.NewSimpleRoute(
+	"/invitations/new",
+	"admin_users.new_invitation",
+	AdminPrefix+UserPrefix,
+)
+
+var AdminUserInvitationCreate = routing.NewSimpleRoute(
+	"/invitations",
+	"admin_users.create_invitation",
+	AdminPrefix+UserPrefix,
+)
+
+var AdminUserRoleUpdate = routing.NewRouteWithUUIDID(
+	"/:id/role",
+	"admin_users.update_role",
+	AdminPrefix+UserPrefix,
+)
+
+var AdminUserDeactivate = routing.NewRouteWithUUIDID(
+	"/:id/deactivate",
+	"admin_users.deactivate",
+	AdminPrefix+UserPrefix,
+)
+
+var AdminUserReactivate = routing.NewRouteWithUUIDID(
+	"/:id/reactivate",
+	"admin_users.reactivate",
+	AdminPrefix+UserPrefix,
+)
+
+var AdminUserDestroy = routing.NewRouteWithUUIDID(
+	"/:id",
+	"admin_users.destroy",
+	AdminPrefix+UserPrefix,
+)
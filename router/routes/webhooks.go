@@ -0,0 +1,49 @@
+package routes
+
+import (
+	"mortenvistisen/internal/routing"
+)
+
+const WebhookPrefix = "/webhooks"
+
+var WebhookIndex = routing.NewSimpleRoute(
+	"",
+	"webhooks.index",
+	AdminPrefix+WebhookPrefix,
+)
+
+var WebhookShow = routing.NewRouteWithUUIDID(
+	"/:id",
+	"webhooks.show",
+	AdminPrefix+WebhookPrefix,
+)
+
+var WebhookNew = routing.NewSimpleRoute(
+	"/new",
+	"webhooks.new",
+	AdminPrefix+WebhookPrefix,
+)
+
+var WebhookCreate = routing.NewSimpleRoute(
+	"",
+	"webhooks.create",
+	AdminPrefix+WebhookPrefix,
+)
+
+var WebhookEdit = routing.NewRouteWithUUIDID(
+	"/:id/edit",
+	"webhooks.edit",
+	AdminPrefix+WebhookPrefix,
+)
+
+var WebhookUpdate = routing.NewRouteWithUUIDID(
+	"/:id",
+	"webhooks.update",
+	AdminPrefix+WebhookPrefix,
+)
+
+var WebhookDestroy = routing.NewRouteWithUUIDID(
+	"/:id",
+	"webhooks.destroy",
+	AdminPrefix+WebhookPrefix,
+)
@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"mortenvistisen/internal/routing"
+)
+
+const WebmentionPrefix = "/webmentions"
+
+// WebmentionReceive is the public endpoint other sites POST webmentions to.
+var WebmentionReceive = routing.NewSimpleRoute(
+	"/webmention",
+	"webmentions.receive",
+	"",
+)
+
+var WebmentionIndex = routing.NewSimpleRoute(
+	"",
+	"webmentions.index",
+	AdminPrefix+WebmentionPrefix,
+)
+
+var WebmentionApprove = routing.NewRouteWithUUIDID(
+	"/:id/approve",
+	"webmentions.approve",
+	AdminPrefix+WebmentionPrefix,
+)
+
+var WebmentionReject = routing.NewRouteWithUUIDID(
+	"/:id/reject",
+	"webmentions.reject",
+	AdminPrefix+WebmentionPrefix,
+)
+
+var WebmentionDestroy = routing.NewRouteWithUUIDID(
+	"/:id",
+	"webmentions.destroy",
+	AdminPrefix+WebmentionPrefix,
+)
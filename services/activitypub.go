@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/riverqueue/river"
+
+	"mortenvistisen/config"
+	"mortenvistisen/internal/activitypub"
+	"mortenvistisen/models"
+	"mortenvistisen/queue"
+	"mortenvistisen/queue/jobs"
+	"mortenvistisen/router/routes"
+)
+
+// ActivityPubSite is the actor the blog federates as. All of its URLs hang
+// off config.BaseURL.
+type ActivityPubSite struct {
+	Username   string
+	ObjectType string
+}
+
+func NewActivityPubSite(username string, objectType string) ActivityPubSite {
+	if objectType != "Note" {
+		objectType = "Article"
+	}
+
+	return ActivityPubSite{
+		Username:   username,
+		ObjectType: objectType,
+	}
+}
+
+// Handle is the address people search for to follow the site, without the
+// leading @.
+func (s ActivityPubSite) Handle() string {
+	return s.Username + "@" + config.Domain
+}
+
+func (s ActivityPubSite) ActorURL() string {
+	return activityPubURL(routes.ActivityPubActor.URL())
+}
+
+// ProfileURL is the HTML page people land on from the actor.
+func (s ActivityPubSite) ProfileURL() string {
+	return activityPubURL(routes.HomePage.URL())
+}
+
+func (s ActivityPubSite) KeyID() string {
+	return s.ActorURL() + "#main-key"
+}
+
+func (s ActivityPubSite) InboxURL() string {
+	return activityPubURL(routes.ActivityPubInbox.URL())
+}
+
+func (s ActivityPubSite) OutboxURL() string {
+	return activityPubURL(routes.ActivityPubOutbox.URL())
+}
+
+func (s ActivityPubSite) FollowersURL() string {
+	return activityPubURL(routes.ActivityPubFollowers.URL())
+}
+
+func (s ActivityPubSite) Actor(key models.ActivityPubKey) activitypub.Actor {
+	return activitypub.Actor{
+		Context:           activitypub.ActorContext,
+		ID:                s.ActorURL(),
+		Type:              "Person",
+		PreferredUsername: s.Username,
+		Name:              config.ProjectName,
+		URL:               s.ProfileURL(),
+		Inbox:             s.InboxURL(),
+		Outbox:            s.OutboxURL(),
+		Followers:         s.FollowersURL(),
+		Endpoints:         &activitypub.Endpoints{SharedInbox: s.InboxURL()},
+		PublicKey: activitypub.PublicKey{
+			ID:           s.KeyID(),
+			Owner:        s.ActorURL(),
+			PublicKeyPem: key.PublicKeyPEM,
+		},
+		Discoverable: true,
+	}
+}
+
+// ArticleObject renders a published article as the object followers see.
+// Notes carry a short summary with a link back, Articles the full post.
+func (s ActivityPubSite) ArticleObject(article models.Article) activitypub.Object {
+	pageURL := activityPubURL(routes.Article.URL(article.Slug))
+
+	object := activitypub.Object{
+		ID:           activityPubURL(routes.ActivityPubArticle.URL(article.Slug)),
+		Type:         s.ObjectType,
+		AttributedTo: s.ActorURL(),
+		URL:          pageURL,
+		Published:    article.FirstPublishedAt.UTC().Format(time.RFC3339),
+		To:           []string{activitypub.PublicCollection},
+		Cc:           []string{s.FollowersURL()},
+	}
+	if article.UpdatedAt.After(article.FirstPublishedAt) {
+		object.Updated = article.UpdatedAt.UTC().Format(time.RFC3339)
+	}
+	if article.ImageLink != "" {
+		object.Image = &activitypub.Image{Type: "Image", URL: article.ImageLink}
+	}
+
+	if s.ObjectType == "Note" {
+		object.Content = fmt.Sprintf(
+			`<p><strong>%s</strong></p><p>%s</p><p><a href="%s">%s</a></p>`,
+			html.EscapeString(article.Title),
+			html.EscapeString(article.Excerpt),
+			pageURL,
+			pageURL,
+		)
+		return object
+	}
+
+	object.Name = article.Title
+	object.Summary = article.Excerpt
+	object.Content = RenderedHTML(article.Rendered, article.Content)
+
+	return object
+}
+
+// CreateActivity wraps article in the Create activity sent to followers and
+// listed in the outbox.
+func (s ActivityPubSite) CreateActivity(article models.Article) activitypub.Activity {
+	object := s.ArticleObject(article)
+	// The object always marshals, it only holds strings.
+	raw, _ := json.Marshal(object)
+
+	return activitypub.Activity{
+		ID:     object.ID + "#create",
+		Type:   "Create",
+		Actor:  s.ActorURL(),
+		Object: raw,
+		To:     object.To,
+		Cc:     object.Cc,
+	}
+}
+
+// EnqueueActivityPubCreate queues delivery of article's Create activity to
+// every follower inbox. It runs inside tx so nothing federates for a publish
+// that is rolled back. It returns the number of deliveries queued.
+func EnqueueActivityPubCreate(
+	ctx context.Context,
+	tx pgx.Tx,
+	insertOnly queue.InsertOnly,
+	site ActivityPubSite,
+	article models.Article,
+) (int, error) {
+	inboxes, err := models.ActivityPubFollowerInboxes(ctx, tx)
+	if err != nil {
+		return 0, fmt.Errorf("find follower inboxes: %w", err)
+	}
+	if len(inboxes) == 0 {
+		return 0, nil
+	}
+
+	activity := site.CreateActivity(article)
+	activity.Context = activitypub.ActivityStreamsContext
+	body, err := json.Marshal(activity)
+	if err != nil {
+		return 0, fmt.Errorf("marshal create activity: %w", err)
+	}
+
+	insertParams := make([]river.InsertManyParams, len(inboxes))
+	for i, inbox := range inboxes {
+		insertParams[i] = river.InsertManyParams{
+			Args: jobs.DeliverActivityArgs{
+				Inbox:    inbox,
+				KeyID:    site.KeyID(),
+				Activity: body,
+			},
+		}
+	}
+
+	if _, err := insertOnly.InsertManyTx(ctx, tx, insertParams); err != nil {
+		return 0, fmt.Errorf("enqueue activity deliveries: %w", err)
+	}
+
+	return len(insertParams), nil
+}
+
+// EnqueueActivityPubAccept queues the Accept answering follow, which must be
+// the Follow activity exactly as received.
+func EnqueueActivityPubAccept(
+	ctx context.Context,
+	tx pgx.Tx,
+	insertOnly queue.InsertOnly,
+	site ActivityPubSite,
+	follow activitypub.Activity,
+	inbox string,
+) error {
+	object, err := json.Marshal(follow)
+	if err != nil {
+		return fmt.Errorf("marshal follow: %w", err)
+	}
+
+	body, err := json.Marshal(activitypub.Activity{
+		Context: activitypub.ActivityStreamsContext,
+		ID:      site.ActorURL() + "#accepts/" + uuid.NewString(),
+		Type:    "Accept",
+		Actor:   site.ActorURL(),
+		Object:  object,
+		To:      []string{follow.Actor},
+	})
+	if err != nil {
+		return fmt.Errorf("marshal accept: %w", err)
+	}
+
+	if _, err := insertOnly.InsertTx(ctx, tx, jobs.DeliverActivityArgs{
+		Inbox:    inbox,
+		KeyID:    site.KeyID(),
+		Activity: body,
+	}, nil); err != nil {
+		return fmt.Errorf("enqueue accept: %w", err)
+	}
+
+	return nil
+}
+
+func activityPubURL(path string) string {
+	return strings.TrimRight(config.BaseURL, "/") + path
+}
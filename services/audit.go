@@ -0,0 +1,111 @@
+package services
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+
+	"mortenvistisen/models"
+)
+
+// auditIgnoredFields are never written to the audit log, either because they
+// change on every write, are derived from other fields or hold secrets.
+var auditIgnoredFields = map[string]struct{}{
+	"CreatedAt": {},
+	"UpdatedAt": {},
+	"Rendered":  {},
+	"Password":  {},
+	"Hash":      {},
+	"Secret":    {},
+}
+
+// AuditDiff compares two values of the same struct type field by field and
+// returns the fields that differ. Pass nil as before for a creation and nil as
+// after for a deletion; every field is then recorded on the one side.
+func AuditDiff(before, after any) map[string]models.AuditChange {
+	changes := map[string]models.AuditChange{}
+
+	beforeValue := auditStruct(before)
+	afterValue := auditStruct(after)
+
+	var structType reflect.Type
+	switch {
+	case beforeValue.IsValid():
+		structType = beforeValue.Type()
+	case afterValue.IsValid():
+		structType = afterValue.Type()
+	default:
+		return changes
+	}
+	if afterValue.IsValid() && afterValue.Type() != structType {
+		return changes
+	}
+
+	for i := range structType.NumField() {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if _, ignored := auditIgnoredFields[field.Name]; ignored {
+			continue
+		}
+
+		var beforeField, afterField any
+		if beforeValue.IsValid() {
+			beforeField = auditValue(beforeValue.Field(i).Interface())
+		}
+		if afterValue.IsValid() {
+			afterField = auditValue(afterValue.Field(i).Interface())
+		}
+
+		if reflect.DeepEqual(beforeField, afterField) {
+			continue
+		}
+
+		changes[field.Name] = models.AuditChange{
+			Before: beforeField,
+			After:  afterField,
+		}
+	}
+
+	return changes
+}
+
+func auditStruct(value any) reflect.Value {
+	if value == nil {
+		return reflect.Value{}
+	}
+
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}
+	}
+
+	return v
+}
+
+func auditValue(value any) any {
+	switch v := value.(type) {
+	case time.Time:
+		if v.IsZero() {
+			return nil
+		}
+		return v.UTC().Format(time.RFC3339)
+	case uuid.UUID:
+		if v == uuid.Nil {
+			return nil
+		}
+		return v.String()
+	case []byte:
+		return nil
+	default:
+		return v
+	}
+}
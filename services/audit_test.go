@@ -0,0 +1,99 @@
+package services_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"mortenvistisen/models"
+	"mortenvistisen/services"
+)
+
+type auditedRecord struct {
+	Title       string
+	Published   bool
+	PublishedAt time.Time
+	UpdatedAt   time.Time
+	Secret      string
+	internal    string
+}
+
+func TestAuditDiff(t *testing.T) {
+	publishedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	record := auditedRecord{
+		Title:       "Hello",
+		Published:   true,
+		PublishedAt: publishedAt,
+		UpdatedAt:   publishedAt,
+		Secret:      "hunter2",
+		internal:    "x",
+	}
+
+	tests := map[string]struct {
+		before any
+		after  any
+		want   map[string]models.AuditChange
+	}{
+		"no changes": {
+			before: record,
+			after:  record,
+			want:   map[string]models.AuditChange{},
+		},
+		"a field changed": {
+			before: record,
+			after: func() auditedRecord {
+				r := record
+				r.Title = "Hello again"
+				r.UpdatedAt = time.Now()
+				r.Secret = "changed"
+				r.internal = "y"
+				return r
+			}(),
+			want: map[string]models.AuditChange{
+				"Title": {Before: "Hello", After: "Hello again"},
+			},
+		},
+		"pointers are compared by value": {
+			before: &record,
+			after:  &auditedRecord{Title: "Hello", PublishedAt: publishedAt},
+			want: map[string]models.AuditChange{
+				"Published": {Before: true, After: false},
+			},
+		},
+		"nil before records a creation": {
+			before: nil,
+			after:  record,
+			want: map[string]models.AuditChange{
+				"Title":       {After: "Hello"},
+				"Published":   {After: true},
+				"PublishedAt": {After: "2026-03-01T12:00:00Z"},
+			},
+		},
+		"nil after records a deletion": {
+			before: record,
+			after:  nil,
+			want: map[string]models.AuditChange{
+				"Title":       {Before: "Hello"},
+				"Published":   {Before: true},
+				"PublishedAt": {Before: "2026-03-01T12:00:00Z"},
+			},
+		},
+		"nil before and after": {
+			want: map[string]models.AuditChange{},
+		},
+		"different types": {
+			before: record,
+			after:  struct{ Title string }{Title: "Hello"},
+			want:   map[string]models.AuditChange{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := services.AuditDiff(tt.before, tt.after)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %#v, got %#v", tt.want, got)
+			}
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 var (
 	ErrInvalidCredentials = errors.New("invalid email or password")
 	ErrEmailNotVerified   = errors.New("email not verified")
+	ErrAccountDeactivated = errors.New("account deactivated")
 )
 
 type LoginData struct {
@@ -40,12 +41,16 @@ func AuthenticateUser(
 	}
 
 	if !validPassword {
-		return models.User{}, errors.New("invalid password")
+		return models.User{}, ErrInvalidCredentials
 	}
 
 	if user.EmailValidatedAt.IsZero() {
 		return models.User{}, ErrEmailNotVerified
 	}
 
+	if user.IsDeactivated() {
+		return models.User{}, ErrAccountDeactivated
+	}
+
 	return user, nil
 }
@@ -0,0 +1,386 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/riverqueue/river"
+
+	"mortenvistisen/config"
+	"mortenvistisen/email"
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models"
+	"mortenvistisen/queue"
+	"mortenvistisen/queue/jobs"
+	"mortenvistisen/router/routes"
+)
+
+const commentEmailVerification = "comment_email_verification"
+
+// CommentSpamThreshold is the spam score from which a verified comment is
+// filed as spam instead of waiting for moderation.
+const CommentSpamThreshold = 5
+
+var (
+	ErrCommentsClosed                 = errors.New("comments are closed for this article")
+	ErrCommentVerificationInvalidCode = errors.New("invalid comment verification code")
+	ErrCommentVerificationExpiredCode = errors.New("comment verification code has expired")
+)
+
+var (
+	commentLinkPattern = regexp.MustCompile(`(?i)(?:https?://|www\.)\S*|\[(?:url|link)=`)
+	commentSpamWords   = []string{
+		"backlinks", "bitcoin", "casino", "cialis", "crypto", "forex",
+		"free money", "loan", "porn", "seo services", "viagra",
+	}
+)
+
+// CommentSpamScore rates how likely a comment is to be spam. The honeypot
+// is a form field hidden from people, so anything in it came from a bot.
+// Scores of CommentSpamThreshold and above are treated as spam.
+func CommentSpamScore(authorName string, content string, honeypot string) int32 {
+	var score int32
+
+	if strings.TrimSpace(honeypot) != "" {
+		score += 10
+	}
+
+	if commentLinkPattern.MatchString(authorName) {
+		score += 3
+	}
+
+	// Judge the words around the links, not the links themselves.
+	links := len(commentLinkPattern.FindAllStringIndex(content, -1))
+	text := commentLinkPattern.ReplaceAllString(content, "")
+	if links >= 3 {
+		score += 3
+	}
+	if links > 0 && len([]rune(strings.TrimSpace(text))) < 40 {
+		score += 2
+	}
+
+	lower := strings.ToLower(authorName + " " + content)
+	for _, word := range commentSpamWords {
+		if strings.Contains(lower, word) {
+			score += 3
+		}
+	}
+
+	var letters, upper int
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				upper++
+			}
+		}
+	}
+	if letters >= 20 && upper*10 > letters*7 {
+		score += 3
+	}
+
+	if hasRepeatedRun(content, 10) {
+		score++
+	}
+
+	return score
+}
+
+func hasRepeatedRun(s string, length int) bool {
+	var previous rune
+	run := 0
+	for _, r := range s {
+		if r == previous && !unicode.IsSpace(r) {
+			run++
+			if run >= length {
+				return true
+			}
+			continue
+		}
+		previous = r
+		run = 1
+	}
+
+	return false
+}
+
+type SubmitCommentData struct {
+	ArticleID   int32
+	ParentID    uuid.UUID
+	AuthorName  string
+	AuthorEmail string
+	Content     string
+	Honeypot    string
+}
+
+// SubmitComment stores an unverified comment and emails the commenter a
+// code to confirm their address, the same way newsletter signups are
+// verified.
+func SubmitComment(
+	ctx context.Context,
+	db storage.Pool,
+	insertOnly queue.InsertOnly,
+	pepper string,
+	data SubmitCommentData,
+) (models.Comment, error) {
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		return models.Comment{}, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	article, err := models.FindArticle(ctx, tx, data.ArticleID)
+	if err != nil {
+		return models.Comment{}, fmt.Errorf("find article: %w", err)
+	}
+	if !article.Published || !article.CommentsOpen {
+		return models.Comment{}, ErrCommentsClosed
+	}
+
+	comment, err := models.CreateComment(ctx, tx, models.CreateCommentData{
+		ArticleID:   article.ID,
+		ParentID:    data.ParentID,
+		AuthorName:  data.AuthorName,
+		AuthorEmail: data.AuthorEmail,
+		Content:     data.Content,
+		SpamScore:   CommentSpamScore(data.AuthorName, data.Content, data.Honeypot),
+	})
+	if err != nil {
+		return models.Comment{}, err
+	}
+
+	meta, err := json.Marshal(map[string]string{
+		"comment_id": comment.ID.String(),
+	})
+	if err != nil {
+		return models.Comment{}, fmt.Errorf("marshal comment token metadata: %w", err)
+	}
+
+	code, err := models.CreateCodeToken(
+		ctx,
+		tx,
+		pepper,
+		commentEmailVerification,
+		time.Now().Add(30*time.Minute),
+		meta,
+	)
+	if err != nil {
+		return models.Comment{}, fmt.Errorf("create comment verification token: %w", err)
+	}
+
+	verifyEmail := email.VerifyComment{
+		ArticleTitle:     article.Title,
+		VerificationCode: code,
+		VerificationURL:  fmt.Sprintf("%s%s", config.BaseURL, routes.CommentVerificationNew.URL()),
+	}
+
+	html, err := verifyEmail.ToHTML()
+	if err != nil {
+		return models.Comment{}, fmt.Errorf("render comment verification html email: %w", err)
+	}
+
+	text, err := verifyEmail.ToText()
+	if err != nil {
+		return models.Comment{}, fmt.Errorf("render comment verification text email: %w", err)
+	}
+
+	_, err = insertOnly.InsertTx(ctx, tx, jobs.SendTransactionalEmailArgs{
+		Data: email.TransactionalData{
+			To:       comment.AuthorEmail,
+			From:     "hello@mortenvistisen.com",
+			Subject:  "Verify your comment",
+			HTMLBody: html,
+			TextBody: text,
+		},
+	}, nil)
+	if err != nil {
+		return models.Comment{}, fmt.Errorf("enqueue comment verification email: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.Comment{}, fmt.Errorf("commit comment transaction: %w", err)
+	}
+
+	return comment, nil
+}
+
+type VerifyCommentData struct {
+	Code string
+}
+
+// VerifyComment confirms the commenter's email and hands the comment to
+// moderation, filed as spam when its spam score is too high.
+func VerifyComment(
+	ctx context.Context,
+	db storage.Pool,
+	pepper string,
+	data VerifyCommentData,
+) (models.Comment, error) {
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		return models.Comment{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	token, err := models.FindTokenByScopeAndHash(
+		ctx,
+		tx,
+		pepper,
+		commentEmailVerification,
+		data.Code,
+	)
+	if err != nil {
+		return models.Comment{}, ErrCommentVerificationInvalidCode
+	}
+
+	if !token.IsValid(data.Code, pepper) {
+		return models.Comment{}, ErrCommentVerificationExpiredCode
+	}
+
+	var meta map[string]string
+	if err := json.Unmarshal(token.MetaData, &meta); err != nil {
+		return models.Comment{}, err
+	}
+
+	commentID, err := uuid.Parse(meta["comment_id"])
+	if err != nil {
+		return models.Comment{}, errors.New("token metadata missing comment_id")
+	}
+
+	comment, err := models.FindComment(ctx, tx, commentID)
+	if err != nil {
+		return models.Comment{}, err
+	}
+
+	if comment.Status == models.CommentStatusUnverified {
+		status := models.CommentStatusPending
+		if comment.SpamScore >= CommentSpamThreshold {
+			status = models.CommentStatusSpam
+		}
+
+		comment, err = models.VerifyComment(ctx, tx, comment.ID, status)
+		if err != nil {
+			return models.Comment{}, err
+		}
+	}
+
+	if err := models.DestroyToken(ctx, tx, token.ID); err != nil {
+		return models.Comment{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.Comment{}, err
+	}
+
+	return comment, nil
+}
+
+// EnqueueCommentNotifications emails the article's author and everyone else
+// who commented in the same thread that comment was published. approved are
+// the article's approved comments. Nobody is told about their own comment.
+// It returns the number of emails queued.
+func EnqueueCommentNotifications(
+	ctx context.Context,
+	tx pgx.Tx,
+	insertOnly queue.InsertOnly,
+	article models.Article,
+	comment models.Comment,
+	approved []models.Comment,
+) (int, error) {
+	recipients := []string{}
+	seen := map[string]bool{comment.AuthorEmail: true}
+	addRecipient := func(address string) {
+		address = strings.ToLower(strings.TrimSpace(address))
+		if address == "" || seen[address] {
+			return
+		}
+		seen[address] = true
+		recipients = append(recipients, address)
+	}
+
+	if article.AuthorID != uuid.Nil {
+		author, err := models.FindUser(ctx, tx, article.AuthorID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return 0, fmt.Errorf("find article author: %w", err)
+		}
+		addRecipient(author.Email)
+	}
+	for _, participant := range models.CommentThreadOf(approved, comment) {
+		addRecipient(participant.AuthorEmail)
+	}
+
+	if len(recipients) == 0 {
+		return 0, nil
+	}
+
+	notification := email.NewCommentNotification{
+		ArticleTitle:   article.Title,
+		CommentAuthor:  comment.AuthorName,
+		CommentExcerpt: commentExcerpt(comment.Content, 300),
+		CommentURL: fmt.Sprintf(
+			"%s%s#comment-%s",
+			strings.TrimRight(config.BaseURL, "/"),
+			routes.Article.URL(article.Slug),
+			comment.ID,
+		),
+	}
+
+	html, err := notification.ToHTML()
+	if err != nil {
+		return 0, fmt.Errorf("render comment notification html email: %w", err)
+	}
+
+	text, err := notification.ToText()
+	if err != nil {
+		return 0, fmt.Errorf("render comment notification text email: %w", err)
+	}
+
+	params := make([]river.InsertManyParams, len(recipients))
+	for i, recipient := range recipients {
+		params[i] = river.InsertManyParams{
+			Args: jobs.SendTransactionalEmailArgs{
+				Data: email.TransactionalData{
+					To:       recipient,
+					From:     "hello@mortenvistisen.com",
+					Subject:  "New comment on " + article.Title,
+					HTMLBody: html,
+					TextBody: text,
+					Metadata: map[string]string{
+						"comment_id": comment.ID.String(),
+					},
+				},
+			},
+		}
+	}
+
+	if _, err := insertOnly.InsertManyTx(ctx, tx, params); err != nil {
+		return 0, fmt.Errorf("enqueue comment notifications: %w", err)
+	}
+
+	return len(params), nil
+}
+
+// commentExcerpt shortens content to at most limit runes on a word
+// boundary.
+func commentExcerpt(content string, limit int) string {
+	content = strings.Join(strings.Fields(content), " ")
+	runes := []rune(content)
+	if len(runes) <= limit {
+		return content
+	}
+
+	excerpt := string(runes[:limit])
+	if i := strings.LastIndex(excerpt, " "); i > 0 {
+		excerpt = excerpt[:i]
+	}
+
+	return excerpt + "…"
+}
@@ -0,0 +1,89 @@
+package services_test
+
+import (
+	"strings"
+	"testing"
+
+	"mortenvistisen/services"
+)
+
+func TestCommentSpamScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		author   string
+		content  string
+		honeypot string
+		spam     bool
+	}{
+		{
+			name:    "ordinary comment",
+			author:  "Jane",
+			content: "Thanks for the write-up, the part about connection pooling cleared things up for me.",
+		},
+		{
+			name:    "comment with a single reference",
+			author:  "Jane",
+			content: "The pgx docs explain this well too: https://pkg.go.dev/github.com/jackc/pgx/v5 if anyone needs more detail on pooling.",
+		},
+		{
+			name:     "honeypot filled",
+			author:   "Jane",
+			content:  "Nice post.",
+			honeypot: "https://example.com",
+			spam:     true,
+		},
+		{
+			name:    "link dump",
+			author:  "Best Deals",
+			content: "Great! https://a.example https://b.example https://c.example",
+			spam:    true,
+		},
+		{
+			name:    "spam words with a link",
+			author:  "crypto king",
+			content: "Cheap viagra at www.example.com",
+			spam:    true,
+		},
+		{
+			name:    "shouting with a bare link",
+			author:  "Jane",
+			content: "CLICK HERE FOR THE BEST OFFERS TODAY https://example.com",
+			spam:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := services.CommentSpamScore(tt.author, tt.content, tt.honeypot)
+			if spam := score >= services.CommentSpamThreshold; spam != tt.spam {
+				t.Errorf("expected spam=%t, got score %d", tt.spam, score)
+			}
+		})
+	}
+}
+
+func TestCommentMarkdownToHTML(t *testing.T) {
+	html := services.CommentMarkdownToHTML(strings.Join([]string{
+		"# Heading",
+		"",
+		"<script>alert(1)</script>",
+		"",
+		"[bad](javascript:alert(1)) [good](https://example.com) ![alt](https://example.com/a.png)",
+	}, "\n"))
+
+	for _, unwanted := range []string{"<h1", "<script", "javascript:", "<img"} {
+		if strings.Contains(html, unwanted) {
+			t.Errorf("expected %q to be removed, got %s", unwanted, html)
+		}
+	}
+
+	for _, wanted := range []string{
+		"<p>Heading</p>",
+		`<a href="https://example.com" rel="nofollow ugc noopener">good</a>`,
+		`<a href="https://example.com/a.png" rel="nofollow ugc noopener">alt</a>`,
+	} {
+		if !strings.Contains(html, wanted) {
+			t.Errorf("expected %q in %s", wanted, html)
+		}
+	}
+}
@@ -0,0 +1,378 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gosimple/slug"
+	"github.com/jackc/pgx/v5"
+
+	"mortenvistisen/internal/frontmatter"
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models"
+)
+
+const maxExportImageSize = 20 << 20
+
+var (
+	markdownImagePattern = regexp.MustCompile(`!\[[^\]]*\]\(\s*<?([^)\s>]+)`)
+	htmlImagePattern     = regexp.MustCompile(`<img[^>]+src=["']([^"']+)["']`)
+)
+
+// ContentExportOptions configures ExportContent. A nil HTTPClient leaves
+// images out of the archive.
+type ContentExportOptions struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// ExportContent writes every article, project and newsletter to w as a zip
+// archive of markdown files with front matter, laid out the way
+// database/sync reads them. Referenced images are stored under images/ with
+// images/manifest.json mapping each original URL to its file.
+func ExportContent(
+	ctx context.Context,
+	exec storage.Executor,
+	w io.Writer,
+	opts ContentExportOptions,
+) error {
+	archive := zip.NewWriter(w)
+
+	exporter := contentExporter{
+		exec:    exec,
+		archive: archive,
+		authors: make(map[uuid.UUID]string),
+		images:  make(map[string]struct{}),
+	}
+
+	if err := exporter.articles(ctx); err != nil {
+		return fmt.Errorf("export articles: %w", err)
+	}
+	if err := exporter.projects(ctx); err != nil {
+		return fmt.Errorf("export projects: %w", err)
+	}
+	if err := exporter.newsletters(ctx); err != nil {
+		return fmt.Errorf("export newsletters: %w", err)
+	}
+	if opts.HTTPClient != nil {
+		if err := exporter.downloadImages(ctx, opts); err != nil {
+			return fmt.Errorf("export images: %w", err)
+		}
+	}
+
+	return archive.Close()
+}
+
+type contentExporter struct {
+	exec      storage.Executor
+	archive   *zip.Writer
+	authors   map[uuid.UUID]string
+	images    map[string]struct{}
+	imageURLs []string
+}
+
+func (e *contentExporter) articles(ctx context.Context) error {
+	articles, err := models.AllArticles(ctx, e.exec)
+	if err != nil {
+		return err
+	}
+
+	tags, err := models.AllTags(ctx, e.exec)
+	if err != nil {
+		return err
+	}
+	tagTitles := make(map[int32]string, len(tags))
+	for _, tag := range tags {
+		tagTitles[tag.ID] = tag.Title
+	}
+
+	for _, article := range articles {
+		tagIDs, err := models.TagIDsForArticle(ctx, e.exec, article.ID)
+		if err != nil {
+			return err
+		}
+		titles := make([]string, 0, len(tagIDs))
+		for _, id := range tagIDs {
+			if title, ok := tagTitles[id]; ok {
+				titles = append(titles, title)
+			}
+		}
+
+		author, err := e.authorEmail(ctx, article.AuthorID)
+		if err != nil {
+			return err
+		}
+
+		meta := frontmatter.Meta{
+			Title:           article.Title,
+			Slug:            article.Slug,
+			Excerpt:         article.Excerpt,
+			MetaTitle:       article.MetaTitle,
+			MetaDescription: article.MetaDescription,
+			ImageLink:       article.ImageLink,
+			Tags:            titles,
+			Published:       article.Published,
+			CommentsOpen:    &article.CommentsOpen,
+			TableOfContents: &article.TableOfContents,
+			Date:            article.FirstPublishedAt,
+			Author:          author,
+			CreatedAt:       article.CreatedAt,
+			UpdatedAt:       article.UpdatedAt,
+		}
+
+		// Estimated read times are left out, so a restore estimates them
+		// again instead of pinning them.
+		if article.ReadTimeOverride {
+			meta.ReadTime = article.ReadTime
+		}
+
+		e.addImage(article.ImageLink)
+		e.collectImages(article.Content)
+
+		if err := e.write("articles", meta, article.Content); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *contentExporter) projects(ctx context.Context) error {
+	projects, err := models.AllProjects(ctx, e.exec)
+	if err != nil {
+		return err
+	}
+
+	for _, project := range projects {
+		author, err := e.authorEmail(ctx, project.AuthorID)
+		if err != nil {
+			return err
+		}
+
+		meta := frontmatter.Meta{
+			Title:      project.Title,
+			Slug:       project.Slug,
+			Excerpt:    project.Description,
+			Published:  project.Published,
+			Date:       project.StartedAt,
+			Status:     project.Status,
+			ProjectURL: project.ProjectURL,
+			Author:     author,
+			CreatedAt:  project.CreatedAt,
+			UpdatedAt:  project.UpdatedAt,
+		}
+
+		e.collectImages(project.Content)
+
+		if err := e.write("projects", meta, project.Content); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *contentExporter) newsletters(ctx context.Context) error {
+	newsletters, err := models.AllNewsletters(ctx, e.exec)
+	if err != nil {
+		return err
+	}
+
+	for _, newsletter := range newsletters {
+		newsletterSlug := newsletter.Slug
+		if newsletterSlug == "" {
+			newsletterSlug = fmt.Sprintf("%s-%d", slug.Make(newsletter.Title), newsletter.ID)
+		}
+
+		meta := frontmatter.Meta{
+			Title:           newsletter.Title,
+			Slug:            newsletterSlug,
+			MetaTitle:       newsletter.MetaTitle,
+			MetaDescription: newsletter.MetaDescription,
+			Published:       newsletter.IsPublished,
+			Date:            newsletter.ReleasedAt,
+			CreatedAt:       newsletter.CreatedAt,
+			UpdatedAt:       newsletter.UpdatedAt,
+		}
+
+		e.collectImages(newsletter.Content)
+
+		if err := e.write("newsletters", meta, newsletter.Content); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *contentExporter) write(dir string, meta frontmatter.Meta, body string) error {
+	contents, err := frontmatter.Render(meta, body)
+	if err != nil {
+		return err
+	}
+
+	file, err := e.archive.CreateHeader(&zip.FileHeader{
+		Name:     path.Join(dir, meta.Slug+".md"),
+		Method:   zip.Deflate,
+		Modified: meta.UpdatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(contents)
+	return err
+}
+
+func (e *contentExporter) authorEmail(ctx context.Context, id uuid.UUID) (string, error) {
+	if id == uuid.Nil {
+		return "", nil
+	}
+	if email, ok := e.authors[id]; ok {
+		return email, nil
+	}
+
+	user, err := models.FindUser(ctx, e.exec, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	e.authors[id] = user.Email
+
+	return user.Email, nil
+}
+
+func (e *contentExporter) collectImages(content string) {
+	for _, match := range markdownImagePattern.FindAllStringSubmatch(content, -1) {
+		e.addImage(match[1])
+	}
+	for _, match := range htmlImagePattern.FindAllStringSubmatch(content, -1) {
+		e.addImage(match[1])
+	}
+}
+
+func (e *contentExporter) addImage(link string) {
+	if link == "" {
+		return
+	}
+	if _, seen := e.images[link]; seen {
+		return
+	}
+	e.images[link] = struct{}{}
+	e.imageURLs = append(e.imageURLs, link)
+}
+
+// downloadImages fetches every collected image. Images that cannot be
+// fetched are logged and left out so one dead link does not fail the export.
+func (e *contentExporter) downloadImages(ctx context.Context, opts ContentExportOptions) error {
+	base, err := url.Parse(opts.BaseURL)
+	if err != nil {
+		return err
+	}
+
+	manifest := make(map[string]string, len(e.imageURLs))
+	for _, link := range e.imageURLs {
+		ref, err := url.Parse(link)
+		if err != nil || (ref.Scheme != "" && ref.Scheme != "http" && ref.Scheme != "https") {
+			continue
+		}
+		if !ref.IsAbs() && !strings.HasPrefix(link, "/") {
+			continue
+		}
+		resolved := base.ResolveReference(ref).String()
+
+		name, err := e.downloadImage(ctx, opts.HTTPClient, resolved)
+		if err != nil {
+			slog.WarnContext(ctx, "could not export image", "url", resolved, "error", err)
+			continue
+		}
+		manifest[link] = name
+	}
+
+	file, err := e.archive.Create("images/manifest.json")
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(manifest)
+}
+
+func (e *contentExporter) downloadImage(
+	ctx context.Context,
+	client *http.Client,
+	link string,
+) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %d", res.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxExportImageSize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(body) > maxExportImageSize {
+		return "", fmt.Errorf("image is larger than %d bytes", maxExportImageSize)
+	}
+
+	sum := sha256.Sum256([]byte(link))
+	name := "images/" + hex.EncodeToString(sum[:8]) + imageExtension(link, res.Header.Get("Content-Type"))
+
+	file, err := e.archive.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Store,
+		Modified: time.Now(),
+	})
+	if err != nil {
+		return "", err
+	}
+	if _, err := file.Write(body); err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
+
+func imageExtension(link, contentType string) string {
+	if parsed, err := url.Parse(link); err == nil {
+		if ext := path.Ext(parsed.Path); ext != "" && len(ext) <= 5 {
+			return strings.ToLower(ext)
+		}
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if exts, err := mime.ExtensionsByType(mediaType); err == nil && len(exts) > 0 {
+		return exts[0]
+	}
+
+	return ""
+}
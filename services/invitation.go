@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"mortenvistisen/config"
+	"mortenvistisen/email"
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models"
+	"mortenvistisen/queue"
+	"mortenvistisen/queue/jobs"
+	"mortenvistisen/router/routes"
+)
+
+const userInvitation = "user_invitation"
+
+var (
+	ErrInvalidInvitation = errors.New("invalid invitation")
+	ErrExpiredInvitation = errors.New("invitation has expired")
+	ErrUserAlreadyExists = errors.New("a user with that email already exists")
+)
+
+type InviteUserData struct {
+	Email string
+	Role  models.Role
+}
+
+func InviteUser(
+	ctx context.Context,
+	db storage.Pool,
+	insertOnly queue.InsertOnly,
+	salt string,
+	data InviteUserData,
+) error {
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	emailAddress := strings.ToLower(strings.TrimSpace(data.Email))
+	if emailAddress == "" {
+		return errors.New("email is required")
+	}
+
+	if !data.Role.Valid() {
+		return fmt.Errorf("invalid role %q", data.Role)
+	}
+
+	_, err = models.FindUserByEmail(ctx, tx, emailAddress)
+	if err == nil {
+		return ErrUserAlreadyExists
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	meta, err := json.Marshal(map[string]string{
+		"email": emailAddress,
+		"role":  string(data.Role),
+	})
+	if err != nil {
+		return err
+	}
+
+	token, err := models.CreateToken(
+		ctx,
+		tx,
+		salt,
+		userInvitation,
+		time.Now().Add(7*24*time.Hour),
+		meta,
+	)
+	if err != nil {
+		return err
+	}
+
+	invitationURL := fmt.Sprintf("%s%s", config.BaseURL, routes.InvitationEdit.URL(token))
+
+	inviteEmail := email.UserInvitation{InvitationURL: invitationURL}
+
+	html, err := inviteEmail.ToHTML()
+	if err != nil {
+		return err
+	}
+
+	text, err := inviteEmail.ToText()
+	if err != nil {
+		return err
+	}
+
+	_, err = insertOnly.InsertTx(ctx, tx, jobs.SendTransactionalEmailArgs{
+		Data: email.TransactionalData{
+			To:       emailAddress,
+			From:     "hello@mortenvistisen.com",
+			Subject:  "You've Been Invited",
+			HTMLBody: html,
+			TextBody: text,
+		},
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+type AcceptInvitationData struct {
+	Token           string
+	Password        string
+	ConfirmPassword string
+}
+
+func AcceptInvitation(
+	ctx context.Context,
+	db storage.Pool,
+	salt string,
+	data AcceptInvitationData,
+) (models.User, error) {
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		return models.User{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	if data.Password != data.ConfirmPassword {
+		return models.User{}, errors.New("passwords do not match")
+	}
+
+	token, err := models.FindTokenByScopeAndHash(
+		ctx,
+		tx,
+		salt,
+		userInvitation,
+		data.Token,
+	)
+	if err != nil {
+		return models.User{}, ErrInvalidInvitation
+	}
+
+	if !token.IsValid(data.Token, salt) {
+		return models.User{}, ErrExpiredInvitation
+	}
+
+	var meta map[string]string
+	if err := json.Unmarshal(token.MetaData, &meta); err != nil {
+		return models.User{}, err
+	}
+
+	emailAddress, ok := meta["email"]
+	if !ok {
+		return models.User{}, errors.New("token metadata missing email")
+	}
+
+	user, err := models.CreateUser(ctx, tx, salt, models.CreateUserData{
+		Email: emailAddress,
+		PasswordPair: models.PasswordPair{
+			Password:        data.Password,
+			ConfirmPassword: data.ConfirmPassword,
+		},
+		EmailValidatedAt: time.Now(),
+		Role:             models.Role(meta["role"]),
+	})
+	if err != nil {
+		return models.User{}, err
+	}
+
+	if err := models.DestroyToken(ctx, tx, token.ID); err != nil {
+		return models.User{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.User{}, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,764 @@
+package services
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+
+	"mortenvistisen/config"
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models"
+	"mortenvistisen/queue"
+	"mortenvistisen/queue/jobs"
+	"mortenvistisen/router/routes"
+)
+
+const maxLinkCheckBody = 64 << 10
+
+// Defaults for LinkCheckOptions.
+const (
+	DefaultLinkCheckConcurrency     = 8
+	DefaultLinkCheckHostConcurrency = 2
+	DefaultLinkCheckHostInterval    = time.Second
+)
+
+// MarkdownLink is a link or image destination in markdown, with the line it
+// is on counted from 1.
+type MarkdownLink struct {
+	Destination string
+	Line        int
+}
+
+// ExtractMarkdownLinks lists the destinations of the links, images and
+// autolinks in content, in document order. Links inside code and raw HTML
+// are not included.
+func ExtractMarkdownLinks(content string) []MarkdownLink {
+	source := []byte(content)
+	doc := newMarkdownParser(nil).Parser().Parse(text.NewReader(source))
+
+	var links []MarkdownLink
+	_ = ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		var destination string
+		switch n := node.(type) {
+		case *ast.Link:
+			destination = string(n.Destination)
+		case *ast.Image:
+			destination = string(n.Destination)
+		case *ast.AutoLink:
+			if n.AutoLinkType != ast.AutoLinkURL {
+				return ast.WalkContinue, nil
+			}
+			destination = string(n.URL(source))
+		default:
+			return ast.WalkContinue, nil
+		}
+
+		if destination != "" {
+			links = append(links, MarkdownLink{
+				Destination: destination,
+				Line:        markdownLinkLine(source, node, destination),
+			})
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	return links
+}
+
+// markdownLinkLine finds the line of an inline node. Inline nodes carry no
+// position of their own, so the destination is searched for in the block
+// holding it. Reference links fall back to the first line of the block.
+func markdownLinkLine(source []byte, node ast.Node, destination string) int {
+	block := node.Parent()
+	for block != nil && (block.Type() != ast.TypeBlock || block.Lines().Len() == 0) {
+		block = block.Parent()
+	}
+	if block == nil {
+		return 1
+	}
+
+	lines := block.Lines()
+	start, stop := lines.At(0).Start, lines.At(lines.Len()-1).Stop
+	offset := start
+	if i := bytes.Index(source[start:stop], []byte(destination)); i >= 0 {
+		offset += i
+	}
+
+	return bytes.Count(source[:offset], []byte("\n")) + 1
+}
+
+// ContentLink is a link found in published content, with every place it was
+// found. Internal links are the path on this site they point to, external
+// links are absolute URLs without a fragment.
+type ContentLink struct {
+	URL      string
+	Internal bool
+	Sources  []models.LinkSource
+}
+
+// CollectContentLinks extracts the links of every published article,
+// project and newsletter. Relative links are resolved against the page they
+// are on; fragments, mailto and other non-web links are left out.
+func CollectContentLinks(ctx context.Context, exec storage.Executor) ([]ContentLink, error) {
+	base, err := url.Parse(config.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	found := map[string]*ContentLink{}
+	add := func(content string, page string, source models.LinkSource) {
+		pageURL := base.ResolveReference(&url.URL{Path: page})
+		for _, link := range ExtractMarkdownLinks(content) {
+			target, internal, ok := resolveContentLink(base, pageURL, link.Destination)
+			if !ok {
+				continue
+			}
+
+			entry, exists := found[target]
+			if !exists {
+				entry = &ContentLink{URL: target, Internal: internal}
+				found[target] = entry
+			}
+			source.Line = link.Line
+			if !slices.Contains(entry.Sources, source) {
+				entry.Sources = append(entry.Sources, source)
+			}
+		}
+	}
+
+	articles, err := models.AllPublishedArticles(ctx, exec)
+	if err != nil {
+		return nil, err
+	}
+	for _, article := range articles {
+		add(article.Content, routes.Article.URL(article.Slug), models.LinkSource{
+			Type:  models.LinkSourceArticle,
+			ID:    article.ID,
+			Title: article.Title,
+		})
+	}
+
+	projects, err := models.AllPublishedProjects(ctx, exec)
+	if err != nil {
+		return nil, err
+	}
+	for _, project := range projects {
+		add(project.Content, routes.Project.URL(project.Slug), models.LinkSource{
+			Type:  models.LinkSourceProject,
+			ID:    project.ID,
+			Title: project.Title,
+		})
+	}
+
+	newsletters, err := models.AllPublishedNewsletters(ctx, exec)
+	if err != nil {
+		return nil, err
+	}
+	for _, newsletter := range newsletters {
+		add(newsletter.Content, routes.Newsletter.URL(newsletter.Slug), models.LinkSource{
+			Type:  models.LinkSourceNewsletter,
+			ID:    newsletter.ID,
+			Title: newsletter.Title,
+		})
+	}
+
+	links := make([]ContentLink, 0, len(found))
+	for _, link := range found {
+		links = append(links, *link)
+	}
+	slices.SortFunc(links, func(a, b ContentLink) int {
+		return cmp.Compare(a.URL, b.URL)
+	})
+
+	return links, nil
+}
+
+// resolveContentLink resolves destination against the page it was found
+// on. It reports false for links that cannot be checked over HTTP.
+func resolveContentLink(base, page *url.URL, destination string) (string, bool, bool) {
+	if strings.HasPrefix(destination, "#") {
+		return "", false, false
+	}
+
+	ref, err := url.Parse(strings.TrimSpace(destination))
+	if err != nil {
+		return "", false, false
+	}
+
+	target := page.ResolveReference(ref)
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return "", false, false
+	}
+	if target.Host == "" {
+		return "", false, false
+	}
+	target.Fragment = ""
+	target.RawFragment = ""
+
+	if strings.EqualFold(target.Host, base.Host) {
+		path := target.EscapedPath()
+		if path == "" {
+			path = "/"
+		}
+		return path, true, true
+	}
+
+	return target.String(), false, true
+}
+
+// SiteRoute is a public GET route of the site that content may link to.
+type SiteRoute struct {
+	Name string
+	Path string
+}
+
+// SiteRoutes holds the public routes of the site. The router registers its
+// routes after the workers that check links are created, so it is filled in
+// once the router is set up.
+type SiteRoutes struct {
+	mu     sync.RWMutex
+	routes []SiteRoute
+}
+
+func NewSiteRoutes() *SiteRoutes {
+	return &SiteRoutes{}
+}
+
+func (s *SiteRoutes) Set(routes []SiteRoute) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.routes = slices.Clone(routes)
+}
+
+func (s *SiteRoutes) Routes() []SiteRoute {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return slices.Clone(s.routes)
+}
+
+// InternalLinkIndex checks paths on this site against its public routes
+// and, for routes with a :slug, against the slugs that currently have a
+// page.
+type InternalLinkIndex struct {
+	routes []SiteRoute
+	slugs  map[string]map[string]struct{}
+}
+
+// NewInternalLinkIndex builds an index from routes and the known slugs per
+// route name. Routes with parameters but no entry in slugs accept any value.
+func NewInternalLinkIndex(routes []SiteRoute, slugs map[string][]string) InternalLinkIndex {
+	index := InternalLinkIndex{
+		routes: routes,
+		slugs:  make(map[string]map[string]struct{}, len(slugs)),
+	}
+	for name, values := range slugs {
+		set := make(map[string]struct{}, len(values))
+		for _, value := range values {
+			set[value] = struct{}{}
+		}
+		index.slugs[name] = set
+	}
+
+	return index
+}
+
+// LoadInternalLinkIndex indexes routes together with the slugs of published
+// articles, projects, newsletters, series and tags.
+func LoadInternalLinkIndex(
+	ctx context.Context,
+	exec storage.Executor,
+	siteRoutes []SiteRoute,
+) (InternalLinkIndex, error) {
+	slugs := map[string][]string{}
+
+	articles, err := models.AllPublishedArticles(ctx, exec)
+	if err != nil {
+		return InternalLinkIndex{}, err
+	}
+	for _, article := range articles {
+		slugs[routes.Article.Name()] = append(slugs[routes.Article.Name()], article.Slug)
+	}
+
+	projects, err := models.AllPublishedProjects(ctx, exec)
+	if err != nil {
+		return InternalLinkIndex{}, err
+	}
+	for _, project := range projects {
+		slugs[routes.Project.Name()] = append(slugs[routes.Project.Name()], project.Slug)
+	}
+
+	newsletters, err := models.AllPublishedNewsletters(ctx, exec)
+	if err != nil {
+		return InternalLinkIndex{}, err
+	}
+	for _, newsletter := range newsletters {
+		slugs[routes.Newsletter.Name()] = append(slugs[routes.Newsletter.Name()], newsletter.Slug)
+	}
+
+	series, err := models.AllPublishedSeries(ctx, exec)
+	if err != nil {
+		return InternalLinkIndex{}, err
+	}
+	for _, s := range series {
+		slugs[routes.Series.Name()] = append(slugs[routes.Series.Name()], s.Slug)
+		slugs[routes.SeriesFeed.Name()] = append(slugs[routes.SeriesFeed.Name()], s.Slug)
+	}
+
+	tags, err := models.AllPublishedTags(ctx, exec)
+	if err != nil {
+		return InternalLinkIndex{}, err
+	}
+	for _, tag := range tags {
+		slugs[routes.Tag.Name()] = append(slugs[routes.Tag.Name()], tag.Slug)
+		slugs[routes.TagPage.Name()] = append(slugs[routes.TagPage.Name()], tag.Slug)
+	}
+
+	// Slug routes stay checked when nothing is published under them.
+	for _, name := range []string{
+		routes.Article.Name(),
+		routes.Project.Name(),
+		routes.Newsletter.Name(),
+		routes.Series.Name(),
+		routes.SeriesFeed.Name(),
+		routes.Tag.Name(),
+		routes.TagPage.Name(),
+	} {
+		if _, ok := slugs[name]; !ok {
+			slugs[name] = nil
+		}
+	}
+
+	return NewInternalLinkIndex(siteRoutes, slugs), nil
+}
+
+// Check reports whether path has a page. Missing pages get status 404 like
+// they would when requested.
+func (i InternalLinkIndex) Check(path string) LinkCheckResult {
+	if unescaped, err := url.PathUnescape(path); err == nil {
+		path = unescaped
+	}
+
+	for _, route := range i.routes {
+		params, ok := matchRoutePath(route.Path, path)
+		if !ok {
+			continue
+		}
+
+		slugs, checked := i.slugs[route.Name]
+		if !checked {
+			return LinkCheckResult{StatusCode: http.StatusOK}
+		}
+		if _, found := slugs[params["slug"]]; found {
+			return LinkCheckResult{StatusCode: http.StatusOK}
+		}
+
+		return LinkCheckResult{
+			StatusCode: http.StatusNotFound,
+			Error:      fmt.Sprintf("nothing published with slug %q", params["slug"]),
+			Broken:     true,
+		}
+	}
+
+	return LinkCheckResult{
+		StatusCode: http.StatusNotFound,
+		Error:      "no route matches the path",
+		Broken:     true,
+	}
+}
+
+// matchRoutePath matches p against an echo route pattern, where :name
+// matches one segment, or the part of a segment before a fixed suffix as in
+// :slug.png, and * matches the rest.
+func matchRoutePath(pattern, p string) (map[string]string, bool) {
+	patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(p, "/"), "/")
+	params := map[string]string{}
+
+	for i, segment := range patternSegments {
+		if segment == "*" {
+			return params, true
+		}
+		if i >= len(pathSegments) {
+			return nil, false
+		}
+
+		name, isParam := strings.CutPrefix(segment, ":")
+		if !isParam {
+			if segment != pathSegments[i] {
+				return nil, false
+			}
+			continue
+		}
+
+		value := pathSegments[i]
+		if dot := strings.Index(name, "."); dot >= 0 {
+			var ok bool
+			value, ok = strings.CutSuffix(value, name[dot:])
+			if !ok {
+				return nil, false
+			}
+			name = name[:dot]
+		}
+		if value == "" {
+			return nil, false
+		}
+		params[name] = value
+	}
+
+	if len(patternSegments) != len(pathSegments) {
+		return nil, false
+	}
+
+	return params, true
+}
+
+// LinkCheckResult is the outcome of checking one link. StatusCode is 0 when
+// no response was received, and Error then says why.
+type LinkCheckResult struct {
+	StatusCode int
+	Error      string
+	Broken     bool
+}
+
+// LinkCheckOptions tune CheckLinks. Zero values fall back to the defaults.
+type LinkCheckOptions struct {
+	// Client makes the requests. Use NewExternalClient in production, as
+	// links in content are not trusted to stay off the internal network.
+	Client *http.Client
+	// Concurrency is how many links are checked at the same time overall.
+	Concurrency int
+	// HostConcurrency is how many requests run at the same time per host.
+	HostConcurrency int
+	// HostInterval is the least time between two requests to the same host.
+	HostInterval time.Duration
+}
+
+// CheckLinks requests every URL in links and reports the results by URL. A
+// HEAD request is tried first; links that fail it are requested again with
+// GET, as many servers answer HEAD wrongly. Requests are spread over hosts
+// so that no host sees more than HostConcurrency requests at a time or more
+// than one per HostInterval.
+func CheckLinks(ctx context.Context, links []string, opts LinkCheckOptions) map[string]LinkCheckResult {
+	if opts.Client == nil {
+		opts.Client = NewExternalClient()
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultLinkCheckConcurrency
+	}
+	if opts.HostConcurrency <= 0 {
+		opts.HostConcurrency = DefaultLinkCheckHostConcurrency
+	}
+	if opts.HostInterval <= 0 {
+		opts.HostInterval = DefaultLinkCheckHostInterval
+	}
+
+	hosts := &linkCheckHosts{
+		concurrency: opts.HostConcurrency,
+		interval:    opts.HostInterval,
+		hosts:       map[string]*linkCheckHost{},
+	}
+
+	pending := make(chan string)
+	go func() {
+		defer close(pending)
+		for _, link := range interleaveLinkHosts(links) {
+			select {
+			case pending <- link:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var mu sync.Mutex
+	results := make(map[string]LinkCheckResult, len(links))
+
+	var wg sync.WaitGroup
+	for range opts.Concurrency {
+		wg.Go(func() {
+			for link := range pending {
+				result := checkLink(ctx, opts.Client, hosts, link)
+
+				mu.Lock()
+				results[link] = result
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+
+	return results
+}
+
+func checkLink(
+	ctx context.Context,
+	client *http.Client,
+	hosts *linkCheckHosts,
+	link string,
+) LinkCheckResult {
+	target, err := url.Parse(link)
+	if err != nil {
+		return LinkCheckResult{Error: err.Error(), Broken: true}
+	}
+
+	release, err := hosts.acquire(ctx, target.Host)
+	if err != nil {
+		return LinkCheckResult{Error: err.Error(), Broken: true}
+	}
+	defer release()
+
+	status, err := requestLink(ctx, client, hosts, target, http.MethodHead)
+	if err == nil && status < http.StatusBadRequest {
+		return LinkCheckResult{StatusCode: status}
+	}
+	if ctx.Err() != nil {
+		return LinkCheckResult{Error: ctx.Err().Error(), Broken: true}
+	}
+
+	status, err = requestLink(ctx, client, hosts, target, http.MethodGet)
+	if err != nil {
+		return LinkCheckResult{Error: linkCheckError(err), Broken: true}
+	}
+
+	return LinkCheckResult{StatusCode: status, Broken: status >= http.StatusBadRequest}
+}
+
+func requestLink(
+	ctx context.Context,
+	client *http.Client,
+	hosts *linkCheckHosts,
+	target *url.URL,
+	method string,
+) (int, error) {
+	if err := hosts.wait(ctx, target.Host); err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "text/html, */*;q=0.5")
+	req.Header.Set("User-Agent", "mortenvistisen-linkcheck/1.0")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(res.Body, maxLinkCheckBody))
+
+	return res.StatusCode, nil
+}
+
+// linkCheckError drops the method and URL that net/http wraps around
+// request errors; the report already shows the URL.
+func linkCheckError(err error) string {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err.Error()
+	}
+
+	return err.Error()
+}
+
+// interleaveLinkHosts orders links so that consecutive links are on
+// different hosts where possible, which keeps the workers from queueing up
+// behind the rate limit of a single host.
+func interleaveLinkHosts(links []string) []string {
+	var hosts []string
+	byHost := map[string][]string{}
+	for _, link := range links {
+		host := link
+		if u, err := url.Parse(link); err == nil {
+			host = u.Host
+		}
+		if _, ok := byHost[host]; !ok {
+			hosts = append(hosts, host)
+		}
+		byHost[host] = append(byHost[host], link)
+	}
+
+	ordered := make([]string, 0, len(links))
+	for len(ordered) < len(links) {
+		for _, host := range hosts {
+			if queued := byHost[host]; len(queued) > 0 {
+				ordered = append(ordered, queued[0])
+				byHost[host] = queued[1:]
+			}
+		}
+	}
+
+	return ordered
+}
+
+type linkCheckHosts struct {
+	concurrency int
+	interval    time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]*linkCheckHost
+}
+
+type linkCheckHost struct {
+	slots chan struct{}
+	next  time.Time
+}
+
+func (h *linkCheckHosts) host(name string) *linkCheckHost {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	host, ok := h.hosts[name]
+	if !ok {
+		host = &linkCheckHost{slots: make(chan struct{}, h.concurrency)}
+		h.hosts[name] = host
+	}
+
+	return host
+}
+
+// acquire takes one of the host's concurrent slots.
+func (h *linkCheckHosts) acquire(ctx context.Context, name string) (func(), error) {
+	host := h.host(name)
+
+	select {
+	case host.slots <- struct{}{}:
+		return func() { <-host.slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// wait blocks until the next request to the host is due and books the one
+// after it.
+func (h *linkCheckHosts) wait(ctx context.Context, name string) error {
+	host := h.host(name)
+
+	h.mu.Lock()
+	now := time.Now()
+	at := host.next
+	if at.Before(now) {
+		at = now
+	}
+	host.next = at.Add(h.interval)
+	h.mu.Unlock()
+
+	if delay := at.Sub(now); delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// LinkCheckSummary counts the links a run of RunLinkCheck checked.
+type LinkCheckSummary struct {
+	Checked int
+	Broken  int
+	Removed int64
+}
+
+// RunLinkCheck checks every link in published content and records the
+// results. Internal links are checked against siteRoutes and the current
+// slugs, external links over HTTP. Links no longer found in any content are
+// removed from the report.
+func RunLinkCheck(
+	ctx context.Context,
+	exec storage.Executor,
+	siteRoutes []SiteRoute,
+	opts LinkCheckOptions,
+) (LinkCheckSummary, error) {
+	var summary LinkCheckSummary
+	startedAt := time.Now()
+
+	links, err := CollectContentLinks(ctx, exec)
+	if err != nil {
+		return summary, err
+	}
+
+	index, err := LoadInternalLinkIndex(ctx, exec, siteRoutes)
+	if err != nil {
+		return summary, err
+	}
+
+	var external []string
+	for _, link := range links {
+		if !link.Internal {
+			external = append(external, link.URL)
+		}
+	}
+	results := CheckLinks(ctx, external, opts)
+	if err := ctx.Err(); err != nil {
+		return summary, err
+	}
+
+	for _, link := range links {
+		result, ok := results[link.URL]
+		if link.Internal {
+			result, ok = index.Check(link.URL), true
+		}
+		if !ok {
+			continue
+		}
+
+		if _, err := models.RecordLinkCheck(ctx, exec, models.RecordLinkCheckData{
+			URL:        link.URL,
+			Internal:   link.Internal,
+			Broken:     result.Broken,
+			StatusCode: result.StatusCode,
+			Error:      result.Error,
+			Sources:    link.Sources,
+			CheckedAt:  time.Now(),
+		}); err != nil {
+			return summary, err
+		}
+
+		summary.Checked++
+		if result.Broken {
+			summary.Broken++
+		}
+	}
+
+	summary.Removed, err = models.DestroyLinkChecksBefore(ctx, exec, startedAt)
+	if err != nil {
+		return summary, err
+	}
+
+	return summary, nil
+}
+
+// EnqueueLinkCheck queues a run of the link checker, unless one is already
+// waiting or running.
+func EnqueueLinkCheck(ctx context.Context, insertOnly queue.InsertOnly) error {
+	if _, err := insertOnly.Insert(ctx, jobs.CheckLinksArgs{}, nil); err != nil {
+		return fmt.Errorf("enqueue link check: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,252 @@
+package services_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"mortenvistisen/services"
+)
+
+func TestExtractMarkdownLinks(t *testing.T) {
+	content := strings.Join([]string{
+		"# Links",
+		"",
+		"See [the docs](https://example.com/docs) and",
+		"![a chart](/media/chart.png).",
+		"",
+		"Plain https://example.org/autolink and www.example.net too.",
+		"",
+		"```",
+		"[not a link](https://example.com/code)",
+		"```",
+		"",
+		"A [reference][ref] and [a fragment](#links).",
+		"",
+		"[ref]: https://example.com/reference",
+	}, "\n")
+
+	expected := []services.MarkdownLink{
+		{Destination: "https://example.com/docs", Line: 3},
+		{Destination: "/media/chart.png", Line: 4},
+		{Destination: "https://example.org/autolink", Line: 6},
+		{Destination: "http://www.example.net", Line: 6},
+		{Destination: "https://example.com/reference", Line: 12},
+		{Destination: "#links", Line: 12},
+	}
+
+	if links := services.ExtractMarkdownLinks(content); !reflect.DeepEqual(links, expected) {
+		t.Errorf("expected %+v, got %+v", expected, links)
+	}
+}
+
+func TestInternalLinkIndex(t *testing.T) {
+	index := services.NewInternalLinkIndex(
+		[]services.SiteRoute{
+			{Name: "pages.home", Path: "/"},
+			{Name: "articles.overview", Path: "/posts"},
+			{Name: "articles.show.slug", Path: "/posts/:slug"},
+			{Name: "og_images.article", Path: "/og/articles/:slug.png"},
+			{Name: "media.file", Path: "/media/:file"},
+		},
+		map[string][]string{
+			"articles.show.slug": {"current-slug"},
+			"og_images.article":  {"current-slug"},
+		},
+	)
+
+	tests := map[string]struct {
+		path   string
+		broken bool
+	}{
+		"home":                   {path: "/", broken: false},
+		"static page":            {path: "/posts", broken: false},
+		"trailing slash":         {path: "/posts/", broken: false},
+		"current slug":           {path: "/posts/current-slug", broken: false},
+		"old slug":               {path: "/posts/old-slug", broken: true},
+		"slug with suffix":       {path: "/og/articles/current-slug.png", broken: false},
+		"old slug with suffix":   {path: "/og/articles/old-slug.png", broken: true},
+		"unchecked parameter":    {path: "/media/anything.webp", broken: false},
+		"unknown route":          {path: "/blog/current-slug", broken: true},
+		"too many segments":      {path: "/posts/current-slug/extra", broken: true},
+		"escaped current slug":   {path: "/posts/current%2Dslug", broken: false},
+		"missing parameter part": {path: "/og/articles/.png", broken: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := index.Check(tt.path)
+			if result.Broken != tt.broken {
+				t.Errorf("expected broken %v for %s, got %+v", tt.broken, tt.path, result)
+			}
+			if tt.broken && result.StatusCode != http.StatusNotFound {
+				t.Errorf("expected status 404 for %s, got %d", tt.path, result.StatusCode)
+			}
+		})
+	}
+}
+
+func TestCheckLinks(t *testing.T) {
+	var mu sync.Mutex
+	methods := map[string][]string{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("/no-head", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/missing", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/error", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.UserAgent() == "" || strings.HasPrefix(r.UserAgent(), "Go-http-client") {
+			t.Errorf("expected a link checker user agent, got %q", r.UserAgent())
+		}
+		mu.Lock()
+		methods[r.URL.Path] = append(methods[r.URL.Path], r.Method)
+		mu.Unlock()
+		mux.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closedURL := closed.URL + "/gone"
+	closed.Close()
+
+	links := []string{
+		server.URL + "/ok",
+		server.URL + "/missing",
+		server.URL + "/no-head",
+		server.URL + "/moved",
+		server.URL + "/error",
+		closedURL,
+	}
+
+	results := services.CheckLinks(context.Background(), links, services.LinkCheckOptions{
+		Client:       server.Client(),
+		HostInterval: time.Millisecond,
+	})
+
+	expected := map[string]struct {
+		status int
+		broken bool
+	}{
+		server.URL + "/ok":      {status: http.StatusOK},
+		server.URL + "/missing": {status: http.StatusNotFound, broken: true},
+		server.URL + "/no-head": {status: http.StatusOK},
+		server.URL + "/moved":   {status: http.StatusNotFound, broken: true},
+		server.URL + "/error":   {status: http.StatusInternalServerError, broken: true},
+		closedURL:               {status: 0, broken: true},
+	}
+
+	if len(results) != len(expected) {
+		t.Fatalf("expected %d results, got %d", len(expected), len(results))
+	}
+	for link, want := range expected {
+		result := results[link]
+		if result.StatusCode != want.status || result.Broken != want.broken {
+			t.Errorf("%s: expected status %d broken %v, got %+v", link, want.status, want.broken, result)
+		}
+	}
+	if result := results[closedURL]; result.Error == "" {
+		t.Errorf("expected an error for an unreachable link, got %+v", result)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if got := methods["/ok"]; !reflect.DeepEqual(got, []string{http.MethodHead}) {
+		t.Errorf("expected a working link to only get HEAD, got %v", got)
+	}
+	if got := methods["/no-head"]; !reflect.DeepEqual(got, []string{http.MethodHead, http.MethodGet}) {
+		t.Errorf("expected a failed HEAD to fall back to GET, got %v", got)
+	}
+}
+
+func TestCheckLinksHostLimits(t *testing.T) {
+	const interval = 20 * time.Millisecond
+
+	var mu sync.Mutex
+	var inFlight, maxInFlight int
+	var requests []time.Time
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		requests = append(requests, time.Now())
+		mu.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	links := make([]string, 6)
+	for i := range links {
+		links[i] = server.URL + "/page/" + string(rune('a'+i))
+	}
+
+	results := services.CheckLinks(context.Background(), links, services.LinkCheckOptions{
+		Client:          server.Client(),
+		Concurrency:     4,
+		HostConcurrency: 1,
+		HostInterval:    interval,
+	})
+
+	for _, link := range links {
+		if result := results[link]; result.Broken {
+			t.Errorf("expected %s to work, got %+v", link, result)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if maxInFlight != 1 {
+		t.Errorf("expected at most 1 request in flight per host, got %d", maxInFlight)
+	}
+	if len(requests) != len(links) {
+		t.Fatalf("expected %d requests, got %d", len(links), len(requests))
+	}
+	for i := 1; i < len(requests); i++ {
+		// Timers may fire a little early relative to the handler's clock.
+		if gap := requests[i].Sub(requests[i-1]); gap < interval-2*time.Millisecond {
+			t.Errorf("expected requests at least %s apart, got %s", interval, gap)
+		}
+	}
+}
+
+func TestCheckLinksCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := services.CheckLinks(ctx, []string{server.URL + "/a", server.URL + "/b"}, services.LinkCheckOptions{
+		Client: server.Client(),
+	})
+
+	for link, result := range results {
+		if !result.Broken || result.Error == "" {
+			t.Errorf("expected %s to fail with the cancelled context, got %+v", link, result)
+		}
+	}
+}
@@ -2,20 +2,39 @@ package services
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"html/template"
+	"strings"
 
 	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/jackc/pgx/v5"
 	"github.com/yuin/goldmark"
 	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/extension"
 	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+
+	"mortenvistisen/config"
+	"mortenvistisen/internal/markdown"
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models"
+	"mortenvistisen/router/routes"
 )
 
-func newMarkdownParser() goldmark.Markdown {
+func newMarkdownParser(resolveEmbed markdown.EmbedResolver) goldmark.Markdown {
 	return goldmark.New(
 		goldmark.WithExtensions(
 			extension.GFM,
+			extension.Footnote,
+			extension.DefinitionList,
+			markdown.CalloutExtension,
+			markdown.FigureExtension,
+			markdown.MathExtension,
+			markdown.NewEmbedExtension(resolveEmbed),
 			highlighting.NewHighlighting(
 				highlighting.WithStyle("gruvbox"),
 				highlighting.WithFormatOptions(
@@ -36,10 +55,294 @@ func newMarkdownParser() goldmark.Markdown {
 	)
 }
 
+// MarkdownToHTML renders content without loaded embeds: embeds become
+// plain links titled by their slug. Stored content is rendered with
+// RenderArticleContent and friends instead.
 func MarkdownToHTML(content string) string {
+	return RenderMarkdown(content, MarkdownOptions{}).HTML
+}
+
+// MarkdownEmbeds are the targets of the embeds in some content, as loaded by
+// LoadMarkdownEmbeds.
+type MarkdownEmbeds map[markdown.EmbedRef]markdown.EmbedTarget
+
+// LoadMarkdownEmbeds looks up the published projects and articles that
+// content embeds. Embeds of anything else are left out when rendering.
+// Links are absolute, as rendered content is also sent in emails and to
+// ActivityPub followers.
+func LoadMarkdownEmbeds(ctx context.Context, exec storage.Executor, content string) (MarkdownEmbeds, error) {
+	doc := newMarkdownParser(nil).Parser().Parse(text.NewReader([]byte(content)))
+
+	embeds := MarkdownEmbeds{}
+	for _, ref := range markdown.EmbedRefs(doc) {
+		switch ref.Kind {
+		case markdown.EmbedProject:
+			project, err := models.FindProjectBySlug(ctx, exec, ref.Slug)
+			if errors.Is(err, pgx.ErrNoRows) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if project.Published {
+				embeds[ref] = markdown.EmbedTarget{
+					Title:       project.Title,
+					Description: project.Description,
+					URL:         absoluteURL(routes.Project.URL(project.Slug)),
+				}
+			}
+		case markdown.EmbedArticle:
+			article, err := models.FindArticleBySlug(ctx, exec, ref.Slug)
+			if errors.Is(err, pgx.ErrNoRows) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if article.Published {
+				embeds[ref] = markdown.EmbedTarget{
+					Title:       article.Title,
+					Description: article.Excerpt,
+					URL:         absoluteURL(routes.Article.URL(article.Slug)),
+				}
+			}
+		}
+	}
+
+	return embeds, nil
+}
+
+// resolve finds the target of an embed. Without loaded embeds it falls back
+// to a link titled by the slug.
+func (e MarkdownEmbeds) resolve(ref markdown.EmbedRef) (markdown.EmbedTarget, bool) {
+	if e != nil {
+		target, ok := e[ref]
+		return target, ok
+	}
+
+	path := routes.Article.URL(ref.Slug)
+	if ref.Kind == markdown.EmbedProject {
+		path = routes.Project.URL(ref.Slug)
+	}
+
+	return markdown.EmbedTarget{Title: ref.Slug, URL: absoluteURL(path)}, true
+}
+
+func absoluteURL(path string) string {
+	return strings.TrimRight(config.BaseURL, "/") + path
+}
+
+// MarkdownSummary is the plain text of the top level paragraphs of
+// content, shortened to at most limit runes. Headings, lists, code blocks,
+// images and raw HTML are left out.
+func MarkdownSummary(content string, limit int) string {
+	source := []byte(content)
+	doc := newMarkdownParser(nil).Parser().Parse(text.NewReader(source))
+
+	var summary strings.Builder
+	for block := doc.FirstChild(); block != nil; block = block.NextSibling() {
+		if block.Kind() != ast.KindParagraph {
+			continue
+		}
+
+		writePlainText(&summary, block, source)
+		summary.WriteString(" ")
+
+		if summary.Len() > limit*4 {
+			break
+		}
+	}
+
+	return commentExcerpt(summary.String(), limit)
+}
+
+// writePlainText writes the text inside node, leaving out images and raw
+// HTML.
+func writePlainText(w *strings.Builder, node ast.Node, source []byte) {
+	_ = ast.Walk(node, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch n := node.(type) {
+		case *ast.RawHTML, *ast.Image:
+			return ast.WalkSkipChildren, nil
+		case *ast.Text:
+			w.Write(n.Segment.Value(source))
+			if n.SoftLineBreak() || n.HardLineBreak() {
+				w.WriteString(" ")
+			}
+		case *ast.String:
+			w.Write(n.Value)
+		}
+
+		return ast.WalkContinue, nil
+	})
+}
+
+type RenderedMarkdown struct {
+	HTML     string
+	Headings []models.Heading
+}
+
+type MarkdownOptions struct {
+	// HeadingAnchors ends each heading in a permalink to itself.
+	HeadingAnchors bool
+	// Embeds are the loaded embed targets; see MarkdownToHTML for what
+	// happens without them.
+	Embeds MarkdownEmbeds
+}
+
+// headingAnchorClass is the class of the permalink appended to headings.
+const headingAnchorClass = "heading-anchor"
+
+// RenderMarkdown renders content and also returns its headings as a tree,
+// linked by their generated IDs.
+func RenderMarkdown(content string, opts MarkdownOptions) RenderedMarkdown {
+	source := []byte(content)
+	md := newMarkdownParser(opts.Embeds.resolve)
+	doc := md.Parser().Parse(text.NewReader(source))
+
+	var flat []models.Heading
+	_ = ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := node.(*ast.Heading)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+
+		rawID, _ := heading.AttributeString("id")
+		id, _ := rawID.([]byte)
+		if len(id) == 0 {
+			return ast.WalkSkipChildren, nil
+		}
+
+		var text strings.Builder
+		writePlainText(&text, heading, source)
+		flat = append(flat, models.Heading{
+			Level: heading.Level,
+			ID:    string(id),
+			Text:  strings.Join(strings.Fields(text.String()), " "),
+		})
+
+		if opts.HeadingAnchors {
+			link := ast.NewLink()
+			link.Destination = []byte("#" + string(id))
+			link.SetAttributeString("class", []byte(headingAnchorClass))
+			link.SetAttributeString("title", []byte("Link to this section"))
+			link.AppendChild(link, ast.NewString([]byte("#")))
+			heading.AppendChild(heading, link)
+		}
+
+		return ast.WalkSkipChildren, nil
+	})
+
+	var out bytes.Buffer
+	if err := md.Renderer().Render(&out, source, doc); err != nil {
+		return RenderedMarkdown{HTML: template.HTMLEscapeString(content)}
+	}
+
+	return RenderedMarkdown{HTML: out.String(), Headings: nestHeadings(flat)}
+}
+
+// nestHeadings builds the heading tree: each heading goes under the
+// closest heading before it with a lower level.
+func nestHeadings(flat []models.Heading) []models.Heading {
+	var nest func(i int, level int) ([]models.Heading, int)
+	nest = func(i int, level int) ([]models.Heading, int) {
+		var siblings []models.Heading
+		for i < len(flat) && flat[i].Level > level {
+			heading := flat[i]
+			heading.Children, i = nest(i+1, heading.Level)
+			siblings = append(siblings, heading)
+		}
+		return siblings, i
+	}
+
+	tree, _ := nest(0, 0)
+	return tree
+}
+
+// UntrustedMarkdownToHTML renders markdown written by readers rather than
+// admins. It supports GFM, leaves raw HTML out and passes the result
+// through SanitizeHTML, so links are nofollow and limited to safe schemes.
+func UntrustedMarkdownToHTML(content string) string {
+	return renderUntrustedMarkdown(newUntrustedMarkdownParser(), content)
+}
+
+// CommentMarkdownToHTML renders a comment with the untrusted profile of
+// UntrustedMarkdownToHTML. Headings become paragraphs and images become
+// links on top of that.
+func CommentMarkdownToHTML(content string) string {
+	return renderUntrustedMarkdown(newUntrustedMarkdownParser(
+		parser.WithASTTransformers(util.Prioritized(commentTransformer{}, 100)),
+	), content)
+}
+
+func renderUntrustedMarkdown(md goldmark.Markdown, content string) string {
 	var out bytes.Buffer
-	if err := newMarkdownParser().Convert([]byte(content), &out); err != nil {
+	if err := md.Convert([]byte(content), &out); err != nil {
 		return template.HTMLEscapeString(content)
 	}
-	return out.String()
+	return SanitizeHTML(out.String())
+}
+
+func newUntrustedMarkdownParser(opts ...parser.Option) goldmark.Markdown {
+	return goldmark.New(
+		goldmark.WithExtensions(
+			extension.GFM,
+		),
+		goldmark.WithParserOptions(opts...),
+		goldmark.WithRendererOptions(
+			html.WithHardWraps(),
+			html.WithXHTML(),
+		),
+	)
+}
+
+// commentTransformer tones a comment down to inline formatting, lists,
+// quotes and code.
+type commentTransformer struct{}
+
+func (commentTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	var headings, images []ast.Node
+	_ = ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch n := node.(type) {
+		case *ast.Heading:
+			headings = append(headings, n)
+		case *ast.Image:
+			images = append(images, n)
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	for _, heading := range headings {
+		paragraph := ast.NewParagraph()
+		paragraph.SetLines(heading.Lines())
+		for child := heading.FirstChild(); child != nil; {
+			next := child.NextSibling()
+			paragraph.AppendChild(paragraph, child)
+			child = next
+		}
+		heading.Parent().ReplaceChild(heading.Parent(), heading, paragraph)
+	}
+
+	for _, node := range images {
+		image := node.(*ast.Image)
+		link := ast.NewLink()
+		link.Destination = image.Destination
+		for child := image.FirstChild(); child != nil; {
+			next := child.NextSibling()
+			link.AppendChild(link, child)
+			child = next
+		}
+		if !link.HasChildren() {
+			link.AppendChild(link, ast.NewString(image.Destination))
+		}
+		image.Parent().ReplaceChild(image.Parent(), image, link)
+	}
 }
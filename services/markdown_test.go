@@ -0,0 +1,143 @@
+package services_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"mortenvistisen/internal/markdown"
+	"mortenvistisen/models"
+	"mortenvistisen/services"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of the markdown tests")
+
+func TestMarkdownSummary(t *testing.T) {
+	tests := map[string]struct {
+		content string
+		limit   int
+		want    string
+	}{
+		"paragraphs only": {
+			content: "# Title\n\nFirst *part* with `code`\nand a [link](https://example.com).\n\n- a list\n\n```go\nfunc main() {}\n```\n\nSecond part.",
+			limit:   160,
+			want:    "First part with code and a link. Second part.",
+		},
+		"images and html": {
+			content: "![alt text](/a.png) Caption <span>inline</span> text.",
+			limit:   160,
+			want:    "Caption inline text.",
+		},
+		"shortened on a word": {
+			content: "One two three four five.",
+			limit:   12,
+			want:    "One two…",
+		},
+		"empty": {
+			content: "## Only a heading",
+			limit:   160,
+			want:    "",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := services.MarkdownSummary(tt.content, tt.limit); got != tt.want {
+				t.Errorf("MarkdownSummary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderMarkdownHeadings(t *testing.T) {
+	content := "# Intro\n\nText.\n\n## Setup *fast*\n\n### Install\n\n## Setup *fast*\n\n#### Deep\n\n## Usage"
+
+	rendered := services.RenderMarkdown(content, services.MarkdownOptions{HeadingAnchors: true})
+
+	want := []models.Heading{
+		{Level: 1, ID: "intro", Text: "Intro", Children: []models.Heading{
+			{Level: 2, ID: "setup-fast", Text: "Setup fast", Children: []models.Heading{
+				{Level: 3, ID: "install", Text: "Install"},
+			}},
+			{Level: 2, ID: "setup-fast-1", Text: "Setup fast", Children: []models.Heading{
+				{Level: 4, ID: "deep", Text: "Deep"},
+			}},
+			{Level: 2, ID: "usage", Text: "Usage"},
+		}},
+	}
+	if !reflect.DeepEqual(rendered.Headings, want) {
+		t.Errorf("headings = %+v, want %+v", rendered.Headings, want)
+	}
+
+	anchor := `<a href="#setup-fast-1" class="heading-anchor" title="Link to this section">#</a></h2>`
+	if !strings.Contains(rendered.HTML, anchor) {
+		t.Errorf("html is missing the anchor %s:\n%s", anchor, rendered.HTML)
+	}
+
+	plain := services.RenderMarkdown(content, services.MarkdownOptions{})
+	if strings.Contains(plain.HTML, "heading-anchor") {
+		t.Errorf("html has anchors although they are off:\n%s", plain.HTML)
+	}
+	if !reflect.DeepEqual(plain.Headings, want) {
+		t.Errorf("headings without anchors = %+v, want %+v", plain.Headings, want)
+	}
+}
+
+// TestRenderMarkdownGolden renders every testdata/markdown/*.md file and
+// compares it to the .golden.html file next to it. Run with -update to
+// rewrite the golden files after an intended change.
+func TestRenderMarkdownGolden(t *testing.T) {
+	embeds := services.MarkdownEmbeds{
+		{Kind: markdown.EmbedProject, Slug: "andurel"}: {
+			Title:       "Andurel",
+			Description: "A Go web framework & generator.",
+			URL:         "/projects/andurel",
+		},
+	}
+
+	inputs, err := filepath.Glob(filepath.Join("testdata", "markdown", "*.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inputs) == 0 {
+		t.Fatal("no markdown test files")
+	}
+
+	for _, input := range inputs {
+		name := strings.TrimSuffix(filepath.Base(input), ".md")
+		t.Run(name, func(t *testing.T) {
+			content, err := os.ReadFile(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := services.RenderMarkdown(string(content), services.MarkdownOptions{Embeds: embeds}).HTML
+
+			golden := strings.TrimSuffix(input, ".md") + ".golden.html"
+			if *update {
+				if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != string(want) {
+				t.Errorf("%s does not match %s:\n%s", input, golden, got)
+			}
+		})
+	}
+}
+
+func TestMarkdownToHTMLEmbedFallback(t *testing.T) {
+	html := services.MarkdownToHTML(`{{< project "andurel" >}}`)
+
+	if !strings.Contains(html, `/projects/andurel"`) || !strings.Contains(html, `<span class="embed-title">andurel</span>`) {
+		t.Errorf("embed without loaded targets is not a link to the project:\n%s", html)
+	}
+}
@@ -0,0 +1,375 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/HugoSmits86/nativewebp"
+	"github.com/google/uuid"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models"
+)
+
+// MediaStorage keeps the files of the media library. Keys are flat file
+// names such as "<uuid>-960w.webp".
+type MediaStorage interface {
+	Put(ctx context.Context, key string, contentType string, data []byte) error
+	// Get returns ErrMediaFileNotFound for keys that were never stored.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+const (
+	// MediaMaxWidth caps the width of the largest stored variant; larger
+	// uploads are scaled down.
+	MediaMaxWidth = 2560
+	// mediaMaxPixels guards against images that are small on disk but
+	// huge once decoded.
+	mediaMaxPixels   = 50_000_000
+	mediaJPEGQuality = 85
+)
+
+// MediaVariantWidths are the extra widths generated below the full size
+// variant, for responsive images and thumbnails.
+var MediaVariantWidths = []int{480, 960, 1600}
+
+var (
+	ErrMediaFileNotFound    = errors.New("media file not found")
+	ErrUnsupportedMediaType = errors.New("unsupported image type, upload a JPEG, PNG or WebP")
+	ErrMediaTooLarge        = errors.New("image dimensions are too large")
+)
+
+// ImageVariant is one encoded file produced from an upload.
+type ImageVariant struct {
+	// Suffix distinguishes resized variants in the storage key, e.g.
+	// "-960w". The full size variant has none.
+	Suffix      string
+	Extension   string
+	ContentType string
+	Width       int
+	Height      int
+	Data        []byte
+}
+
+type ProcessedImage struct {
+	Width    int
+	Height   int
+	Variants []ImageVariant
+}
+
+// ProcessImage decodes an uploaded image and re-encodes it at the full
+// size and every smaller MediaVariantWidths width, each in the uploaded
+// format and as WebP. WebP uploads use PNG as their fallback format.
+// Re-encoding leaves EXIF and other metadata behind, so a JPEG's
+// orientation is applied to the pixels first.
+func ProcessImage(data []byte) (ProcessedImage, error) {
+	contentType := http.DetectContentType(data)
+
+	var fallback func(w io.Writer, img image.Image) error
+	var fallbackType, fallbackExtension string
+	switch contentType {
+	case "image/jpeg":
+		fallback = func(w io.Writer, img image.Image) error {
+			return jpeg.Encode(w, img, &jpeg.Options{Quality: mediaJPEGQuality})
+		}
+		fallbackType, fallbackExtension = "image/jpeg", ".jpg"
+	case "image/png", "image/webp":
+		fallback = png.Encode
+		fallbackType, fallbackExtension = "image/png", ".png"
+	default:
+		return ProcessedImage{}, ErrUnsupportedMediaType
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return ProcessedImage{}, errors.Join(ErrUnsupportedMediaType, err)
+	}
+	if cfg.Width*cfg.Height > mediaMaxPixels {
+		return ProcessedImage{}, ErrMediaTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return ProcessedImage{}, errors.Join(ErrUnsupportedMediaType, err)
+	}
+	if contentType == "image/jpeg" {
+		img = applyOrientation(img, jpegOrientation(data))
+	}
+
+	full := img
+	if img.Bounds().Dx() > MediaMaxWidth {
+		full = resizeImage(img, MediaMaxWidth)
+	}
+
+	sized := []image.Image{full}
+	suffixes := []string{""}
+	for _, width := range MediaVariantWidths {
+		if width < full.Bounds().Dx() {
+			sized = append(sized, resizeImage(full, width))
+			suffixes = append(suffixes, fmt.Sprintf("-%dw", width))
+		}
+	}
+
+	processed := ProcessedImage{
+		Width:  full.Bounds().Dx(),
+		Height: full.Bounds().Dy(),
+	}
+	for i, variant := range sized {
+		var buf bytes.Buffer
+		if err := fallback(&buf, variant); err != nil {
+			return ProcessedImage{}, fmt.Errorf("encode %s: %w", fallbackType, err)
+		}
+		processed.Variants = append(processed.Variants, ImageVariant{
+			Suffix:      suffixes[i],
+			Extension:   fallbackExtension,
+			ContentType: fallbackType,
+			Width:       variant.Bounds().Dx(),
+			Height:      variant.Bounds().Dy(),
+			Data:        buf.Bytes(),
+		})
+
+		var webp bytes.Buffer
+		if err := nativewebp.Encode(&webp, variant, nil); err != nil {
+			return ProcessedImage{}, fmt.Errorf("encode webp: %w", err)
+		}
+		processed.Variants = append(processed.Variants, ImageVariant{
+			Suffix:      suffixes[i],
+			Extension:   ".webp",
+			ContentType: "image/webp",
+			Width:       variant.Bounds().Dx(),
+			Height:      variant.Bounds().Dy(),
+			Data:        webp.Bytes(),
+		})
+	}
+
+	return processed, nil
+}
+
+func resizeImage(img image.Image, width int) image.Image {
+	bounds := img.Bounds()
+	height := max(1, bounds.Dy()*width/bounds.Dx())
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+
+	return dst
+}
+
+// jpegOrientation reads the EXIF orientation of a JPEG, 1 to 8, returning
+// 1 when there is none.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		// Start of scan: the metadata segments are all behind us.
+		if marker == 0xDA {
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+		if length < 2 || i+2+length > len(data) {
+			return 1
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+
+	return 1
+}
+
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	offset := int(order.Uint32(tiff[4:8]))
+	if offset < 8 || offset+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[offset : offset+2]))
+	for i := range entries {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:entry+2]) == 0x0112 {
+			orientation := int(order.Uint16(tiff[entry+8 : entry+10]))
+			if orientation < 1 || orientation > 8 {
+				return 1
+			}
+			return orientation
+		}
+	}
+
+	return 1
+}
+
+// applyOrientation turns img upright according to an EXIF orientation.
+func applyOrientation(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	src := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+	w, h := bounds.Dx(), bounds.Dy()
+
+	dstW, dstH := w, h
+	if orientation >= 5 {
+		dstW, dstH = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dstW, dstH))
+
+	for y := range dstH {
+		for x := range dstW {
+			var sx, sy int
+			switch orientation {
+			case 2:
+				sx, sy = w-1-x, y
+			case 3:
+				sx, sy = w-1-x, h-1-y
+			case 4:
+				sx, sy = x, h-1-y
+			case 5:
+				sx, sy = y, x
+			case 6:
+				sx, sy = y, h-1-x
+			case 7:
+				sx, sy = w-1-y, h-1-x
+			case 8:
+				sx, sy = w-1-y, x
+			}
+			copy(dst.Pix[dst.PixOffset(x, y):dst.PixOffset(x, y)+4], src.Pix[src.PixOffset(sx, sy):src.PixOffset(sx, sy)+4])
+		}
+	}
+
+	return dst
+}
+
+type UploadMediaData struct {
+	UploadedBy uuid.UUID
+	Filename   string
+	AltText    string
+	Content    []byte
+}
+
+// UploadMedia processes an uploaded image, stores its variants and records
+// it in the media library. Files already stored are removed again when a
+// later step fails.
+func UploadMedia(
+	ctx context.Context,
+	db storage.Pool,
+	store MediaStorage,
+	data UploadMediaData,
+) (models.Media, error) {
+	processed, err := ProcessImage(data.Content)
+	if err != nil {
+		return models.Media{}, err
+	}
+
+	id := uuid.New()
+	variants := make([]models.MediaVariant, 0, len(processed.Variants))
+	for _, variant := range processed.Variants {
+		key := id.String() + variant.Suffix + variant.Extension
+		if err := store.Put(ctx, key, variant.ContentType, variant.Data); err != nil {
+			removeMediaFiles(ctx, store, variants)
+			return models.Media{}, fmt.Errorf("store %s: %w", key, err)
+		}
+
+		variants = append(variants, models.MediaVariant{
+			StorageKey:  key,
+			ContentType: variant.ContentType,
+			Width:       int32(variant.Width),
+			Height:      int32(variant.Height),
+			ByteSize:    int64(len(variant.Data)),
+		})
+	}
+
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		removeMediaFiles(ctx, store, variants)
+		return models.Media{}, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	media, err := models.CreateMedia(ctx, tx, models.CreateMediaData{
+		ID:         id,
+		UploadedBy: data.UploadedBy,
+		Filename:   data.Filename,
+		AltText:    data.AltText,
+		Width:      int32(processed.Width),
+		Height:     int32(processed.Height),
+		Variants:   variants,
+	})
+	if err == nil {
+		err = tx.Commit(ctx)
+	}
+	if err != nil {
+		removeMediaFiles(ctx, store, variants)
+		return models.Media{}, err
+	}
+
+	return media, nil
+}
+
+// DestroyMedia removes the media from the library and deletes its files.
+// Files that fail to delete are logged and left behind rather than
+// keeping a record that points at half deleted variants.
+func DestroyMedia(
+	ctx context.Context,
+	db storage.Pool,
+	store MediaStorage,
+	media models.Media,
+) error {
+	if err := models.DestroyMedia(ctx, db.Conn(), media.ID); err != nil {
+		return err
+	}
+
+	removeMediaFiles(ctx, store, media.Variants)
+
+	return nil
+}
+
+func removeMediaFiles(ctx context.Context, store MediaStorage, variants []models.MediaVariant) {
+	for _, variant := range variants {
+		if err := store.Delete(ctx, variant.StorageKey); err != nil {
+			slog.ErrorContext(
+				ctx,
+				"failed to delete media file",
+				"key", variant.StorageKey,
+				"error", err,
+			)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package services_test
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"golang.org/x/image/webp"
+
+	"mortenvistisen/services"
+)
+
+// withExifOrientation inserts an EXIF segment carrying orientation right
+// after the JPEG's start of image marker.
+func withExifOrientation(t *testing.T, data []byte, orientation byte) []byte {
+	t.Helper()
+
+	tiff := []byte{
+		'M', 'M', 0x00, 0x2A, 0x00, 0x00, 0x00, 0x08,
+		0x00, 0x01,
+		0x01, 0x12, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01, 0x00, orientation, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00,
+	}
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	length := len(payload) + 2
+	segment := append([]byte{0xFF, 0xE1, byte(length >> 8), byte(length)}, payload...)
+
+	out := append([]byte{}, data[:2]...)
+	out = append(out, segment...)
+	return append(out, data[2:]...)
+}
+
+func TestProcessImageAppliesOrientationAndStripsExif(t *testing.T) {
+	// Left half red, right half blue, stored sideways with orientation 6,
+	// so the upright image is red on top and blue below.
+	src := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	for y := range 20 {
+		for x := range 40 {
+			if x < 20 {
+				src.Set(x, y, color.RGBA{R: 255, A: 255})
+			} else {
+				src.Set(x, y, color.RGBA{B: 255, A: 255})
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, src, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatal(err)
+	}
+	upload := withExifOrientation(t, buf.Bytes(), 6)
+
+	processed, err := services.ProcessImage(upload)
+	if err != nil {
+		t.Fatalf("process image: %v", err)
+	}
+	if processed.Width != 20 || processed.Height != 40 {
+		t.Fatalf("expected rotated dimensions 20x40, got %dx%d", processed.Width, processed.Height)
+	}
+	if len(processed.Variants) != 2 {
+		t.Fatalf("expected a JPEG and a WebP variant, got %d", len(processed.Variants))
+	}
+
+	for _, variant := range processed.Variants {
+		if bytes.Contains(variant.Data, []byte("Exif")) {
+			t.Errorf("expected EXIF to be stripped from %s", variant.ContentType)
+		}
+	}
+
+	out, err := jpeg.Decode(bytes.NewReader(processed.Variants[0].Data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, _, b, _ := out.At(10, 5).RGBA(); r < b {
+		t.Errorf("expected the top of the upright image to be red")
+	}
+	if r, _, b, _ := out.At(10, 35).RGBA(); b < r {
+		t.Errorf("expected the bottom of the upright image to be blue")
+	}
+}
+
+func TestProcessImageGeneratesResizedVariants(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 2000, 500))
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, src); err != nil {
+		t.Fatal(err)
+	}
+
+	processed, err := services.ProcessImage(buf.Bytes())
+	if err != nil {
+		t.Fatalf("process image: %v", err)
+	}
+
+	widths := map[string]int{}
+	for _, variant := range processed.Variants {
+		widths[variant.Suffix+variant.Extension] = variant.Width
+
+		if variant.ContentType == "image/webp" {
+			cfg, err := webp.DecodeConfig(bytes.NewReader(variant.Data))
+			if err != nil {
+				t.Fatalf("decode webp %q: %v", variant.Suffix, err)
+			}
+			if cfg.Width != variant.Width || cfg.Height != variant.Height {
+				t.Errorf("expected webp %q to be %dx%d, got %dx%d", variant.Suffix, variant.Width, variant.Height, cfg.Width, cfg.Height)
+			}
+		}
+	}
+
+	expected := map[string]int{
+		".png":        2000,
+		".webp":       2000,
+		"-480w.png":   480,
+		"-480w.webp":  480,
+		"-960w.png":   960,
+		"-960w.webp":  960,
+		"-1600w.png":  1600,
+		"-1600w.webp": 1600,
+	}
+	if len(widths) != len(expected) {
+		t.Fatalf("expected %d variants, got %v", len(expected), widths)
+	}
+	for name, width := range expected {
+		if widths[name] != width {
+			t.Errorf("expected %s to be %d wide, got %d", name, width, widths[name])
+		}
+	}
+}
+
+func TestProcessImageRejectsUnsupportedTypes(t *testing.T) {
+	_, err := services.ProcessImage([]byte("GIF89a not really an image"))
+	if !errors.Is(err, services.ErrUnsupportedMediaType) {
+		t.Errorf("expected ErrUnsupportedMediaType, got %v", err)
+	}
+}
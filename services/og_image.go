@@ -0,0 +1,283 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models"
+)
+
+const (
+	OGImageWidth  = 1200
+	OGImageHeight = 630
+	// ogImageVersion is part of every fingerprint. Bump it when the design
+	// changes so every stored image is rendered again.
+	ogImageVersion = "1"
+	ogImageMargin  = 80
+)
+
+// ogTitleSizes are tried in order until the title fits in three lines; at
+// the smallest size it is cut off after four.
+var ogTitleSizes = []float64{76, 68, 60, 52, 46}
+
+var (
+	ogBackground = color.RGBA{0x1d, 0x23, 0x2a, 0xff}
+	ogAccent     = color.RGBA{0x60, 0x5d, 0xff, 0xff}
+	ogLabel      = color.RGBA{0x9c, 0x9a, 0xff, 0xff}
+	ogText       = color.RGBA{0xf1, 0xf5, 0xfb, 0xff}
+	ogMuted      = color.RGBA{0xa6, 0xad, 0xbb, 0xff}
+	ogDivider    = color.RGBA{0x2f, 0x37, 0x42, 0xff}
+)
+
+// OGImageCard is what an Open Graph image shows.
+type OGImageCard struct {
+	// Label names the kind of content, e.g. "Article".
+	Label    string
+	Title    string
+	Tags     []string
+	Date     time.Time
+	SiteName string
+}
+
+// Fingerprint identifies the rendered image, so a stored image can be
+// checked against the content it was rendered from.
+func (c OGImageCard) Fingerprint() string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		ogImageVersion,
+		c.Label,
+		c.Title,
+		strings.Join(c.Tags, "\x1f"),
+		c.date(),
+		c.SiteName,
+	}, "\x00")))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func (c OGImageCard) date() string {
+	if c.Date.IsZero() {
+		return ""
+	}
+
+	return c.Date.Format("January 2, 2006")
+}
+
+type ogFontSet struct {
+	regular *opentype.Font
+	bold    *opentype.Font
+}
+
+var ogFonts = sync.OnceValues(func() (ogFontSet, error) {
+	regular, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		return ogFontSet{}, fmt.Errorf("parse regular font: %w", err)
+	}
+	bold, err := opentype.Parse(gobold.TTF)
+	if err != nil {
+		return ogFontSet{}, fmt.Errorf("parse bold font: %w", err)
+	}
+
+	return ogFontSet{regular, bold}, nil
+})
+
+// RenderOGImage draws the card as a 1200x630 PNG: the label and site name
+// on top, the title wrapped below them and the tags and date in a footer.
+func RenderOGImage(card OGImageCard) ([]byte, error) {
+	fonts, err := ogFonts()
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, OGImageWidth, OGImageHeight))
+	draw.Draw(img, img.Bounds(), image.NewUniform(ogBackground), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(0, 0, 16, OGImageHeight), image.NewUniform(ogAccent), image.Point{}, draw.Src)
+	draw.Draw(
+		img,
+		image.Rect(ogImageMargin, 510, OGImageWidth-ogImageMargin, 512),
+		image.NewUniform(ogDivider),
+		image.Point{},
+		draw.Src,
+	)
+
+	textWidth := OGImageWidth - 2*ogImageMargin
+
+	label, err := newOGFace(fonts.bold, 28)
+	if err != nil {
+		return nil, err
+	}
+	small, err := newOGFace(fonts.regular, 28)
+	if err != nil {
+		return nil, err
+	}
+
+	drawOGText(img, label, ogLabel, ogImageMargin, 120, strings.ToUpper(card.Label))
+	siteName := fitOGText(small, card.SiteName, textWidth/2)
+	drawOGText(img, small, ogMuted, OGImageWidth-ogImageMargin-font.MeasureString(small, siteName).Round(), 120, siteName)
+
+	var title font.Face
+	var lines []string
+	var size float64
+	for _, size = range ogTitleSizes {
+		title, err = newOGFace(fonts.bold, size)
+		if err != nil {
+			return nil, err
+		}
+		lines = wrapOGText(title, card.Title, textWidth)
+		if len(lines) <= 3 {
+			break
+		}
+	}
+	if len(lines) > 4 {
+		lines = lines[:4]
+		lines[3] = fitOGText(title, lines[3]+"…", textWidth)
+	}
+
+	lineHeight := int(size * 1.2)
+	for i, line := range lines {
+		drawOGText(img, title, ogText, ogImageMargin, 190+int(size)+i*lineHeight, line)
+	}
+
+	date := card.date()
+	dateWidth := font.MeasureString(small, date).Round()
+	drawOGText(img, small, ogMuted, OGImageWidth-ogImageMargin-dateWidth, 575, date)
+
+	tags := ""
+	for _, tag := range card.Tags {
+		next := strings.TrimSpace(tags + "  #" + tag)
+		if font.MeasureString(small, next).Round() > textWidth-dateWidth-40 {
+			break
+		}
+		tags = next
+	}
+	drawOGText(img, small, ogMuted, ogImageMargin, 575, tags)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("encode png: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func newOGFace(f *opentype.Font, size float64) (font.Face, error) {
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{
+		Size:    size,
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create font face: %w", err)
+	}
+
+	return face, nil
+}
+
+func drawOGText(img draw.Image, face font.Face, c color.Color, x, y int, text string) {
+	drawer := font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(c),
+		Face: face,
+		Dot:  fixed.P(x, y),
+	}
+	drawer.DrawString(text)
+}
+
+// wrapOGText breaks text into lines of at most width pixels, splitting
+// words that are too long for a line of their own.
+func wrapOGText(face font.Face, text string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		candidate := strings.TrimSpace(line + " " + word)
+		if font.MeasureString(face, candidate).Round() <= width {
+			line = candidate
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+
+		line = ""
+		for _, r := range word {
+			if line != "" && font.MeasureString(face, line+string(r)).Round() > width {
+				lines = append(lines, line)
+				line = ""
+			}
+			line += string(r)
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// fitOGText cuts text short with an ellipsis so it fits in width pixels.
+func fitOGText(face font.Face, text string, width int) string {
+	if font.MeasureString(face, text).Round() <= width {
+		return text
+	}
+
+	runes := []rune(strings.TrimSuffix(text, "…"))
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		candidate := strings.TrimSpace(string(runes)) + "…"
+		if font.MeasureString(face, candidate).Round() <= width {
+			return candidate
+		}
+	}
+
+	return ""
+}
+
+// OGImageFor returns the stored image of a subject, rendering and storing
+// it first when it is missing or was rendered from an older card.
+func OGImageFor(
+	ctx context.Context,
+	exec storage.Executor,
+	subjectType string,
+	subjectID int32,
+	card OGImageCard,
+) (models.OGImage, error) {
+	fingerprint := card.Fingerprint()
+
+	stored, err := models.FindOGImage(ctx, exec, subjectType, subjectID)
+	if err == nil && stored.Fingerprint == fingerprint {
+		return stored, nil
+	}
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return models.OGImage{}, err
+	}
+
+	rendered, err := RenderOGImage(card)
+	if err != nil {
+		return models.OGImage{}, err
+	}
+
+	return models.UpsertOGImage(ctx, exec, models.UpsertOGImageData{
+		SubjectType: subjectType,
+		SubjectID:   subjectID,
+		Fingerprint: fingerprint,
+		Image:       rendered,
+	})
+}
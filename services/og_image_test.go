@@ -0,0 +1,76 @@
+package services_test
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"mortenvistisen/services"
+)
+
+func TestRenderOGImage(t *testing.T) {
+	cards := map[string]services.OGImageCard{
+		"short": {
+			Label:    "Article",
+			Title:    "Hello",
+			Tags:     []string{"go", "htmx"},
+			Date:     time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC),
+			SiteName: "example.com",
+		},
+		"long": {
+			Label:    "Newsletter",
+			Title:    strings.Repeat("A title that keeps on going ", 20) + strings.Repeat("x", 200),
+			Tags:     []string{strings.Repeat("tag", 100)},
+			SiteName: "example.com",
+		},
+		"empty": {},
+	}
+
+	for name, card := range cards {
+		t.Run(name, func(t *testing.T) {
+			data, err := services.RenderOGImage(card)
+			if err != nil {
+				t.Fatalf("render: %v", err)
+			}
+
+			img, err := png.Decode(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("decode png: %v", err)
+			}
+			if got := img.Bounds().Size(); got.X != services.OGImageWidth || got.Y != services.OGImageHeight {
+				t.Fatalf("size = %v, want %dx%d", got, services.OGImageWidth, services.OGImageHeight)
+			}
+		})
+	}
+}
+
+func TestOGImageCardFingerprint(t *testing.T) {
+	card := services.OGImageCard{
+		Label:    "Article",
+		Title:    "Hello",
+		Tags:     []string{"go"},
+		Date:     time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC),
+		SiteName: "example.com",
+	}
+
+	same := card
+	same.Date = card.Date.Add(time.Hour)
+	if card.Fingerprint() != same.Fingerprint() {
+		t.Error("fingerprint changed although the drawn date did not")
+	}
+
+	retitled := card
+	retitled.Title = "Hello again"
+	if card.Fingerprint() == retitled.Fingerprint() {
+		t.Error("fingerprint did not change with the title")
+	}
+
+	// Tags are joined with a separator, so moving text between them counts.
+	retagged := card
+	retagged.Tags = []string{"g", "o"}
+	if card.Fingerprint() == retagged.Fingerprint() {
+		t.Error("fingerprint did not change with the tags")
+	}
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models"
+)
+
+const accountLockoutScope = "account_lockout"
+
+// MaxRateLimitWindow is the longest Window a rate limit or lockout policy
+// may have. PruneRateLimits deletes older hits, so a longer window would
+// never fill up; Validate rejects such policies.
+const MaxRateLimitWindow = time.Hour
+
+var ErrAccountLocked = errors.New("account temporarily locked")
+
+// RateLimitPolicy describes a sliding window limit. A key that records more
+// than Limit hits within Window is banned from the scope for BanDuration.
+type RateLimitPolicy struct {
+	Scope       string
+	Limit       int32
+	Window      time.Duration
+	BanDuration time.Duration
+}
+
+// Validate reports a policy that cannot be enforced, such as one with a
+// window longer than MaxRateLimitWindow.
+func (p RateLimitPolicy) Validate() error {
+	if p.Limit <= 0 {
+		return fmt.Errorf("rate limit %s: limit must be above zero", p.Scope)
+	}
+
+	return validateWindow("rate limit "+p.Scope, p.Window)
+}
+
+// LockoutPolicy describes how many failed logins an account may have within
+// Window before it is locked for Duration.
+type LockoutPolicy struct {
+	MaxFailures int32
+	Window      time.Duration
+	Duration    time.Duration
+}
+
+// Validate reports a policy that cannot be enforced, such as one with a
+// window longer than MaxRateLimitWindow.
+func (p LockoutPolicy) Validate() error {
+	if p.MaxFailures <= 0 {
+		return errors.New("account lockout: max failures must be above zero")
+	}
+
+	return validateWindow("account lockout", p.Window)
+}
+
+func validateWindow(name string, window time.Duration) error {
+	if window <= 0 || window > MaxRateLimitWindow {
+		return fmt.Errorf("%s: window %s must be above zero and at most %s", name, window, MaxRateLimitWindow)
+	}
+
+	return nil
+}
+
+// CheckRateLimit records a hit for key and reports whether the request is
+// allowed under the policy.
+func CheckRateLimit(
+	ctx context.Context,
+	db storage.Pool,
+	policy RateLimitPolicy,
+	key string,
+) (bool, error) {
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = models.FindActiveRateLimitBan(ctx, tx, policy.Scope, key)
+	if err == nil {
+		return false, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return false, fmt.Errorf("find active ban: %w", err)
+	}
+
+	hits, err := recordHit(ctx, tx, policy.Scope, key, policy.Window)
+	if err != nil {
+		return false, err
+	}
+
+	allowed := hits <= int64(policy.Limit)
+	if !allowed {
+		if _, err := models.UpsertRateLimitBan(ctx, tx, models.UpsertRateLimitBanData{
+			Scope:     policy.Scope,
+			Key:       key,
+			Reason:    fmt.Sprintf("exceeded %d requests within %s", policy.Limit, policy.Window),
+			ExpiresAt: time.Now().Add(policy.BanDuration),
+		}); err != nil {
+			return false, fmt.Errorf("upsert ban: %w", err)
+		}
+
+		if err := models.ClearRateLimitHits(ctx, tx, policy.Scope, key); err != nil {
+			return false, fmt.Errorf("clear hits: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return allowed, nil
+}
+
+// AuthenticateUserWithLockout wraps AuthenticateUser with per-account failed
+// login tracking. Accounts exceeding the policy are rejected with
+// ErrAccountLocked until the lockout expires.
+func AuthenticateUserWithLockout(
+	ctx context.Context,
+	db storage.Pool,
+	salt string,
+	policy LockoutPolicy,
+	data LoginData,
+) (models.User, error) {
+	key := strings.ToLower(strings.TrimSpace(data.Email))
+
+	_, err := models.FindActiveRateLimitBan(ctx, db.Conn(), accountLockoutScope, key)
+	if err == nil {
+		return models.User{}, ErrAccountLocked
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return models.User{}, fmt.Errorf("find account lockout: %w", err)
+	}
+
+	user, err := AuthenticateUser(ctx, db, salt, data)
+	if errors.Is(err, ErrInvalidCredentials) {
+		if lockErr := registerFailedLogin(ctx, db, policy, key); lockErr != nil {
+			return models.User{}, lockErr
+		}
+
+		return models.User{}, err
+	}
+	if err != nil {
+		return models.User{}, err
+	}
+
+	if err := models.ClearRateLimitHits(ctx, db.Conn(), accountLockoutScope, key); err != nil {
+		return models.User{}, fmt.Errorf("clear failed logins: %w", err)
+	}
+
+	return user, nil
+}
+
+func registerFailedLogin(
+	ctx context.Context,
+	db storage.Pool,
+	policy LockoutPolicy,
+	key string,
+) error {
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	failures, err := recordHit(ctx, tx, accountLockoutScope, key, policy.Window)
+	if err != nil {
+		return err
+	}
+
+	if failures >= int64(policy.MaxFailures) {
+		if _, err := models.UpsertRateLimitBan(ctx, tx, models.UpsertRateLimitBanData{
+			Scope:     accountLockoutScope,
+			Key:       key,
+			Reason:    fmt.Sprintf("%d failed logins within %s", failures, policy.Window),
+			ExpiresAt: time.Now().Add(policy.Duration),
+		}); err != nil {
+			return fmt.Errorf("upsert lockout: %w", err)
+		}
+
+		if err := models.ClearRateLimitHits(ctx, tx, accountLockoutScope, key); err != nil {
+			return fmt.Errorf("clear failed logins: %w", err)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// PruneRateLimits deletes hits older than MaxRateLimitWindow and bans that
+// have expired. Keys are otherwise only pruned when they are hit again, so
+// clients that never come back would leave their rows behind.
+func PruneRateLimits(
+	ctx context.Context,
+	exec storage.Executor,
+) (hits int64, bans int64, err error) {
+	hits, err = models.DestroyRateLimitHitsBefore(ctx, exec, time.Now().Add(-MaxRateLimitWindow))
+	if err != nil {
+		return 0, 0, fmt.Errorf("prune hits: %w", err)
+	}
+
+	bans, err = models.DestroyExpiredRateLimitBans(ctx, exec)
+	if err != nil {
+		return hits, 0, fmt.Errorf("prune bans: %w", err)
+	}
+
+	return hits, bans, nil
+}
+
+func recordHit(
+	ctx context.Context,
+	exec storage.Executor,
+	scope string,
+	key string,
+	window time.Duration,
+) (int64, error) {
+	windowStart := time.Now().Add(-window)
+
+	if err := models.PruneRateLimitHits(ctx, exec, scope, key, windowStart); err != nil {
+		return 0, fmt.Errorf("prune hits: %w", err)
+	}
+
+	if err := models.RecordRateLimitHit(ctx, exec, models.RecordRateLimitHitData{
+		Scope: scope,
+		Key:   key,
+	}); err != nil {
+		return 0, fmt.Errorf("record hit: %w", err)
+	}
+
+	hits, err := models.CountRateLimitHitsSince(ctx, exec, scope, key, windowStart)
+	if err != nil {
+		return 0, fmt.Errorf("count hits: %w", err)
+	}
+
+	return hits, nil
+}
@@ -0,0 +1,181 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"mortenvistisen/database"
+	"mortenvistisen/services"
+)
+
+func TestRateLimitPolicyValidate(t *testing.T) {
+	tests := map[string]struct {
+		policy  services.RateLimitPolicy
+		wantErr bool
+	}{
+		"window at the prune cap": {
+			policy: services.RateLimitPolicy{Scope: "test", Limit: 5, Window: services.MaxRateLimitWindow},
+		},
+		"window beyond the prune cap": {
+			policy:  services.RateLimitPolicy{Scope: "test", Limit: 5, Window: services.MaxRateLimitWindow + time.Minute},
+			wantErr: true,
+		},
+		"no window": {
+			policy:  services.RateLimitPolicy{Scope: "test", Limit: 5},
+			wantErr: true,
+		},
+		"no limit": {
+			policy:  services.RateLimitPolicy{Scope: "test", Window: time.Minute},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := tt.policy.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLockoutPolicyValidate(t *testing.T) {
+	tests := map[string]struct {
+		policy  services.LockoutPolicy
+		wantErr bool
+	}{
+		"window at the prune cap": {
+			policy: services.LockoutPolicy{MaxFailures: 5, Window: services.MaxRateLimitWindow},
+		},
+		"window beyond the prune cap": {
+			policy:  services.LockoutPolicy{MaxFailures: 5, Window: 2 * services.MaxRateLimitWindow},
+			wantErr: true,
+		},
+		"no failures allowed": {
+			policy:  services.LockoutPolicy{Window: time.Minute},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := tt.policy.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCheckRateLimit(t *testing.T) {
+	tdb := database.RequireTestDB(t)
+	ctx := context.Background()
+
+	tests := map[string]struct {
+		policy services.RateLimitPolicy
+		// wait is slept before the final request.
+		wait time.Duration
+		hits int
+		want []bool
+	}{
+		"within the limit": {
+			policy: services.RateLimitPolicy{Scope: "test", Limit: 3, Window: time.Minute, BanDuration: time.Minute},
+			hits:   3,
+			want:   []bool{true, true, true},
+		},
+		"over the limit is banned": {
+			policy: services.RateLimitPolicy{Scope: "test", Limit: 2, Window: time.Minute, BanDuration: time.Minute},
+			hits:   4,
+			want:   []bool{true, true, false, false},
+		},
+		"hits outside the window are not counted": {
+			policy: services.RateLimitPolicy{Scope: "test", Limit: 2, Window: 200 * time.Millisecond, BanDuration: time.Minute},
+			wait:   300 * time.Millisecond,
+			hits:   3,
+			want:   []bool{true, true, true},
+		},
+		"ban expires": {
+			policy: services.RateLimitPolicy{Scope: "test", Limit: 1, Window: time.Minute, BanDuration: 200 * time.Millisecond},
+			wait:   300 * time.Millisecond,
+			hits:   3,
+			want:   []bool{true, false, true},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			key := uuid.NewString()
+
+			for i := range tt.hits {
+				if i == tt.hits-1 {
+					time.Sleep(tt.wait)
+				}
+
+				allowed, err := services.CheckRateLimit(ctx, tdb.DB, tt.policy, key)
+				if err != nil {
+					t.Fatalf("check rate limit: %v", err)
+				}
+				if allowed != tt.want[i] {
+					t.Errorf("hit %d: expected allowed %v, got %v", i+1, tt.want[i], allowed)
+				}
+			}
+		})
+	}
+}
+
+func TestAuthenticateUserWithLockout(t *testing.T) {
+	tdb := database.RequireTestDB(t)
+	ctx := context.Background()
+
+	tests := map[string]struct {
+		policy services.LockoutPolicy
+		// wait is slept before the final attempt.
+		wait time.Duration
+		want []error
+	}{
+		"below max failures": {
+			policy: services.LockoutPolicy{MaxFailures: 3, Window: time.Minute, Duration: time.Minute},
+			want:   []error{services.ErrInvalidCredentials, services.ErrInvalidCredentials},
+		},
+		"locked at max failures": {
+			policy: services.LockoutPolicy{MaxFailures: 2, Window: time.Minute, Duration: time.Minute},
+			want: []error{
+				services.ErrInvalidCredentials,
+				services.ErrInvalidCredentials,
+				services.ErrAccountLocked,
+			},
+		},
+		"lockout expires": {
+			policy: services.LockoutPolicy{MaxFailures: 2, Window: time.Minute, Duration: 200 * time.Millisecond},
+			wait:   300 * time.Millisecond,
+			want: []error{
+				services.ErrInvalidCredentials,
+				services.ErrInvalidCredentials,
+				services.ErrInvalidCredentials,
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			data := services.LoginData{
+				Email:    uuid.NewString() + "@example.com",
+				Password: "wrong password",
+			}
+
+			for i, want := range tt.want {
+				if i == len(tt.want)-1 {
+					time.Sleep(tt.wait)
+				}
+
+				_, err := services.AuthenticateUserWithLockout(ctx, tdb.DB, "pepper", tt.policy, data)
+				if !errors.Is(err, want) {
+					t.Errorf("attempt %d: expected %v, got %v", i+1, want, err)
+				}
+			}
+		})
+	}
+}
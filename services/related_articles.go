@@ -0,0 +1,208 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/jackc/pgx/v5"
+
+	"mortenvistisen/models"
+	"mortenvistisen/queue"
+	"mortenvistisen/queue/jobs"
+)
+
+const (
+	// RelatedArticlesPerArticle is how many recommendations are stored for
+	// each article.
+	RelatedArticlesPerArticle = 10
+
+	relatedTagWeight  = 0.4
+	relatedTextWeight = 0.6
+	// minRelatedScore keeps articles that only share a common word or two
+	// from being recommended.
+	minRelatedScore = 0.05
+
+	// Title and excerpt terms count more than terms in the body.
+	relatedTitleWeight   = 3
+	relatedExcerptWeight = 2
+)
+
+// relatedStopWords are frequent English words that say nothing about what an
+// article is about. Rarer filler is handled by the IDF weighting.
+var relatedStopWords = map[string]bool{
+	"about": true, "after": true, "also": true, "and": true, "are": true,
+	"because": true, "but": true, "can": true, "could": true, "did": true,
+	"does": true, "for": true, "from": true, "had": true, "has": true,
+	"have": true, "how": true, "into": true, "its": true, "just": true,
+	"more": true, "not": true, "now": true, "one": true, "only": true,
+	"our": true, "out": true, "should": true, "some": true, "than": true,
+	"that": true, "the": true, "their": true, "them": true, "then": true,
+	"there": true, "these": true, "they": true, "this": true, "those": true,
+	"was": true, "were": true, "what": true, "when": true, "where": true,
+	"which": true, "while": true, "who": true, "why": true, "will": true,
+	"with": true, "would": true, "you": true, "your": true,
+}
+
+// RelatedDocument is the part of an article that relatedness is computed
+// from.
+type RelatedDocument struct {
+	ID      int32
+	Title   string
+	Excerpt string
+	Content string
+	TagIDs  []int32
+}
+
+// ScoreRelatedArticles compares every pair of documents and returns each
+// document's best matches, at most perArticle of them. A score blends the
+// overlap of the tags (Jaccard) with the TF-IDF cosine similarity of title,
+// excerpt and content, and is symmetric.
+func ScoreRelatedArticles(docs []RelatedDocument, perArticle int) []models.RelatedArticle {
+	vectors := relatedTermVectors(docs)
+
+	candidates := make([][]models.RelatedArticle, len(docs))
+	for i := range docs {
+		for j := i + 1; j < len(docs); j++ {
+			score := relatedTagWeight*tagJaccard(docs[i].TagIDs, docs[j].TagIDs) +
+				relatedTextWeight*cosine(vectors[i], vectors[j])
+			if score < minRelatedScore {
+				continue
+			}
+
+			candidates[i] = append(candidates[i], models.RelatedArticle{
+				ArticleID:        docs[i].ID,
+				RelatedArticleID: docs[j].ID,
+				Score:            score,
+			})
+			candidates[j] = append(candidates[j], models.RelatedArticle{
+				ArticleID:        docs[j].ID,
+				RelatedArticleID: docs[i].ID,
+				Score:            score,
+			})
+		}
+	}
+
+	var relations []models.RelatedArticle
+	for _, related := range candidates {
+		slices.SortFunc(related, func(a, b models.RelatedArticle) int {
+			if c := cmp.Compare(b.Score, a.Score); c != 0 {
+				return c
+			}
+			return cmp.Compare(a.RelatedArticleID, b.RelatedArticleID)
+		})
+		relations = append(relations, related[:min(len(related), perArticle)]...)
+	}
+
+	return relations
+}
+
+// EnqueueRelatedArticlesRefresh queues a recomputation of every article's
+// recommendations. Any published change can move scores across the whole
+// site, since the IDF weights depend on every article.
+func EnqueueRelatedArticlesRefresh(
+	ctx context.Context,
+	tx pgx.Tx,
+	insertOnly queue.InsertOnly,
+) error {
+	if _, err := insertOnly.InsertTx(ctx, tx, jobs.RefreshRelatedArticlesArgs{}, nil); err != nil {
+		return fmt.Errorf("enqueue related articles refresh: %w", err)
+	}
+
+	return nil
+}
+
+// relatedTermVectors builds a unit length TF-IDF vector per document. Terms
+// found in every document carry no weight and are left out.
+func relatedTermVectors(docs []RelatedDocument) []map[string]float64 {
+	counts := make([]map[string]float64, len(docs))
+	documentFrequency := make(map[string]int)
+
+	for i, doc := range docs {
+		counts[i] = make(map[string]float64)
+		addTerms(counts[i], doc.Title, relatedTitleWeight)
+		addTerms(counts[i], doc.Excerpt, relatedExcerptWeight)
+		addTerms(counts[i], doc.Content, 1)
+
+		for term := range counts[i] {
+			documentFrequency[term]++
+		}
+	}
+
+	vectors := make([]map[string]float64, len(docs))
+	for i, termCounts := range counts {
+		vector := make(map[string]float64, len(termCounts))
+		var norm float64
+		for term, count := range termCounts {
+			idf := math.Log(float64(len(docs)) / float64(documentFrequency[term]))
+			if idf == 0 {
+				continue
+			}
+			weight := (1 + math.Log(count)) * idf
+			vector[term] = weight
+			norm += weight * weight
+		}
+
+		norm = math.Sqrt(norm)
+		for term := range vector {
+			vector[term] /= norm
+		}
+		vectors[i] = vector
+	}
+
+	return vectors
+}
+
+func addTerms(counts map[string]float64, text string, weight float64) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for _, word := range words {
+		if len([]rune(word)) < 3 || relatedStopWords[word] || isNumber(word) {
+			continue
+		}
+		counts[word] += weight
+	}
+}
+
+func isNumber(word string) bool {
+	return strings.IndexFunc(word, func(r rune) bool { return !unicode.IsDigit(r) }) < 0
+}
+
+func cosine(a, b map[string]float64) float64 {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+
+	var dot float64
+	for term, weight := range a {
+		dot += weight * b[term]
+	}
+
+	return dot
+}
+
+func tagJaccard(a, b []int32) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	union := make(map[int32]bool, len(a)+len(b))
+	for _, id := range a {
+		union[id] = true
+	}
+	shared := 0
+	for _, id := range slices.Compact(slices.Sorted(slices.Values(b))) {
+		if union[id] {
+			shared++
+		}
+		union[id] = true
+	}
+
+	return float64(shared) / float64(len(union))
+}
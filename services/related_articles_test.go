@@ -0,0 +1,83 @@
+package services_test
+
+import (
+	"testing"
+
+	"mortenvistisen/models"
+	"mortenvistisen/services"
+)
+
+func TestScoreRelatedArticles(t *testing.T) {
+	docs := []services.RelatedDocument{
+		{
+			ID:      1,
+			Title:   "Deploying Go services with Docker",
+			Excerpt: "Small container images for Go binaries",
+			Content: "Multi-stage Docker builds keep Go images small.",
+			TagIDs:  []int32{1, 2},
+		},
+		{
+			ID:      2,
+			Title:   "Go containers in production",
+			Excerpt: "Running Docker images built from Go",
+			Content: "Distroless images and Docker health checks for Go services.",
+			TagIDs:  []int32{1, 2},
+		},
+		{
+			ID:      3,
+			Title:   "Sourdough starter basics",
+			Excerpt: "Feeding schedules for bread",
+			Content: "Flour, water and patience make a starter.",
+			TagIDs:  []int32{3},
+		},
+		{
+			ID:      4,
+			Title:   "Postgres indexes explained",
+			Excerpt: "When a btree helps",
+			Content: "Indexes speed up lookups on large tables.",
+			TagIDs:  []int32{1},
+		},
+	}
+
+	relations := services.ScoreRelatedArticles(docs, 10)
+
+	related := make(map[int32][]models.RelatedArticle)
+	for _, relation := range relations {
+		related[relation.ArticleID] = append(related[relation.ArticleID], relation)
+	}
+
+	if len(related[1]) == 0 || related[1][0].RelatedArticleID != 2 {
+		t.Fatalf("expected article 2 to be the best match for article 1, got %+v", related[1])
+	}
+	if len(related[2]) == 0 || related[2][0].RelatedArticleID != 1 {
+		t.Fatalf("expected article 1 to be the best match for article 2, got %+v", related[2])
+	}
+	if related[1][0].Score != related[2][0].Score {
+		t.Errorf("expected symmetric scores, got %v and %v", related[1][0].Score, related[2][0].Score)
+	}
+	if len(related[3]) != 0 {
+		t.Errorf("expected no matches for an unrelated article, got %+v", related[3])
+	}
+
+	for id, matches := range related {
+		for i := 1; i < len(matches); i++ {
+			if matches[i].Score > matches[i-1].Score {
+				t.Errorf("matches for article %d are not ordered by score: %+v", id, matches)
+			}
+		}
+		for _, match := range matches {
+			if match.RelatedArticleID == id {
+				t.Errorf("article %d is related to itself", id)
+			}
+		}
+	}
+
+	limited := services.ScoreRelatedArticles(docs, 1)
+	perArticle := make(map[int32]int)
+	for _, relation := range limited {
+		perArticle[relation.ArticleID]++
+		if perArticle[relation.ArticleID] > 1 {
+			t.Fatalf("expected at most one match per article, got %+v", limited)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"mortenvistisen/internal/storage"
+	"mortenvistisen/models"
+)
+
+// markdownRendererVersion is part of every content hash. Bump it when a
+// change to the markdown pipeline changes its output; stored content is
+// then rendered again on its next view or by database/rerender.
+const markdownRendererVersion = "1"
+
+// contentHash identifies content as rendered by the current renderer with
+// the given options.
+func contentHash(content string, anchors bool) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		markdownRendererVersion,
+		strconv.FormatBool(anchors),
+		content,
+	}, "\x00")))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func renderContent(
+	ctx context.Context,
+	exec storage.Executor,
+	content string,
+	anchors bool,
+) (models.RenderedContent, error) {
+	embeds, err := LoadMarkdownEmbeds(ctx, exec, content)
+	if err != nil {
+		return models.RenderedContent{}, err
+	}
+
+	rendered := RenderMarkdown(content, MarkdownOptions{HeadingAnchors: anchors, Embeds: embeds})
+
+	return models.RenderedContent{
+		HTML:     rendered.HTML,
+		Hash:     contentHash(content, anchors),
+		Headings: rendered.Headings,
+	}, nil
+}
+
+// RenderArticleContent renders an article's content and stores it. Heading
+// permalinks follow the article's table of contents setting.
+func RenderArticleContent(
+	ctx context.Context,
+	exec storage.Executor,
+	article models.Article,
+) (models.Article, error) {
+	rendered, err := renderContent(ctx, exec, article.Content, article.TableOfContents)
+	if err != nil {
+		return models.Article{}, err
+	}
+
+	return models.SetArticleRenderedContent(ctx, exec, article.ID, rendered)
+}
+
+// EnsureArticleContent renders an article's content unless what is stored
+// was rendered from the same content by the current renderer.
+func EnsureArticleContent(
+	ctx context.Context,
+	exec storage.Executor,
+	article models.Article,
+) (models.Article, error) {
+	if article.Rendered.Hash == contentHash(article.Content, article.TableOfContents) {
+		return article, nil
+	}
+
+	return RenderArticleContent(ctx, exec, article)
+}
+
+func RenderProjectContent(
+	ctx context.Context,
+	exec storage.Executor,
+	project models.Project,
+) (models.Project, error) {
+	rendered, err := renderContent(ctx, exec, project.Content, false)
+	if err != nil {
+		return models.Project{}, err
+	}
+	rendered.Headings = nil
+
+	return models.SetProjectRenderedContent(ctx, exec, project.ID, rendered)
+}
+
+func EnsureProjectContent(
+	ctx context.Context,
+	exec storage.Executor,
+	project models.Project,
+) (models.Project, error) {
+	if project.Rendered.Hash == contentHash(project.Content, false) {
+		return project, nil
+	}
+
+	return RenderProjectContent(ctx, exec, project)
+}
+
+func RenderNewsletterContent(
+	ctx context.Context,
+	exec storage.Executor,
+	newsletter models.Newsletter,
+) (models.Newsletter, error) {
+	rendered, err := renderContent(ctx, exec, newsletter.Content, false)
+	if err != nil {
+		return models.Newsletter{}, err
+	}
+	rendered.Headings = nil
+
+	return models.SetNewsletterRenderedContent(ctx, exec, newsletter.ID, rendered)
+}
+
+func EnsureNewsletterContent(
+	ctx context.Context,
+	exec storage.Executor,
+	newsletter models.Newsletter,
+) (models.Newsletter, error) {
+	if newsletter.Rendered.Hash == contentHash(newsletter.Content, false) {
+		return newsletter, nil
+	}
+
+	return RenderNewsletterContent(ctx, exec, newsletter)
+}
+
+// RenderedHTML is the stored HTML of content, or the content rendered now
+// if it has never been stored, as for rows older than the stored HTML.
+func RenderedHTML(rendered models.RenderedContent, content string) string {
+	if rendered.Hash == "" {
+		return MarkdownToHTML(content)
+	}
+
+	return rendered.HTML
+}
+
+// RerenderContentResult counts the articles, projects and newsletters that
+// RerenderContent rendered.
+type RerenderContentResult struct {
+	Articles    int
+	Projects    int
+	Newsletters int
+}
+
+// RerenderContent renders the stale content of every article, project and
+// newsletter, or all of it with force. Force is for changes the hash
+// cannot see, such as the new title of an embedded project.
+func RerenderContent(
+	ctx context.Context,
+	exec storage.Executor,
+	force bool,
+) (RerenderContentResult, error) {
+	var result RerenderContentResult
+
+	articles, err := models.AllArticles(ctx, exec)
+	if err != nil {
+		return result, err
+	}
+	for _, article := range articles {
+		if !force && article.Rendered.Hash == contentHash(article.Content, article.TableOfContents) {
+			continue
+		}
+		if _, err := RenderArticleContent(ctx, exec, article); err != nil {
+			return result, err
+		}
+		result.Articles++
+	}
+
+	projects, err := models.AllProjects(ctx, exec)
+	if err != nil {
+		return result, err
+	}
+	for _, project := range projects {
+		if !force && project.Rendered.Hash == contentHash(project.Content, false) {
+			continue
+		}
+		if _, err := RenderProjectContent(ctx, exec, project); err != nil {
+			return result, err
+		}
+		result.Projects++
+	}
+
+	newsletters, err := models.AllNewsletters(ctx, exec)
+	if err != nil {
+		return result, err
+	}
+	for _, newsletter := range newsletters {
+		if !force && newsletter.Rendered.Hash == contentHash(newsletter.Content, false) {
+			continue
+		}
+		if _, err := RenderNewsletterContent(ctx, exec, newsletter); err != nil {
+			return result, err
+		}
+		result.Newsletters++
+	}
+
+	return result, nil
+}
@@ -0,0 +1,288 @@
+package services
+
+import (
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// untrustedLinkRel is set on every link in sanitized HTML, replacing any
+// rel the input had.
+const untrustedLinkRel = "nofollow ugc noopener"
+
+// sanitizeAllowedAttributes lists the elements SanitizeHTML keeps and the
+// attributes each of them may carry. Elements that are not listed are
+// unwrapped: their tags are dropped and their children are kept.
+var sanitizeAllowedAttributes = map[atom.Atom][]string{
+	atom.A:          {"href", "title"},
+	atom.Blockquote: nil,
+	atom.Br:         nil,
+	atom.Code:       {"class"},
+	atom.Del:        nil,
+	atom.Em:         nil,
+	atom.H1:         nil,
+	atom.H2:         nil,
+	atom.H3:         nil,
+	atom.H4:         nil,
+	atom.H5:         nil,
+	atom.H6:         nil,
+	atom.Hr:         nil,
+	atom.Img:        {"src", "alt", "title"},
+	atom.Input:      {"type", "checked", "disabled"},
+	atom.Li:         nil,
+	atom.Ol:         {"start"},
+	atom.P:          nil,
+	atom.Pre:        nil,
+	atom.S:          nil,
+	atom.Strong:     nil,
+	atom.Table:      nil,
+	atom.Tbody:      nil,
+	atom.Td:         {"align"},
+	atom.Th:         {"align"},
+	atom.Thead:      nil,
+	atom.Tr:         nil,
+	atom.Ul:         nil,
+}
+
+// sanitizeDroppedElements are removed together with everything inside
+// them, as their content is code, styling or otherwise not meant as text.
+var sanitizeDroppedElements = map[atom.Atom]struct{}{
+	atom.Embed:     {},
+	atom.Frame:     {},
+	atom.Frameset:  {},
+	atom.Head:      {},
+	atom.Iframe:    {},
+	atom.Math:      {},
+	atom.Noembed:   {},
+	atom.Noframes:  {},
+	atom.Noscript:  {},
+	atom.Object:    {},
+	atom.Plaintext: {},
+	atom.Script:    {},
+	atom.Select:    {},
+	atom.Style:     {},
+	atom.Svg:       {},
+	atom.Template:  {},
+	atom.Textarea:  {},
+	atom.Title:     {},
+	atom.Xmp:       {},
+}
+
+// sanitizeURLSchemes are the schemes links may use. Images are limited to
+// http and https.
+var sanitizeURLSchemes = map[string]struct{}{
+	"http":   {},
+	"https":  {},
+	"mailto": {},
+}
+
+var (
+	sanitizeCodeClass  = regexp.MustCompile(`^language-[A-Za-z0-9_+#.-]+$`)
+	sanitizeAlignValue = regexp.MustCompile(`^(left|center|right)$`)
+	sanitizeOlStart    = regexp.MustCompile(`^[0-9]{1,9}$`)
+)
+
+// SanitizeHTML keeps the elements and attributes of an allowlist and drops
+// everything else, so the result is safe to show on the site whatever the
+// input was. Links may only point to http, https and mailto URLs or be
+// relative, images may only load http and https URLs, and every link gets
+// rel="nofollow ugc noopener". Comments, styles, event handlers, scripts
+// and SVG or MathML content never make it through.
+func SanitizeHTML(input string) string {
+	nodes, err := html.ParseFragment(strings.NewReader(input), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "div",
+		DataAtom: atom.Div,
+	})
+	if err != nil {
+		return html.EscapeString(input)
+	}
+
+	var out strings.Builder
+	for _, node := range nodes {
+		sanitizeNode(&out, node)
+	}
+
+	return out.String()
+}
+
+func sanitizeNode(out *strings.Builder, node *html.Node) {
+	switch node.Type {
+	case html.TextNode:
+		out.WriteString(html.EscapeString(node.Data))
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	// Foreign content is parsed by other rules than HTML, so none of it is
+	// kept, not even its text.
+	if node.Namespace != "" {
+		return
+	}
+	if _, dropped := sanitizeDroppedElements[node.DataAtom]; dropped {
+		return
+	}
+
+	allowed, ok := sanitizeAllowedAttributes[node.DataAtom]
+	if !ok {
+		sanitizeChildren(out, node)
+		return
+	}
+
+	attrs, ok := sanitizeAttributes(node, allowed)
+	if !ok {
+		if node.DataAtom == atom.A {
+			sanitizeChildren(out, node)
+		}
+		return
+	}
+
+	out.WriteString("<")
+	out.WriteString(node.Data)
+	for _, attr := range attrs {
+		out.WriteString(" ")
+		out.WriteString(attr.Key)
+		out.WriteString(`="`)
+		out.WriteString(html.EscapeString(attr.Val))
+		out.WriteString(`"`)
+	}
+
+	if sanitizeVoidElement(node.DataAtom) {
+		out.WriteString(" />")
+		return
+	}
+
+	out.WriteString(">")
+	sanitizeChildren(out, node)
+	out.WriteString("</")
+	out.WriteString(node.Data)
+	out.WriteString(">")
+}
+
+func sanitizeChildren(out *strings.Builder, node *html.Node) {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		sanitizeNode(out, child)
+	}
+}
+
+// sanitizeAttributes returns the allowed attributes of node. It reports
+// false when the element is not kept at all: links without a safe href,
+// images without a safe src and inputs other than checkboxes. Checkboxes
+// are always disabled.
+func sanitizeAttributes(node *html.Node, allowed []string) ([]html.Attribute, bool) {
+	var attrs []html.Attribute
+	seen := map[string]struct{}{}
+
+	for _, attr := range node.Attr {
+		if attr.Namespace != "" {
+			continue
+		}
+		key := strings.ToLower(attr.Key)
+		if _, dup := seen[key]; dup {
+			continue
+		}
+		if !slices.Contains(allowed, key) {
+			continue
+		}
+
+		val, ok := sanitizeAttributeValue(node.DataAtom, key, attr.Val)
+		if !ok {
+			continue
+		}
+
+		seen[key] = struct{}{}
+		attrs = append(attrs, html.Attribute{Key: key, Val: val})
+	}
+
+	switch node.DataAtom {
+	case atom.A:
+		if _, ok := seen["href"]; !ok {
+			return nil, false
+		}
+		attrs = append(attrs, html.Attribute{Key: "rel", Val: untrustedLinkRel})
+	case atom.Img:
+		if _, ok := seen["src"]; !ok {
+			return nil, false
+		}
+	case atom.Input:
+		if _, ok := seen["type"]; !ok {
+			return nil, false
+		}
+		if _, ok := seen["disabled"]; !ok {
+			attrs = append(attrs, html.Attribute{Key: "disabled"})
+		}
+	}
+
+	return attrs, true
+}
+
+func sanitizeAttributeValue(element atom.Atom, key, val string) (string, bool) {
+	switch key {
+	case "href":
+		return sanitizeURL(val, sanitizeURLSchemes)
+	case "src":
+		return sanitizeURL(val, map[string]struct{}{"http": {}, "https": {}})
+	case "class":
+		return val, sanitizeCodeClass.MatchString(val)
+	case "align":
+		return val, sanitizeAlignValue.MatchString(val)
+	case "start":
+		return val, sanitizeOlStart.MatchString(val)
+	case "type":
+		return val, element == atom.Input && val == "checkbox"
+	case "checked", "disabled":
+		return "", true
+	default:
+		return val, true
+	}
+}
+
+// sanitizeURL accepts relative URLs and absolute URLs with one of schemes.
+// Anything that does not parse cleanly is rejected rather than guessed at,
+// as browsers are more lenient than url.Parse.
+func sanitizeURL(raw string, schemes map[string]struct{}) (string, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", false
+	}
+	for _, r := range raw {
+		if r < ' ' || r == 0x7f || r == '\\' {
+			return "", false
+		}
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", false
+	}
+
+	if u.Scheme == "" {
+		// A colon before the first slash would be read as a scheme by a
+		// browser even where url.Parse does not.
+		if i := strings.IndexAny(raw, ":/?#"); i >= 0 && raw[i] == ':' {
+			return "", false
+		}
+		return raw, true
+	}
+
+	if _, ok := schemes[strings.ToLower(u.Scheme)]; !ok {
+		return "", false
+	}
+
+	return raw, true
+}
+
+func sanitizeVoidElement(element atom.Atom) bool {
+	switch element {
+	case atom.Br, atom.Hr, atom.Img, atom.Input:
+		return true
+	default:
+		return false
+	}
+}
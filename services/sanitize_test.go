@@ -0,0 +1,273 @@
+package services_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"mortenvistisen/services"
+)
+
+// sanitizeSeeds are known cross-site scripting payloads, used both as
+// plain cases and to seed the fuzzers.
+var sanitizeSeeds = []string{
+	"",
+	"plain text",
+	"<script>alert(1)</script>",
+	"<SCRIPT SRC=//evil.example/x.js></SCRIPT>",
+	`<img src=x onerror=alert(1)>`,
+	`<img src="javascript:alert(1)">`,
+	`<a href="javascript:alert(1)">x</a>`,
+	`<a href="JaVaScRiPt:alert(1)">x</a>`,
+	`<a href="java&#x09;script:alert(1)">x</a>`,
+	`<a href="&#106;avascript:alert(1)">x</a>`,
+	`<a href=" javascript:alert(1)">x</a>`,
+	`<a href="data:text/html;base64,PHNjcmlwdD5hbGVydCgxKTwvc2NyaXB0Pg==">x</a>`,
+	`<a href="vbscript:msgbox(1)">x</a>`,
+	`<a href="https://example.com" rel="opener" target="_blank" onclick="alert(1)">x</a>`,
+	`<p style="background:url(javascript:alert(1))">x</p>`,
+	`<svg><script>alert(1)</script></svg>`,
+	`<svg onload=alert(1)>`,
+	`<math><mtext><table><mglyph><style><img src=x onerror=alert(1)>`,
+	`<noscript><p title="</noscript><img src=x onerror=alert(1)>">`,
+	`<iframe src="https://evil.example"></iframe>`,
+	`<form action="https://evil.example"><input type="password"></form>`,
+	`<!-- <script>alert(1)</script> -->`,
+	`<style>@import "https://evil.example/x.css";</style>`,
+	`<template><script>alert(1)</script></template>`,
+	`<textarea><script>alert(1)</script></textarea>`,
+	`<object data="x.swf"></object><embed src="x.swf">`,
+	`<base href="https://evil.example/">`,
+	`<meta http-equiv="refresh" content="0;url=https://evil.example">`,
+	`<div><p>unclosed <b>bold`,
+	"[x](javascript:alert(1))",
+	"[x](<javascript:alert(1)>)",
+	"![x](javascript:alert(1))",
+	"<javascript:alert(1)>",
+	"[x]: javascript:alert(1)\n\n[link][x]",
+	"<https://example.com/?q=\"><script>alert(1)</script>>",
+	"```html\n<script>alert(1)</script>\n```",
+	"- [x] done\n- [ ] todo",
+	"| a | b |\n|:--|--:|\n| <script>x</script> | 2 |",
+	"www.example.com and mail@example.com",
+}
+
+// assertSanitized fails t when out has markup SanitizeHTML must never
+// produce.
+func assertSanitized(t *testing.T, input, out string) {
+	t.Helper()
+
+	nodes, err := html.ParseFragment(strings.NewReader(out), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "div",
+		DataAtom: atom.Div,
+	})
+	if err != nil {
+		t.Fatalf("sanitized output does not parse: %v", err)
+	}
+
+	var check func(node *html.Node)
+	check = func(node *html.Node) {
+		switch node.Type {
+		case html.CommentNode, html.DoctypeNode:
+			t.Errorf("input %q: unexpected %v node in %q", input, node.Type, out)
+		case html.ElementNode:
+			switch node.DataAtom {
+			case atom.Script, atom.Style, atom.Iframe, atom.Object, atom.Embed,
+				atom.Svg, atom.Math, atom.Form, atom.Base, atom.Meta, atom.Link,
+				atom.Textarea, atom.Template, atom.Noscript, atom.Frame:
+				t.Errorf("input %q: unexpected <%s> in %q", input, node.Data, out)
+			}
+			if node.Namespace != "" {
+				t.Errorf("input %q: unexpected foreign element %s in %q", input, node.Data, out)
+			}
+
+			for _, attr := range node.Attr {
+				key := strings.ToLower(attr.Key)
+				switch {
+				case strings.HasPrefix(key, "on"), key == "style", key == "target",
+					key == "action", key == "formaction", key == "srcset":
+					t.Errorf("input %q: unexpected %s attribute in %q", input, key, out)
+				case key == "href" || key == "src":
+					assertSafeURL(t, input, out, attr.Val)
+				}
+			}
+
+			if node.DataAtom == atom.A && attrValue(node, "rel") != "nofollow ugc noopener" {
+				t.Errorf("input %q: link without nofollow rel in %q", input, out)
+			}
+		}
+
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			check(child)
+		}
+	}
+
+	for _, node := range nodes {
+		check(node)
+	}
+}
+
+func assertSafeURL(t *testing.T, input, out, raw string) {
+	t.Helper()
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Errorf("input %q: unparsable url %q in %q", input, raw, out)
+		return
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https", "mailto":
+	default:
+		t.Errorf("input %q: unsafe url %q in %q", input, raw, out)
+	}
+
+	if i := strings.IndexAny(raw, ":/?#"); u.Scheme == "" && i >= 0 && raw[i] == ':' {
+		t.Errorf("input %q: url %q looks like a scheme in %q", input, raw, out)
+	}
+}
+
+func attrValue(node *html.Node, key string) string {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+func TestSanitizeHTML(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		want  string
+	}{
+		"keeps allowed markup": {
+			input: `<p>Hello <strong>there</strong> <em>you</em></p>`,
+			want:  `<p>Hello <strong>there</strong> <em>you</em></p>`,
+		},
+		"drops scripts with their content": {
+			input: `<p>a</p><script>alert(1)</script><p>b</p>`,
+			want:  `<p>a</p><p>b</p>`,
+		},
+		"unwraps unknown elements": {
+			input: `<div><span class="x">text</span></div>`,
+			want:  `text`,
+		},
+		"forces link rel": {
+			input: `<a href="https://example.com" rel="opener" target="_blank">x</a>`,
+			want:  `<a href="https://example.com" rel="nofollow ugc noopener">x</a>`,
+		},
+		"unwraps links with unsafe schemes": {
+			input: `<a href="javascript:alert(1)">x</a>`,
+			want:  `x`,
+		},
+		"keeps relative and mailto links": {
+			input: `<a href="/articles/x">a</a><a href="mailto:me@example.com">b</a>`,
+			want:  `<a href="/articles/x" rel="nofollow ugc noopener">a</a><a href="mailto:me@example.com" rel="nofollow ugc noopener">b</a>`,
+		},
+		"drops images with unsafe sources": {
+			input: `<img src="data:image/png;base64,AAAA" alt="x"><img src="https://example.com/a.png" onerror="alert(1)" alt="a">`,
+			want:  `<img src="https://example.com/a.png" alt="a" />`,
+		},
+		"drops event handlers and styles": {
+			input: `<p style="color:red" onclick="alert(1)">x</p>`,
+			want:  `<p>x</p>`,
+		},
+		"keeps code languages only": {
+			input: `<pre><code class="language-go">x</code></pre><code class="evil">y</code>`,
+			want:  `<pre><code class="language-go">x</code></pre><code>y</code>`,
+		},
+		"disables checkboxes": {
+			input: `<input type="checkbox" checked=""><input type="text" value="x">`,
+			want:  `<input type="checkbox" checked="" disabled="" />`,
+		},
+		"escapes text": {
+			input: `&lt;script&gt;`,
+			want:  `&lt;script&gt;`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := services.SanitizeHTML(tt.input); got != tt.want {
+				t.Errorf("SanitizeHTML(%q)\n got %q\nwant %q", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	for _, seed := range sanitizeSeeds {
+		assertSanitized(t, seed, services.SanitizeHTML(seed))
+	}
+}
+
+func TestUntrustedMarkdownToHTML(t *testing.T) {
+	html := services.UntrustedMarkdownToHTML(strings.Join([]string{
+		"## Heading",
+		"",
+		"<div onclick=\"alert(1)\">raw</div>",
+		"",
+		"[bad](javascript:alert(1)) [good](https://example.com) ![alt](https://example.com/a.png)",
+		"",
+		"- [x] done",
+	}, "\n"))
+
+	for _, unwanted := range []string{"<div", "onclick", "javascript:", "<!--"} {
+		if strings.Contains(html, unwanted) {
+			t.Errorf("expected %q to be removed, got %s", unwanted, html)
+		}
+	}
+
+	for _, wanted := range []string{
+		"<h2>Heading</h2>",
+		`<a href="https://example.com" rel="nofollow ugc noopener">good</a>`,
+		`<img src="https://example.com/a.png" alt="alt" />`,
+		`<input checked="" disabled="" type="checkbox" />`,
+	} {
+		if !strings.Contains(html, wanted) {
+			t.Errorf("expected %q in %s", wanted, html)
+		}
+	}
+
+	for _, seed := range sanitizeSeeds {
+		assertSanitized(t, seed, services.UntrustedMarkdownToHTML(seed))
+	}
+}
+
+func FuzzSanitizeHTML(f *testing.F) {
+	for _, seed := range sanitizeSeeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		out := services.SanitizeHTML(input)
+		assertSanitized(t, input, out)
+
+		if again := services.SanitizeHTML(out); strings.Count(again, "<") > strings.Count(out, "<") {
+			t.Errorf("sanitizing %q again added markup: %q to %q", input, out, again)
+		}
+	})
+}
+
+func FuzzUntrustedMarkdownToHTML(f *testing.F) {
+	for _, seed := range sanitizeSeeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		assertSanitized(t, input, services.UntrustedMarkdownToHTML(input))
+	})
+}
+
+func FuzzCommentMarkdownToHTML(f *testing.F) {
+	for _, seed := range sanitizeSeeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		assertSanitized(t, input, services.CommentMarkdownToHTML(input))
+	})
+}
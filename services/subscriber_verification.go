@@ -133,6 +133,7 @@ type VerifySubscriberData struct {
 func VerifySubscriber(
 	ctx context.Context,
 	db storage.Pool,
+	insertOnly queue.InsertOnly,
 	pepper string,
 	data VerifySubscriberData,
 ) (models.Subscriber, error) {
@@ -188,6 +189,21 @@ func VerifySubscriber(
 		if err != nil {
 			return models.Subscriber{}, err
 		}
+
+		if _, err := EnqueueWebhooks(
+			ctx,
+			tx,
+			insertOnly,
+			models.WebhookEventSubscriberVerified,
+			SubscriberWebhookData{
+				ID:           subscriber.ID,
+				Email:        subscriber.Email,
+				SubscribedAt: subscriber.SubscribedAt,
+				Referer:      subscriber.Referer,
+			},
+		); err != nil {
+			return models.Subscriber{}, err
+		}
 	}
 
 	if err := models.DestroyToken(ctx, tx, token.ID); err != nil {
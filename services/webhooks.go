@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/riverqueue/river"
+
+	"mortenvistisen/models"
+	"mortenvistisen/queue"
+	"mortenvistisen/queue/jobs"
+)
+
+// WebhookPayload is the JSON body POSTed to webhook endpoints. ID is shared
+// by every delivery of the same event so receivers can deduplicate retries.
+type WebhookPayload struct {
+	ID        uuid.UUID           `json:"id"`
+	Event     models.WebhookEvent `json:"event"`
+	CreatedAt time.Time           `json:"created_at"`
+	Data      any                 `json:"data"`
+}
+
+type ArticleWebhookData struct {
+	ID          int32     `json:"id"`
+	Title       string    `json:"title"`
+	Slug        string    `json:"slug"`
+	Excerpt     string    `json:"excerpt"`
+	URL         string    `json:"url"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+type NewsletterWebhookData struct {
+	ID         int32     `json:"id"`
+	Title      string    `json:"title"`
+	Slug       string    `json:"slug"`
+	URL        string    `json:"url"`
+	ReleasedAt time.Time `json:"released_at"`
+}
+
+type SubscriberWebhookData struct {
+	ID           int32     `json:"id"`
+	Email        string    `json:"email"`
+	SubscribedAt time.Time `json:"subscribed_at"`
+	Referer      string    `json:"referer"`
+}
+
+// EnqueueWebhooks queues one delivery per active endpoint subscribed to
+// event. It runs inside tx so deliveries only go out if the change that
+// triggered them is committed. It returns the number of deliveries queued.
+func EnqueueWebhooks(
+	ctx context.Context,
+	tx pgx.Tx,
+	insertOnly queue.InsertOnly,
+	event models.WebhookEvent,
+	data any,
+) (int, error) {
+	endpoints, err := models.ActiveWebhookEndpointsForEvent(ctx, tx, event)
+	if err != nil {
+		return 0, fmt.Errorf("find webhook endpoints: %w", err)
+	}
+	if len(endpoints) == 0 {
+		return 0, nil
+	}
+
+	payload := WebhookPayload{
+		ID:        uuid.New(),
+		Event:     event,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("marshal webhook payload: %w", err)
+	}
+
+	insertParams := make([]river.InsertManyParams, len(endpoints))
+	for i, endpoint := range endpoints {
+		insertParams[i] = river.InsertManyParams{
+			Args: jobs.DeliverWebhookArgs{
+				EndpointID: endpoint.ID,
+				EventID:    payload.ID,
+				Event:      string(event),
+				Payload:    body,
+			},
+		}
+	}
+
+	if _, err := insertOnly.InsertManyTx(ctx, tx, insertParams); err != nil {
+		return 0, fmt.Errorf("enqueue webhooks: %w", err)
+	}
+
+	return len(insertParams), nil
+}
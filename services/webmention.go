@@ -0,0 +1,399 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/riverqueue/river"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"mortenvistisen/queue"
+	"mortenvistisen/queue/jobs"
+)
+
+const (
+	maxWebmentionDocumentSize = 1 << 20
+	maxWebmentionExcerpt      = 280
+)
+
+var (
+	ErrNoWebmentionEndpoint        = errors.New("target does not advertise a webmention endpoint")
+	ErrWebmentionSourceGone        = errors.New("webmention source no longer exists")
+	ErrWebmentionSourceMissingLink = errors.New("webmention source does not link to target")
+)
+
+var (
+	linkHeaderPattern = regexp.MustCompile(`<([^>]*)>([^<]*)`)
+	linkRelPattern    = regexp.MustCompile(`(?i)\brel\s*=\s*(?:"([^"]*)"|([^\s;,]+))`)
+)
+
+// WebmentionSource is what could be learned about the page that sent a
+// mention.
+type WebmentionSource struct {
+	Title      string
+	AuthorName string
+	AuthorURL  string
+	Excerpt    string
+}
+
+// DiscoverWebmentionEndpoint finds the endpoint target advertises, first in
+// its Link headers and then in the first <link> or <a> with
+// rel="webmention". Relative endpoints are resolved against the final URL
+// after redirects.
+func DiscoverWebmentionEndpoint(
+	ctx context.Context,
+	client *http.Client,
+	target string,
+) (string, error) {
+	res, err := getWebmentionDocument(ctx, client, target)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return "", fmt.Errorf("target responded with status %d", res.StatusCode)
+	}
+
+	base := res.Request.URL
+
+	for _, header := range res.Header.Values("Link") {
+		for _, match := range linkHeaderPattern.FindAllStringSubmatch(header, -1) {
+			if hasWebmentionRel(linkHeaderRel(match[2])) {
+				return resolveWebmentionURL(base, match[1])
+			}
+		}
+	}
+
+	if !isHTMLContentType(res.Header.Get("Content-Type")) {
+		return "", ErrNoWebmentionEndpoint
+	}
+
+	doc, err := html.Parse(io.LimitReader(res.Body, maxWebmentionDocumentSize))
+	if err != nil {
+		return "", err
+	}
+
+	for n := range doc.Descendants() {
+		if n.Type != html.ElementNode || (n.DataAtom != atom.Link && n.DataAtom != atom.A) {
+			continue
+		}
+		href, ok := htmlAttr(n, "href")
+		if ok && hasWebmentionRel(attrOrEmpty(n, "rel")) {
+			return resolveWebmentionURL(base, href)
+		}
+	}
+
+	return "", ErrNoWebmentionEndpoint
+}
+
+// SendWebmention notifies endpoint that source links to target.
+func SendWebmention(
+	ctx context.Context,
+	client *http.Client,
+	endpoint string,
+	source string,
+	target string,
+) error {
+	form := url.Values{"source": {source}, "target": {target}}
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		endpoint,
+		strings.NewReader(form.Encode()),
+	)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(res.Body, maxWebmentionDocumentSize))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("webmention endpoint responded with status %d", res.StatusCode)
+	}
+
+	return nil
+}
+
+// FetchWebmentionSource fetches source and checks that it links to target.
+// It returns ErrWebmentionSourceGone when the source has been removed and
+// ErrWebmentionSourceMissingLink when it no longer mentions target; both mean
+// the mention should be dropped. Other errors are worth retrying.
+func FetchWebmentionSource(
+	ctx context.Context,
+	client *http.Client,
+	source string,
+	target string,
+) (WebmentionSource, error) {
+	res, err := getWebmentionDocument(ctx, client, source)
+	if err != nil {
+		return WebmentionSource{}, err
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusGone || res.StatusCode == http.StatusNotFound:
+		return WebmentionSource{}, ErrWebmentionSourceGone
+	case res.StatusCode < 200 || res.StatusCode > 299:
+		return WebmentionSource{}, fmt.Errorf("source responded with status %d", res.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxWebmentionDocumentSize))
+	if err != nil {
+		return WebmentionSource{}, err
+	}
+
+	if !isHTMLContentType(res.Header.Get("Content-Type")) {
+		if !strings.Contains(string(body), target) {
+			return WebmentionSource{}, ErrWebmentionSourceMissingLink
+		}
+		return WebmentionSource{}, nil
+	}
+
+	doc, err := html.Parse(strings.NewReader(string(body)))
+	if err != nil {
+		return WebmentionSource{}, err
+	}
+
+	base := res.Request.URL
+	want := normalizeWebmentionURL(target)
+
+	var found WebmentionSource
+	linked := false
+	for n := range doc.Descendants() {
+		if n.Type != html.ElementNode {
+			continue
+		}
+
+		for _, key := range []string{"href", "src"} {
+			if value, ok := htmlAttr(n, key); ok && !linked {
+				if resolved, err := base.Parse(strings.TrimSpace(value)); err == nil &&
+					normalizeWebmentionURL(resolved.String()) == want {
+					linked = true
+				}
+			}
+		}
+
+		switch {
+		case n.DataAtom == atom.Title && found.Title == "":
+			found.Title = nodeText(n)
+		case n.DataAtom == atom.Meta:
+			name := strings.ToLower(attrOrEmpty(n, "name") + attrOrEmpty(n, "property"))
+			content := attrOrEmpty(n, "content")
+			switch name {
+			case "author":
+				if found.AuthorName == "" {
+					found.AuthorName = content
+				}
+			case "description", "og:description":
+				if found.Excerpt == "" {
+					found.Excerpt = content
+				}
+			}
+		case hasClass(n, "p-author") && found.AuthorName == "":
+			found.AuthorName = nodeText(n)
+		case (n.DataAtom == atom.Link || n.DataAtom == atom.A) &&
+			slices.Contains(strings.Fields(attrOrEmpty(n, "rel")), "author") &&
+			found.AuthorURL == "":
+			if resolved, err := base.Parse(attrOrEmpty(n, "href")); err == nil {
+				found.AuthorURL = resolved.String()
+			}
+		}
+	}
+
+	if !linked {
+		return WebmentionSource{}, ErrWebmentionSourceMissingLink
+	}
+
+	found.Excerpt = truncateRunes(found.Excerpt, maxWebmentionExcerpt)
+
+	return found, nil
+}
+
+// ExternalLinks returns the absolute http(s) links in htmlContent that point
+// away from source's host, in document order and without duplicates.
+func ExternalLinks(htmlContent string, source string) []string {
+	sourceURL, err := url.Parse(source)
+	if err != nil {
+		return nil
+	}
+
+	doc, err := html.Parse(strings.NewReader(htmlContent))
+	if err != nil {
+		return nil
+	}
+
+	var links []string
+	for n := range doc.Descendants() {
+		if n.Type != html.ElementNode || n.DataAtom != atom.A {
+			continue
+		}
+
+		link, err := url.Parse(strings.TrimSpace(attrOrEmpty(n, "href")))
+		if err != nil || (link.Scheme != "http" && link.Scheme != "https") {
+			continue
+		}
+		if strings.EqualFold(link.Host, sourceURL.Host) {
+			continue
+		}
+		link.Fragment = ""
+
+		if target := link.String(); !slices.Contains(links, target) {
+			links = append(links, target)
+		}
+	}
+
+	return links
+}
+
+// EnqueueWebmentions queues a webmention to every external page linked from
+// htmlContent. It runs inside tx so nothing is sent for changes that are
+// rolled back. It returns the number of webmentions queued.
+func EnqueueWebmentions(
+	ctx context.Context,
+	tx pgx.Tx,
+	insertOnly queue.InsertOnly,
+	source string,
+	htmlContent string,
+) (int, error) {
+	targets := ExternalLinks(htmlContent, source)
+	if len(targets) == 0 {
+		return 0, nil
+	}
+
+	insertParams := make([]river.InsertManyParams, len(targets))
+	for i, target := range targets {
+		insertParams[i] = river.InsertManyParams{
+			Args: jobs.SendWebmentionArgs{
+				Source: source,
+				Target: target,
+			},
+		}
+	}
+
+	if _, err := insertOnly.InsertManyTx(ctx, tx, insertParams); err != nil {
+		return 0, fmt.Errorf("enqueue webmentions: %w", err)
+	}
+
+	return len(insertParams), nil
+}
+
+func getWebmentionDocument(
+	ctx context.Context,
+	client *http.Client,
+	link string,
+) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/html, */*;q=0.5")
+	req.Header.Set("User-Agent", "mortenvistisen-webmention/1.0")
+
+	return client.Do(req)
+}
+
+func linkHeaderRel(params string) string {
+	match := linkRelPattern.FindStringSubmatch(params)
+	if match == nil {
+		return ""
+	}
+	if match[1] != "" {
+		return match[1]
+	}
+
+	return match[2]
+}
+
+func hasWebmentionRel(rel string) bool {
+	for _, value := range strings.Fields(rel) {
+		if strings.EqualFold(value, "webmention") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func resolveWebmentionURL(base *url.URL, href string) (string, error) {
+	resolved, err := base.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return "", err
+	}
+
+	return resolved.String(), nil
+}
+
+// normalizeWebmentionURL makes links comparable regardless of fragments and
+// trailing slashes.
+func normalizeWebmentionURL(link string) string {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+	parsed.Fragment = ""
+	parsed.Host = strings.ToLower(parsed.Host)
+	parsed.Path = strings.TrimSuffix(parsed.Path, "/")
+
+	return parsed.String()
+}
+
+func isHTMLContentType(contentType string) bool {
+	return contentType == "" || strings.Contains(contentType, "html")
+}
+
+func htmlAttr(n *html.Node, key string) (string, bool) {
+	for _, attr := range n.Attr {
+		if attr.Namespace == "" && strings.EqualFold(attr.Key, key) {
+			return attr.Val, true
+		}
+	}
+
+	return "", false
+}
+
+func attrOrEmpty(n *html.Node, key string) string {
+	value, _ := htmlAttr(n, key)
+	return value
+}
+
+func hasClass(n *html.Node, class string) bool {
+	return slices.Contains(strings.Fields(attrOrEmpty(n, "class")), class)
+}
+
+func nodeText(n *html.Node) string {
+	var b strings.Builder
+	for child := range n.Descendants() {
+		if child.Type == html.TextNode {
+			b.WriteString(child.Data)
+			b.WriteByte(' ')
+		}
+	}
+
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+func truncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+
+	return strings.TrimSpace(string(runes[:limit])) + "…"
+}
@@ -0,0 +1,312 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"mortenvistisen/services"
+)
+
+func TestDiscoverWebmentionEndpoint(t *testing.T) {
+	tests := []struct {
+		name     string
+		handler  func(server string) http.HandlerFunc
+		expected string
+		err      error
+	}{
+		{
+			name: "absolute link header",
+			handler: func(server string) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Link", fmt.Sprintf(`<%s/endpoint>; rel="webmention"`, server))
+				}
+			},
+			expected: "/endpoint",
+		},
+		{
+			name: "relative link header among others",
+			handler: func(string) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Add("Link", `</feed>; rel="alternate", </hooks/mention?x=1>; rel="other webmention"`)
+				}
+			},
+			expected: "/hooks/mention?x=1",
+		},
+		{
+			name: "unquoted rel",
+			handler: func(string) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Link", `</endpoint>; rel=webmention`)
+				}
+			},
+			expected: "/endpoint",
+		},
+		{
+			name: "link element",
+			handler: func(string) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Content-Type", "text/html; charset=utf-8")
+					fmt.Fprint(w, `<html><head><link rel="webmention" href="/from-link"></head><body><a rel="webmention" href="/from-anchor">x</a></body></html>`)
+				}
+			},
+			expected: "/from-link",
+		},
+		{
+			name: "anchor element relative to page",
+			handler: func(string) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Content-Type", "text/html")
+					fmt.Fprint(w, `<p><a href="/not-it">no</a><a rel="nofollow webmention" href="mention">yes</a></p>`)
+				}
+			},
+			expected: "/post/mention",
+		},
+		{
+			name: "empty href is the page itself",
+			handler: func(string) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Content-Type", "text/html")
+					fmt.Fprint(w, `<link rel="webmention" href="">`)
+				}
+			},
+			expected: "/post/page",
+		},
+		{
+			name: "no endpoint",
+			handler: func(string) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Content-Type", "text/html")
+					fmt.Fprint(w, `<a href="/somewhere">nothing</a>`)
+				}
+			},
+			err: services.ErrNoWebmentionEndpoint,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var server *httptest.Server
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tt.handler(server.URL)(w, r)
+			}))
+			defer server.Close()
+
+			endpoint, err := services.DiscoverWebmentionEndpoint(
+				context.Background(),
+				server.Client(),
+				server.URL+"/post/page",
+			)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("expected error %v, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if endpoint != server.URL+tt.expected {
+				t.Errorf("expected %q, got %q", server.URL+tt.expected, endpoint)
+			}
+		})
+	}
+}
+
+func TestDiscoverWebmentionEndpointFollowsRedirects(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/new/page", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/new/page", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<link rel="webmention" href="endpoint">`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	endpoint, err := services.DiscoverWebmentionEndpoint(context.Background(), server.Client(), server.URL+"/old")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := server.URL + "/new/endpoint"; endpoint != expected {
+		t.Errorf("expected %q, got %q", expected, endpoint)
+	}
+}
+
+func TestSendWebmention(t *testing.T) {
+	var source, target, contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+		contentType = r.Header.Get("Content-Type")
+		source = r.FormValue("source")
+		target = r.FormValue("target")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	err := services.SendWebmention(
+		context.Background(),
+		server.Client(),
+		server.URL+"/webmention",
+		"https://mortenvistisen.com/posts/hello",
+		"https://example.com/a?b=c&d=e",
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if contentType != "application/x-www-form-urlencoded" {
+		t.Errorf("unexpected content type %q", contentType)
+	}
+	if source != "https://mortenvistisen.com/posts/hello" {
+		t.Errorf("unexpected source %q", source)
+	}
+	if target != "https://example.com/a?b=c&d=e" {
+		t.Errorf("unexpected target %q", target)
+	}
+}
+
+func TestSendWebmentionRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	err := services.SendWebmention(context.Background(), server.Client(), server.URL, "https://a.example/", "https://b.example/")
+	if err == nil {
+		t.Fatal("expected an error for a 400 response")
+	}
+}
+
+func TestFetchWebmentionSource(t *testing.T) {
+	const target = "https://mortenvistisen.com/posts/hello"
+
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		expected    services.WebmentionSource
+		err         error
+	}{
+		{
+			name:        "links to target",
+			status:      http.StatusOK,
+			contentType: "text/html",
+			body: `<html><head><title> A reply </title><meta name="description" content="Nice post."><meta name="author" content="Jane"></head>
+				<body><a rel="author" href="/about">me</a><a href="https://mortenvistisen.com/posts/hello/#comments">link</a></body></html>`,
+			expected: services.WebmentionSource{
+				Title:      "A reply",
+				AuthorName: "Jane",
+				AuthorURL:  "/about",
+				Excerpt:    "Nice post.",
+			},
+		},
+		{
+			name:        "author from h-card",
+			status:      http.StatusOK,
+			contentType: "text/html",
+			body:        `<article class="h-entry"><span class="p-author h-card">Sam   Doe</span><a href="https://MortenVistisen.com/posts/hello">x</a></article>`,
+			expected:    services.WebmentionSource{AuthorName: "Sam Doe"},
+		},
+		{
+			name:        "link only in text",
+			status:      http.StatusOK,
+			contentType: "text/html",
+			body:        `<p>I read https://mortenvistisen.com/posts/hello today</p>`,
+			err:         services.ErrWebmentionSourceMissingLink,
+		},
+		{
+			name:        "plain text mention",
+			status:      http.StatusOK,
+			contentType: "text/plain",
+			body:        "see " + target,
+		},
+		{
+			name:   "gone",
+			status: http.StatusGone,
+			err:    services.ErrWebmentionSourceGone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var server *httptest.Server
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+				}
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+
+			source, err := services.FetchWebmentionSource(context.Background(), server.Client(), server.URL+"/reply", target)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("expected error %v, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			expected := tt.expected
+			if expected.AuthorURL != "" {
+				expected.AuthorURL = server.URL + expected.AuthorURL
+			}
+			if source != expected {
+				t.Errorf("expected %+v, got %+v", expected, source)
+			}
+		})
+	}
+}
+
+func TestFetchWebmentionSourceServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	_, err := services.FetchWebmentionSource(context.Background(), server.Client(), server.URL, "https://a.example/")
+	if err == nil || errors.Is(err, services.ErrWebmentionSourceGone) {
+		t.Fatalf("expected a retryable error, got %v", err)
+	}
+}
+
+func TestExternalClientRefusesLoopback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request should not reach a loopback server")
+	}))
+	defer server.Close()
+
+	_, err := services.DiscoverWebmentionEndpoint(context.Background(), services.NewExternalClient(), server.URL)
+	if err == nil {
+		t.Fatal("expected the external client to refuse a loopback address")
+	}
+}
+
+func TestExternalLinks(t *testing.T) {
+	content := `<p>
+		<a href="https://example.com/a#section">one</a>
+		<a href="https://mortenvistisen.com/posts/other">internal</a>
+		<a href="/posts/relative">relative</a>
+		<a href="mailto:me@example.com">mail</a>
+		<a href="http://example.org/b">two</a>
+		<a href="https://example.com/a">duplicate</a>
+	</p>`
+
+	links := services.ExternalLinks(content, "https://mortenvistisen.com/posts/hello")
+
+	expected := []string{"https://example.com/a", "http://example.org/b"}
+	if !slices.Equal(links, expected) {
+		t.Errorf("expected %v, got %v", expected, links)
+	}
+}
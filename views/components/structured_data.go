@@ -0,0 +1,140 @@
+package components
+
+import (
+	"encoding/json"
+	"log/slog"
+)
+
+// The schema.org types below are marshalled to JSON-LD by AddJSONLD. Each
+// one adds its own @type; the @context is added once for the document.
+
+type SchemaPerson struct {
+	Name     string   `json:"name"`
+	URL      string   `json:"url,omitempty"`
+	JobTitle string   `json:"jobTitle,omitempty"`
+	SameAs   []string `json:"sameAs,omitempty"`
+}
+
+func (p SchemaPerson) MarshalJSON() ([]byte, error) {
+	type plain SchemaPerson
+	return json.Marshal(struct {
+		Type string `json:"@type"`
+		plain
+	}{"Person", plain(p)})
+}
+
+type SchemaWebSite struct {
+	Name        string       `json:"name"`
+	URL         string       `json:"url"`
+	Description string       `json:"description,omitempty"`
+	InLanguage  string       `json:"inLanguage,omitempty"`
+	Author      SchemaPerson `json:"author"`
+}
+
+func (w SchemaWebSite) MarshalJSON() ([]byte, error) {
+	type plain SchemaWebSite
+	return json.Marshal(struct {
+		Type string `json:"@type"`
+		plain
+	}{"WebSite", plain(w)})
+}
+
+// SchemaBlogPosting describes an article. Dates and TimeRequired, the read
+// time, are ISO 8601.
+type SchemaBlogPosting struct {
+	Headline         string       `json:"headline"`
+	Description      string       `json:"description,omitempty"`
+	URL              string       `json:"url"`
+	MainEntityOfPage string       `json:"mainEntityOfPage"`
+	Image            []string     `json:"image,omitempty"`
+	DatePublished    string       `json:"datePublished,omitempty"`
+	DateModified     string       `json:"dateModified,omitempty"`
+	Author           SchemaPerson `json:"author"`
+	Publisher        SchemaPerson `json:"publisher"`
+	Keywords         []string     `json:"keywords,omitempty"`
+	WordCount        int          `json:"wordCount,omitempty"`
+	TimeRequired     string       `json:"timeRequired,omitempty"`
+	InLanguage       string       `json:"inLanguage,omitempty"`
+}
+
+func (b SchemaBlogPosting) MarshalJSON() ([]byte, error) {
+	type plain SchemaBlogPosting
+	return json.Marshal(struct {
+		Type string `json:"@type"`
+		plain
+	}{"BlogPosting", plain(b)})
+}
+
+// SchemaCreativeWork describes a project. It is typed SoftwareSourceCode
+// when CodeRepository is set.
+type SchemaCreativeWork struct {
+	Name               string       `json:"name"`
+	Description        string       `json:"description,omitempty"`
+	URL                string       `json:"url"`
+	Image              []string     `json:"image,omitempty"`
+	DateCreated        string       `json:"dateCreated,omitempty"`
+	DateModified       string       `json:"dateModified,omitempty"`
+	Author             SchemaPerson `json:"author"`
+	CreativeWorkStatus string       `json:"creativeWorkStatus,omitempty"`
+	CodeRepository     string       `json:"codeRepository,omitempty"`
+	SameAs             []string     `json:"sameAs,omitempty"`
+}
+
+func (c SchemaCreativeWork) MarshalJSON() ([]byte, error) {
+	schemaType := "CreativeWork"
+	if c.CodeRepository != "" {
+		schemaType = "SoftwareSourceCode"
+	}
+
+	type plain SchemaCreativeWork
+	return json.Marshal(struct {
+		Type string `json:"@type"`
+		plain
+	}{schemaType, plain(c)})
+}
+
+type SchemaBreadcrumb struct {
+	Name string
+	URL  string
+}
+
+// SchemaBreadcrumbList lists the trail to a page, starting at the home
+// page. Positions are numbered in order.
+type SchemaBreadcrumbList []SchemaBreadcrumb
+
+func (l SchemaBreadcrumbList) MarshalJSON() ([]byte, error) {
+	type listItem struct {
+		Type     string `json:"@type"`
+		Position int    `json:"position"`
+		Name     string `json:"name"`
+		Item     string `json:"item"`
+	}
+
+	items := make([]listItem, len(l))
+	for i, crumb := range l {
+		items[i] = listItem{"ListItem", i + 1, crumb.Name, crumb.URL}
+	}
+
+	return json.Marshal(struct {
+		Type            string     `json:"@type"`
+		ItemListElement []listItem `json:"itemListElement"`
+	}{"BreadcrumbList", items})
+}
+
+// AddJSONLD adds a schema.org document to the page. encoding/json escapes
+// <, > and &, so the document cannot break out of its script tag.
+func AddJSONLD(doc json.Marshaler) HeadDataOption {
+	data, err := json.Marshal(doc)
+	if err != nil || len(data) < 2 || data[0] != '{' {
+		slog.Error("failed to marshal structured data", "error", err)
+		return func(*HeadData) {}
+	}
+
+	jsonLD := `{"@context":"https://schema.org"`
+	if len(data) > 2 {
+		jsonLD += ","
+	}
+	jsonLD += string(data[1:])
+
+	return AddStructuredData(jsonLD)
+}
@@ -1,7 +1,23 @@
 package views
 
-import "mortenvistisen/services"
+import (
+	"mortenvistisen/models"
+	"mortenvistisen/services"
+)
 
-func markdownToHTML(content string) string {
-	return services.MarkdownToHTML(content)
+// tableOfContentsMinHeadings is how many headings an article needs before
+// it gets a table of contents; shorter articles are easy enough to scan.
+const tableOfContentsMinHeadings = 3
+
+func commentMarkdownToHTML(content string) string {
+	return services.CommentMarkdownToHTML(content)
+}
+
+func countHeadings(headings []models.Heading) int {
+	count := len(headings)
+	for _, heading := range headings {
+		count += countHeadings(heading.Children)
+	}
+
+	return count
 }
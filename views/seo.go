@@ -0,0 +1,219 @@
+package views
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"mortenvistisen/config"
+	"mortenvistisen/models"
+	"mortenvistisen/router/routes"
+	"mortenvistisen/services"
+	"mortenvistisen/views/components"
+)
+
+// seoDescriptionLength keeps generated descriptions within what search
+// engines show in a result.
+const seoDescriptionLength = 160
+
+// seoLanguage is the language of all content on the site.
+const seoLanguage = "en-US"
+
+// codeHosts are the hosts whose project links are treated as the
+// project's source code.
+var codeHosts = []string{"github.com", "gitlab.com", "codeberg.org", "bitbucket.org", "sr.ht"}
+
+// absoluteURL makes a site path absolute, as link previews and structured
+// data require.
+func absoluteURL(path string) string {
+	return strings.TrimRight(config.BaseURL, "/") + path
+}
+
+// seoDescription returns the first of the candidates that is not empty,
+// falling back to a summary of the markdown content.
+func seoDescription(content string, candidates ...string) string {
+	for _, candidate := range candidates {
+		if candidate = strings.TrimSpace(candidate); candidate != "" {
+			return candidate
+		}
+	}
+
+	return services.MarkdownSummary(content, seoDescriptionLength)
+}
+
+func seoDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(time.RFC3339)
+}
+
+// breadcrumbs adds a BreadcrumbList from the home page to the given pages.
+func breadcrumbs(crumbs ...components.SchemaBreadcrumb) components.HeadDataOption {
+	list := components.SchemaBreadcrumbList{{Name: "Home", URL: absoluteURL(routes.HomePage.URL())}}
+	for _, crumb := range crumbs {
+		list = append(list, components.SchemaBreadcrumb{Name: crumb.Name, URL: absoluteURL(crumb.URL)})
+	}
+
+	return components.AddJSONLD(list)
+}
+
+func websiteJSONLD() components.HeadDataOption {
+	return components.AddJSONLD(components.SchemaWebSite{
+		Name:        config.ProjectName,
+		URL:         absoluteURL(routes.HomePage.URL()),
+		Description: components.SiteDescription,
+		InLanguage:  seoLanguage,
+		Author:      components.SiteAuthor(),
+	})
+}
+
+func articleHead(page ArticlePage) []components.HeadDataOption {
+	article := page.Article
+	path := routes.Article.URL(article.Slug)
+	description := seoDescription(article.Content, article.MetaDescription, article.Excerpt)
+
+	image := article.ImageLink
+	imageOpts := []components.HeadDataOption{components.SetImage(image)}
+	if image == "" {
+		image = absoluteURL(routes.ArticleOGImage.URL(article.Slug))
+		imageOpts = []components.HeadDataOption{
+			components.SetImage(image),
+			components.SetImageSize(services.OGImageWidth, services.OGImageHeight),
+		}
+	}
+
+	keywords := make([]string, len(page.Tags))
+	for i, tag := range page.Tags {
+		keywords[i] = tag.Title
+	}
+
+	opts := []components.HeadDataOption{
+		components.SetTitle(metaOr(article.MetaTitle, article.Title)),
+		components.SetDescription(description),
+		components.SetSlug(path),
+		components.SetImageAlt(article.Title),
+		components.SetMetaType("article"),
+		components.SetExtraMeta(components.MetaContent{Property: "article:published_time", Content: seoDate(article.FirstPublishedAt)}),
+		components.SetExtraMeta(components.MetaContent{Property: "article:modified_time", Content: seoDate(article.UpdatedAt)}),
+		components.SetExtraMeta(components.MetaContent{Property: "article:author", Content: absoluteURL(routes.AboutPage.URL())}),
+	}
+	opts = append(opts, imageOpts...)
+	for _, keyword := range keywords {
+		opts = append(opts, components.SetExtraMeta(components.MetaContent{Property: "article:tag", Content: keyword}))
+	}
+
+	return append(opts,
+		components.AddJSONLD(components.SchemaBlogPosting{
+			Headline:         article.Title,
+			Description:      description,
+			URL:              absoluteURL(path),
+			MainEntityOfPage: absoluteURL(path),
+			Image:            []string{image},
+			DatePublished:    seoDate(article.FirstPublishedAt),
+			DateModified:     seoDate(article.UpdatedAt),
+			Author:           components.SiteAuthor(),
+			Publisher:        components.SiteAuthor(),
+			Keywords:         keywords,
+			WordCount:        int(article.Stats.WordCount),
+			TimeRequired:     fmt.Sprintf("PT%dM", article.ReadTime),
+			InLanguage:       seoLanguage,
+		}),
+		breadcrumbs(
+			components.SchemaBreadcrumb{Name: "Posts", URL: routes.ArticleOverview.URL()},
+			components.SchemaBreadcrumb{Name: article.Title, URL: path},
+		),
+	)
+}
+
+func newsletterHead(newsletter models.Newsletter) []components.HeadDataOption {
+	path := routes.Newsletter.URL(newsletter.Slug)
+	description := seoDescription(newsletter.Content, newsletter.MetaDescription)
+	image := absoluteURL(routes.NewsletterOGImage.URL(newsletter.Slug))
+
+	return []components.HeadDataOption{
+		components.SetTitle(metaOr(newsletter.MetaTitle, newsletter.Title)),
+		components.SetDescription(description),
+		components.SetSlug(path),
+		components.SetImage(image),
+		components.SetImageSize(services.OGImageWidth, services.OGImageHeight),
+		components.SetImageAlt(newsletter.Title),
+		components.SetMetaType("article"),
+		components.SetExtraMeta(components.MetaContent{Property: "article:published_time", Content: seoDate(newsletter.ReleasedAt)}),
+		components.SetExtraMeta(components.MetaContent{Property: "article:modified_time", Content: seoDate(newsletter.UpdatedAt)}),
+		components.AddJSONLD(components.SchemaBlogPosting{
+			Headline:         newsletter.Title,
+			Description:      description,
+			URL:              absoluteURL(path),
+			MainEntityOfPage: absoluteURL(path),
+			Image:            []string{image},
+			DatePublished:    seoDate(newsletter.ReleasedAt),
+			DateModified:     seoDate(newsletter.UpdatedAt),
+			Author:           components.SiteAuthor(),
+			Publisher:        components.SiteAuthor(),
+			WordCount:        len(strings.Fields(newsletter.Content)),
+			InLanguage:       seoLanguage,
+		}),
+		breadcrumbs(
+			components.SchemaBreadcrumb{Name: "Newsletters", URL: routes.NewsletterOverview.URL()},
+			components.SchemaBreadcrumb{Name: newsletter.Title, URL: path},
+		),
+	}
+}
+
+func projectHead(project models.Project) []components.HeadDataOption {
+	path := routes.Project.URL(project.Slug)
+	description := seoDescription(project.Content, project.Description)
+	image := absoluteURL(routes.ProjectOGImage.URL(project.Slug))
+
+	work := components.SchemaCreativeWork{
+		Name:               project.Title,
+		Description:        description,
+		URL:                absoluteURL(path),
+		Image:              []string{image},
+		DateCreated:        seoDate(project.StartedAt),
+		DateModified:       seoDate(project.UpdatedAt),
+		Author:             components.SiteAuthor(),
+		CreativeWorkStatus: project.Status,
+	}
+	if project.ProjectURL != "" {
+		if isCodeHost(project.ProjectURL) {
+			work.CodeRepository = project.ProjectURL
+		} else {
+			work.SameAs = []string{project.ProjectURL}
+		}
+	}
+
+	return []components.HeadDataOption{
+		components.SetTitle(project.Title),
+		components.SetDescription(description),
+		components.SetSlug(path),
+		components.SetImage(image),
+		components.SetImageSize(services.OGImageWidth, services.OGImageHeight),
+		components.SetImageAlt(project.Title),
+		components.SetMetaType("article"),
+		components.AddJSONLD(work),
+		breadcrumbs(
+			components.SchemaBreadcrumb{Name: "Projects", URL: routes.ProjectOverview.URL()},
+			components.SchemaBreadcrumb{Name: project.Title, URL: path},
+		),
+	}
+}
+
+func isCodeHost(link string) bool {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+	for _, codeHost := range codeHosts {
+		if host == codeHost || strings.HasSuffix(host, "."+codeHost) {
+			return true
+		}
+	}
+
+	return false
+}